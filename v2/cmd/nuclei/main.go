@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/internal/runner"
 )
@@ -16,4 +18,8 @@ func main() {
 
 	nucleiRunner.RunEnumeration()
 	nucleiRunner.Close()
+
+	if nucleiRunner.Failed() {
+		os.Exit(1)
+	}
 }
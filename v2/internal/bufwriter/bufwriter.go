@@ -62,6 +62,14 @@ func (w *Writer) WriteString(data string) error {
 	return err
 }
 
+// Flush writes the buffered data to the underlying file
+func (w *Writer) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.writer.Flush()
+}
+
 // Close closes the underlying writer flushing everything to disk
 func (w *Writer) Close() error {
 	w.mutex.Lock()
@@ -0,0 +1,149 @@
+package debugwriter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// Writer writes request/response debug dumps either to stderr or
+// to a directory with one file per dump and an index file.
+//
+// Writes are serialized so dumps from concurrent requests don't interleave.
+type Writer struct {
+	mutex     *sync.Mutex
+	directory string
+	index     *os.File
+	counter   uint64
+}
+
+// stderrWriter is used when no writer has been configured
+var stderrWriter = &Writer{mutex: &sync.Mutex{}}
+
+// reUnsafeChars matches characters not allowed in dump file names
+var reUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// New creates a new debug writer. If directory is empty the dumps are written to stderr.
+func New(directory string) (*Writer, error) {
+	writer := &Writer{mutex: &sync.Mutex{}, directory: directory}
+
+	if directory == "" {
+		return writer, nil
+	}
+
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	index, err := os.Create(path.Join(directory, "index.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	writer.index = index
+
+	return writer, nil
+}
+
+// NextID returns a new identifier used to pair the dumps of the same exchange
+func (w *Writer) NextID() uint64 {
+	if w == nil {
+		w = stderrWriter
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.counter++
+
+	return w.counter
+}
+
+// Meta contains the metadata of the request of a dump
+type Meta struct {
+	// TemplatePath is the path of the template file
+	TemplatePath string
+	// Index is the position of the request among the requests of the template
+	Index int
+	// Attempt is the number of times the request was sent, once known
+	Attempt int
+	// Payloads contains the payload values of the request
+	Payloads map[string]interface{}
+}
+
+// String returns the metadata as space separated key=value pairs
+func (m *Meta) String() string {
+	if m == nil {
+		return ""
+	}
+
+	builder := &strings.Builder{}
+	fmt.Fprintf(builder, "template=%s request=%d", m.TemplatePath, m.Index)
+
+	if m.Attempt > 0 {
+		fmt.Fprintf(builder, " attempt=%d", m.Attempt)
+	}
+
+	names := make([]string, 0, len(m.Payloads))
+	for name := range m.Payloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(builder, " %s=%v", name, m.Payloads[name])
+	}
+
+	return builder.String()
+}
+
+// Dump writes a dump of the specified kind (eg. "HTTP request") for a template and target,
+// with the metadata of its request
+func (w *Writer) Dump(id uint64, kind, templateID, target string, meta *Meta, data []byte) {
+	if w == nil {
+		w = stderrWriter
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.directory == "" {
+		gologger.Infof("Dumped %s for %s (%s) [%s]\n\n", kind, target, templateID, meta.String())
+		fmt.Fprintf(os.Stderr, "%s\n", string(data))
+
+		return
+	}
+
+	directory := path.Join(w.directory, reUnsafeChars.ReplaceAllString(templateID, "_"))
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		gologger.Warningf("Could not create debug directory %s: %s\n", directory, err)
+		return
+	}
+
+	filename := path.Join(directory, fmt.Sprintf("%06d-%s.txt", id, reUnsafeChars.ReplaceAllString(kind, "-")))
+	if err := ioutil.WriteFile(filename, data, 0600); err != nil {
+		gologger.Warningf("Could not write debug dump %s: %s\n", filename, err)
+		return
+	}
+
+	fmt.Fprintf(w.index, "%06d\t%s\t%s\t%s\t%s\t%s\n", id, templateID, kind, target, filename, meta.String())
+}
+
+// Close closes the index file, if any
+func (w *Writer) Close() error {
+	if w == nil || w.index == nil {
+		return nil
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.index.Close()
+}
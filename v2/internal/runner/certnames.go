@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/certnames"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// Modes of the tls certificate names harvesting
+const (
+	certificateNamesAdd    = "add"
+	certificateNamesReport = "report"
+)
+
+// certificateNameModes contains the supported certificate names modes
+var certificateNameModes = map[string]bool{
+	certificateNamesAdd:    true,
+	certificateNamesReport: true,
+}
+
+// harvestCertificateNames collects the names of the tls certificates of
+// the https inputs. The names in scope are returned as targets in the add
+// mode, the others are reported as related assets.
+func (r *Runner) harvestCertificateNames(inputs []string) []string {
+	timeout := time.Duration(r.options.Timeout) * time.Second
+
+	var mutex sync.Mutex
+
+	var urls []string
+
+	swg := sizedwaitgroup.New(discoveryConcurrency)
+
+	for _, address := range tlsAddresses(inputs) {
+		swg.Add()
+
+		go func(address string) {
+			defer swg.Done()
+
+			names, err := certnames.Harvest(address, timeout)
+			if err != nil {
+				gologger.Verbosef("Could not harvest certificate names: %s\n", address, err)
+				return
+			}
+
+			host, port, _ := net.SplitHostPort(address)
+
+			var hostURLs []string
+
+			for _, name := range names {
+				if r.options.TLSNames == certificateNamesAdd && certnames.InScope(name, host, r.options.TLSNamesScope) {
+					hostURLs = append(hostURLs, httpsURL(name, port))
+					continue
+				}
+
+				gologger.Infof("Related asset %s found in the certificate of %s\n", name, address)
+			}
+
+			mutex.Lock()
+			urls = append(urls, hostURLs...)
+			mutex.Unlock()
+		}(address)
+	}
+
+	swg.Wait()
+
+	return urls
+}
+
+// tlsAddresses returns the deduplicated addresses of the https inputs,
+// the inputs without a scheme being tried on the https port.
+func tlsAddresses(inputs []string) []string {
+	seen := make(map[string]struct{})
+
+	var addresses []string
+
+	for _, input := range inputs {
+		if input == "" {
+			continue
+		}
+
+		if !strings.Contains(input, "://") {
+			input = "https://" + input
+		}
+
+		parsed, err := url.Parse(input)
+		if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+			continue
+		}
+
+		port := parsed.Port()
+		if port == "" {
+			port = "443"
+		}
+
+		address := net.JoinHostPort(parsed.Hostname(), port)
+		if _, ok := seen[address]; !ok {
+			seen[address] = struct{}{}
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// httpsURL returns the https url of a host, leaving out the default port
+func httpsURL(host, port string) string {
+	if port == "443" {
+		return "https://" + host
+	}
+
+	return "https://" + net.JoinHostPort(host, port)
+}
@@ -1,14 +1,15 @@
 package runner
 
 import (
-	"bufio"
 	"os"
 	"path"
 	"regexp"
-	"strings"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/exclude"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
 )
 
 // nucleiConfig contains some configuration options for nuclei
@@ -17,7 +18,7 @@ type nucleiConfig struct {
 	CurrentVersion     string    `json:"current-version,omitempty"`
 	LastChecked        time.Time `json:"last-checked,omitempty"`
 
-	// IgnorePaths ignores all the paths listed unless specified manually
+	// IgnorePaths contains the templates excluded from the scans
 	IgnorePaths []string `json:"ignore-paths,omitempty"`
 }
 
@@ -72,47 +73,61 @@ func (r *Runner) writeConfiguration(config *nucleiConfig) error {
 
 const nucleiIgnoreFile = ".nuclei-ignore"
 
-// readNucleiIgnoreFile reads the nuclei ignore file marking it in map
-func (r *Runner) readNucleiIgnoreFile() {
-	file, err := os.Open(path.Join(r.templatesConfig.TemplatesDirectory, nucleiIgnoreFile))
-	if err != nil {
-		return
+// loadExclusions returns the deny-list of templates of the nuclei-ignore files
+// of the templates directory and of the working directory, of the exclusion
+// file and of the excluded templates given on the command line
+func (r *Runner) loadExclusions() (*exclude.List, error) {
+	exclusions := exclude.New(r.options.DeniedTemplates...)
+
+	ignoreFiles := []string{nucleiIgnoreFile}
+	if r.templatesConfig != nil {
+		exclusions.Add(r.templatesConfig.IgnorePaths...)
+		ignoreFiles = append(ignoreFiles, path.Join(r.templatesConfig.TemplatesDirectory, nucleiIgnoreFile))
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		text := scanner.Text()
-		if text == "" {
-			continue
+	// the nuclei-ignore files are optional
+	for _, file := range ignoreFiles {
+		if entries, err := exclude.ReadFile(file); err == nil {
+			exclusions.Add(entries...)
 		}
-		if strings.HasPrefix(text, "#") {
-			continue
+	}
+
+	if r.options.DeniedTemplatesFile != "" {
+		entries, err := exclude.ReadFile(r.options.DeniedTemplatesFile)
+		if err != nil {
+			return nil, err
 		}
-		r.templatesConfig.IgnorePaths = append(r.templatesConfig.IgnorePaths, text)
+		exclusions.Add(entries...)
 	}
+
+	return exclusions, nil
 }
 
-// checkIfInNucleiIgnore checks if a path falls under nuclei-ignore rules.
-func (r *Runner) checkIfInNucleiIgnore(item string) bool {
-	if r.templatesConfig == nil {
+// excludedTemplate returns true if a parsed template is denied by its id or path
+func (r *Runner) excludedTemplate(id, path string) bool {
+	if !r.exclusions.Template(id, path) {
 		return false
 	}
 
-	for _, paths := range r.templatesConfig.IgnorePaths {
-		// If we have a path to ignore, check if it's in the item.
-		if paths[len(paths)-1] == '/' {
-			if strings.Contains(item, paths) {
-				return true
-			}
+	gologger.Warningf("Excluding template %s", id)
 
-			continue
-		}
-		// Check for file based extension in ignores
-		if strings.HasSuffix(item, paths) {
-			return true
-		}
+	return true
+}
+
+// tooIntrusive returns true if a template is more intrusive than allowed
+func (r *Runner) tooIntrusive(template *templates.Template) bool {
+	if r.options.Intrusiveness == "" {
+		return false
+	}
+
+	allowed, _ := templates.IntrusivenessLevel(r.options.Intrusiveness)
+	class := template.Intrusiveness()
+
+	if level, _ := templates.IntrusivenessLevel(class); level <= allowed {
+		return false
 	}
 
-	return false
+	gologger.Warningf("Excluding %s template %s, use -intrusiveness %s to run it", class, template.ID, class)
+
+	return true
 }
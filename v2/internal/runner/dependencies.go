@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// templateDependencies returns for each template the templates it waits
+// for: the templates or capability providers it requires and the templates
+// publishing the scan context values it uses, whose names are recorded.
+// Dependencies forming cycles are ignored.
+func (r *Runner) templateDependencies(parsedTemplates []interface{}) [][]int {
+	publishers := make(map[string][]int)
+	providers := make(map[string][]int)
+
+	for i, t := range parsedTemplates {
+		template, ok := t.(*templates.Template)
+		if !ok {
+			continue
+		}
+
+		for _, name := range template.PublishedValues() {
+			publishers[name] = append(publishers[name], i)
+		}
+
+		providers[template.ID] = append(providers[template.ID], i)
+
+		for _, capability := range template.Provides {
+			providers[capability] = append(providers[capability], i)
+		}
+	}
+
+	dependencies := make([][]int, len(parsedTemplates))
+	r.contextValues = make(map[string][]string)
+
+	for i, t := range parsedTemplates {
+		template, ok := t.(*templates.Template)
+		if !ok {
+			continue
+		}
+
+		seen := make(map[int]struct{})
+
+		add := func(indexes []int) {
+			for _, index := range indexes {
+				if _, ok := seen[index]; !ok && index != i {
+					seen[index] = struct{}{}
+					dependencies[i] = append(dependencies[i], index)
+				}
+			}
+		}
+
+		for _, name := range template.Requires {
+			indexes, ok := providers[name]
+			if !ok {
+				gologger.Warningf("No template provides %s required by %s\n", name, template.ID)
+				continue
+			}
+
+			add(indexes)
+		}
+
+		for _, name := range template.Placeholders() {
+			indexes, ok := publishers[name]
+			if !ok {
+				continue
+			}
+
+			r.contextValues[template.ID] = append(r.contextValues[template.ID], name)
+			add(indexes)
+		}
+	}
+
+	for i := range dependencies {
+		if dependsOn(dependencies, i, i, make(map[int]bool)) {
+			gologger.Warningf("Ignoring the cyclic dependencies of %s\n", parsedTemplates[i].(*templates.Template).ID)
+			dependencies[i] = nil
+		}
+	}
+
+	return dependencies
+}
+
+// dependsOn returns true if a template depends on another one, directly
+// or through other templates.
+func dependsOn(dependencies [][]int, from, to int, visited map[int]bool) bool {
+	for _, dependency := range dependencies[from] {
+		if dependency == to {
+			return true
+		}
+
+		if visited[dependency] {
+			continue
+		}
+
+		visited[dependency] = true
+
+		if dependsOn(dependencies, dependency, to, visited) {
+			return true
+		}
+	}
+
+	return false
+}
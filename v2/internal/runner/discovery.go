@@ -0,0 +1,183 @@
+package runner
+
+import (
+	"bufio"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/crawler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// discoveryConcurrency is the number of targets crawled in parallel
+const discoveryConcurrency = 10
+
+// targets returns the deduplicated targets of the scan
+func (r *Runner) targets() []string {
+	var targets []string
+
+	scanner := bufio.NewScanner(strings.NewReader(r.input))
+	for scanner.Scan() {
+		targets = append(targets, scanner.Text())
+	}
+
+	return targets
+}
+
+// newCrawler creates the crawler discovering the inputs of the targets
+func (r *Runner) newCrawler() *crawler.Crawler {
+	c, err := crawler.New(&crawler.Options{
+		Depth:         r.options.CrawlDepth,
+		MaxURLs:       r.options.CrawlMaxURLs,
+		Timeout:       time.Duration(r.options.Timeout) * time.Second,
+		RespectRobots: r.options.CrawlRobots,
+		ProxyURL:      r.options.ProxyURL,
+	})
+	if err != nil {
+		gologger.Fatalf("Could not create crawler: %s\n", err)
+	}
+
+	return c
+}
+
+// discoverURLs crawls the targets, collecting the urls discovered
+// for the templates requiring full urls.
+func (r *Runner) discoverURLs() {
+	c := r.newCrawler()
+
+	var mutex sync.Mutex
+
+	swg := sizedwaitgroup.New(discoveryConcurrency)
+
+	for _, target := range r.targets() {
+		swg.Add()
+
+		go func(target string) {
+			defer swg.Done()
+
+			urls, err := c.Crawl(target)
+			if err != nil {
+				gologger.Warningf("Could not crawl %s: %s\n", target, err)
+				return
+			}
+
+			gologger.Verbosef("Discovered %d urls\n", target, len(urls))
+
+			mutex.Lock()
+			r.addDiscovered(target, urls)
+			mutex.Unlock()
+		}(target)
+	}
+
+	swg.Wait()
+}
+
+// seedPaths fetches the robots.txt and sitemaps of the targets, exposing the
+// discovered paths as the host:paths wordlist of each target.
+func (r *Runner) seedPaths() {
+	c := r.newCrawler()
+
+	var mutex sync.Mutex
+
+	swg := sizedwaitgroup.New(discoveryConcurrency)
+
+	for _, target := range r.targets() {
+		swg.Add()
+
+		go func(target string) {
+			defer swg.Done()
+
+			paths, urls, err := c.Seed(target)
+			if err != nil {
+				gologger.Warningf("Could not seed paths for %s: %s\n", target, err)
+				return
+			}
+
+			gologger.Verbosef("Seeded %d paths from robots.txt and sitemaps\n", target, len(paths))
+			generators.SetHostWordlist(target, "paths", paths)
+
+			mutex.Lock()
+			r.addDiscovered(target, urls)
+			mutex.Unlock()
+		}(target)
+	}
+
+	swg.Wait()
+}
+
+// archivedURLs pulls the historical urls of the targets from web archives
+// for the templates requiring full urls.
+func (r *Runner) archivedURLs() {
+	c := r.newCrawler()
+	sources := strings.Split(r.options.Archive, ",")
+
+	var mutex sync.Mutex
+
+	swg := sizedwaitgroup.New(discoveryConcurrency)
+
+	for _, target := range r.targets() {
+		swg.Add()
+
+		go func(target string) {
+			defer swg.Done()
+
+			urls, err := c.Archived(target, sources)
+			if err != nil {
+				gologger.Warningf("Could not get archived urls for %s: %s\n", target, err)
+			}
+
+			gologger.Verbosef("Found %d archived urls\n", target, len(urls))
+
+			mutex.Lock()
+			r.addDiscovered(target, urls)
+			mutex.Unlock()
+		}(target)
+	}
+
+	swg.Wait()
+}
+
+// addDiscovered adds the urls discovered for a target, skipping the known ones
+func (r *Runner) addDiscovered(target string, urls []string) {
+	if r.discovered == nil {
+		r.discovered = make(map[string][]string)
+	}
+
+	known := make(map[string]struct{}, len(r.discovered[target]))
+	for _, url := range r.discovered[target] {
+		known[url] = struct{}{}
+	}
+
+	for _, url := range urls {
+		if _, ok := known[url]; ok || url == target {
+			continue
+		}
+
+		known[url] = struct{}{}
+		r.discovered[target] = append(r.discovered[target], url)
+
+		// allocate global rate limiters
+		r.rateLimiter.Add(url, r.options.RateLimit)
+	}
+}
+
+// templateInputs returns the inputs a template is run against, the
+// discovered urls being added for the templates requiring full urls.
+func (r *Runner) templateInputs(template *templates.Template) []string {
+	targets := r.targets()
+	if !template.RequiresURLs || len(r.discovered) == 0 {
+		return targets
+	}
+
+	inputs := make([]string, 0, len(targets))
+	for _, target := range targets {
+		inputs = append(inputs, target)
+		inputs = append(inputs, r.discovered[target]...)
+	}
+
+	return inputs
+}
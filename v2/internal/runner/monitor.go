@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/metrics"
+	"github.com/projectdiscovery/nuclei/v2/pkg/monitor"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scancontext"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// initMonitor sets up the schedule, history and alerting of the
+// monitoring mode
+func (r *Runner) initMonitor() {
+	var err error
+
+	// the schedule was validated with the options
+	r.schedule, _ = monitor.ParseSchedule(r.options.Monitor)
+
+	r.history, err = monitor.LoadHistory(r.options.MonitorHistory)
+	if err != nil {
+		gologger.Fatalf("Could not read monitoring history '%s': %s\n", r.options.MonitorHistory, err)
+	}
+
+	if r.options.MonitorWebhook != "" {
+		r.alerter, err = monitor.NewAlerter(r.options.MonitorWebhook, time.Duration(r.options.Timeout)*time.Second)
+		if err != nil {
+			gologger.Fatalf("Could not create monitoring alerts: %s\n", err)
+		}
+	}
+}
+
+// runMonitor re-runs the scan on the schedule until the process is
+// interrupted between two runs. The templates are parsed again for each
+// run, picking up their changes.
+func (r *Runner) runMonitor() {
+	for {
+		next := r.schedule.Next(time.Now())
+		if next.IsZero() {
+			gologger.Fatalf("The monitoring schedule '%s' never runs\n", r.options.Monitor)
+		}
+
+		gologger.Infof("Next monitoring run at %s", next.Format(time.RFC3339))
+		if !waitUntil(next) {
+			gologger.Infof("Monitoring stopped")
+			return
+		}
+
+		// each run starts from scratch
+		r.summary = summary.New()
+		r.templateMetrics = metrics.New(r.options.SuspiciousMatchRate)
+		r.budget = budget.New(r.options.MaxRequestsTotal, r.options.MaxRequestsTemplate)
+		r.errorLog.Reset()
+		r.scanContext = scancontext.New()
+		if r.report != nil {
+			r.report = report.New(r.format.Labels)
+		}
+
+		runID := r.beginStoredRun(next)
+
+		r.runTemplates()
+		r.writeSummary()
+		r.writeReport()
+		r.endStoredRun(runID)
+		r.recordRun(next)
+
+		// the findings are kept on disk while waiting for the next run
+		if r.output != nil {
+			if err := r.output.Flush(); err != nil {
+				gologger.Errorf("Could not write output file '%s': %s\n", r.options.Output, err)
+			}
+		}
+	}
+}
+
+// waitUntil waits for the time of the next run, returning false if the
+// process was interrupted meanwhile
+func waitUntil(next time.Time) bool {
+	signals, stop := notifyInterrupt()
+	defer stop()
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-signals:
+		return false
+	}
+}
+
+// recordRun adds the findings of a run to the history and alerts of the
+// findings that appeared or were resolved since the previous run
+func (r *Runner) recordRun(started time.Time) {
+	changes := r.history.Add(&monitor.Run{Time: started, Findings: r.summary.Records()})
+
+	if err := r.history.Save(r.options.MonitorHistory); err != nil {
+		gologger.Errorf("Could not write monitoring history '%s': %s\n", r.options.MonitorHistory, err)
+	}
+
+	if changes.Empty() {
+		return
+	}
+
+	gologger.Infof("%d new and %d resolved findings since the previous run", len(changes.New), len(changes.Resolved))
+
+	// only the new findings are notified, the previous ones already were
+	r.notifyFindings(changes.New)
+
+	if err := r.alerter.Alert(changes); err != nil {
+		gologger.Errorf("Could not send monitoring alert: %s\n", err)
+	}
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/oob"
+)
+
+// newOOBClient returns the client of the provider of the out-of-band
+// interactions, nil when none is configured
+func (r *Runner) newOOBClient() (*oob.Client, error) {
+	var provider oob.Provider
+
+	switch {
+	case r.options.CollaboratorBIID != "":
+		collaborator, err := oob.NewCollaborator(&oob.CollaboratorOptions{
+			Server:  r.options.CollaboratorServer,
+			BIID:    r.options.CollaboratorBIID,
+			Payload: r.options.CollaboratorPayload,
+		})
+		if err != nil {
+			return nil, err
+		}
+		provider = collaborator
+	case r.options.OOBServer != "":
+		remote, err := oob.NewRemote(&oob.RemoteOptions{
+			URL:   r.options.OOBServer,
+			Token: r.options.OOBToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		provider = remote
+	default:
+		return nil, nil
+	}
+
+	gologger.Infof("Receiving the out-of-band interactions on %s\n", provider.Domain())
+
+	return oob.New(provider, time.Duration(r.options.OOBWait)*time.Second), nil
+}
+
+// serveOOB runs the callback server receiving the out-of-band interactions
+// of the scans until it fails
+func (r *Runner) serveOOB() {
+	token := r.options.OOBToken
+	if token == "" {
+		data := make([]byte, 16)
+		_, _ = rand.Read(data)
+		token = hex.EncodeToString(data)
+		gologger.Infof("Generated the token of the polling api: %s\n", token)
+	}
+
+	server, err := oob.NewServer(&oob.ServerOptions{
+		Domain:      r.options.OOBServe,
+		IP:          r.options.OOBServeIP,
+		DNSAddress:  r.options.OOBServeDNS,
+		HTTPAddress: r.options.OOBServeHTTP,
+		APIAddress:  r.options.OOBServeAPI,
+		Token:       token,
+		RebindAfter: r.options.OOBServeRebindAfter,
+	})
+	if err != nil {
+		gologger.Fatalf("Could not create callback server: %s\n", err)
+	}
+
+	gologger.Infof("Receiving the callbacks of %s\n", r.options.OOBServe)
+
+	if err := server.ListenAndServe(); err != nil {
+		gologger.Fatalf("Could not serve the callbacks: %s\n", err)
+	}
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/workflows"
+)
+
+// Template execution orders
+const (
+	orderSeverity = "severity"
+	orderRequests = "requests"
+	orderPriority = "priority"
+)
+
+// templateOrders contains the supported template execution orders
+var templateOrders = map[string]bool{
+	orderSeverity: true,
+	orderRequests: true,
+	orderPriority: true,
+}
+
+// severityRanks ranks the severities from the most to the least critical
+var severityRanks = map[string]int{
+	"critical": 5,
+	"high":     4,
+	"medium":   3,
+	"low":      2,
+	"info":     1,
+}
+
+// orderTemplates sorts the parsed templates in the order they should be
+// executed, keeping the loading order between equivalent templates.
+func orderTemplates(parsedTemplates []interface{}, order string) {
+	var less func(i, j int) bool
+
+	switch order {
+	case orderSeverity:
+		less = func(i, j int) bool {
+			return templateSeverityRank(parsedTemplates[i]) > templateSeverityRank(parsedTemplates[j])
+		}
+	case orderRequests:
+		less = func(i, j int) bool {
+			return templateRequestCount(parsedTemplates[i]) < templateRequestCount(parsedTemplates[j])
+		}
+	case orderPriority:
+		less = func(i, j int) bool {
+			return templatePriority(parsedTemplates[i]) > templatePriority(parsedTemplates[j])
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(parsedTemplates, less)
+}
+
+// templateSeverityRank returns the rank of the severity of a template or workflow
+func templateSeverityRank(t interface{}) int {
+	switch tp := t.(type) {
+	case *templates.Template:
+		return severityRanks[strings.ToLower(tp.Info.Severity)]
+	case *workflows.Workflow:
+		return severityRanks[strings.ToLower(tp.Info.Severity)]
+	}
+
+	return 0
+}
+
+// templateRequestCount returns the number of requests of a template per target.
+// Workflows are run last as their requests are only known while running.
+func templateRequestCount(t interface{}) int64 {
+	if tp, ok := t.(*templates.Template); ok {
+		return tp.GetRequestCount()
+	}
+
+	return int64(^uint64(0) >> 1)
+}
+
+// templatePriority returns the priority declared in the info of a template or workflow
+func templatePriority(t interface{}) int {
+	switch tp := t.(type) {
+	case *templates.Template:
+		return tp.Info.Priority
+	case *workflows.Workflow:
+		return tp.Info.Priority
+	}
+
+	return 0
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/portscan"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// portScanConcurrency is the number of ports of a host connected to at once
+const portScanConcurrency = 100
+
+// scanPorts runs a connect scan of the hosts of the inputs, returning
+// the http urls of their open ports.
+func (r *Runner) scanPorts(inputs []string) []string {
+	ports, err := r.scannedPorts()
+	if err != nil {
+		gologger.Fatalf("Could not parse ports: %s\n", err)
+	}
+
+	scanner := portscan.New(time.Duration(r.options.PortScanTimeout)*time.Millisecond, portScanConcurrency)
+	r.portScan = portscan.NewResults(ports)
+
+	var mutex sync.Mutex
+
+	var urls []string
+
+	swg := sizedwaitgroup.New(discoveryConcurrency)
+
+	for _, host := range inputHosts(inputs) {
+		swg.Add()
+
+		go func(host string) {
+			defer swg.Done()
+
+			open := scanner.Scan(host, ports)
+			r.portScan.Add(host, open)
+
+			gologger.Verbosef("Found %d open ports\n", host, len(open))
+
+			var hostURLs []string
+
+			for _, port := range open {
+				hostURLs = append(hostURLs, portURL(host, port, scanner.IsTLS(host, port)))
+			}
+
+			mutex.Lock()
+			urls = append(urls, hostURLs...)
+			mutex.Unlock()
+		}(host)
+	}
+
+	swg.Wait()
+
+	return urls
+}
+
+// scannedPorts returns the ports selected by the port scan options
+func (r *Runner) scannedPorts() ([]int, error) {
+	if r.options.Ports != "" {
+		return portscan.Parse(r.options.Ports)
+	}
+
+	return portscan.Top(r.options.TopPorts), nil
+}
+
+// inputHosts returns the deduplicated hosts of the inputs
+func inputHosts(inputs []string) []string {
+	seen := make(map[string]struct{})
+
+	var hosts []string
+
+	for _, input := range inputs {
+		if input == "" {
+			continue
+		}
+
+		if !strings.Contains(input, "://") {
+			input = "http://" + input
+		}
+
+		parsed, err := url.Parse(input)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+
+		host := parsed.Hostname()
+		if _, ok := seen[host]; !ok {
+			seen[host] = struct{}{}
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+// portURL returns the url of the http service of an open port, leaving
+// out the default port of the scheme.
+func portURL(host string, port int, isTLS bool) string {
+	scheme := "http"
+	if isTLS {
+		scheme = "https"
+	}
+
+	if (scheme == "http" && port == 80) || (scheme == "https" && port == 443) {
+		return scheme + "://" + host
+	}
+
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"net/http/cookiejar"
 	"os"
 	"path"
 	"path/filepath"
@@ -17,6 +16,7 @@ import (
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
 	"github.com/projectdiscovery/nuclei/v2/pkg/atomicboolean"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cookies"
 	"github.com/projectdiscovery/nuclei/v2/pkg/executer"
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
@@ -29,10 +29,20 @@ type workflowTemplates struct {
 	Templates []*workflows.Template
 }
 
+// requestCounter is implemented by the requests of all the protocols
+type requestCounter interface {
+	GetRequestCount() int64
+}
+
 // processTemplateWithList processes a template and runs the enumeration on all the targets
 func (r *Runner) processTemplateWithList(p progress.IProgress, template *templates.Template, request interface{}) bool {
 	var httpExecuter *executer.HTTPExecuter
 	var dnsExecuter *executer.DNSExecuter
+	var smugglingExecuter *executer.SmugglingExecuter
+	var storageExecuter *executer.StorageExecuter
+	var takeoverExecuter *executer.TakeoverExecuter
+	var auditExecuter *executer.AuditExecuter
+	var serviceExecuter *executer.ServiceExecuter
 	var err error
 
 	// Create an executer based on the request type.
@@ -48,6 +58,115 @@ func (r *Runner) processTemplateWithList(p progress.IProgress, template *templat
 			ColoredOutput: !r.options.NoColor,
 			Colorizer:     r.colorizer,
 			Decolorizer:   r.decolorizer,
+			Scheduler:     r.scheduler,
+			DryRun:        r.options.DryRun,
+			DebugWriter:   r.debugWriter,
+			Format:        r.format,
+			Summary:       r.summary,
+			Report:        r.report,
+			ErrorLog:      r.errorLog,
+			IgnoreList:    r.ignoreList,
+		})
+	case *requests.SmugglingRequest:
+		smugglingExecuter = executer.NewSmugglingExecuter(&executer.SmugglingOptions{
+			Template:         template,
+			SmugglingRequest: value,
+			Writer:           r.output,
+			JSON:             r.options.JSON,
+			JSONRequests:     r.options.JSONRequests,
+			ColoredOutput:    !r.options.NoColor,
+			Colorizer:        r.colorizer,
+			Decolorizer:      r.decolorizer,
+			Scheduler:        r.scheduler,
+			DryRun:           r.options.DryRun,
+			Format:           r.format,
+			Summary:          r.summary,
+			Report:           r.report,
+			ErrorLog:         r.errorLog,
+			IgnoreList:       r.ignoreList,
+			Connector:        r.connector,
+			RateLimiter:      r.rateLimiter,
+			Budget:           r.budget,
+		})
+	case *requests.StorageRequest:
+		storageExecuter, err = executer.NewStorageExecuter(&executer.StorageOptions{
+			Template:       template,
+			StorageRequest: value,
+			Writer:         r.output,
+			JSON:           r.options.JSON,
+			ColoredOutput:  !r.options.NoColor,
+			Colorizer:      r.colorizer,
+			Decolorizer:    r.decolorizer,
+			Scheduler:      r.scheduler,
+			DryRun:         r.options.DryRun,
+			Format:         r.format,
+			Summary:        r.summary,
+			Report:         r.report,
+			ErrorLog:       r.errorLog,
+			IgnoreList:     r.ignoreList,
+			Connector:      r.connector,
+		})
+	case *requests.ServiceRequest:
+		serviceExecuter = executer.NewServiceExecuter(&executer.ServiceOptions{
+			Debug:          r.options.Debug,
+			Template:       template,
+			ServiceRequest: value,
+			Writer:         r.output,
+			JSON:           r.options.JSON,
+			JSONRequests:   r.options.JSONRequests,
+			ColoredOutput:  !r.options.NoColor,
+			Colorizer:      r.colorizer,
+			Decolorizer:    r.decolorizer,
+			Scheduler:      r.scheduler,
+			DryRun:         r.options.DryRun,
+			DebugWriter:    r.debugWriter,
+			Format:         r.format,
+			Summary:        r.summary,
+			Report:         r.report,
+			ErrorLog:       r.errorLog,
+			IgnoreList:     r.ignoreList,
+			PortScan:       r.portScan,
+			VulnDB:         r.vulnDB,
+			ScanContext:    r.scanContext,
+		})
+	case *requests.TakeoverRequest:
+		takeoverExecuter, err = executer.NewTakeoverExecuter(&executer.TakeoverOptions{
+			Template:        template,
+			TakeoverRequest: value,
+			Writer:          r.output,
+			JSON:            r.options.JSON,
+			ColoredOutput:   !r.options.NoColor,
+			Colorizer:       r.colorizer,
+			Decolorizer:     r.decolorizer,
+			Scheduler:       r.scheduler,
+			DryRun:          r.options.DryRun,
+			Format:          r.format,
+			Summary:         r.summary,
+			Report:          r.report,
+			ErrorLog:        r.errorLog,
+			IgnoreList:      r.ignoreList,
+			Connector:       r.connector,
+		})
+	case *requests.AuditRequest:
+		auditExecuter, err = executer.NewAuditExecuter(&executer.AuditOptions{
+			Template:      template,
+			AuditRequest:  value,
+			Profile:       r.options.AuditProfile,
+			Writer:        r.output,
+			JSON:          r.options.JSON,
+			ColoredOutput: !r.options.NoColor,
+			Colorizer:     r.colorizer,
+			Decolorizer:   r.decolorizer,
+			Scheduler:     r.scheduler,
+			DryRun:        r.options.DryRun,
+			Format:        r.format,
+			Summary:       r.summary,
+			Report:        r.report,
+			ErrorLog:      r.errorLog,
+			IgnoreList:    r.ignoreList,
+			Connector:     r.connector,
+			RateLimiter:   r.rateLimiter,
+			Budget:        r.budget,
 		})
 	case *requests.BulkHTTPRequest:
 		httpExecuter, err = executer.NewHTTPExecuter(&executer.HTTPOptions{
@@ -59,6 +178,23 @@ func (r *Runner) processTemplateWithList(p progress.IProgress, template *templat
 			Retries:          r.options.Retries,
 			ProxyURL:         r.options.ProxyURL,
 			ProxySocksURL:    r.options.ProxySocksURL,
+			ProxyAuth:        r.options.ProxyAuth,
+			TrustStore:       r.trustStore,
+			TLSProfile:       r.tlsProfile,
+			Resolver:         r.resolver,
+			RetryIPs:         r.options.RetryIPs,
+			ScanAllIPs:       r.options.ScanAllIPs,
+			DedupAliases:     r.options.DedupAliases,
+			Attribution:      r.attribution,
+			UserAgents:       r.userAgents,
+			Cookies:          r.cookies,
+			MaxResponseSize:  r.options.MaxResponseSize,
+			RateLimiter:      r.rateLimiter,
+			Metrics:          r.templateMetrics,
+			Budget:           r.budget,
+			OOB:              r.oob,
+			Secrets:          r.secrets,
+			Cassettes:        r.cassettes,
 			CustomHeaders:    r.options.CustomHeaders,
 			JSON:             r.options.JSON,
 			JSONRequests:     r.options.JSONRequests,
@@ -67,12 +203,30 @@ func (r *Runner) processTemplateWithList(p progress.IProgress, template *templat
 			Colorizer:        &r.colorizer,
 			Decolorizer:      r.decolorizer,
 			StopAtFirstMatch: r.options.StopAtFirstMatch,
+			Scheduler:        r.scheduler,
+			AutoThrottle:     r.autoThrottle,
+			ResponseCache:    r.responseCache,
+			DryRun:           r.options.DryRun,
+			DebugWriter:      r.debugWriter,
+			Format:           r.format,
+			Summary:          r.summary,
+			Report:           r.report,
+			ErrorLog:         r.errorLog,
+			IgnoreList:       r.ignoreList,
+			VerifyMatches:    r.options.VerifyMatches,
+			PayloadSampling:  r.payloadSampling,
+			Screenshotter:    r.screenshotter,
+			Credentials:      r.credentials,
+			Baselines:        r.baselines,
+			VulnDB:           r.vulnDB,
+			ScanContext:      r.scanContext,
+			ContextValues:    r.contextValues[template.ID],
 		})
 	}
 
 	if err != nil {
-		p.Drop(request.(*requests.BulkHTTPRequest).GetRequestCount())
-		gologger.Warningf("Could not create http client: %s\n", err)
+		p.Drop(request.(requestCounter).GetRequestCount())
+		gologger.Warningf("Could not create executer: %s\n", err)
 
 		return false
 	}
@@ -81,25 +235,62 @@ func (r *Runner) processTemplateWithList(p progress.IProgress, template *templat
 
 	var wg sync.WaitGroup
 
-	scanner := bufio.NewScanner(strings.NewReader(r.input))
-	for scanner.Scan() {
-		URL := scanner.Text()
+	for _, URL := range r.templateInputs(template) {
 		wg.Add(1)
 		go func(URL string) {
 			defer wg.Done()
 
-			var result executer.Result
+			// the required templates and capabilities must have matched on the target
+			if !r.scanContext.Satisfied(URL, template.Requires) {
+				p.Drop(request.(requestCounter).GetRequestCount())
+				return
+			}
+
+			result := &executer.Result{}
 
 			if httpExecuter != nil {
-				result = httpExecuter.ExecuteHTTP(p, URL)
+				httpResult := httpExecuter.ExecuteHTTP(p, URL)
+				result = &httpResult
 				globalresult.Or(result.GotResults)
 			}
 
 			if dnsExecuter != nil {
-				result = dnsExecuter.ExecuteDNS(p, URL)
+				dnsResult := dnsExecuter.ExecuteDNS(p, URL)
+				result = &dnsResult
+				globalresult.Or(result.GotResults)
+			}
+
+			if smugglingExecuter != nil {
+				result = smugglingExecuter.ExecuteSmuggling(p, URL)
+				globalresult.Or(result.GotResults)
+			}
+
+			if storageExecuter != nil {
+				result = storageExecuter.ExecuteStorage(p, URL)
+				globalresult.Or(result.GotResults)
+			}
+
+			if takeoverExecuter != nil {
+				result = takeoverExecuter.ExecuteTakeover(p, URL)
+				globalresult.Or(result.GotResults)
+			}
+
+			if auditExecuter != nil {
+				result = auditExecuter.ExecuteAudit(p, URL)
+				globalresult.Or(result.GotResults)
+			}
+
+			if serviceExecuter != nil {
+				result = serviceExecuter.ExecuteService(p, URL)
 				globalresult.Or(result.GotResults)
 			}
 
+			r.templateMetrics.Execution(template.ID, result.GotResults, result.Error != nil)
+
+			if result.GotResults {
+				r.scanContext.Satisfy(URL, append([]string{template.ID}, template.Provides...)...)
+			}
+
 			if result.Error != nil {
 				gologger.Warningf("[%s] Could not execute step: %s\n", r.colorizer.Colorizer.BrightBlue(template.ID), result.Error)
 			}
@@ -172,15 +363,11 @@ func (r *Runner) processWorkflowWithList(p progress.IProgress, workflow *workflo
 }
 
 func (r *Runner) preloadWorkflowTemplates(p progress.IProgress, workflow *workflows.Workflow) (*[]workflowTemplates, error) {
-	var jar *cookiejar.Jar
+	var jar *cookies.Jar
 
+	// the templates of the workflow share a jar when reusing the cookies
 	if workflow.CookieReuse {
-		var err error
-		jar, err = cookiejar.New(nil)
-
-		if err != nil {
-			return nil, err
-		}
+		jar = cookies.NewJar(r.cookies, true)
 	}
 
 	// Single yaml provided
@@ -208,24 +395,59 @@ func (r *Runner) preloadWorkflowTemplates(p progress.IProgress, workflow *workfl
 			if err != nil {
 				return nil, err
 			}
+			r.severityMapping.Apply(t)
 
 			template := &workflows.Template{Progress: p}
 			if len(t.BulkRequestsHTTP) > 0 {
 				template.HTTPOptions = &executer.HTTPOptions{
-					Debug:         r.options.Debug,
-					Writer:        r.output,
-					Template:      t,
-					Timeout:       r.options.Timeout,
-					Retries:       r.options.Retries,
-					ProxyURL:      r.options.ProxyURL,
-					ProxySocksURL: r.options.ProxySocksURL,
-					CustomHeaders: r.options.CustomHeaders,
-					JSON:          r.options.JSON,
-					JSONRequests:  r.options.JSONRequests,
-					CookieJar:     jar,
-					ColoredOutput: !r.options.NoColor,
-					Colorizer:     &r.colorizer,
-					Decolorizer:   r.decolorizer,
+					Debug:           r.options.Debug,
+					Writer:          r.output,
+					Template:        t,
+					Timeout:         r.options.Timeout,
+					Retries:         r.options.Retries,
+					ProxyURL:        r.options.ProxyURL,
+					ProxySocksURL:   r.options.ProxySocksURL,
+					ProxyAuth:       r.options.ProxyAuth,
+					TrustStore:      r.trustStore,
+					TLSProfile:      r.tlsProfile,
+					Resolver:        r.resolver,
+					RetryIPs:        r.options.RetryIPs,
+					ScanAllIPs:      r.options.ScanAllIPs,
+					DedupAliases:    r.options.DedupAliases,
+					Attribution:     r.attribution,
+					UserAgents:      r.userAgents,
+					Cookies:         r.cookies,
+					MaxResponseSize: r.options.MaxResponseSize,
+					RateLimiter:     r.rateLimiter,
+					Metrics:         r.templateMetrics,
+					Budget:          r.budget,
+					OOB:             r.oob,
+					Secrets:         r.secrets,
+					Cassettes:       r.cassettes,
+					CustomHeaders:   r.options.CustomHeaders,
+					JSON:            r.options.JSON,
+					JSONRequests:    r.options.JSONRequests,
+					CookieJar:       jar,
+					ColoredOutput:   !r.options.NoColor,
+					Colorizer:       &r.colorizer,
+					Decolorizer:     r.decolorizer,
+					Scheduler:       r.scheduler,
+					AutoThrottle:    r.autoThrottle,
+					ResponseCache:   r.responseCache,
+					DryRun:          r.options.DryRun,
+					DebugWriter:     r.debugWriter,
+					Format:          r.format,
+					Summary:         r.summary,
+					Report:          r.report,
+					ErrorLog:        r.errorLog,
+					IgnoreList:      r.ignoreList,
+					VerifyMatches:   r.options.VerifyMatches,
+					PayloadSampling: r.payloadSampling,
+					Screenshotter:   r.screenshotter,
+					Credentials:     r.credentials,
+					Baselines:       r.baselines,
+					VulnDB:          r.vulnDB,
+					ScanContext:     r.scanContext,
 				}
 			} else if len(t.RequestsDNS) > 0 {
 				template.DNSOptions = &executer.DNSOptions{
@@ -237,10 +459,18 @@ func (r *Runner) preloadWorkflowTemplates(p progress.IProgress, workflow *workfl
 					ColoredOutput: !r.options.NoColor,
 					Colorizer:     r.colorizer,
 					Decolorizer:   r.decolorizer,
+					Scheduler:     r.scheduler,
+					DryRun:        r.options.DryRun,
+					DebugWriter:   r.debugWriter,
+					Format:        r.format,
+					Summary:       r.summary,
+					Report:        r.report,
+					ErrorLog:      r.errorLog,
+					IgnoreList:    r.ignoreList,
 				}
 			}
 
-			if template.DNSOptions != nil || template.HTTPOptions != nil {
+			if (template.DNSOptions != nil || template.HTTPOptions != nil) && !r.excludedTemplate(t.ID, t.GetPath()) && !r.tooIntrusive(t) {
 				wtlst = append(wtlst, template)
 			}
 		} else {
@@ -274,27 +504,70 @@ func (r *Runner) preloadWorkflowTemplates(p progress.IProgress, workflow *workfl
 				if err != nil {
 					return nil, err
 				}
+				r.severityMapping.Apply(t)
 				template := &workflows.Template{Progress: p}
 				if len(t.BulkRequestsHTTP) > 0 {
 					template.HTTPOptions = &executer.HTTPOptions{
-						Debug:         r.options.Debug,
-						Writer:        r.output,
-						Template:      t,
-						Timeout:       r.options.Timeout,
-						Retries:       r.options.Retries,
-						ProxyURL:      r.options.ProxyURL,
-						ProxySocksURL: r.options.ProxySocksURL,
-						CustomHeaders: r.options.CustomHeaders,
-						CookieJar:     jar,
+						Debug:           r.options.Debug,
+						Writer:          r.output,
+						Template:        t,
+						Timeout:         r.options.Timeout,
+						Retries:         r.options.Retries,
+						ProxyURL:        r.options.ProxyURL,
+						ProxySocksURL:   r.options.ProxySocksURL,
+						ProxyAuth:       r.options.ProxyAuth,
+						TrustStore:      r.trustStore,
+						TLSProfile:      r.tlsProfile,
+						Resolver:        r.resolver,
+						RetryIPs:        r.options.RetryIPs,
+						ScanAllIPs:      r.options.ScanAllIPs,
+						DedupAliases:    r.options.DedupAliases,
+						Attribution:     r.attribution,
+						UserAgents:      r.userAgents,
+						Cookies:         r.cookies,
+						MaxResponseSize: r.options.MaxResponseSize,
+						RateLimiter:     r.rateLimiter,
+						Metrics:         r.templateMetrics,
+						Budget:          r.budget,
+						OOB:             r.oob,
+						Secrets:         r.secrets,
+						Cassettes:       r.cassettes,
+						CustomHeaders:   r.options.CustomHeaders,
+						CookieJar:       jar,
+						Scheduler:       r.scheduler,
+						AutoThrottle:    r.autoThrottle,
+						ResponseCache:   r.responseCache,
+						DryRun:          r.options.DryRun,
+						DebugWriter:     r.debugWriter,
+						Format:          r.format,
+						Summary:         r.summary,
+						Report:          r.report,
+						ErrorLog:        r.errorLog,
+						IgnoreList:      r.ignoreList,
+						VerifyMatches:   r.options.VerifyMatches,
+						PayloadSampling: r.payloadSampling,
+						Screenshotter:   r.screenshotter,
+						Credentials:     r.credentials,
+						Baselines:       r.baselines,
+						VulnDB:          r.vulnDB,
+						ScanContext:     r.scanContext,
 					}
 				} else if len(t.RequestsDNS) > 0 {
 					template.DNSOptions = &executer.DNSOptions{
-						Debug:    r.options.Debug,
-						Template: t,
-						Writer:   r.output,
+						Debug:       r.options.Debug,
+						Template:    t,
+						Writer:      r.output,
+						Scheduler:   r.scheduler,
+						DryRun:      r.options.DryRun,
+						DebugWriter: r.debugWriter,
+						Format:      r.format,
+						Summary:     r.summary,
+						Report:      r.report,
+						ErrorLog:    r.errorLog,
+						IgnoreList:  r.ignoreList,
 					}
 				}
-				if template.DNSOptions != nil || template.HTTPOptions != nil {
+				if (template.DNSOptions != nil || template.HTTPOptions != nil) && !r.excludedTemplate(t.ID, t.GetPath()) && !r.tooIntrusive(t) {
 					wtlst = append(wtlst, template)
 				}
 			}
@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/recommend"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// maxRecommendBodySize is the size of the response bodies read to detect
+// the technologies of the targets
+const maxRecommendBodySize = 1024 * 1024
+
+// recommendationPlan contains the templates recommended for a target
+type recommendationPlan struct {
+	Target          string                      `json:"target"`
+	Technologies    []string                    `json:"technologies"`
+	Recommendations []*recommend.Recommendation `json:"recommendations"`
+}
+
+// recommendTemplates prints the templates relevant to the technologies
+// detected on the targets as a plan, without running them
+func (r *Runner) recommendTemplates() {
+	definitions := r.options.Templates
+	if len(definitions) == 0 && r.templatesConfig != nil && r.templatesConfig.TemplatesDirectory != "" {
+		definitions = []string{r.templatesConfig.TemplatesDirectory}
+	}
+
+	parsedTemplates, _ := r.getParsedTemplatesFor(r.getIncludedTemplates(definitions), r.options.Severity)
+
+	candidates := make([]*recommend.Template, 0, len(parsedTemplates))
+	for _, t := range parsedTemplates {
+		if tp, ok := t.(*templates.Template); ok {
+			candidates = append(candidates, &recommend.Template{ID: tp.ID, Tags: tp.Info.Tags, Path: tp.GetPath()})
+		}
+	}
+
+	if len(candidates) == 0 {
+		gologger.Fatalf("Error, no templates were found.\n")
+	}
+
+	detected := make(map[string][]string)
+	targets := r.targets()
+
+	if r.options.RecommendFrom != "" {
+		file, err := os.Open(r.options.RecommendFrom)
+		if err != nil {
+			gologger.Fatalf("Could not open results file '%s': %s\n", r.options.RecommendFrom, err)
+		}
+
+		detected, err = recommend.FromResults(file)
+		file.Close()
+
+		if err != nil {
+			gologger.Fatalf("Could not read results file '%s': %s\n", r.options.RecommendFrom, err)
+		}
+
+		// the hosts of the results are planned without targets
+		if len(targets) == 0 {
+			for host := range detected {
+				targets = append(targets, host)
+			}
+		}
+	}
+
+	client := r.recommendClient()
+
+	for _, target := range targets {
+		technologies, ok := detected[targetHost(target)]
+		if !ok {
+			technologies = r.detectTechnologies(client, target)
+		}
+
+		plan := &recommendationPlan{
+			Target:          target,
+			Technologies:    technologies,
+			Recommendations: recommend.Recommend(technologies, candidates),
+		}
+
+		r.printRecommendation(plan)
+	}
+}
+
+// recommendClient returns the client requesting the targets to detect
+// their technologies
+func (r *Runner) recommendClient() *http.Client {
+	transport := &http.Transport{
+		// nolint:gosec // the technologies are detected on any target
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: !r.trustStore.Verify(), RootCAs: r.trustStore.Roots()},
+	}
+
+	if r.options.ProxyURL != "" {
+		if proxyURL, err := url.Parse(r.options.ProxyURL); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	return &http.Client{Transport: transport, Timeout: time.Duration(r.options.Timeout) * time.Second}
+}
+
+// detectTechnologies requests a target, returning the technologies
+// detected in its response
+func (r *Runner) detectTechnologies(client *http.Client, target string) []string {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "https://" + target
+	}
+
+	resp, err := client.Get(target)
+	if err != nil {
+		gologger.Warningf("Could not detect the technologies of %s: %s\n", target, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxRecommendBodySize))
+
+	return recommend.Detect(resp, string(body))
+}
+
+// printRecommendation prints the recommended templates of a target and the
+// command running them
+func (r *Runner) printRecommendation(plan *recommendationPlan) {
+	if r.options.JSON {
+		data, err := jsoniter.Marshal(plan)
+		if err != nil {
+			gologger.Warningf("Could not marshal recommendation of %s: %s\n", plan.Target, err)
+			return
+		}
+
+		gologger.Silentf("%s\n", string(data))
+
+		return
+	}
+
+	builder := &strings.Builder{}
+
+	if len(plan.Technologies) == 0 {
+		fmt.Fprintf(builder, "[%s] no technology detected\n", plan.Target)
+		gologger.Silentf("%s", builder.String())
+
+		return
+	}
+
+	fmt.Fprintf(builder, "[%s] detected %s\n", plan.Target, strings.Join(plan.Technologies, ", "))
+
+	seen := make(map[string]struct{})
+	command := []string{"nuclei", "-target", plan.Target}
+
+	for _, recommendation := range plan.Recommendations {
+		fmt.Fprintf(builder, "  %s: %d templates (%s)\n", recommendation.Technology, len(recommendation.Templates), strings.Join(recommendation.Templates, ", "))
+
+		for _, path := range recommendation.Paths {
+			if _, ok := seen[path]; !ok {
+				seen[path] = struct{}{}
+				command = append(command, "-t", path)
+			}
+		}
+	}
+
+	if len(seen) > 0 {
+		fmt.Fprintf(builder, "  plan: %s\n", strings.Join(command, " "))
+	} else {
+		builder.WriteString("  no template relevant to the detected technologies\n")
+	}
+
+	gologger.Silentf("%s", builder.String())
+}
+
+// targetHost returns the host of a target, as keyed in the results
+func targetHost(target string) string {
+	if parsed, err := url.Parse(target); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+
+	return target
+}
@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/screenshot"
+)
+
+// writeReport renders the html report of the findings, printed as pdf
+// when requested
+func (r *Runner) writeReport() {
+	if r.report == nil {
+		return
+	}
+
+	htmlFile := r.options.ReportHTML
+	if htmlFile == "" {
+		// the pdf is printed from a temporary html report
+		file, err := ioutil.TempFile("", "nuclei-report-*.html")
+		if err != nil {
+			gologger.Errorf("Could not create report file: %s\n", err)
+			return
+		}
+		file.Close()
+		defer os.Remove(file.Name())
+
+		htmlFile = file.Name()
+	}
+
+	if err := r.report.WriteHTML(htmlFile, r.summary.Report()); err != nil {
+		gologger.Errorf("Could not write html report '%s': %s\n", htmlFile, err)
+		return
+	}
+
+	if r.options.ReportPDF == "" {
+		return
+	}
+
+	browser, err := screenshot.FindBrowser(r.options.ScreenshotBrowser)
+	if err != nil {
+		gologger.Errorf("Could not write pdf report: %s\n", err)
+		return
+	}
+
+	if err := report.WritePDF(htmlFile, r.options.ReportPDF, browser); err != nil {
+		gologger.Errorf("Could not write pdf report '%s': %s\n", r.options.ReportPDF, err)
+	}
+}
@@ -9,16 +9,53 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/debugwriter"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
 	"github.com/projectdiscovery/nuclei/v2/pkg/atomicboolean"
+	"github.com/projectdiscovery/nuclei/v2/pkg/attribution"
+	"github.com/projectdiscovery/nuclei/v2/pkg/autothrottle"
+	"github.com/projectdiscovery/nuclei/v2/pkg/baseline"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cache"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cassette"
 	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cookies"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/exclude"
+	"github.com/projectdiscovery/nuclei/v2/pkg/executer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/gate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/globalratelimiter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/inputs"
+	"github.com/projectdiscovery/nuclei/v2/pkg/metrics"
+	"github.com/projectdiscovery/nuclei/v2/pkg/monitor"
+	"github.com/projectdiscovery/nuclei/v2/pkg/notify"
+	"github.com/projectdiscovery/nuclei/v2/pkg/oob"
+	"github.com/projectdiscovery/nuclei/v2/pkg/portscan"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/resolver"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scancontext"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/schema"
+	"github.com/projectdiscovery/nuclei/v2/pkg/screenshot"
+	"github.com/projectdiscovery/nuclei/v2/pkg/secrets"
+	"github.com/projectdiscovery/nuclei/v2/pkg/store"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/targets"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tlsprofile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/trust"
+	"github.com/projectdiscovery/nuclei/v2/pkg/useragent"
+	"github.com/projectdiscovery/nuclei/v2/pkg/vulndb"
 	"github.com/projectdiscovery/nuclei/v2/pkg/workflows"
+	"github.com/remeh/sizedwaitgroup"
 )
 
 // Runner is a client for running the enumeration process.
@@ -37,6 +74,88 @@ type Runner struct {
 	// progress tracking
 	progress progress.IProgress
 
+	// scheduler controls delays between requests and scan windows
+	scheduler *scheduler.Scheduler
+	// autoThrottle adapts the request rate to the hosts health
+	autoThrottle *autothrottle.AutoThrottle
+	// responseCache reuses responses of identical requests
+	responseCache *cache.Cache
+	// debugWriter writes the request/response dumps in debug mode
+	debugWriter *debugwriter.Writer
+	// format contains the console results formatting options
+	format executer.FormatOptions
+	// severityMapping overrides the severities of the templates
+	severityMapping *templates.SeverityMapping
+	// summary collects the statistics of the scan
+	summary *summary.Summary
+	// attribution annotates the http requests of the scan
+	attribution *attribution.Attribution
+	// userAgents picks the user agents of the http requests
+	userAgents *useragent.Picker
+	// cookies contains the cookie policy of the http requests
+	cookies *cookies.Options
+	// rateLimiter limits the requests to the inputs of the scan
+	rateLimiter *globalratelimiter.GlobalRateLimiter
+	// templateMetrics collects the statistics of every template
+	templateMetrics *metrics.Metrics
+	// budget caps the requests of the scan
+	budget *budget.Budget
+	// oob collects the out-of-band interactions of the requests
+	oob *oob.Client
+	// exclusions denies the templates excluded from the scans
+	exclusions *exclude.List
+	// ignoreList suppresses accepted findings
+	ignoreList *ignore.List
+	// credentials authenticate to the kubernetes and docker apis
+	credentials *targets.Credentials
+	// screenshotter captures the pages of the http findings
+	screenshotter *screenshot.Screenshotter
+	// discovered contains the urls discovered for each target
+	discovered map[string][]string
+	// payloadSampling bounds the payload permutations sent per template
+	payloadSampling *generators.Sampling
+	// gate decides if the findings should fail the scan
+	gate *gate.Gate
+	// failed reports if findings failed the gate
+	failed bool
+	// baselines contains the soft 404 baselines of the hosts
+	baselines *baseline.Baselines
+	// portScan contains the open ports found by the port scan
+	portScan *portscan.Results
+	// vulnDB correlates the detected versions with known vulnerabilities
+	vulnDB *vulndb.Database
+	// secrets detects the secrets leaked by the http responses
+	secrets *secrets.Scanner
+	// scanContext contains the values published by the templates per target
+	scanContext *scancontext.Context
+	// contextValues contains the published values used by each template
+	contextValues map[string][]string
+	// schedule re-runs the scan in monitoring mode
+	schedule *monitor.Schedule
+	// history contains the findings of the monitoring runs
+	history *monitor.History
+	// alerter posts the changes of the findings of the monitoring runs
+	alerter *monitor.Alerter
+	// notifier posts the findings to the chat webhooks
+	notifier *notify.Notifier
+	// report collects the findings for the html and pdf reports
+	report *report.Report
+	// store persists the runs and their findings
+	store store.ResultStore
+	// trustStore verifies the certificate chains of the targets
+	trustStore *trust.Store
+	// tlsProfile restricts the tls versions and cipher suites of the targets
+	tlsProfile *tlsprofile.Profile
+	// resolver resolves the addresses of the targets
+	resolver *resolver.Resolver
+	// connector opens the connections of the requests not sent by the
+	// http executers, through the proxies and with the tls configuration
+	connector *connector.Connector
+	// errorLog records the failures of the templates on the targets
+	errorLog *errorlog.Log
+	// cassettes records or replays the responses of the templates
+	cassettes *cassette.Library
+
 	// output coloring
 	colorizer   colorizer.NucleiColorizer
 	decolorizer *regexp.Regexp
@@ -45,13 +164,40 @@ type Runner struct {
 // New creates a new client for running enumeration process.
 func New(options *Options) (*Runner, error) {
 	runner := &Runner{
-		options: options,
+		options:     options,
+		rateLimiter: globalratelimiter.New(),
+	}
+
+	if options.NewTemplate != "" {
+		runner.newTemplate()
+		os.Exit(0)
+	}
+
+	if options.OOBServe != "" {
+		runner.serveOOB()
+		os.Exit(0)
+	}
+
+	if options.TemplateSchema {
+		templateSchema, err := schema.Generate()
+		if err != nil {
+			gologger.Fatalf("Could not generate the template schema: %s\n", err)
+		}
+
+		fmt.Println(string(templateSchema))
+		os.Exit(0)
 	}
 
 	if err := runner.updateTemplates(); err != nil {
 		gologger.Labelf("Could not update templates: %s\n", err)
 	}
 
+	if options.UpdateVulnDB {
+		if err := runner.updateVulnDB(); err != nil {
+			gologger.Labelf("Could not update vulnerability database: %s\n", err)
+		}
+	}
+
 	// output coloring
 	useColor := !options.NoColor
 	runner.colorizer = *colorizer.NewNucleiColorizer(aurora.NewAurora(useColor))
@@ -61,18 +207,28 @@ func New(options *Options) (*Runner, error) {
 		runner.decolorizer = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
 	}
 
+	if options.SeverityMapping != "" {
+		mapping, err := templates.ParseSeverityMapping(options.SeverityMapping)
+		if err != nil {
+			gologger.Fatalf("Could not read severity mapping file '%s': %s\n", options.SeverityMapping, err)
+		}
+		runner.severityMapping = mapping
+	}
+
+	exclusions, exclusionsErr := runner.loadExclusions()
+	if exclusionsErr != nil {
+		gologger.Fatalf("Could not read excluded templates file '%s': %s\n", options.DeniedTemplatesFile, exclusionsErr)
+	}
+	runner.exclusions = exclusions
+
 	if options.TemplateList {
 		runner.listAvailableTemplates()
 		os.Exit(0)
 	}
 
-	if (len(options.Templates) == 0 || (options.Targets == "" && !options.Stdin && options.Target == "")) && options.UpdateTemplates {
+	if (len(options.Templates) == 0 || (options.Targets == "" && !options.Stdin && options.Target == "")) && (options.UpdateTemplates || options.UpdateVulnDB) {
 		os.Exit(0)
 	}
-	// Read nucleiignore file if given a templateconfig
-	if runner.templatesConfig != nil {
-		runner.readNucleiIgnoreFile()
-	}
 
 	// If we have stdin, write it to a new file
 	if options.Stdin {
@@ -121,22 +277,22 @@ func New(options *Options) (*Runner, error) {
 
 	dupeCount := 0
 	sb := strings.Builder{}
-	scanner := bufio.NewScanner(input)
 	runner.inputCount = 0
 
-	for scanner.Scan() {
-		url := scanner.Text()
+	addInput := func(url string) {
 		// skip empty lines
 		if url == "" {
-			continue
+			return
 		}
+
+		url = targets.Normalize(url)
 		// deduplication
 		if _, ok := usedInput[url]; !ok {
 			usedInput[url] = struct{}{}
 			runner.inputCount++
 
 			// allocate global rate limiters
-			globalratelimiter.Add(url, options.RateLimit)
+			runner.rateLimiter.Add(url, options.RateLimit)
 
 			sb.WriteString(url)
 			sb.WriteString("\n")
@@ -144,10 +300,126 @@ func New(options *Options) (*Runner, error) {
 			dupeCount++
 		}
 	}
-	input.Close()
+
+	runner.credentials = targets.NewCredentials()
+
+	trustStore, err := trust.New(options.TLSVerify, options.TLSCABundle)
+	if err != nil {
+		gologger.Fatalf("Could not load the trusted certificates: %s\n", err)
+	}
+	runner.trustStore = trustStore
+
+	var ciphers []string
+	if options.TLSCiphers != "" {
+		ciphers = strings.Split(options.TLSCiphers, ",")
+	}
+
+	tlsProfile, err := tlsprofile.New(options.TLSMinVersion, options.TLSMaxVersion, ciphers)
+	if err != nil {
+		gologger.Fatalf("Could not configure tls: %s\n", err)
+	}
+	runner.tlsProfile = tlsProfile
+	// the pins were validated with the options
+	pins, _ := options.pinnedIPs()
+	resolverOptions := &resolver.Options{Pins: pins, CacheSize: options.DNSCacheSize}
+	// the proxies resolve the targets themselves
+	if !options.NoDNSCache && options.ProxyURL == "" && options.ProxySocksURL == "" {
+		resolverOptions.CacheTTL = time.Duration(options.DNSCacheTTL) * time.Second
+	}
+	runner.resolver = resolver.New(resolverOptions)
+
+	runner.connector, err = connector.New(&connector.Options{
+		ProxyURL:      options.ProxyURL,
+		ProxySocksURL: options.ProxySocksURL,
+		ProxyAuth:     options.ProxyAuth,
+		Timeout:       time.Duration(options.Timeout) * time.Second,
+		TrustStore:    trustStore,
+		TLSProfile:    tlsProfile,
+		Resolver:      runner.resolver,
+	})
+	if err != nil {
+		gologger.Fatalf("Could not configure the connections: %s\n", err)
+	}
+
+	if options.Kubeconfig != "" {
+		server, err := runner.credentials.LoadKubeconfig(options.Kubeconfig, options.KubeContext)
+		if err != nil {
+			gologger.Fatalf("Could not load kubeconfig '%s': %s\n", options.Kubeconfig, err)
+		}
+
+		addInput(server)
+	}
+
+	if options.DockerCertPath != "" {
+		if err := runner.credentials.LoadDockerCerts(options.DockerCertPath); err != nil {
+			gologger.Fatalf("Could not load docker certificates from '%s': %s\n", options.DockerCertPath, err)
+		}
+	}
+
+	if input != nil {
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			addInput(scanner.Text())
+		}
+		input.Close()
+	}
+
+	if options.OpenAPI != "" {
+		endpoints, err := inputs.OpenAPI(options.OpenAPI, options.OpenAPIBase)
+		if err != nil {
+			gologger.Fatalf("Could not import OpenAPI document '%s': %s\n", options.OpenAPI, err)
+		}
+
+		for _, endpoint := range endpoints {
+			gologger.Verbosef("Imported %s %s\n", "openapi", endpoint.Method, endpoint.URL)
+			addInput(endpoint.URL)
+		}
+	}
+
+	if options.Postman != "" {
+		endpoints, err := inputs.Postman(options.Postman, options.PostmanEnvironment)
+		if err != nil {
+			gologger.Fatalf("Could not import Postman collection '%s': %s\n", options.Postman, err)
+		}
+
+		for _, endpoint := range endpoints {
+			gologger.Verbosef("Imported %s %s\n", "postman", endpoint.Method, endpoint.URL)
+			addInput(endpoint.URL)
+		}
+
+		// only the headers shared by all the requests, like the
+		// collection authentication, can be sent to every target
+		for name, value := range inputs.CommonHeaders(endpoints) {
+			options.CustomHeaders = append(options.CustomHeaders, name+": "+value)
+		}
+	}
+
+	if options.TopPorts > 0 || options.Ports != "" {
+		for _, url := range runner.scanPorts(strings.Split(sb.String(), "\n")) {
+			addInput(url)
+		}
+	}
+
+	if options.TLSNames != "" {
+		for _, url := range runner.harvestCertificateNames(strings.Split(sb.String(), "\n")) {
+			addInput(url)
+		}
+	}
 
 	runner.input = sb.String()
 
+	if options.Crawl {
+		runner.discoverURLs()
+	}
+
+	if options.SeedPaths {
+		runner.seedPaths()
+	}
+
+	if options.Archive != "" {
+		runner.archivedURLs()
+	}
+
 	if dupeCount > 0 {
 		gologger.Labelf("Supplied input was automatically deduplicated (%d removed).", dupeCount)
 	}
@@ -161,46 +433,252 @@ func New(options *Options) (*Runner, error) {
 		runner.output = output
 	}
 
-	// Creates the progress tracking object
-	runner.progress = progress.NewProgress(runner.colorizer.Colorizer, options.EnableProgressBar)
+	runner.scheduler, err = scheduler.New(options.Delay, options.RandomDelay, options.ScanWindow)
+	if err != nil {
+		gologger.Fatalf("Could not create scheduler: %s\n", err)
+	}
+
+	if options.AutoThrottle {
+		runner.autoThrottle = autothrottle.New(options.AutoThrottleMin, options.AutoThrottleMax)
+	}
+
+	if options.ResponseCache {
+		runner.responseCache, err = cache.New(time.Duration(options.ResponseCacheTTL)*time.Second, options.ResponseCacheSize, options.ResponseCacheDir)
+		if err != nil {
+			gologger.Fatalf("Could not create response cache: %s\n", err)
+		}
+	}
+
+	if options.Debug {
+		runner.debugWriter, err = debugwriter.New(options.DebugDirectory)
+		if err != nil {
+			gologger.Fatalf("Could not create debug directory '%s': %s\n", options.DebugDirectory, err)
+		}
+	}
+
+	runner.format = executer.FormatOptions{Verbosity: options.verbosity(), Compact: options.Compact}
+	// the labels were validated with the options
+	runner.format.Labels, _ = options.labels()
+
+	runner.summary = summary.New()
+	runner.templateMetrics = metrics.New(options.SuspiciousMatchRate)
+	runner.budget = budget.New(options.MaxRequestsTotal, options.MaxRequestsTemplate)
+
+	runner.errorLog, err = errorlog.New(options.ErrorLog, options.JSON)
+	if err != nil {
+		gologger.Fatalf("Could not create error log file '%s': %s\n", options.ErrorLog, err)
+	}
+
+	if options.Record != "" {
+		runner.cassettes, err = cassette.New(options.Record, true)
+	} else if options.Replay != "" {
+		runner.cassettes, err = cassette.New(options.Replay, false)
+	}
+
+	if err != nil {
+		gologger.Fatalf("Could not open cassettes directory: %s\n", err)
+	}
+
+	if options.IgnoreFindings != "" {
+		runner.ignoreList, err = ignore.Load(options.IgnoreFindings)
+		if err != nil {
+			gologger.Fatalf("Could not read findings ignore file '%s': %s\n", options.IgnoreFindings, err)
+		}
+
+		for _, rule := range runner.ignoreList.Expired() {
+			gologger.Warningf("Ignore rule for %s expired on %s, its findings are reported again", rule.Template, rule.Expires)
+		}
+	}
+
+	runner.userAgents, err = useragent.New(&useragent.Options{
+		Strategy:  options.UserAgentStrategy,
+		UserAgent: options.UserAgent,
+		PoolFile:  options.UserAgentPool,
+	})
+	if err != nil {
+		gologger.Fatalf("Could not configure user agents: %s\n", err)
+	}
+
+	runner.cookies = &cookies.Options{PerHost: options.CookiePerHost}
+	if options.CookieFile != "" {
+		runner.cookies.Cookies, err = cookies.ParseFile(options.CookieFile)
+		if err != nil {
+			gologger.Fatalf("Could not read cookies file '%s': %s\n", options.CookieFile, err)
+		}
+	}
+
+	runner.oob, err = runner.newOOBClient()
+	if err != nil {
+		return nil, err
+	}
+
+	runner.attribution = attribution.New(&attribution.Options{
+		Headers: options.Annotate,
+		ScanID:  options.ScanID,
+		Contact: options.AnnotateContact,
+		Param:   options.AnnotateParam,
+	})
+	if runner.attribution != nil {
+		gologger.Infof("Annotating the requests with scan id %s\n", runner.attribution.ScanID())
+	}
+
+	if options.PayloadLimit > 0 || options.PayloadSample {
+		runner.payloadSampling = &generators.Sampling{Limit: options.PayloadLimit, Random: options.PayloadSample, Seed: options.PayloadSeed}
+
+		if options.PayloadSample && options.PayloadSeed == 0 {
+			runner.payloadSampling.Seed = time.Now().UnixNano()
+			gologger.Infof("Using payload sampling seed %d\n", runner.payloadSampling.Seed)
+		}
+	}
+
+	if options.Screenshots != "" {
+		// the browser can't chain both proxies, the http one is preferred
+		proxy := options.ProxyURL
+		if proxy == "" {
+			proxy = options.ProxySocksURL
+		}
+
+		runner.screenshotter, err = screenshot.New(options.Screenshots, options.ScreenshotBrowser, proxy)
+		if err != nil {
+			gologger.Warningf("Screenshots are disabled: %s\n", err)
+		}
+	}
+
+	if options.Correlate {
+		runner.vulnDB = runner.loadVulnDB()
+	}
+
+	if options.Secrets || options.SecretsRules != "" {
+		runner.secrets, err = secrets.New(options.SecretsRules)
+		if err != nil {
+			return nil, err
+		}
+		gologger.Infof("Detecting secrets in the http responses with %d rules\n", len(runner.secrets.Rules()))
+	}
+
+	runner.scanContext = scancontext.New()
+
+	// the baselines are only fetched for the templates with baseline matchers,
+	// through the connections of the other requests
+	runner.baselines = baseline.New(&baseline.Options{
+		Timeout:   time.Duration(options.Timeout) * time.Second,
+		Transport: runner.connector.Transport(),
+		OnRequest: runner.baselineRequest,
+	})
+
+	if options.FailOn != "" {
+		runner.gate, err = gate.New(options.FailOn, options.FailOnAllowlist)
+		if err != nil {
+			gologger.Fatalf("Could not create scan gate: %s\n", err)
+		}
+	}
+
+	if options.Store != "" {
+		runner.store, err = store.Open(options.Store)
+		if err != nil {
+			gologger.Fatalf("Could not open result store: %s\n", err)
+		}
+	}
+
+	if options.ReportHTML != "" || options.ReportPDF != "" {
+		runner.report = report.New(runner.format.Labels)
+	}
+
+	runner.notifier, err = notify.New(&notify.Options{
+		Webhooks: options.Notify,
+		Severity: options.NotifySeverity,
+		Timeout:  time.Duration(options.Timeout) * time.Second,
+	})
+	if err != nil {
+		gologger.Fatalf("Could not create notifications: %s\n", err)
+	}
+
+	if options.Monitor != "" {
+		runner.initMonitor()
+	}
+
+	// Creates the progress tracking object, once per process as the
+	// monitoring runs would draw it again
+	runner.progress = progress.NewProgress(runner.colorizer.Colorizer, options.EnableProgressBar && options.Monitor == "")
 
 	return runner, nil
 }
 
+// baselineRequest takes the baseline request of a template to an url from
+// the budget and the rate limit of the scan. The fields are read at every
+// call as they are replaced between the passes of the monitor.
+func (r *Runner) baselineRequest(templateID, reqURL string) bool {
+	if !r.budget.Take(templateID) {
+		return false
+	}
+
+	r.rateLimiter.Take(reqURL)
+	r.summary.Request()
+
+	return true
+}
+
 // Close releases all the resources and cleans up
 func (r *Runner) Close() {
 	if r.output != nil {
 		r.output.Close()
 	}
+	r.debugWriter.Close()
+	if err := r.errorLog.Close(); err != nil {
+		gologger.Errorf("Could not write error log file '%s': %s\n", r.options.ErrorLog, err)
+	}
+	if err := r.cassettes.Close(); err != nil {
+		gologger.Errorf("Could not write cassettes to '%s': %s\n", r.options.Record, err)
+	}
+	if r.store != nil {
+		r.store.Close()
+	}
+	r.oob.Close()
 	os.Remove(r.tempFile)
 }
 
 // RunEnumeration sets up the input layer for giving input nuclei.
 // binary and runs the actual enumeration
 func (r *Runner) RunEnumeration() {
-	// resolves input templates definitions and any optional exclusion
-	includedTemplates := r.getTemplatesFor(r.options.Templates)
-	excludedTemplates := r.getTemplatesFor(r.options.ExcludedTemplates)
-	// defaults to all templates
-	allTemplates := includedTemplates
-
-	if len(excludedTemplates) > 0 {
-		excludedMap := make(map[string]struct{}, len(excludedTemplates))
-		for _, excl := range excludedTemplates {
-			excludedMap[excl] = struct{}{}
-		}
-		// rebuild list with only non-excluded templates
-		allTemplates = []string{}
-
-		for _, incl := range includedTemplates {
-			if _, found := excludedMap[incl]; !found {
-				allTemplates = append(allTemplates, incl)
-			} else {
-				gologger.Warningf("Excluding '%s'", incl)
-			}
+	if r.options.Test {
+		r.runTests()
+		return
+	}
+
+	if r.options.Recommend || r.options.RecommendFrom != "" {
+		r.recommendTemplates()
+		return
+	}
+
+	if r.schedule != nil {
+		r.runMonitor()
+		return
+	}
+
+	runID := r.beginStoredRun(time.Now())
+
+	if !r.runTemplates() {
+		if r.output != nil {
+			r.output.Close()
+			os.Remove(r.options.Output)
 		}
+
+		gologger.Infof("No results found. Happy hacking!")
 	}
 
+	r.writeSummary()
+	r.writeReport()
+	r.endStoredRun(runID)
+	r.notifyFindings(r.summary.Records())
+	r.checkGate()
+}
+
+// runTemplates parses the templates and runs them on the inputs,
+// returning true if there were results
+func (r *Runner) runTemplates() bool {
+	// resolves input templates definitions and any optional exclusion
+	allTemplates := r.getIncludedTemplates(r.options.Templates)
+
 	// pre-parse all the templates, apply filters
 	availableTemplates, workflowCount := r.getParsedTemplatesFor(allTemplates, r.options.Severity)
 	templateCount := len(availableTemplates)
@@ -211,6 +689,25 @@ func (r *Runner) RunEnumeration() {
 		gologger.Fatalf("Error, no templates were found.\n")
 	}
 
+	orderTemplates(availableTemplates, r.options.TemplateOrder)
+
+	// templates modified while scanning are re-run as soon as they change
+	var watcher *templateWatcher
+	if r.options.WatchTemplates {
+		watcher = r.newTemplateWatcher(allTemplates)
+	}
+
+	// the templates wait for the templates they require and for the
+	// publishers of the values they use
+	dependencies := r.templateDependencies(availableTemplates)
+
+	// align the results on the longest template id
+	for _, t := range availableTemplates {
+		if tp, ok := t.(*templates.Template); ok && len(tp.ID) > r.format.IDWidth {
+			r.format.IDWidth = len(tp.ID)
+		}
+	}
+
 	gologger.Infof("Using %s rules (%s templates, %s workflows)",
 		r.colorizer.Colorizer.Bold(templateCount).String(),
 		r.colorizer.Colorizer.Bold(templateCount-workflowCount).String(),
@@ -222,7 +719,7 @@ func (r *Runner) RunEnumeration() {
 	for _, t := range availableTemplates {
 		switch av := t.(type) {
 		case *templates.Template:
-			totalRequests += (av.GetHTTPRequestCount() + av.GetDNSRequestCount()) * r.inputCount
+			totalRequests += av.GetRequestCount() * int64(len(r.templateInputs(av)))
 		case *workflows.Workflow:
 			// workflows will dynamically adjust the totals while running, as
 			// it can't be know in advance which requests will be called
@@ -230,7 +727,8 @@ func (r *Runner) RunEnumeration() {
 	}
 
 	var (
-		wgtemplates sync.WaitGroup
+		wgtemplates = sizedwaitgroup.New(r.options.TemplateConcurrency)
+		waiting     sync.WaitGroup
 		results     atomicboolean.AtomBool
 	)
 
@@ -241,34 +739,100 @@ func (r *Runner) RunEnumeration() {
 		p := r.progress
 		p.InitProgressbar(r.inputCount, templateCount, totalRequests)
 
-		for _, t := range availableTemplates {
-			wgtemplates.Add(1)
-			go func(template interface{}) {
+		stopWatching := make(chan struct{})
+		var watching sync.WaitGroup
+		if watcher != nil {
+			watching.Add(1)
+
+			go func() {
+				defer watching.Done()
+
+				r.watchTemplates(watcher, &results, stopWatching)
+			}()
+		}
+
+		done := make([]chan struct{}, len(availableTemplates))
+		for i := range done {
+			done[i] = make(chan struct{})
+		}
+
+		for i, t := range availableTemplates {
+			run := func(i int, template interface{}) {
 				defer wgtemplates.Done()
-				switch tt := template.(type) {
-				case *templates.Template:
-					for _, request := range tt.RequestsDNS {
-						results.Or(r.processTemplateWithList(p, tt, request))
-					}
-					for _, request := range tt.BulkRequestsHTTP {
-						results.Or(r.processTemplateWithList(p, tt, request))
-					}
-				case *workflows.Workflow:
-					results.Or(r.processWorkflowWithList(p, template.(*workflows.Workflow)))
+				defer close(done[i])
+
+				results.Or(r.runTemplate(p, template))
+			}
+
+			if len(dependencies[i]) == 0 {
+				wgtemplates.Add()
+				go run(i, t)
+
+				continue
+			}
+
+			waiting.Add(1)
+
+			go func(i int, template interface{}) {
+				defer waiting.Done()
+
+				for _, dependency := range dependencies[i] {
+					<-done[dependency]
 				}
-			}(t)
+
+				wgtemplates.Add()
+				run(i, template)
+			}(i, t)
 		}
 
+		waiting.Wait()
 		wgtemplates.Wait()
 		p.Wait()
-	}
 
-	if !results.Get() {
-		if r.output != nil {
-			r.output.Close()
-			os.Remove(r.options.Output)
+		if watcher != nil {
+			waitWatchedTemplates(stopWatching, &watching)
 		}
+	}
 
-		gologger.Infof("No results found. Happy hacking!")
+	return results.Get()
+}
+
+// runTemplate runs all the requests of a template or a workflow on the
+// inputs, returning true if there were results
+func (r *Runner) runTemplate(p progress.IProgress, template interface{}) bool {
+	var results atomicboolean.AtomBool
+
+	switch tt := template.(type) {
+	case *templates.Template:
+		for _, request := range tt.RequestsDNS {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.BulkRequestsHTTP {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.RequestsSmuggling {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.RequestsStorage {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.RequestsTakeover {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.RequestsAudit {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+		for _, request := range tt.RequestsService {
+			results.Or(r.processTemplateWithList(p, tt, request))
+		}
+	case *workflows.Workflow:
+		results.Or(r.processWorkflowWithList(p, tt))
 	}
+
+	return results.Get()
+}
+
+// Failed returns true if the findings of the scan failed the gate
+func (r *Runner) Failed() bool {
+	return r.failed
 }
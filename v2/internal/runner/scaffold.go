@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// newTemplate scaffolds a template skeleton for a protocol into the
+// current directory, asking for the information not given by the flags
+// when run from a terminal
+func (r *Runner) newTemplate() {
+	options := &templates.ScaffoldOptions{
+		Protocol: r.options.NewTemplate,
+		ID:       r.options.NewTemplateID,
+		Name:     r.options.NewTemplateName,
+		Author:   r.options.NewTemplateAuthor,
+		Severity: r.options.NewTemplateSeverity,
+	}
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		reader := bufio.NewReader(os.Stdin)
+
+		options.ID = prompt(reader, "Template id", options.ID)
+		options.Name = prompt(reader, "Template name", options.Name)
+		options.Author = prompt(reader, "Author", options.Author)
+		options.Severity = prompt(reader, "Severity (info, low, medium, high, critical)", options.Severity)
+		if options.Protocol != "workflow" {
+			options.Tags = prompt(reader, "Tags (comma separated)", options.Tags)
+		}
+	}
+
+	data, err := templates.Scaffold(options)
+	if err != nil {
+		gologger.Fatalf("Could not create template: %s\n", err)
+	}
+
+	path := options.ID + ".yaml"
+	if _, err := os.Stat(path); err == nil {
+		gologger.Fatalf("Could not create template: %s already exists\n", path)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		gologger.Fatalf("Could not create template: %s\n", err)
+	}
+
+	gologger.Infof("Created %s template %s, complete its TODO stubs", options.Protocol, path)
+}
+
+// prompt asks for a value on the terminal, keeping the current one if
+// nothing is entered
+func prompt(reader *bufio.Reader, label, current string) string {
+	if current != "" {
+		fmt.Fprintf(os.Stderr, "%s [%s]: ", label, current)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: ", label)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return current
+	}
+
+	if line = strings.TrimSpace(line); line != "" {
+		return line
+	}
+
+	return current
+}
@@ -0,0 +1,17 @@
+package runner
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyInterrupt relays the interrupt and termination signals to a channel
+// until the returned function is called, so that the runner is closed and
+// its outputs flushed instead of the process being stopped.
+func notifyInterrupt() (signals <-chan os.Signal, stop func()) {
+	relayed := make(chan os.Signal, 1)
+	signal.Notify(relayed, os.Interrupt, syscall.SIGTERM)
+
+	return relayed, func() { signal.Stop(relayed) }
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"bufio"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// beginStoredRun records the start of a run and its targets in the
+// result store, returning the id of the run
+func (r *Runner) beginStoredRun(started time.Time) int64 {
+	if r.store == nil {
+		return 0
+	}
+
+	runID, err := r.store.BeginRun(started, r.format.Labels)
+	if err != nil {
+		gologger.Errorf("Could not store run: %s\n", err)
+		return 0
+	}
+
+	var targets []string
+
+	scanner := bufio.NewScanner(strings.NewReader(r.input))
+	for scanner.Scan() {
+		if target := strings.TrimSpace(scanner.Text()); target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	if err := r.store.AddTargets(runID, targets); err != nil {
+		gologger.Errorf("Could not store run targets: %s\n", err)
+	}
+
+	return runID
+}
+
+// endStoredRun records the findings and statistics of a run in the
+// result store
+func (r *Runner) endStoredRun(runID int64) {
+	if r.store == nil || runID == 0 {
+		return
+	}
+
+	if err := r.store.AddFindings(runID, r.summary.Records()); err != nil {
+		gologger.Errorf("Could not store run findings: %s\n", err)
+	}
+
+	if err := r.store.EndRun(runID, r.summary.Report()); err != nil {
+		gologger.Errorf("Could not store run: %s\n", err)
+	}
+}
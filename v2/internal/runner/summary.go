@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/metrics"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// severityOrder is the order in which severities are printed in the summary
+var severityOrder = []string{"critical", "high", "medium", "low", "info", "unknown"}
+
+// writeSummary prints the end of scan summary and optionally writes it as json
+func (r *Runner) writeSummary() {
+	report := r.summary.Report()
+	report.ErrorKinds = r.errorLog.Counts()
+	for _, event := range r.budget.Events() {
+		report.Events = append(report.Events, event.String())
+	}
+
+	gologger.Infof("Scan completed in %s: %d requests sent, %d findings, %d errors",
+		report.Duration, report.Requests, report.Findings, report.Errors)
+
+	// the targets which couldn't be tested, by kind of failure
+	kinds := make([]string, 0, len(report.ErrorKinds))
+	for kind := range report.ErrorKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		gologger.Infof("  %s errors: %d", kind, report.ErrorKinds[kind])
+	}
+
+	// where the time of the requests went
+	for _, phase := range summary.Phases {
+		if total, ok := report.Phases[phase]; ok {
+			gologger.Infof("  time in %s: %s", phase, total)
+		}
+	}
+
+	for _, event := range report.Events {
+		gologger.Warningf("  %s\n", event)
+	}
+
+	for _, severity := range severityOrder {
+		if count, ok := report.Severities[severity]; ok {
+			gologger.Infof("  %s: %d", r.colorizer.GetColorizedSeverity(severity), count)
+		}
+	}
+
+	hosts := make([]string, 0, len(report.Hosts))
+	for host := range report.Hosts {
+		hosts = append(hosts, host)
+	}
+
+	// most affected hosts first
+	sort.Slice(hosts, func(i, j int) bool {
+		if report.Hosts[hosts[i]] != report.Hosts[hosts[j]] {
+			return report.Hosts[hosts[i]] > report.Hosts[hosts[j]]
+		}
+
+		return hosts[i] < hosts[j]
+	})
+
+	for _, host := range hosts {
+		gologger.Infof("  %s: %d findings", host, report.Hosts[host])
+	}
+
+	// the findings grouped with identical ones found on other aliases
+	records := r.summary.Records()
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Matched < records[j].Matched
+	})
+
+	for _, record := range records {
+		if len(record.Aliases) > 0 {
+			gologger.Infof("  %s on %s also found on %s", record.Template, record.Matched, strings.Join(record.Aliases, ", "))
+		}
+	}
+
+	if r.options.SummaryJSON != "" {
+		if err := report.WriteJSON(r.options.SummaryJSON); err != nil {
+			gologger.Errorf("Could not write summary file '%s': %s\n", r.options.SummaryJSON, err)
+		}
+	}
+
+	r.writeTemplateMetrics()
+}
+
+// writeTemplateMetrics warns about the templates matching most of the
+// targets and optionally writes the statistics of every template as json
+func (r *Runner) writeTemplateMetrics() {
+	templates := r.templateMetrics.Templates()
+
+	for _, template := range templates {
+		if template.Suspicious {
+			gologger.Warningf("[%s] Matched %.0f%% of %d targets, its matchers are likely broken\n",
+				r.colorizer.Colorizer.BrightBlue(template.Template), template.MatchRate*100, template.Executions)
+		}
+	}
+
+	if r.options.TemplateMetrics != "" {
+		if err := metrics.WriteJSON(r.options.TemplateMetrics, templates); err != nil {
+			gologger.Errorf("Could not write template metrics file '%s': %s\n", r.options.TemplateMetrics, err)
+		}
+	}
+}
+
+// notifyFindings posts the findings above the notification threshold
+// to the chat webhooks
+func (r *Runner) notifyFindings(findings []*summary.Record) {
+	if err := r.notifier.Notify(findings); err != nil {
+		gologger.Errorf("%s\n", err)
+	}
+}
+
+// checkGate marks the scan as failed if any finding not allowlisted fails the gate
+func (r *Runner) checkGate() {
+	if r.gate == nil {
+		return
+	}
+
+	var failing int
+
+	for hash, severity := range r.summary.Findings() {
+		if r.gate.Fails(hash, severity) {
+			failing++
+		}
+	}
+
+	if failing > 0 {
+		r.failed = true
+		gologger.Errorf("%d findings matched the fail condition '%s'\n", failing, r.options.FailOn)
+	}
+}
@@ -0,0 +1,131 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/workflows"
+)
+
+// templateListEntry contains the metadata of a listed template
+type templateListEntry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Severity string `json:"severity,omitempty"`
+	Tags     string `json:"tags,omitempty"`
+	Author   string `json:"author"`
+	Protocol string `json:"protocol"`
+	Path     string `json:"path"`
+}
+
+// listAvailableTemplates prints the templates selected by the template
+// and severity filters, defaulting to the installed templates directory.
+func (r *Runner) listAvailableTemplates() {
+	definitions := r.options.Templates
+	if len(definitions) == 0 {
+		if r.templatesConfig == nil || r.templatesConfig.TemplatesDirectory == "" {
+			gologger.Errorf("No templates provided and no templates directory found\n")
+			return
+		}
+
+		definitions = []string{r.templatesConfig.TemplatesDirectory}
+	}
+
+	parsedTemplates, _ := r.getParsedTemplatesFor(r.getIncludedTemplates(definitions), r.options.Severity)
+
+	entries := make([]templateListEntry, 0, len(parsedTemplates))
+
+	for _, t := range parsedTemplates {
+		switch tp := t.(type) {
+		case *templates.Template:
+			entries = append(entries, templateListEntry{
+				ID:       tp.ID,
+				Name:     tp.Info.Name,
+				Severity: tp.Info.Severity,
+				Tags:     tp.Info.Tags,
+				Author:   tp.Info.Author,
+				Protocol: templateProtocol(tp),
+				Path:     tp.GetPath(),
+			})
+		case *workflows.Workflow:
+			entries = append(entries, templateListEntry{
+				ID:       tp.ID,
+				Name:     tp.Info.Name,
+				Severity: tp.Info.Severity,
+				Tags:     tp.Info.Tags,
+				Author:   tp.Info.Author,
+				Protocol: "workflow",
+				Path:     tp.GetPath(),
+			})
+		}
+	}
+
+	if r.options.JSON {
+		for i := range entries {
+			data, err := jsoniter.Marshal(entries[i])
+			if err != nil {
+				gologger.Warningf("Could not marshal template %s: %s\n", entries[i].ID, err)
+				continue
+			}
+
+			gologger.Silentf("%s\n", string(data))
+		}
+
+		return
+	}
+
+	builder := &strings.Builder{}
+	// colors would break the column alignment, so the table is kept plain
+	writer := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(writer, "ID\tSEVERITY\tPROTOCOL\tAUTHOR\tTAGS\n")
+
+	for i := range entries {
+		entry := &entries[i]
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", entry.ID, entry.Severity, entry.Protocol, entry.Author, entry.Tags)
+	}
+
+	writer.Flush()
+
+	gologger.Silentf("%s", builder.String())
+	gologger.Infof("Listed %d templates", len(entries))
+}
+
+// templateProtocol returns the protocols used by the requests of a template
+func templateProtocol(template *templates.Template) string {
+	var protocols []string
+
+	if len(template.BulkRequestsHTTP) > 0 {
+		protocols = append(protocols, "http")
+	}
+
+	if len(template.RequestsDNS) > 0 {
+		protocols = append(protocols, "dns")
+	}
+
+	if len(template.RequestsSmuggling) > 0 {
+		protocols = append(protocols, "smuggling")
+	}
+
+	if len(template.RequestsStorage) > 0 {
+		protocols = append(protocols, "storage")
+	}
+
+	if len(template.RequestsTakeover) > 0 {
+		protocols = append(protocols, "takeover")
+	}
+
+	if len(template.RequestsAudit) > 0 {
+		protocols = append(protocols, "audit")
+	}
+
+	if len(template.RequestsService) > 0 {
+		protocols = append(protocols, "service")
+	}
+
+	return strings.Join(protocols, ",")
+}
@@ -60,7 +60,7 @@ func (r *Runner) getTemplatesFor(definitions []string) []string {
 			}
 
 			for _, match := range matches {
-				if !r.checkIfInNucleiIgnore(match) {
+				if !r.exclusions.Path(match) {
 					processed[match] = true
 
 					allTemplates = append(allTemplates, match)
@@ -92,7 +92,7 @@ func (r *Runner) getTemplatesFor(definitions []string) []string {
 					absPath,
 					func(path string, d *godirwalk.Dirent) error {
 						if !d.IsDir() && strings.HasSuffix(path, ".yaml") {
-							if !r.checkIfInNucleiIgnore(path) && isNewPath(path, processed) {
+							if !r.exclusions.Path(path) && isNewPath(path, processed) {
 								matches = append(matches, path)
 								processed[path] = true
 							}
@@ -121,6 +121,29 @@ func (r *Runner) getTemplatesFor(definitions []string) []string {
 	return allTemplates
 }
 
+// getIncludedTemplates resolves the input template definitions removing the excluded ones.
+func (r *Runner) getIncludedTemplates(definitions []string) []string {
+	includedTemplates := r.getTemplatesFor(definitions)
+	excludedTemplates := r.getTemplatesFor(r.options.ExcludedTemplates)
+
+	excludedMap := make(map[string]struct{}, len(excludedTemplates))
+	for _, excl := range excludedTemplates {
+		excludedMap[excl] = struct{}{}
+	}
+	// rebuild list with only non-excluded templates
+	allTemplates := []string{}
+
+	for _, incl := range includedTemplates {
+		if _, found := excludedMap[incl]; !found && !r.exclusions.Path(incl) {
+			allTemplates = append(allTemplates, incl)
+		} else {
+			gologger.Warningf("Excluding '%s'", incl)
+		}
+	}
+
+	return allTemplates
+}
+
 // getParsedTemplatesFor parse the specified templates and returns a slice of the parsable ones, optionally filtered
 // by severity, along with a flag indicating if workflows are present.
 func (r *Runner) getParsedTemplatesFor(templatePaths []string, severities string) (parsedTemplates []interface{}, workflowCount int) {
@@ -135,17 +158,25 @@ func (r *Runner) getParsedTemplatesFor(templatePaths []string, severities string
 		t, err := r.parseTemplateFile(match)
 		switch tp := t.(type) {
 		case *templates.Template:
+			if r.excludedTemplate(tp.ID, match) || r.tooIntrusive(tp) {
+				continue
+			}
+
 			// only include if severity matches or no severity filtering
 			sev := strings.ToLower(tp.Info.Severity)
 			if !filterBySeverity || hasMatchingSeverity(sev, allSeverities) {
 				parsedTemplates = append(parsedTemplates, tp)
-				gologger.Infof("%s\n", r.templateLogMsg(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity))
+				r.logLoadedTemplate(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity)
 			} else {
 				gologger.Warningf("Excluding template %s due to severity filter (%s not in [%s])", tp.ID, sev, severities)
 			}
 		case *workflows.Workflow:
+			if r.excludedTemplate(tp.ID, match) {
+				continue
+			}
+
 			parsedTemplates = append(parsedTemplates, tp)
-			gologger.Infof("%s\n", r.templateLogMsg(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity))
+			r.logLoadedTemplate(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity)
 			workflowCount++
 		default:
 			gologger.Errorf("Could not parse file '%s': %s\n", match, err)
@@ -159,12 +190,14 @@ func (r *Runner) parseTemplateFile(file string) (interface{}, error) {
 	// check if it's a template
 	template, errTemplate := templates.Parse(file)
 	if errTemplate == nil {
+		r.severityMapping.Apply(template)
 		return template, nil
 	}
 
 	// check if it's a workflow
 	workflow, errWorkflow := workflows.Parse(file)
 	if errWorkflow == nil {
+		workflow.Info.Severity = r.severityMapping.Severity(workflow.ID, workflow.Info.Severity)
 		return workflow, nil
 	}
 
@@ -193,53 +226,14 @@ func (r *Runner) templateLogMsg(id, name, author, severity string) string {
 	return message
 }
 
-func (r *Runner) logAvailableTemplate(tplPath string) {
-	t, err := r.parseTemplateFile(tplPath)
-	if t != nil {
-		switch tp := t.(type) {
-		case *templates.Template:
-			gologger.Silentf("%s\n", r.templateLogMsg(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity))
-		case *workflows.Workflow:
-			gologger.Silentf("%s\n", r.templateLogMsg(tp.ID, tp.Info.Name, tp.Info.Author, tp.Info.Severity))
-		default:
-			gologger.Errorf("Could not parse file '%s': %s\n", tplPath, err)
-		}
-	}
-}
-
-// ListAvailableTemplates prints available templates to stdout
-func (r *Runner) listAvailableTemplates() {
-	if r.templatesConfig == nil {
+// logLoadedTemplate logs a template selected for the scan, the
+// template list mode printing its own listing instead.
+func (r *Runner) logLoadedTemplate(id, name, author, severity string) {
+	if r.options.TemplateList {
 		return
 	}
 
-	if _, err := os.Stat(r.templatesConfig.TemplatesDirectory); os.IsNotExist(err) {
-		gologger.Errorf("%s does not exists", r.templatesConfig.TemplatesDirectory)
-		return
-	}
-
-	gologger.Silentf(
-		"\nListing available v.%s nuclei templates for %s",
-		r.templatesConfig.CurrentVersion,
-		r.templatesConfig.TemplatesDirectory,
-	)
-	err := directoryWalker(
-		r.templatesConfig.TemplatesDirectory,
-		func(path string, d *godirwalk.Dirent) error {
-			if d.IsDir() && path != r.templatesConfig.TemplatesDirectory {
-				gologger.Silentf("\n%s:\n\n", r.colorizer.Colorizer.Bold(r.colorizer.Colorizer.BgBrightBlue(d.Name())).String())
-			} else if strings.HasSuffix(path, ".yaml") {
-				r.logAvailableTemplate(path)
-			}
-
-			return nil
-		},
-	)
-
-	// directory couldn't be walked
-	if err != nil {
-		gologger.Labelf("Could not find templates in directory '%s': %s\n", r.templatesConfig.TemplatesDirectory, err)
-	}
+	gologger.Infof("%s\n", r.templateLogMsg(id, name, author, severity))
 }
 
 func (r *Runner) resolvePathIfRelative(filePath string) (string, error) {
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scancontext"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/targets"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templatetest"
+)
+
+// runTests validates the templates against the fixtures of their tests,
+// failing the run if any outcome differs from the expected one
+func (r *Runner) runTests() {
+	availableTemplates, _ := r.getParsedTemplatesFor(r.getIncludedTemplates(r.options.Templates), r.options.Severity)
+
+	var passed, failed int
+
+	for _, t := range availableTemplates {
+		template, ok := t.(*templates.Template)
+		if !ok || len(template.Tests) == 0 {
+			continue
+		}
+
+		for i, test := range template.Tests {
+			name := test.Name
+			if name == "" {
+				name = template.ID
+			}
+
+			if err := r.runTest(template, test); err != nil {
+				gologger.Errorf("[FAIL] %s (test %d: %s): %s\n", template.ID, i+1, name, err)
+				failed++
+
+				continue
+			}
+
+			gologger.Infof("[PASS] %s (test %d: %s)", template.ID, i+1, name)
+			passed++
+		}
+	}
+
+	if passed+failed == 0 {
+		gologger.Fatalf("Error, no templates with tests were found.\n")
+	}
+
+	gologger.Infof("%d tests passed, %d failed", passed, failed)
+
+	if failed > 0 {
+		r.failed = true
+	}
+}
+
+// runTest runs a template against the target of a test, comparing its
+// outcome with the expected one
+func (r *Runner) runTest(template *templates.Template, test *templatetest.Test) error {
+	target := test.Target
+
+	if len(test.Responses) > 0 {
+		server := templatetest.NewServer(test.Responses)
+		defer server.Close()
+
+		target = server.URL
+	}
+
+	if test.Compose != "" {
+		if err := templatetest.ComposeUp(test.Compose); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := templatetest.ComposeDown(test.Compose); err != nil {
+				gologger.Warningf("Could not stop the compose target of %s: %s\n", template.ID, err)
+			}
+		}()
+	}
+
+	target = targets.Normalize(target)
+	r.rateLimiter.Add(target, r.options.RateLimit)
+
+	// each test starts from scratch
+	r.input = target + "\n"
+	r.inputCount = 1
+	r.summary = summary.New()
+	r.errorLog.Reset()
+	r.scanContext = scancontext.New()
+
+	matched := r.runTemplate(&progress.NoOpProgress{}, template)
+
+	return test.Check(matched, len(r.summary.Records()))
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/vulndb"
+)
+
+// vulnDBFilename is the filename of the updated vulnerability database
+const vulnDBFilename = ".nuclei-vulndb.json"
+
+// vulnDBUpdateTimeout is the timeout of each page fetched from the NVD
+const vulnDBUpdateTimeout = 2 * time.Minute
+
+// vulnDBPath returns the path of the updated vulnerability database
+func vulnDBPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(home, vulnDBFilename), nil
+}
+
+// updateVulnDB fetches the vulnerabilities of the known products from
+// the NVD, replacing the updated database.
+func (r *Runner) updateVulnDB() error {
+	file, err := vulnDBPath()
+	if err != nil {
+		return err
+	}
+
+	products := vulndb.DefaultProducts
+	if db, err := vulndb.Load(file); err == nil {
+		products = db.Products
+	}
+
+	db, err := vulndb.Update(products, &vulndb.UpdateOptions{
+		APIKey:  os.Getenv("NVD_API_KEY"),
+		Timeout: vulnDBUpdateTimeout,
+		OnProduct: func(product *vulndb.Product) {
+			gologger.Infof("Fetching the vulnerabilities of %s\n", product.Name)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := db.Save(file); err != nil {
+		return err
+	}
+
+	gologger.Infof("Saved %d vulnerabilities to %s\n", len(db.Vulnerabilities), file)
+
+	return nil
+}
+
+// loadVulnDB returns the vulnerability database given by the user, else
+// the updated one, else the bundled one.
+func (r *Runner) loadVulnDB() *vulndb.Database {
+	if r.options.VulnDB != "" {
+		db, err := vulndb.Load(r.options.VulnDB)
+		if err != nil {
+			gologger.Fatalf("Could not load vulnerability database '%s': %s\n", r.options.VulnDB, err)
+		}
+
+		return db
+	}
+
+	if file, err := vulnDBPath(); err == nil {
+		if db, err := vulndb.Load(file); err == nil {
+			return db
+		}
+	}
+
+	return vulndb.Default()
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/atomicboolean"
+)
+
+// templateWatchInterval is the interval at which the template files are
+// checked for modifications
+const templateWatchInterval = time.Second
+
+// templateWatcher detects the template files created or modified since they
+// were last seen. The files are polled, which also works for the template
+// directories mounted from network filesystems.
+type templateWatcher struct {
+	runner   *Runner
+	excluded map[string]struct{}
+	modified map[string]time.Time
+}
+
+// newTemplateWatcher returns a watcher of the included templates, starting
+// from their current state.
+func (r *Runner) newTemplateWatcher(templatePaths []string) *templateWatcher {
+	w := &templateWatcher{
+		runner:   r,
+		excluded: make(map[string]struct{}),
+		modified: make(map[string]time.Time),
+	}
+	for _, path := range r.getTemplatesFor(r.options.ExcludedTemplates) {
+		w.excluded[path] = struct{}{}
+	}
+
+	for _, path := range templatePaths {
+		if info, err := os.Stat(path); err == nil {
+			w.modified[path] = info.ModTime()
+		}
+	}
+
+	return w
+}
+
+// Changed returns the included templates created or modified since the
+// previous call.
+func (w *templateWatcher) Changed() []string {
+	var changed []string
+
+	for _, path := range w.runner.getTemplatesFor(w.runner.options.Templates) {
+		if _, excluded := w.excluded[path]; excluded {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if modified, ok := w.modified[path]; ok && modified.Equal(info.ModTime()) {
+			continue
+		}
+
+		w.modified[path] = info.ModTime()
+		changed = append(changed, path)
+	}
+
+	return changed
+}
+
+// Reload parses the templates modified since the previous call, compiling
+// their matchers again. The templates of the scans in flight are left as is.
+func (w *templateWatcher) Reload() []interface{} {
+	changed := w.Changed()
+	if len(changed) == 0 {
+		return nil
+	}
+
+	reloaded, _ := w.runner.getParsedTemplatesFor(changed, w.runner.options.Severity)
+	gologger.Infof("Reloaded %d modified templates", len(reloaded))
+
+	return reloaded
+}
+
+// watchTemplates re-runs the templates modified on disk on the inputs until
+// stop is closed, returning once the templates being re-run are over.
+func (r *Runner) watchTemplates(watcher *templateWatcher, results *atomicboolean.AtomBool, stop <-chan struct{}) {
+	gologger.Infof("Watching the templates for modifications")
+
+	p := &progress.NoOpProgress{}
+
+	ticker := time.NewTicker(templateWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, template := range watcher.Reload() {
+				results.Or(r.runTemplate(p, template))
+			}
+		}
+	}
+}
+
+// waitWatchedTemplates keeps re-running the modified templates after the
+// scan until the process is interrupted, then stops the watcher.
+func waitWatchedTemplates(stop chan struct{}, watching *sync.WaitGroup) {
+	signals, stopSignals := notifyInterrupt()
+	defer stopSignals()
+
+	gologger.Infof("Scan finished, still watching the templates until interrupted")
+	<-signals
+
+	close(stop)
+	watching.Wait()
+}
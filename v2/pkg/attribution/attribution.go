@@ -0,0 +1,110 @@
+package attribution
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The headers identifying the requests of a scan
+const (
+	ScannerHeader = "X-Scanner"
+	ScanIDHeader  = "X-Scan-ID"
+	ContactHeader = "X-Scanner-Contact"
+)
+
+// Options contains the annotations of the requests of a scan
+type Options struct {
+	// Headers adds the scanner and scan id headers to the requests
+	Headers bool
+	// ScanID identifies the scan, a random one being generated when empty
+	ScanID string
+	// Contact is an optional contact address sent in a header
+	Contact string
+	// Param is the optional name of a query parameter set to the scan id
+	Param string
+}
+
+// Attribution annotates the requests of a scan
+type Attribution struct {
+	scanID  string
+	headers map[string]string
+	param   string
+}
+
+// New returns the annotations of the requests of a scan, or nil if the
+// requests aren't annotated
+func New(options *Options) *Attribution {
+	if !options.Headers && options.Contact == "" && options.Param == "" {
+		return nil
+	}
+
+	a := &Attribution{
+		scanID:  options.ScanID,
+		headers: make(map[string]string),
+		param:   options.Param,
+	}
+
+	if a.scanID == "" {
+		data := make([]byte, 8)
+		_, _ = rand.Read(data)
+		a.scanID = hex.EncodeToString(data)
+	}
+
+	if options.Headers {
+		a.headers[ScannerHeader] = "nuclei"
+		a.headers[ScanIDHeader] = a.scanID
+	}
+
+	if options.Contact != "" {
+		a.headers[ContactHeader] = options.Contact
+	}
+
+	return a
+}
+
+// ScanID returns the id of the scan
+func (a *Attribution) ScanID() string {
+	if a == nil {
+		return ""
+	}
+
+	return a.scanID
+}
+
+// Headers returns the names of the annotation headers sorted, and their values
+func (a *Attribution) Headers() ([]string, map[string]string) {
+	if a == nil {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(a.headers))
+	for name := range a.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, a.headers
+}
+
+// MarkURL returns a url or a request path with the marker query parameter
+// appended, before any fragment
+func (a *Attribution) MarkURL(target string) string {
+	if a == nil || a.param == "" {
+		return target
+	}
+
+	var fragment string
+	if i := strings.Index(target, "#"); i != -1 {
+		target, fragment = target[:i], target[i:]
+	}
+
+	separator := "?"
+	if strings.Contains(target, "?") {
+		separator = "&"
+	}
+
+	return target + separator + url.QueryEscape(a.param) + "=" + url.QueryEscape(a.scanID) + fragment
+}
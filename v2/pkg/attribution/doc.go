@@ -0,0 +1,3 @@
+// Package attribution annotates the requests of a scan so that the
+// defenders of the targets can attribute the traffic.
+package attribution
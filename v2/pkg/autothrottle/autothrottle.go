@@ -0,0 +1,131 @@
+package autothrottle
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// backoffFactor multiplies the delay of a host showing signs of distress
+	backoffFactor = 2
+	// recoveryFactor reduces the delay of a healthy host
+	recoveryFactor = 0.9
+	// latencyFactor is how much slower than average a response has to be to be considered unhealthy
+	latencyFactor = 3
+	// latencyWeight is the weight of the last response in the moving latency average
+	latencyWeight = 0.2
+)
+
+// AutoThrottle adapts the request rate of each host within bounds
+// based on the error rate and latency of its responses.
+type AutoThrottle struct {
+	sync.Mutex
+	minDelay time.Duration
+	maxDelay time.Duration
+	hosts    map[string]*hostState
+}
+
+// hostState holds the throttling state of a single host
+type hostState struct {
+	sync.Mutex
+	delay   time.Duration
+	latency time.Duration
+	next    time.Time
+}
+
+// New creates a new auto throttle where each host is allowed
+// between minRate and maxRate requests per second.
+func New(minRate, maxRate int) *AutoThrottle {
+	if minRate <= 0 {
+		minRate = 1
+	}
+
+	if maxRate < minRate {
+		maxRate = minRate
+	}
+
+	return &AutoThrottle{
+		minDelay: time.Second / time.Duration(maxRate),
+		maxDelay: time.Second / time.Duration(minRate),
+		hosts:    make(map[string]*hostState),
+	}
+}
+
+// host returns the state of a host, creating it if required
+func (a *AutoThrottle) host(key string) *hostState {
+	a.Lock()
+	defer a.Unlock()
+
+	state, ok := a.hosts[key]
+	if !ok {
+		state = &hostState{delay: a.minDelay}
+		a.hosts[key] = state
+	}
+
+	return state
+}
+
+// Take blocks until the next request to the host is allowed.
+func (a *AutoThrottle) Take(key string) {
+	if a == nil {
+		return
+	}
+
+	state := a.host(key)
+
+	state.Lock()
+	now := time.Now()
+	wait := state.next.Sub(now)
+
+	if wait < 0 {
+		wait = 0
+	}
+
+	state.next = now.Add(wait + state.delay)
+	state.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// Report adjusts the rate of a host from the outcome of a request.
+func (a *AutoThrottle) Report(key string, latency time.Duration, resp *http.Response, err error) {
+	if a == nil {
+		return
+	}
+
+	state := a.host(key)
+
+	state.Lock()
+	defer state.Unlock()
+
+	unhealthy := err != nil || (resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError))
+
+	if !unhealthy && state.latency > 0 && latency > latencyFactor*state.latency {
+		unhealthy = true
+	}
+
+	if err == nil {
+		if state.latency == 0 {
+			state.latency = latency
+		} else {
+			state.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(state.latency))
+		}
+	}
+
+	if unhealthy {
+		state.delay *= backoffFactor
+		if state.delay > a.maxDelay {
+			state.delay = a.maxDelay
+		}
+
+		return
+	}
+
+	state.delay = time.Duration(float64(state.delay) * recoveryFactor)
+	if state.delay < a.minDelay {
+		state.delay = a.minDelay
+	}
+}
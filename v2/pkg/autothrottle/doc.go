@@ -0,0 +1,3 @@
+// Package autothrottle implements an adaptive per-host rate limiting
+// backing off from targets with high error rates or latency.
+package autothrottle
@@ -0,0 +1,119 @@
+package baseline
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const maxBodySize = 2 * 1024 * 1024
+
+// Options contains the configuration of the baseline requests
+type Options struct {
+	// Timeout is the timeout of each request
+	Timeout time.Duration
+	// Transport is the transport of the requests, the default one when nil
+	Transport http.RoundTripper
+	// OnRequest is optionally called before the request of a template to
+	// the host of an url, which isn't sent if it returns false
+	OnRequest func(templateID, reqURL string) bool
+}
+
+// Baselines fetches and keeps the baseline fingerprint of each host
+type Baselines struct {
+	httpClient *http.Client
+	onRequest  func(templateID, reqURL string) bool
+
+	mutex sync.Mutex
+	hosts map[string]*hostBaseline
+}
+
+// hostBaseline is the baseline of a host, fetched once
+type hostBaseline struct {
+	sync.Mutex
+	fetched     bool
+	fingerprint *Fingerprint
+}
+
+// New creates the baselines of the hosts
+func New(options *Options) *Baselines {
+	return &Baselines{
+		httpClient: &http.Client{
+			Transport: options.Transport,
+			Timeout:   options.Timeout,
+			// the redirects of the soft 404 pages are part of their fingerprint
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		onRequest: options.OnRequest,
+		hosts:     make(map[string]*hostBaseline),
+	}
+}
+
+// Get returns the fingerprint of the response of the host of an url to a
+// random path, or nil if it couldn't be fetched. The host is requested for
+// the first template allowed to send the request.
+func (b *Baselines) Get(templateID, reqURL string) *Fingerprint {
+	if b == nil {
+		return nil
+	}
+
+	parsed, err := url.Parse(reqURL)
+	if err != nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	host, ok := b.hosts[parsed.Host]
+	if !ok {
+		host = &hostBaseline{}
+		b.hosts[parsed.Host] = host
+	}
+	b.mutex.Unlock()
+
+	host.Lock()
+	defer host.Unlock()
+
+	if !host.fetched {
+		if b.onRequest != nil && !b.onRequest(templateID, reqURL) {
+			return nil
+		}
+
+		host.fingerprint = b.fetch(parsed.Scheme + "://" + parsed.Host)
+		host.fetched = true
+	}
+
+	return host.fingerprint
+}
+
+// fetch requests a random path of a base url and fingerprints the response
+func (b *Baselines) fetch(base string) *Fingerprint {
+	path := "/" + randomPath()
+
+	resp, err := b.httpClient.Get(base + path)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil
+	}
+
+	return NewFingerprint(resp.StatusCode, string(body), path)
+}
+
+// randomPath returns a path which doesn't exist on the hosts
+func randomPath() string {
+	data := make([]byte, 12)
+	_, _ = rand.Read(data)
+
+	return hex.EncodeToString(data)
+}
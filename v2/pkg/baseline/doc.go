@@ -0,0 +1,3 @@
+// Package baseline fingerprints the responses of the hosts to paths which
+// don't exist, so matchers can discard the soft 404 responses looking alike.
+package baseline
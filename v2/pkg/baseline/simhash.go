@@ -0,0 +1,75 @@
+package baseline
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tokenSeparator splits the normalized bodies into tokens
+	tokenSeparator = regexp.MustCompile(`[^a-z0-9_]+`)
+	// volatileToken matches the numbers and hashes changing between responses
+	volatileToken = regexp.MustCompile(`^(?:[0-9]+|[0-9a-f]{16,})$`)
+)
+
+// Fingerprint contains the features compared between responses
+type Fingerprint struct {
+	StatusCode int
+	Simhash    uint64
+}
+
+// NewFingerprint fingerprints a response, the requested path being removed
+// from the body since the soft 404 pages often reflect it.
+func NewFingerprint(statusCode int, body, path string) *Fingerprint {
+	body = strings.ToLower(body)
+	if path = strings.Trim(strings.ToLower(path), "/"); path != "" {
+		body = strings.ReplaceAll(body, path, "")
+	}
+
+	return &Fingerprint{StatusCode: statusCode, Simhash: simhash(body)}
+}
+
+// Similarity returns the similarity between two fingerprints from 0 to 1.
+// Responses with different status codes are never similar.
+func (f *Fingerprint) Similarity(other *Fingerprint) float64 {
+	if f.StatusCode != other.StatusCode {
+		return 0
+	}
+
+	return 1 - float64(bits.OnesCount64(f.Simhash^other.Simhash))/64
+}
+
+// simhash computes the simhash of the tokens of a text
+func simhash(text string) uint64 {
+	var weights [64]int
+
+	for _, token := range tokenSeparator.Split(text, -1) {
+		if token == "" || volatileToken.MatchString(token) {
+			continue
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum64()
+
+		for i := 0; i < 64; i++ {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+
+	for i, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+
+	return hash
+}
@@ -0,0 +1,108 @@
+package budget
+
+import (
+	"fmt"
+	"sync"
+)
+
+// The scopes of the budgets
+const (
+	ScopeTotal    = "total"
+	ScopeTemplate = "template"
+)
+
+// Event records a budget exceeded during a scan
+type Event struct {
+	Scope    string
+	Template string
+	Limit    int64
+}
+
+// String returns a description of the event
+func (e *Event) String() string {
+	if e.Scope == ScopeTemplate {
+		return fmt.Sprintf("budget exceeded: %d requests of template %s", e.Limit, e.Template)
+	}
+
+	return fmt.Sprintf("budget exceeded: %d requests of the scan", e.Limit)
+}
+
+// Budget caps the requests of a scan and of each of its templates
+type Budget struct {
+	total       int64
+	perTemplate int64
+
+	mutex     sync.Mutex
+	sent      int64
+	templates map[string]int64
+	events    []*Event
+	// exceeded contains the scopes whose event was recorded
+	exceeded map[string]struct{}
+}
+
+// New creates a budget of requests in total and per template, returning
+// nil when both are unlimited
+func New(total, perTemplate int64) *Budget {
+	if total <= 0 && perTemplate <= 0 {
+		return nil
+	}
+
+	return &Budget{
+		total:       total,
+		perTemplate: perTemplate,
+		templates:   make(map[string]int64),
+		exceeded:    make(map[string]struct{}),
+	}
+}
+
+// Take reports whether a request of a template fits in the budget, taking
+// it from the budget when it does
+func (b *Budget) Take(template string) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.total > 0 && b.sent >= b.total {
+		b.exceed(ScopeTotal, "", b.total)
+		return false
+	}
+
+	if b.perTemplate > 0 && b.templates[template] >= b.perTemplate {
+		b.exceed(ScopeTemplate, template, b.perTemplate)
+		return false
+	}
+
+	b.sent++
+	b.templates[template]++
+
+	return true
+}
+
+// exceed records the first time a budget was exceeded, the mutex being held
+func (b *Budget) exceed(scope, template string, limit int64) {
+	key := scope + ":" + template
+	if _, ok := b.exceeded[key]; ok {
+		return
+	}
+
+	b.exceeded[key] = struct{}{}
+	b.events = append(b.events, &Event{Scope: scope, Template: template, Limit: limit})
+}
+
+// Events returns the budgets exceeded in the order they were
+func (b *Budget) Events() []*Event {
+	if b == nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	events := make([]*Event, len(b.events))
+	copy(events, b.events)
+
+	return events
+}
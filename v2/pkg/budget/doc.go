@@ -0,0 +1,3 @@
+// Package budget caps the number of requests sent by a scan and by each of
+// its templates, recording the budgets exceeded.
+package budget
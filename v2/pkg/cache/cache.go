@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Cache stores http responses keyed by their normalized request
+// for a limited amount of time, in memory and optionally on disk.
+// Once the cache is full the entries stored first are evicted.
+type Cache struct {
+	sync.Mutex
+	ttl       time.Duration
+	size      int
+	directory string
+	items     map[string]*list.Element
+	// order holds the cached entries in the order they were stored,
+	// which is also the order they expire in
+	order *list.List
+}
+
+// item is an entry of the cache along with its key
+type item struct {
+	key   string
+	entry *Entry
+}
+
+// Entry is a cached http response
+type Entry struct {
+	StatusCode int           `json:"status_code"`
+	Proto      string        `json:"proto"`
+	Header     http.Header   `json:"header"`
+	Body       []byte        `json:"body"`
+	Duration   time.Duration `json:"duration"`
+	Expires    time.Time     `json:"expires"`
+}
+
+// New creates a new response cache with the given ttl, holding at most
+// size responses in memory if size is positive. If directory is not empty
+// the responses are also persisted there across runs.
+func New(ttl time.Duration, size int, directory string) (*Cache, error) {
+	if directory != "" {
+		if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Cache{ttl: ttl, size: size, directory: directory, items: make(map[string]*list.Element), order: list.New()}, nil
+}
+
+// Key returns the cache key of a dumped request
+func Key(dumpedRequest []byte) string {
+	hash := sha256.Sum256(dumpedRequest)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Get returns a copy of the cached response for the key, if still valid.
+func (c *Cache) Get(key string) (*http.Response, time.Duration, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.Lock()
+	var entry *Entry
+	element, ok := c.items[key]
+	if ok {
+		entry = element.Value.(*item).entry
+	}
+	c.Unlock()
+
+	if !ok && c.directory != "" {
+		entry, ok = c.readFromDisk(key)
+	}
+
+	if !ok {
+		return nil, 0, false
+	}
+
+	if time.Now().After(entry.Expires) {
+		c.remove(key)
+		return nil, 0, false
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.StatusCode, http.StatusText(entry.StatusCode)),
+		StatusCode:    entry.StatusCode,
+		Proto:         entry.Proto,
+		Header:        entry.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+	}
+
+	return resp, entry.Duration, true
+}
+
+// Set stores a response and its raw body for the key.
+func (c *Cache) Set(key string, resp *http.Response, body []byte, duration time.Duration) {
+	if c == nil {
+		return
+	}
+
+	entry := &Entry{
+		StatusCode: resp.StatusCode,
+		Proto:      resp.Proto,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Duration:   duration,
+		Expires:    time.Now().Add(c.ttl),
+	}
+
+	c.store(key, entry)
+
+	if c.directory != "" {
+		c.writeToDisk(key, entry)
+	}
+}
+
+// store adds an entry to the memory of the cache, evicting the expired
+// entries and the ones stored first when the cache is full
+func (c *Cache) store(key string, entry *Entry) {
+	c.Lock()
+	defer c.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+	}
+	c.items[key] = c.order.PushBack(&item{key: key, entry: entry})
+
+	now := time.Now()
+	for element := c.order.Front(); element != nil; element = c.order.Front() {
+		oldest := element.Value.(*item)
+		if !now.After(oldest.entry.Expires) && (c.size <= 0 || c.order.Len() <= c.size) {
+			break
+		}
+
+		c.order.Remove(element)
+		delete(c.items, oldest.key)
+	}
+}
+
+// remove drops an expired entry from the memory and the directory of the cache
+func (c *Cache) remove(key string) {
+	c.Lock()
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+	c.Unlock()
+
+	if c.directory != "" {
+		//nolint:errcheck // the entry is expired anyway
+		os.Remove(path.Join(c.directory, key+".json"))
+	}
+}
+
+// readFromDisk loads a cached entry from the cache directory
+func (c *Cache) readFromDisk(key string) (*Entry, bool) {
+	data, err := ioutil.ReadFile(path.Join(c.directory, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := &Entry{}
+	if err := jsoniter.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+
+	c.store(key, entry)
+
+	return entry, true
+}
+
+// writeToDisk persists a cached entry in the cache directory
+func (c *Cache) writeToDisk(key string, entry *Entry) {
+	data, err := jsoniter.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	//nolint:errcheck // a failed write only results in a cache miss
+	ioutil.WriteFile(path.Join(c.directory, key+".json"), data, 0600)
+}
@@ -0,0 +1,3 @@
+// Package cache implements a response cache shared by templates
+// to avoid sending identical requests to the same targets.
+package cache
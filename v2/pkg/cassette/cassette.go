@@ -0,0 +1,209 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Interaction is a recorded response to a request
+type Interaction struct {
+	// Request identifies the request the response was received for
+	Request string `yaml:"request"`
+	// Status is the status code of the response
+	Status int `yaml:"status"`
+	// Proto is the protocol of the response
+	Proto string `yaml:"proto,omitempty"`
+	// Headers contains the headers of the response
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	// Body is the body of the response
+	Body string `yaml:"body,omitempty"`
+	// Duration is the time the response took, for the time based matchers
+	Duration time.Duration `yaml:"duration"`
+}
+
+// Cassette contains the interactions of a template
+type Cassette struct {
+	mutex        sync.Mutex
+	path         string
+	Interactions []*Interaction `yaml:"interactions"`
+	served       map[string]int
+	modified     bool
+}
+
+// Record adds the response to a request to the cassette
+func (c *Cassette) Record(request string, resp *http.Response, body []byte, duration time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.Interactions = append(c.Interactions, &Interaction{
+		Request:  request,
+		Status:   resp.StatusCode,
+		Proto:    resp.Proto,
+		Headers:  resp.Header.Clone(),
+		Body:     string(body),
+		Duration: duration,
+	})
+	c.modified = true
+}
+
+// Replay returns the response recorded for a request, the responses
+// recorded for the same request being replayed in order, the last one
+// repeatedly.
+func (c *Cassette) Replay(request string) (*http.Response, time.Duration, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var recorded []*Interaction
+
+	for _, interaction := range c.Interactions {
+		if interaction.Request == request {
+			recorded = append(recorded, interaction)
+		}
+	}
+
+	if len(recorded) == 0 {
+		return nil, 0, fmt.Errorf("no response recorded in %s for %s", c.path, request)
+	}
+
+	position := c.served[request]
+	if position >= len(recorded) {
+		position = len(recorded) - 1
+	}
+	c.served[request] = position + 1
+
+	interaction := recorded[position]
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         interaction.Proto,
+		Header:        http.Header(interaction.Headers).Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Body))),
+		ContentLength: int64(len(interaction.Body)),
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+
+	return resp, interaction.Duration, nil
+}
+
+// save writes the cassette to its file if interactions were recorded
+func (c *Cassette) save() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.modified {
+		return nil
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, data, 0600)
+}
+
+// load reads a cassette from its file
+func load(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{path: path, served: make(map[string]int)}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("could not parse cassette %s: %s", path, err)
+	}
+
+	return c, nil
+}
+
+// Library contains the cassettes of the templates in a directory
+type Library struct {
+	mutex     sync.Mutex
+	directory string
+	recording bool
+	cassettes map[string]*Cassette
+}
+
+// New returns the library of the cassettes of a directory, recording the
+// responses into new cassettes or replaying the existing ones.
+func New(directory string, recording bool) (*Library, error) {
+	if recording {
+		if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+			return nil, err
+		}
+	} else if _, err := os.Stat(directory); err != nil {
+		return nil, err
+	}
+
+	return &Library{directory: directory, recording: recording, cassettes: make(map[string]*Cassette)}, nil
+}
+
+// Recording returns true if the responses are recorded
+func (l *Library) Recording() bool {
+	return l != nil && l.recording
+}
+
+// Cassette returns the cassette of a template. The recorded cassettes are
+// started from scratch, replacing the previous recordings.
+func (l *Library) Cassette(templateID string) (*Cassette, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if c, ok := l.cassettes[templateID]; ok {
+		return c, nil
+	}
+
+	path := filepath.Join(l.directory, templateID+".yaml")
+
+	var c *Cassette
+
+	if l.recording {
+		c = &Cassette{path: path, served: make(map[string]int)}
+	} else {
+		var err error
+		if c, err = load(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l.cassettes[templateID] = c
+
+	return c, nil
+}
+
+// Close writes the recorded cassettes
+func (l *Library) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, c := range l.cassettes {
+		if err := c.save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,4 @@
+// Package cassette records the http responses of the templates into
+// cassette files and replays them, allowing to iterate on the matchers
+// of the templates offline and deterministically.
+package cassette
@@ -0,0 +1,100 @@
+package certnames
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+)
+
+// Scopes of the harvested names added as targets
+const (
+	DomainScope = "domain"
+	AllScope    = "all"
+)
+
+// Scopes contains the supported scopes
+var Scopes = map[string]bool{
+	DomainScope: true,
+	AllScope:    true,
+}
+
+// Harvest returns the deduplicated names of the certificate served by an
+// address other than the host itself. Wildcard names are returned
+// without their wildcard label.
+func Harvest(address string, timeout time.Duration) ([]string, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true, ServerName: host}) // nolint:gosec // the names of invalid certificates are harvested too
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	certificates := conn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return nil, nil
+	}
+
+	leaf := certificates[0]
+	seen := map[string]struct{}{strings.ToLower(host): {}}
+
+	var names []string
+
+	add := func(name string) {
+		name = strings.TrimPrefix(strings.ToLower(strings.TrimSuffix(name, ".")), "*.")
+		if name == "" || strings.ContainsAny(name, "* ") {
+			return
+		}
+
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	add(leaf.Subject.CommonName)
+
+	for _, name := range leaf.DNSNames {
+		add(name)
+	}
+
+	for _, ip := range leaf.IPAddresses {
+		add(ip.String())
+	}
+
+	return names, nil
+}
+
+// InScope returns true if a harvested name is in the scope of the host
+// whose certificate it was found in. The domain scope accepts the names
+// sharing the last two labels of the host.
+func InScope(name, host, scope string) bool {
+	if scope == AllScope {
+		return true
+	}
+
+	// ip addresses only share a domain with themselves
+	if net.ParseIP(name) != nil || net.ParseIP(host) != nil {
+		return false
+	}
+
+	domain := baseDomain(host)
+
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// baseDomain returns the last two labels of a host
+func baseDomain(host string) string {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	if len(labels) <= 2 {
+		return strings.Join(labels, ".")
+	}
+
+	return strings.Join(labels[len(labels)-2:], ".")
+}
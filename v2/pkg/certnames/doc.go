@@ -0,0 +1,3 @@
+// Package certnames harvests the dns names and ip addresses of the tls
+// certificates of the targets, finding the hosts sharing their certificate.
+package certnames
@@ -0,0 +1,170 @@
+package connector
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/resolver"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tlsprofile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/trust"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tunnel"
+)
+
+// Options contains the network configuration of the scan
+type Options struct {
+	// ProxyURL is the url of the http proxy, with its credentials
+	ProxyURL string
+	// ProxySocksURL is the url of the socks5 proxy, with its credentials
+	ProxySocksURL string
+	// ProxyAuth is the authentication scheme of the http proxy
+	ProxyAuth string
+	// Timeout is the timeout of the connections
+	Timeout time.Duration
+	// TrustStore verifies the certificate chains of the targets
+	TrustStore *trust.Store
+	// TLSProfile restricts the tls versions and cipher suites
+	TLSProfile *tlsprofile.Profile
+	// Resolver chooses the addresses of the hosts
+	Resolver *resolver.Resolver
+}
+
+// Connector opens the connections of the scan to the targets. A nil
+// connector connects directly without verifying the certificates.
+type Connector struct {
+	// tunnel tunnels the raw connections through the socks and http proxies
+	tunnel *tunnel.Dialer
+	// socks connects through the socks proxy only, the http proxy being
+	// handled by the transport
+	socks    *tunnel.Dialer
+	proxyURL *url.URL
+	trust    *trust.Store
+	profile  *tlsprofile.Profile
+	resolver *resolver.Resolver
+
+	transport *http.Transport
+}
+
+// New creates a connector from the network configuration of the scan
+func New(options *Options) (*Connector, error) {
+	raw, err := tunnel.New(&tunnel.Options{
+		ProxyURL: options.ProxyURL,
+		Auth:     options.ProxyAuth,
+		SocksURL: options.ProxySocksURL,
+		Timeout:  options.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	socks, err := tunnel.New(&tunnel.Options{SocksURL: options.ProxySocksURL, Timeout: options.Timeout})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Connector{
+		tunnel:   raw,
+		socks:    socks,
+		trust:    options.TrustStore,
+		profile:  options.TLSProfile,
+		resolver: options.Resolver,
+	}
+
+	if options.ProxyURL != "" {
+		// the url was validated by the tunnel
+		c.proxyURL, _ = url.Parse(options.ProxyURL)
+	}
+
+	c.transport = c.newTransport()
+
+	return c, nil
+}
+
+// newTransport creates the transport of the http requests, the ntlm
+// authenticated proxies being tunneled as it is bound to the connection
+func (c *Connector) newTransport() *http.Transport {
+	transport := &http.Transport{
+		TLSClientConfig: c.profile.Apply(&tls.Config{
+			RootCAs:            c.trust.Roots(),
+			InsecureSkipVerify: !c.trust.Verify(), // nolint:gosec // the chains are only verified when requested
+		}),
+		DisableKeepAlives: true,
+	}
+
+	dial := c.socks.DialContext
+	if c.tunnel.Auth() == "ntlm" {
+		dial = c.tunnel.DialContext
+	} else if c.proxyURL != nil {
+		transport.Proxy = http.ProxyURL(c.proxyURL)
+	}
+
+	transport.DialContext = c.resolver.Dial(dial)
+
+	return transport
+}
+
+// Transport returns the transport of the http requests, shared by the
+// clients of the scan
+func (c *Connector) Transport() *http.Transport {
+	if c == nil {
+		return (&Connector{}).newTransport()
+	}
+
+	return c.transport
+}
+
+// TLSConfig returns the tls configuration of the connections to a host
+func (c *Connector) TLSConfig(serverName string) *tls.Config {
+	if c == nil {
+		return (*trust.Store)(nil).Config(serverName)
+	}
+
+	return c.profile.Apply(c.trust.Config(serverName))
+}
+
+// DialContext opens a connection to an address, tunneled through the
+// proxies and to the address chosen for its host
+func (c *Connector) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if c == nil {
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}
+
+	return c.resolver.Dial(c.tunnel.DialContext)(ctx, network, address)
+}
+
+// Dial opens a plain or tls connection to the host of a url, the tls
+// handshake being completed
+func (c *Connector) Dial(ctx context.Context, target *url.URL) (net.Conn, error) {
+	address := target.Host
+	if target.Port() == "" {
+		if target.Scheme == "https" {
+			address = net.JoinHostPort(target.Hostname(), "443")
+		} else {
+			address = net.JoinHostPort(target.Hostname(), "80")
+		}
+	}
+
+	conn, err := c.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if target.Scheme != "https" {
+		return conn, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) // nolint:errcheck // best effort
+	}
+
+	tlsConn := tls.Client(conn, c.TLSConfig(target.Hostname()))
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return tlsConn, nil
+}
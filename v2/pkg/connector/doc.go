@@ -0,0 +1,5 @@
+// Package connector opens the connections of the requests not sent by the
+// http client of the templates, like the smuggling probes and the bucket,
+// takeover and header audit requests, through the proxies of the scan,
+// with its tls configuration and to the addresses chosen for the hosts.
+package connector
@@ -0,0 +1,4 @@
+// Package cookies contains the cookie jars of the http requests, seeded
+// with the cookies imported from a file and optionally isolating the
+// cookies of every host.
+package cookies
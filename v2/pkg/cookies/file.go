@@ -0,0 +1,141 @@
+package cookies
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// httpOnlyPrefix marks the http only cookies of the netscape cookie files
+const httpOnlyPrefix = "#HttpOnly_"
+
+// jsonCookie is a cookie of the json cookie files exported by the browsers
+type jsonCookie struct {
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Domain         string  `json:"domain"`
+	Path           string  `json:"path"`
+	Secure         bool    `json:"secure"`
+	HTTPOnly       bool    `json:"httpOnly"`
+	HostOnly       bool    `json:"hostOnly"`
+	ExpirationDate float64 `json:"expirationDate"`
+}
+
+// ParseFile reads the cookies of a netscape cookies.txt file or of a json
+// array of cookies as exported by the browsers. The expired cookies are
+// skipped, and the domains of the cookies shared with the subdomains start
+// with a dot.
+func ParseFile(file string) ([]*http.Cookie, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return parseJSON(trimmed)
+	}
+
+	return parseNetscape(data)
+}
+
+// parseJSON parses a json array of cookies
+func parseJSON(data []byte) ([]*http.Cookie, error) {
+	var parsed []jsonCookie
+	if err := jsoniter.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+
+	cookies := make([]*http.Cookie, 0, len(parsed))
+
+	for _, c := range parsed {
+		if c.Name == "" || c.Domain == "" {
+			return nil, errors.New("cookie without name or domain")
+		}
+
+		domain := strings.TrimPrefix(c.Domain, ".")
+		if !c.HostOnly {
+			domain = "." + domain
+		}
+
+		cookie := &http.Cookie{Name: c.Name, Value: c.Value, Domain: domain, Path: c.Path, Secure: c.Secure, HttpOnly: c.HTTPOnly}
+		if c.ExpirationDate > 0 {
+			seconds, fraction := math.Modf(c.ExpirationDate)
+			cookie.Expires = time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+		}
+
+		cookies = appendValid(cookies, cookie)
+	}
+
+	return cookies, nil
+}
+
+// parseNetscape parses a netscape cookies.txt file, whose lines contain the
+// domain, the subdomains flag, the path, the secure flag, the expiry, the
+// name and the value of the cookies separated by tabs
+func parseNetscape(data []byte) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(text, httpOnlyPrefix)
+		if httpOnly {
+			text = strings.TrimPrefix(text, httpOnlyPrefix)
+		}
+
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookie on line %d", line)
+		}
+
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie expiry on line %d", line)
+		}
+
+		// the cookies not shared with the subdomains are host only
+		domain := strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") {
+			domain = "." + domain
+		}
+
+		cookie := &http.Cookie{
+			Domain:   domain,
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+		}
+
+		cookies = appendValid(cookies, cookie)
+	}
+
+	return cookies, scanner.Err()
+}
+
+// appendValid appends a cookie unless it expired
+func appendValid(cookies []*http.Cookie, cookie *http.Cookie) []*http.Cookie {
+	if !cookie.Expires.IsZero() && cookie.Expires.Before(time.Now()) {
+		return cookies
+	}
+
+	return append(cookies, cookie)
+}
@@ -0,0 +1,105 @@
+package cookies
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Options contains the cookie policy of the jars
+type Options struct {
+	// PerHost isolates the cookies of every scheme, host and port
+	PerHost bool
+	// Cookies contains the cookies sent to their domains by every jar
+	Cookies []*http.Cookie
+}
+
+// Jar is a cookie jar seeded with the imported cookies. The cookies of the
+// responses are kept only when they are reused.
+type Jar struct {
+	perHost bool
+	reuse   bool
+	seed    []*http.Cookie
+
+	mutex sync.Mutex
+	jars  map[string]*cookiejar.Jar
+}
+
+// NewJar returns a jar with a cookie policy, keeping the cookies of the
+// responses if reuse is true. It returns nil if the jar would be empty.
+func NewJar(options *Options, reuse bool) *Jar {
+	if options == nil {
+		options = &Options{}
+	}
+
+	if !reuse && len(options.Cookies) == 0 {
+		return nil
+	}
+
+	return &Jar{
+		perHost: options.PerHost,
+		reuse:   reuse,
+		seed:    options.Cookies,
+		jars:    make(map[string]*cookiejar.Jar),
+	}
+}
+
+// SetCookies keeps the cookies of a response if they are reused
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if !j.reuse {
+		return
+	}
+
+	j.jar(u).SetCookies(u, cookies)
+}
+
+// Cookies returns the cookies to send in a request
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar(u).Cookies(u)
+}
+
+// jar returns the jar of the host of a url, seeding it when it is created
+func (j *Jar) jar(u *url.URL) *cookiejar.Jar {
+	var key string
+	if j.perHost {
+		key = u.Scheme + "://" + u.Host
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if jar, ok := j.jars[key]; ok {
+		return jar
+	}
+
+	// the jar can't fail without public suffixes
+	jar, _ := cookiejar.New(nil)
+	for _, cookie := range j.seed {
+		seeded := *cookie
+		// the cookies of a single host are set without a domain
+		if !strings.HasPrefix(seeded.Domain, ".") {
+			seeded.Domain = ""
+		}
+		jar.SetCookies(cookieURL(cookie), []*http.Cookie{&seeded})
+	}
+	j.jars[key] = jar
+
+	return jar
+}
+
+// cookieURL returns the url an imported cookie is set from
+func cookieURL(cookie *http.Cookie) *url.URL {
+	scheme := "http"
+	if cookie.Secure {
+		scheme = "https"
+	}
+
+	path := cookie.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &url.URL{Scheme: scheme, Host: strings.TrimPrefix(cookie.Domain, "."), Path: path}
+}
@@ -0,0 +1,157 @@
+package crawler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Archive sources of historical urls
+const (
+	ArchiveWayback     = "wayback"
+	ArchiveCommonCrawl = "commoncrawl"
+)
+
+// ArchiveSources contains the supported archive sources
+var ArchiveSources = map[string]bool{
+	ArchiveWayback:     true,
+	ArchiveCommonCrawl: true,
+}
+
+// staticExtensions contains the extensions of the archived urls not worth scanning
+var staticExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".css": true, ".woff": true, ".woff2": true, ".ttf": true, ".eot": true, ".mp4": true, ".mp3": true,
+}
+
+// Archived returns the historical urls of the host of a target recorded by the
+// archive sources, rewritten to the scheme and host of the target.
+func (c *Crawler) Archived(target string, sources []string) ([]string, error) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+
+	var urls []string
+
+	for _, source := range sources {
+		var archived []string
+
+		switch source {
+		case ArchiveWayback:
+			archived, err = c.wayback(base.Hostname())
+		case ArchiveCommonCrawl:
+			archived, err = c.commonCrawl(base.Hostname())
+		default:
+			err = fmt.Errorf("unknown archive source %s", source)
+		}
+
+		if err != nil {
+			return urls, err
+		}
+
+		for _, archivedURL := range archived {
+			link, err := url.Parse(strings.TrimSpace(archivedURL))
+			if err != nil || link.Hostname() != base.Hostname() || staticExtensions[strings.ToLower(path.Ext(link.Path))] {
+				continue
+			}
+
+			link.Scheme = base.Scheme
+			link.Host = base.Host
+			link.Fragment = ""
+
+			if _, ok := seen[link.String()]; ok {
+				continue
+			}
+
+			seen[link.String()] = struct{}{}
+			urls = append(urls, link.String())
+
+			if c.options.MaxURLs > 0 && len(urls) >= c.options.MaxURLs {
+				return urls, nil
+			}
+		}
+	}
+
+	return urls, nil
+}
+
+// wayback returns the urls of a host recorded by the Wayback Machine
+func (c *Crawler) wayback(host string) ([]string, error) {
+	query := url.Values{}
+	query.Set("url", host+"/*")
+	query.Set("fl", "original")
+	query.Set("collapse", "urlkey")
+	query.Set("output", "txt")
+
+	if c.options.MaxURLs > 0 {
+		query.Set("limit", fmt.Sprint(c.options.MaxURLs*2))
+	}
+
+	body, err := c.Get("https://web.archive.org/cdx/search/cdx?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		urls = append(urls, scanner.Text())
+	}
+
+	return urls, nil
+}
+
+// commonCrawl returns the urls of a host recorded by the latest Common Crawl index
+func (c *Crawler) commonCrawl(host string) ([]string, error) {
+	body, err := c.Get("https://index.commoncrawl.org/collinfo.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []struct {
+		API string `json:"cdx-api"`
+	}
+
+	if err := jsoniter.Unmarshal([]byte(body), &indexes); err != nil {
+		return nil, err
+	}
+
+	if len(indexes) == 0 {
+		return nil, errors.New("no common crawl index found")
+	}
+
+	query := url.Values{}
+	query.Set("url", host+"/*")
+	query.Set("fl", "url")
+	query.Set("output", "json")
+
+	// the indexes are listed from the most recent
+	body, err = c.Get(indexes[0].API + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var record struct {
+			URL string `json:"url"`
+		}
+
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &record); err == nil {
+			urls = append(urls, record.URL)
+		}
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,202 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const maxBodySize = 2 * 1024 * 1024
+
+var (
+	// linkAttribute matches the attributes of the html elements referencing urls
+	linkAttribute = regexp.MustCompile(`(?i)(?:href|src|action)\s*=\s*["']([^"'#]+)`)
+	// formElement matches the html forms with their content
+	formElement = regexp.MustCompile(`(?is)<form[^>]*>.*?</form>`)
+	// formAction matches the action of a form
+	formAction = regexp.MustCompile(`(?i)action\s*=\s*["']([^"']*)`)
+	// inputName matches the name of the fields of a form
+	inputName = regexp.MustCompile(`(?i)<(?:input|select|textarea)[^>]*\sname\s*=\s*["']([^"']+)`)
+)
+
+// Options contains the configuration of the crawler
+type Options struct {
+	// Depth is the maximum number of links followed from the target
+	Depth int
+	// MaxURLs is the maximum number of urls discovered per target
+	MaxURLs int
+	// Timeout is the timeout of each request
+	Timeout time.Duration
+	// RespectRobots skips the paths disallowed by the robots.txt of the target
+	RespectRobots bool
+	// ProxyURL is an optional http proxy
+	ProxyURL string
+}
+
+// Crawler discovers the urls of a target staying on its host
+type Crawler struct {
+	options    *Options
+	httpClient *http.Client
+}
+
+// New creates a new crawler
+func New(options *Options) (*Crawler, error) {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &Crawler{
+		options: options,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+		},
+	}, nil
+}
+
+// Crawl returns the urls discovered on the host of a target, the forms being
+// returned with their fields as query parameters.
+func (c *Crawler) Crawl(target string) ([]string, error) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	var robots *Robots
+	if c.options.RespectRobots {
+		robots = c.Robots(base)
+	}
+
+	seen := map[string]struct{}{base.String(): {}}
+	queue := []string{base.String()}
+
+	var discovered []string
+
+	for depth := 0; depth <= c.options.Depth && len(queue) > 0; depth++ {
+		var next []string
+
+		for _, current := range queue {
+			body, err := c.Get(current)
+			if err != nil {
+				continue
+			}
+
+			currentURL, _ := url.Parse(current)
+
+			for _, link := range extractLinks(currentURL, body) {
+				if _, ok := seen[link.String()]; ok || link.Host != base.Host || !robots.Allows(link.Path) {
+					continue
+				}
+
+				seen[link.String()] = struct{}{}
+				discovered = append(discovered, link.String())
+				next = append(next, link.String())
+
+				if c.options.MaxURLs > 0 && len(discovered) >= c.options.MaxURLs {
+					return discovered, nil
+				}
+			}
+		}
+
+		queue = next
+	}
+
+	return discovered, nil
+}
+
+// Robots fetches and parses the robots.txt file of a target, nil if it has none
+func (c *Crawler) Robots(base *url.URL) *Robots {
+	body, err := c.Get(base.Scheme + "://" + base.Host + "/robots.txt")
+	if err != nil {
+		return nil
+	}
+
+	return ParseRobots(strings.NewReader(body))
+}
+
+// Get returns the body of a successful response for an url
+func (c *Crawler) Get(URL string) (string, error) {
+	resp, err := c.httpClient.Get(URL)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// extractLinks returns the absolute http urls referenced by a page,
+// the forms being converted to urls with their fields in the query.
+func extractLinks(page *url.URL, body string) []*url.URL {
+	var links []*url.URL
+
+	for _, match := range linkAttribute.FindAllStringSubmatch(body, -1) {
+		if link := resolve(page, match[1]); link != nil {
+			links = append(links, link)
+		}
+	}
+
+	for _, form := range formElement.FindAllString(body, -1) {
+		action := ""
+		if match := formAction.FindStringSubmatch(form); match != nil {
+			action = match[1]
+		}
+
+		link := resolve(page, action)
+		if link == nil {
+			continue
+		}
+
+		query := link.Query()
+		for _, match := range inputName.FindAllStringSubmatch(form, -1) {
+			query.Set(match[1], "test")
+		}
+
+		link.RawQuery = query.Encode()
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// resolve returns the absolute url of a reference without its fragment,
+// nil if it isn't an http url.
+func resolve(page *url.URL, reference string) *url.URL {
+	link, err := page.Parse(html.UnescapeString(strings.TrimSpace(reference)))
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+		return nil
+	}
+
+	link.Fragment = ""
+
+	return link
+}
@@ -0,0 +1,3 @@
+// Package crawler discovers the urls and forms of a target
+// so they can be used as inputs by the templates.
+package crawler
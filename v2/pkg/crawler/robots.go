@@ -0,0 +1,101 @@
+package crawler
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Robots contains the rules and sitemaps of a robots.txt file
+type Robots struct {
+	// Disallowed contains the path prefixes disallowed for all user agents
+	Disallowed []string
+	// Allowed contains the path prefixes explicitly allowed for all user agents
+	Allowed []string
+	// Sitemaps contains the sitemap urls declared in the file
+	Sitemaps []string
+}
+
+// ParseRobots parses the rules of the wildcard user agent of a robots.txt file
+func ParseRobots(reader io.Reader) *Robots {
+	robots := &Robots{}
+	wildcard := false
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		field := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch field {
+		case "user-agent":
+			wildcard = value == "*"
+		case "disallow":
+			if wildcard && value != "" {
+				robots.Disallowed = append(robots.Disallowed, value)
+			}
+		case "allow":
+			if wildcard && value != "" {
+				robots.Allowed = append(robots.Allowed, value)
+			}
+		case "sitemap":
+			robots.Sitemaps = append(robots.Sitemaps, value)
+		}
+	}
+
+	return robots
+}
+
+// Paths returns the paths referenced by the rules of the file
+func (r *Robots) Paths() []string {
+	if r == nil {
+		return nil
+	}
+
+	var paths []string
+
+	for _, path := range append(append([]string{}, r.Allowed...), r.Disallowed...) {
+		// wildcard rules don't reference a concrete path
+		if index := strings.IndexAny(path, "*$"); index >= 0 {
+			path = path[:index]
+		}
+
+		if path != "" && path != "/" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// Allows reports if a path can be crawled, the longest matching rule winning
+func (r *Robots) Allows(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	allowed, length := true, -1
+
+	for _, rule := range r.Disallowed {
+		if strings.HasPrefix(path, rule) && len(rule) > length {
+			allowed, length = false, len(rule)
+		}
+	}
+
+	for _, rule := range r.Allowed {
+		if strings.HasPrefix(path, rule) && len(rule) >= length {
+			allowed, length = true, len(rule)
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,88 @@
+package crawler
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strings"
+)
+
+// maxSitemaps is the maximum number of sitemaps fetched per target
+const maxSitemaps = 10
+
+// sitemap is either a sitemap listing urls or an index listing sitemaps
+type sitemap struct {
+	URLs     []sitemapLocation `xml:"url"`
+	Sitemaps []sitemapLocation `xml:"sitemap"`
+}
+
+type sitemapLocation struct {
+	Loc string `xml:"loc"`
+}
+
+// Seed returns the paths referenced by the robots.txt of a target and
+// the urls of its host listed in its sitemaps.
+func (c *Crawler) Seed(target string) (paths, urls []string, err error) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seenPaths := make(map[string]struct{})
+	addPath := func(path string) {
+		if _, ok := seenPaths[path]; !ok && path != "" && path != "/" {
+			seenPaths[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+
+	robots := c.Robots(base)
+	for _, path := range robots.Paths() {
+		addPath(path)
+	}
+
+	queue := []string{base.Scheme + "://" + base.Host + "/sitemap.xml"}
+	if robots != nil {
+		queue = append(queue, robots.Sitemaps...)
+	}
+
+	seenSitemaps := make(map[string]struct{})
+
+	for len(queue) > 0 && len(seenSitemaps) < maxSitemaps {
+		current := queue[0]
+		queue = queue[1:]
+
+		if _, ok := seenSitemaps[current]; ok {
+			continue
+		}
+		seenSitemaps[current] = struct{}{}
+
+		body, err := c.Get(current)
+		if err != nil {
+			continue
+		}
+
+		parsed := &sitemap{}
+		if err := xml.Unmarshal([]byte(body), parsed); err != nil {
+			continue
+		}
+
+		for _, location := range parsed.Sitemaps {
+			queue = append(queue, strings.TrimSpace(location.Loc))
+		}
+
+		for _, location := range parsed.URLs {
+			link, err := url.Parse(strings.TrimSpace(location.Loc))
+			if err != nil || link.Host != base.Host {
+				continue
+			}
+
+			addPath(link.Path)
+
+			if c.options.MaxURLs <= 0 || len(urls) < c.options.MaxURLs {
+				urls = append(urls, link.String())
+			}
+		}
+	}
+
+	return paths, urls, nil
+}
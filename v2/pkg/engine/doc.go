@@ -0,0 +1,4 @@
+// Package engine runs the http and dns templates on targets in a single
+// call, without progress bars nor files, for the embedded and serverless
+// executions of nuclei.
+package engine
@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/executer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/globalratelimiter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// The defaults bounding the resources of a scan
+const (
+	DefaultConcurrency     = 10
+	DefaultTimeout         = 10
+	DefaultRateLimit       = 150
+	DefaultMaxResponseSize = 4 * 1024 * 1024
+)
+
+// Options contains the configuration of an engine
+type Options struct {
+	// Templates contains the paths of the template files to run
+	Templates []string
+	// Targets contains the urls or hosts the templates are run on
+	Targets []string
+	// Concurrency is the number of targets scanned at once
+	Concurrency int
+	// Timeout is the timeout of the requests in seconds
+	Timeout int
+	// Retries is the number of retries of the failed requests
+	Retries int
+	// RateLimit is the maximum number of requests per second to a target
+	RateLimit int
+	// MaxResponseSize bounds the bytes read of the response bodies
+	MaxResponseSize int64
+	// MaxRequests caps the http requests sent by a run, unlimited when 0
+	MaxRequests int64
+	// ProxyURL is an optional http proxy of the requests
+	ProxyURL string
+	// Headers contains custom headers sent with the http requests, as name: value
+	Headers []string
+	// RateLimiter limits the requests to the targets instead of a limiter of
+	// RateLimit requests per second created by every run
+	RateLimiter globalratelimiter.RateLimiter
+	// Logger receives the log entries and the output lines of the scan,
+	// printed with gologger by default
+	Logger logging.Logger
+}
+
+// Result contains the findings of a scan and its statistics
+type Result struct {
+	Findings []*report.Finding
+	Stats    *summary.Report
+}
+
+// Engine runs templates on targets. The engines keep no state between runs,
+// the templates being parsed again by every run.
+type Engine struct {
+	options *Options
+}
+
+// New returns an engine running the http and dns templates of the options,
+// the missing limits being set to their defaults
+func New(options *Options) (*Engine, error) {
+	if len(options.Templates) == 0 {
+		return nil, errors.New("no templates given")
+	}
+
+	config := *options
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultConcurrency
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = DefaultRateLimit
+	}
+	if config.MaxResponseSize <= 0 {
+		config.MaxResponseSize = DefaultMaxResponseSize
+	}
+
+	e := &Engine{options: &config}
+
+	// the templates are validated once
+	if _, err := e.parseTemplates(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// parseTemplates parses the templates of the engine, the payloads of the
+// requests being iterated by the parsed templates
+func (e *Engine) parseTemplates() ([]*templates.Template, error) {
+	parsed := make([]*templates.Template, 0, len(e.options.Templates))
+
+	for _, path := range e.options.Templates {
+		template, err := templates.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse template %s: %s", path, err)
+		}
+
+		if len(template.BulkRequestsHTTP)+len(template.RequestsDNS) == 0 {
+			return nil, fmt.Errorf("template %s has no http or dns requests", template.ID)
+		}
+
+		if len(template.RequestsSmuggling)+len(template.RequestsStorage)+len(template.RequestsTakeover)+len(template.RequestsAudit)+len(template.RequestsService) > 0 {
+			return nil, fmt.Errorf("template %s has requests the engine can't run", template.ID)
+		}
+
+		parsed = append(parsed, template)
+	}
+
+	return parsed, nil
+}
+
+// Run runs the templates on the targets until they are all scanned or the
+// context is done, in which case the findings found so far are returned
+// along with the error of the context.
+func (e *Engine) Run(ctx context.Context) (*Result, error) {
+	parsed, err := e.parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	scan := &scan{
+		engine:      e,
+		ctx:         ctx,
+		summary:     summary.New(),
+		report:      report.New(nil),
+		colorizer:   colorizer.NewNucleiColorizer(aurora.NewAurora(false)),
+		rateLimiter: e.options.RateLimiter,
+		budget:      budget.New(e.options.MaxRequests, 0),
+	}
+
+	if scan.rateLimiter == nil {
+		rateLimiter := globalratelimiter.New()
+		for _, target := range e.options.Targets {
+			rateLimiter.Add(target, e.options.RateLimit)
+		}
+		scan.rateLimiter = rateLimiter
+	}
+
+	for _, template := range parsed {
+		for _, request := range template.BulkRequestsHTTP {
+			if err := scan.runHTTP(template, request); err != nil {
+				return scan.result(), err
+			}
+		}
+
+		for _, request := range template.RequestsDNS {
+			scan.runDNS(template, request)
+		}
+
+		if ctx.Err() != nil {
+			return scan.result(), ctx.Err()
+		}
+	}
+
+	return scan.result(), ctx.Err()
+}
+
+// scan contains the state of a run of an engine
+type scan struct {
+	engine      *Engine
+	ctx         context.Context
+	summary     *summary.Summary
+	report      *report.Report
+	colorizer   *colorizer.NucleiColorizer
+	rateLimiter globalratelimiter.RateLimiter
+	budget      *budget.Budget
+}
+
+// result returns the findings and statistics of the scan
+func (s *scan) result() *Result {
+	stats := s.summary.Report()
+	for _, event := range s.budget.Events() {
+		stats.Events = append(stats.Events, event.String())
+	}
+
+	return &Result{Findings: s.report.Findings(), Stats: stats}
+}
+
+// runHTTP runs a http request of a template on the targets
+func (s *scan) runHTTP(template *templates.Template, request *requests.BulkHTTPRequest) error {
+	options := s.engine.options
+
+	httpExecuter, err := executer.NewHTTPExecuter(&executer.HTTPOptions{
+		Template:        template,
+		BulkHTTPRequest: request,
+		Timeout:         options.Timeout,
+		Retries:         options.Retries,
+		ProxyURL:        options.ProxyURL,
+		CustomHeaders:   options.Headers,
+		CookieReuse:     request.CookieReuse,
+		Colorizer:       s.colorizer,
+		Summary:         s.summary,
+		Report:          s.report,
+		Context:         s.ctx,
+		MaxResponseSize: options.MaxResponseSize,
+		RateLimiter:     s.rateLimiter,
+		Logger:          options.Logger,
+		Budget:          s.budget,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create executer of %s: %s", template.ID, err)
+	}
+
+	s.forTargets(func(target string) {
+		httpExecuter.ExecuteHTTP(&progress.NoOpProgress{}, target)
+	})
+
+	return nil
+}
+
+// runDNS runs a dns request of a template on the targets
+func (s *scan) runDNS(template *templates.Template, request *requests.DNSRequest) {
+	dnsExecuter := executer.NewDNSExecuter(&executer.DNSOptions{
+		Template:   template,
+		DNSRequest: request,
+		Colorizer:  *s.colorizer,
+		Summary:    s.summary,
+		Report:     s.report,
+		Logger:     s.engine.options.Logger,
+	})
+
+	s.forTargets(func(target string) {
+		dnsExecuter.ExecuteDNS(&progress.NoOpProgress{}, target)
+	})
+}
+
+// forTargets calls a function on the targets, concurrently up to the
+// concurrency of the engine, until the context is done
+func (s *scan) forTargets(run func(target string)) {
+	swg := sizedwaitgroup.New(s.engine.options.Concurrency)
+
+	for _, target := range s.engine.options.Targets {
+		if s.ctx.Err() != nil {
+			break
+		}
+
+		swg.Add()
+		go func(target string) {
+			defer swg.Done()
+			run(target)
+		}(target)
+	}
+
+	swg.Wait()
+}
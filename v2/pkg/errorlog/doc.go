@@ -0,0 +1,4 @@
+// Package errorlog records the failures of the templates on the targets,
+// classified by kind, separately from the results so that the targets
+// which couldn't be tested aren't mistaken for the ones not vulnerable.
+package errorlog
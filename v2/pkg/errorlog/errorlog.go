@@ -0,0 +1,168 @@
+package errorlog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// The kinds of the failures
+const (
+	KindTimeout    = "timeout"
+	KindDNS        = "dns"
+	KindTLS        = "tls"
+	KindConnection = "connection"
+	KindOther      = "other"
+)
+
+// kindPatterns contains the messages of the errors of each kind, the
+// errors being wrapped by the clients as text
+var kindPatterns = []struct {
+	kind     string
+	patterns []string
+}{
+	{KindTimeout, []string{"timeout", "deadline exceeded", "timed out"}},
+	{KindDNS, []string{"no such host", "server misbehaving", "lookup ", "dns"}},
+	{KindTLS, []string{"tls:", "x509:", "certificate", "handshake"}},
+	{KindConnection, []string{"connection refused", "connection reset", "broken pipe", "no route to host", "network is unreachable", "eof"}},
+}
+
+// Classify returns the kind of a failure
+func Classify(err error) string {
+	message := strings.ToLower(err.Error())
+
+	for _, kind := range kindPatterns {
+		for _, pattern := range kind.patterns {
+			if strings.Contains(message, pattern) {
+				return kind.kind
+			}
+		}
+	}
+
+	return KindOther
+}
+
+// Record is a failure of a template on a target
+type Record struct {
+	Timestamp time.Time `json:"timestamp"`
+	Template  string    `json:"template"`
+	Target    string    `json:"target"`
+	Kind      string    `json:"kind"`
+	Error     string    `json:"error"`
+}
+
+// Log counts the failures by kind and writes them to a file if any
+type Log struct {
+	mutex  sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	json   bool
+	counts map[string]int
+}
+
+// New creates a log of the failures, written to a file as json lines or
+// text when a file is specified
+func New(file string, json bool) (*Log, error) {
+	l := &Log{json: json, counts: make(map[string]int)}
+
+	if file == "" {
+		return l, nil
+	}
+
+	output, err := os.Create(file)
+	if err != nil {
+		return nil, err
+	}
+
+	l.file, l.writer = output, bufio.NewWriter(output)
+
+	return l, nil
+}
+
+// Add records the failure of a template on a target
+func (l *Log) Add(template, target string, err error) {
+	if l == nil || err == nil {
+		return
+	}
+
+	record := &Record{
+		Timestamp: time.Now(),
+		Template:  template,
+		Target:    target,
+		Kind:      Classify(err),
+		Error:     err.Error(),
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.counts[record.Kind]++
+
+	if l.writer == nil {
+		return
+	}
+
+	if l.json {
+		data, err := jsoniter.Marshal(record)
+		if err != nil {
+			return
+		}
+
+		l.writer.Write(data)     // nolint:errcheck // reported on close
+		l.writer.WriteByte('\n') // nolint:errcheck // reported on close
+
+		return
+	}
+
+	fmt.Fprintf(l.writer, "[%s] [%s] [%s] %s: %s\n", record.Timestamp.Format(time.RFC3339), record.Kind, record.Template, record.Target, record.Error)
+}
+
+// Counts returns the number of failures by kind
+func (l *Log) Counts() map[string]int {
+	if l == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	counts := make(map[string]int, len(l.counts))
+	for kind, count := range l.counts {
+		counts[kind] = count
+	}
+
+	return counts
+}
+
+// Reset clears the counts, the failures already written being kept
+func (l *Log) Reset() {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	l.counts = make(map[string]int)
+	l.mutex.Unlock()
+}
+
+// Close flushes and closes the file of the log, if any
+func (l *Log) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.writer.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+
+	return l.file.Close()
+}
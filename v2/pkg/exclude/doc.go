@@ -0,0 +1,3 @@
+// Package exclude denies templates by id, path or glob pattern
+// before they are scheduled.
+package exclude
@@ -0,0 +1,140 @@
+package exclude
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// List is a deny-list of templates. An entry is either:
+//   - a glob pattern, containing any of *?[, matched against the template
+//     ids and the trailing elements of the template paths
+//   - a directory, ending with a /, excluding the templates under it
+//   - a path, containing a / or ending with .yaml, excluding the templates
+//     whose paths end with it
+//   - a template id otherwise
+type List struct {
+	ids         map[string]struct{}
+	directories []string
+	paths       []string
+	patterns    []string
+}
+
+// New returns a deny-list of the entries
+func New(entries ...string) *List {
+	l := &List{ids: make(map[string]struct{})}
+	l.Add(entries...)
+
+	return l
+}
+
+// Add adds entries to the deny-list, the empty ones and the comments
+// starting with a # being skipped
+func (l *List) Add(entries ...string) {
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+
+		switch {
+		case entry == "" || strings.HasPrefix(entry, "#"):
+		case strings.ContainsAny(entry, "*?["):
+			l.patterns = append(l.patterns, filepath.ToSlash(entry))
+		case strings.HasSuffix(entry, "/"):
+			l.directories = append(l.directories, entry)
+		case strings.Contains(entry, "/") || strings.HasSuffix(entry, ".yaml"):
+			l.paths = append(l.paths, entry)
+		default:
+			l.ids[strings.ToLower(entry)] = struct{}{}
+		}
+	}
+}
+
+// ReadFile returns the entries of a deny-list file, one per line
+func ReadFile(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entries = append(entries, scanner.Text())
+	}
+
+	return entries, scanner.Err()
+}
+
+// Empty returns true if the deny-list has no entries
+func (l *List) Empty() bool {
+	return l == nil || len(l.ids)+len(l.directories)+len(l.paths)+len(l.patterns) == 0
+}
+
+// Path returns true if a template file is excluded by its path
+func (l *List) Path(file string) bool {
+	if l.Empty() {
+		return false
+	}
+
+	file = filepath.ToSlash(file)
+
+	for _, directory := range l.directories {
+		if strings.Contains(file, directory) {
+			return true
+		}
+	}
+
+	for _, suffix := range l.paths {
+		if strings.HasSuffix(file, suffix) {
+			return true
+		}
+	}
+
+	for _, pattern := range l.patterns {
+		if matchPath(pattern, file) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Template returns true if a parsed template is excluded by its id or path
+func (l *List) Template(id, file string) bool {
+	if l.Empty() {
+		return false
+	}
+
+	id = strings.ToLower(id)
+	if _, ok := l.ids[id]; ok {
+		return true
+	}
+
+	for _, pattern := range l.patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), id); matched {
+			return true
+		}
+	}
+
+	return l.Path(file)
+}
+
+// matchPath returns true if a pattern matches a path or any of its
+// trailing elements, so that relative patterns match absolute paths
+func matchPath(pattern, file string) bool {
+	for {
+		if matched, _ := path.Match(pattern, file); matched {
+			return true
+		}
+
+		i := strings.Index(file, "/")
+		if i == -1 {
+			return false
+		}
+
+		file = file[i+1:]
+	}
+}
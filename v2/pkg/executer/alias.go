@@ -0,0 +1,49 @@
+package executer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/resolver"
+)
+
+// aliasKey returns the key grouping the identical findings of the hosts
+// resolving to the same address: the template, the matcher, the address, the
+// port and path requested and a fingerprint of the response. It is empty when
+// the address the response was received from is unknown.
+func aliasKey(templateID, matcherName, matched string, resp *http.Response, body string) string {
+	ip := matchers.RemoteIP(resp)
+	if ip == "" && resp.Request != nil {
+		ip = resolver.IP(resp.Request.Context())
+	}
+
+	if ip == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(matched)
+	if err != nil {
+		return ""
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = parsed.Scheme
+	}
+
+	fingerprint := sha256.Sum256([]byte(strconv.Itoa(resp.StatusCode) + "\n" + body))
+
+	return strings.Join([]string{
+		templateID,
+		matcherName,
+		ip,
+		port,
+		parsed.RequestURI(),
+		hex.EncodeToString(fingerprint[:]),
+	}, " ")
+}
@@ -0,0 +1,45 @@
+package executer
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+)
+
+// attemptsKey is the context key of the attempts of a request
+type attemptsKey struct{}
+
+// withAttempts returns a copy of the request counting its attempts
+func withAttempts(req *http.Request) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptsKey{}, new(int32)))
+}
+
+// attempts returns the number of times a response's request was sent, the
+// retries included
+func attempts(resp *http.Response) int {
+	if resp.Request == nil {
+		return 1
+	}
+
+	counter, ok := resp.Request.Context().Value(attemptsKey{}).(*int32)
+	if !ok || atomic.LoadInt32(counter) == 0 {
+		return 1
+	}
+
+	return int(atomic.LoadInt32(counter))
+}
+
+// countingTransport counts the attempts of the requests sent by the retrying
+// client, the redirects excluded
+type countingTransport struct {
+	http.RoundTripper
+}
+
+// RoundTrip sends a request, counting it unless it follows a redirect
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if counter, ok := req.Context().Value(attemptsKey{}).(*int32); ok && req.Response == nil {
+		atomic.AddInt32(counter, 1)
+	}
+
+	return t.RoundTripper.RoundTrip(req)
+}
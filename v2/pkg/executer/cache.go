@@ -0,0 +1,70 @@
+package executer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	retryablehttp "github.com/projectdiscovery/retryablehttp-go"
+)
+
+// cacheVerification is the response to the request verifying that the
+// response to a request was cached
+type cacheVerification struct {
+	resp     *http.Response
+	body     string
+	headers  string
+	duration time.Duration
+}
+
+// verifyCache requests the url of a request again, without its headers and
+// body, for the matchers of the verification part to check if the response
+// to the request was cached. It is nil when the template doesn't verify
+// the cache or the verification failed.
+func (e *HTTPExecuter) verifyCache(reqURL string, request *requests.HTTPRequest) *cacheVerification {
+	if !e.bulkHTTPRequest.CacheVerify || !e.budget.Take(e.template.ID) {
+		return nil
+	}
+
+	var target string
+	if request.RawRequest != nil {
+		target = request.RawRequest.FullURL
+	}
+	if request.Request != nil {
+		target = request.Request.URL.String()
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		e.log.Host(reqURL).Verbosef("Could not verify the cache of %s: %s", target, err)
+		return nil
+	}
+	req = req.WithContext(e.ctx)
+	req.Header.Set("Accept", "*/*")
+
+	e.waitForTurn(reqURL)
+	e.summary.Request()
+
+	start := time.Now()
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		e.log.Host(reqURL).Verbosef("Could not verify the cache of %s: %s", target, err)
+		return nil
+	}
+
+	data, err := e.readBody(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		e.log.Host(reqURL).Verbosef("Could not verify the cache of %s: %s", target, err)
+		return nil
+	}
+
+	return &cacheVerification{
+		resp:     resp,
+		body:     string(data),
+		headers:  headersToString(resp.Header),
+		duration: time.Since(start),
+	}
+}
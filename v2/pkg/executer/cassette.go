@@ -0,0 +1,23 @@
+package executer
+
+import (
+	"fmt"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+)
+
+// replaying returns true if the responses are replayed from the cassette
+// of the template instead of being requested
+func (e *HTTPExecuter) replaying() bool {
+	return e.cassette != nil && !e.recording
+}
+
+// cassetteKey identifies a request in the cassettes by its position in the
+// template, its method and url, the random values of the requests left out
+func cassetteKey(request *requests.HTTPRequest) string {
+	if request.RawRequest != nil {
+		return fmt.Sprintf("%d %s %s", request.Index, request.RawRequest.Method, request.RawRequest.FullURL)
+	}
+
+	return fmt.Sprintf("%d %s %s", request.Index, request.Request.Method, request.Request.URL.String())
+}
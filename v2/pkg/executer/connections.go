@@ -0,0 +1,130 @@
+package executer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+)
+
+// reusedConnection is a connection kept open between the requests to a host
+type reusedConnection struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// connectionPool keeps a single connection per target for the requests
+// reusing the connection of the previous request.
+type connectionPool struct {
+	mutex       sync.Mutex
+	connections map[string]*reusedConnection
+}
+
+// newConnectionPool creates a new empty connection pool
+func newConnectionPool() *connectionPool {
+	return &connectionPool{connections: make(map[string]*reusedConnection)}
+}
+
+// get returns the connection of a target if any
+func (c *connectionPool) get(reqURL string) *reusedConnection {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.connections[reqURL]
+}
+
+// set stores the connection of a target
+func (c *connectionPool) set(reqURL string, connection *reusedConnection) {
+	c.mutex.Lock()
+	c.connections[reqURL] = connection
+	c.mutex.Unlock()
+}
+
+// close closes and removes the connection of a target
+func (c *connectionPool) close(reqURL string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if connection, ok := c.connections[reqURL]; ok {
+		connection.conn.Close()
+		delete(c.connections, reqURL)
+	}
+}
+
+// doReusedHTTP writes a dumped request on the connection of the previous
+// request to the target, opening it for the first request.
+func (e *HTTPExecuter) doReusedHTTP(reqURL string, data []byte) (*http.Response, error) {
+	connection := e.connections.get(reqURL)
+	if connection == nil {
+		conn, err := e.dialTarget(context.Background(), reqURL)
+		if err != nil {
+			return nil, err
+		}
+
+		connection = &reusedConnection{conn: conn, reader: bufio.NewReader(conn)}
+		e.connections.set(reqURL, connection)
+	}
+
+	if e.timeout > 0 {
+		connection.conn.SetDeadline(time.Now().Add(e.timeout)) // nolint:errcheck // best effort
+	}
+
+	if _, err := connection.conn.Write(data); err != nil {
+		e.connections.close(reqURL)
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(connection.reader, nil)
+	if err != nil {
+		e.connections.close(reqURL)
+		return nil, err
+	}
+
+	// the body must be fully read before the next request on the connection
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		e.connections.close(reqURL)
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	e.setConnectionState(context.Background(), resp, connection.conn, reqURL)
+
+	if resp.Close {
+		e.connections.close(reqURL)
+	}
+
+	return resp, nil
+}
+
+// crlf converts the line endings of raw request data to "\r\n", the data
+// already converted being left as is when the request is sent again
+func crlf(data string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(data, "\r\n", "\n"), "\n", "\r\n")
+}
+
+// dumpWireRequest returns the bytes of a request as sent on the wire
+func dumpWireRequest(request *requests.HTTPRequest, reqURL string) ([]byte, error) {
+	switch {
+	case request.Exact:
+		return []byte(request.RawRequest.Raw), nil
+	case request.Request != nil:
+		return httputil.DumpRequestOut(request.Request.Request, true)
+	default:
+		// burp uses "\r\n" as new line character
+		if !request.RawRequest.BodyEncoded {
+			request.RawRequest.Data = crlf(request.RawRequest.Data)
+		}
+		return requests.Dump(request, reqURL)
+	}
+}
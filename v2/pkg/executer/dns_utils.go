@@ -1,6 +1,16 @@
 package executer
 
-import "net/url"
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	retryabledns "github.com/projectdiscovery/retryabledns"
+)
 
 // isURL tests a string to determine if it is a well-structured url or not.
 func isURL(toTest string) bool {
@@ -28,3 +38,125 @@ func extractDomain(theURL string) string {
 
 	return hostname
 }
+
+// zoneTransferTimeout is the timeout for each zone transfer attempt
+const zoneTransferTimeout = 10 * time.Second
+
+// transferZone attempts an AXFR of the queried zone against each of its
+// nameservers, returning the records of the first successful transfer
+// along with the nameservers which allowed it.
+func transferZone(client *retryabledns.Client, req *dns.Msg) (*dns.Msg, []string, error) {
+	zone := req.Question[0].Name
+
+	nsReq := new(dns.Msg)
+	nsReq.Id = dns.Id()
+	nsReq.RecursionDesired = true
+	nsReq.Question = []dns.Question{{Name: zone, Qtype: dns.TypeNS, Qclass: dns.ClassINET}}
+
+	nsResp, err := client.Do(nsReq)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nameservers []string
+
+	for _, answer := range nsResp.Answer {
+		if ns, ok := answer.(*dns.NS); ok {
+			nameservers = append(nameservers, strings.TrimSuffix(ns.Ns, "."))
+		}
+	}
+
+	if len(nameservers) == 0 {
+		return nil, nil, fmt.Errorf("no nameservers found for %s", zone)
+	}
+
+	resp := new(dns.Msg)
+	resp.Id = req.Id
+	resp.Response = true
+	resp.Question = req.Question
+	resp.Rcode = dns.RcodeRefused
+
+	var transferred []string
+
+	for _, nameserver := range nameservers {
+		transfer := &dns.Transfer{DialTimeout: zoneTransferTimeout, ReadTimeout: zoneTransferTimeout}
+
+		axfr := new(dns.Msg)
+		axfr.SetAxfr(zone)
+
+		envelopes, err := transfer.In(axfr, net.JoinHostPort(nameserver, "53"))
+		if err != nil {
+			continue
+		}
+
+		var records []dns.RR
+
+		failed := false
+
+		// the channel must be drained even if the transfer fails
+		for envelope := range envelopes {
+			if envelope.Error != nil {
+				failed = true
+				continue
+			}
+
+			records = append(records, envelope.RR...)
+		}
+
+		if failed || len(records) == 0 {
+			continue
+		}
+
+		if len(transferred) == 0 {
+			resp.Answer = records
+			resp.Rcode = dns.RcodeSuccess
+		}
+
+		transferred = append(transferred, nameserver)
+	}
+
+	return resp, transferred, nil
+}
+
+// detectWildcard queries a random name in the zone of the request and
+// reports whether the zone has a wildcard record and whether the response
+// only contains the records the wildcard resolves to.
+func detectWildcard(client *retryabledns.Client, req, resp *dns.Msg) (wildcard, wildcardAnswer bool, err error) {
+	question := req.Question[0]
+
+	probe := new(dns.Msg)
+	probe.Id = dns.Id()
+	probe.RecursionDesired = req.RecursionDesired
+	probe.Question = []dns.Question{{Name: requests.WildcardName(question.Name), Qtype: question.Qtype, Qclass: question.Qclass}}
+
+	probeResp, err := client.Do(probe)
+	if err != nil {
+		return false, false, err
+	}
+
+	if len(probeResp.Answer) == 0 {
+		return false, false, nil
+	}
+
+	wildcardRecords := make(map[string]struct{}, len(probeResp.Answer))
+	for _, answer := range probeResp.Answer {
+		wildcardRecords[recordData(answer)] = struct{}{}
+	}
+
+	if len(resp.Answer) == 0 {
+		return true, false, nil
+	}
+
+	for _, answer := range resp.Answer {
+		if _, ok := wildcardRecords[recordData(answer)]; !ok {
+			return true, false, nil
+		}
+	}
+
+	return true, true, nil
+}
+
+// recordData returns the data of a record without its owner name and ttl
+func recordData(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
@@ -0,0 +1,118 @@
+package executer
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+)
+
+// exactBody closes the connection of an exact request along with the response body
+type exactBody struct {
+	io.ReadCloser
+	conn net.Conn
+}
+
+// Close closes the response body and the underlying connection
+func (b *exactBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.conn.Close()
+
+	return err
+}
+
+// doExactHTTP writes the raw bytes of a request on a new connection to the
+// target and parses the response.
+func (e *HTTPExecuter) doExactHTTP(ctx context.Context, reqURL, raw string) (*http.Response, error) {
+	conn, err := e.dialTarget(ctx, reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Write([]byte(raw)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	resp.Body = &exactBody{ReadCloser: resp.Body, conn: conn}
+	e.setConnectionState(ctx, resp, conn, reqURL)
+
+	return resp, nil
+}
+
+// setConnectionState sets the tls state of the connection a response was
+// read from, and its target verifying the certificates for the dsl
+func (e *HTTPExecuter) setConnectionState(ctx context.Context, resp *http.Response, conn net.Conn, reqURL string) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		resp.TLS = &state
+	}
+
+	if target, err := url.Parse(reqURL); err == nil {
+		resp.Request = matchers.WithTrustStore((&http.Request{URL: target}).WithContext(ctx), e.trust)
+	}
+}
+
+// dialsRawRequests returns true if the unsafe and pipelined requests must be
+// sent on the connections of the executer, through the proxies, with the
+// tls configuration of the scan or to the addresses chosen for the hosts
+func (e *HTTPExecuter) dialsRawRequests() bool {
+	return e.tunnel != nil || e.trust.Verify() || e.tlsProfile != nil || e.retryIPs || e.scanAllIPs || e.resolver.Pinned()
+}
+
+// dialTarget opens a plain or tls connection to the host of a URL, or to the
+// address chosen for it in the context, tunneled through the proxy if any
+func (e *HTTPExecuter) dialTarget(ctx context.Context, reqURL string) (net.Conn, error) {
+	parsed, err := url.Parse(reqURL)
+	if err != nil {
+		return nil, err
+	}
+
+	address := parsed.Host
+	if parsed.Port() == "" {
+		if parsed.Scheme == "https" {
+			address = net.JoinHostPort(parsed.Hostname(), "443")
+		} else {
+			address = net.JoinHostPort(parsed.Hostname(), "80")
+		}
+	}
+
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	conn, err := e.resolver.Dial(e.tunnel.DialContext)(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(e.timeout)) // nolint:errcheck // best effort
+	}
+
+	if parsed.Scheme == "https" {
+		tlsConn := tls.Client(conn, e.tlsProfile.Apply(e.trust.Config(parsed.Hostname())))
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		conn = tlsConn
+	}
+
+	return conn, nil
+}
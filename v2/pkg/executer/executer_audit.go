@@ -0,0 +1,203 @@
+package executer
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/globalratelimiter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/headeraudit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// defaultAuditTimeout is the default timeout in seconds of the audit http requests
+const defaultAuditTimeout = 10
+
+// AuditExecuter is a client for auditing the security headers
+// of a target against the policy of a template.
+type AuditExecuter struct {
+	coloredOutput bool
+	jsonOutput    bool
+	template      *templates.Template
+	auditRequest  *requests.AuditRequest
+	profile       string
+	auditor       *headeraudit.Auditor
+	writer        *bufwriter.Writer
+	scheduler     *scheduler.Scheduler
+	dryRun        bool
+	format        FormatOptions
+	summary       *summary.Summary
+	report        *report.Report
+	errorLog      *errorlog.Log
+	ignoreList    *ignore.List
+	log           *logging.Entry
+	rateLimiter   globalratelimiter.RateLimiter
+	budget        *budget.Budget
+
+	colorizer   colorizer.NucleiColorizer
+	decolorizer *regexp.Regexp
+}
+
+// AuditOptions contains configuration options for the audit executer.
+type AuditOptions struct {
+	ColoredOutput bool
+	JSON          bool
+	Template      *templates.Template
+	AuditRequest  *requests.AuditRequest
+	// Profile overrides the policy profile of the template
+	Profile    string
+	Writer     *bufwriter.Writer
+	Scheduler  *scheduler.Scheduler
+	DryRun     bool
+	Format     FormatOptions
+	Summary    *summary.Summary
+	Report     *report.Report
+	ErrorLog   *errorlog.Log
+	IgnoreList *ignore.List
+	Logger     logging.Logger
+	// Connector provides the transport of the audit requests, with the
+	// proxies and the tls configuration of the scan
+	Connector *connector.Connector
+	// RateLimiter limits the requests to the targets, the limiter shared by
+	// the executers without one
+	RateLimiter globalratelimiter.RateLimiter
+	// Budget caps the requests sent by the template
+	Budget *budget.Budget
+
+	Colorizer   colorizer.NucleiColorizer
+	Decolorizer *regexp.Regexp
+}
+
+// NewAuditExecuter creates a new audit executer from a template
+// and an audit request.
+func NewAuditExecuter(options *AuditOptions) (*AuditExecuter, error) {
+	profile := options.AuditRequest.Profile
+	if options.Profile != "" {
+		profile = options.Profile
+	}
+	if profile == "" {
+		profile = headeraudit.DefaultProfile
+	}
+
+	checks, err := headeraudit.Policy(profile, options.AuditRequest.Checks, options.AuditRequest.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := options.AuditRequest.Timeout
+	if timeout <= 0 {
+		timeout = defaultAuditTimeout
+	}
+
+	auditor := headeraudit.New(&headeraudit.Options{
+		Checks:     checks,
+		HSTSMaxAge: options.AuditRequest.HSTSMaxAge,
+		Timeout:    time.Duration(timeout) * time.Second,
+		Transport:  options.Connector.Transport(),
+		OnRequest:  options.Summary.Request,
+	})
+
+	rateLimiter := options.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = globalratelimiter.Default()
+	}
+
+	return &AuditExecuter{
+		coloredOutput: options.ColoredOutput,
+		jsonOutput:    options.JSON,
+		template:      options.Template,
+		auditRequest:  options.AuditRequest,
+		profile:       profile,
+		auditor:       auditor,
+		writer:        options.Writer,
+		scheduler:     options.Scheduler,
+		dryRun:        options.DryRun,
+		format:        options.Format,
+		summary:       options.Summary,
+		report:        options.Report,
+		errorLog:      options.ErrorLog,
+		ignoreList:    options.IgnoreList,
+		log:           logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		rateLimiter:   rateLimiter,
+		budget:        options.Budget,
+		colorizer:     options.Colorizer,
+		decolorizer:   options.Decolorizer,
+	}, nil
+}
+
+// ExecuteAudit audits the security headers of the urls of a target,
+// reporting the failed checks of the policy
+func (e *AuditExecuter) ExecuteAudit(p progress.IProgress, reqURL string) (result *Result) {
+	result = &Result{}
+
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
+	remaining := e.auditRequest.GetRequestCount()
+
+	for _, URL := range e.auditRequest.GetURLs(reqURL) {
+		// in dry run mode the audit is only printed
+		if e.dryRun {
+			e.log.Host(reqURL).Silentf("[%s] [%s] %s %s\n", e.template.ID, "audit", e.profile, URL)
+			p.Update()
+			remaining--
+
+			continue
+		}
+
+		if !e.budget.Take(e.template.ID) {
+			e.log.Host(reqURL).Verbosef("Skipped the remaining requests to %s, the request budget was exceeded", reqURL)
+			p.Drop(remaining)
+
+			return
+		}
+
+		e.rateLimiter.Take(reqURL)
+		e.scheduler.Wait(hostFromURL(reqURL))
+
+		items, err := e.auditor.Audit(URL)
+		if err != nil {
+			result.Error = errors.Wrap(err, "could not audit headers")
+			p.Drop(remaining)
+
+			return
+		}
+
+		p.Update()
+		remaining--
+
+		for _, item := range items {
+			if item.Passed && !e.auditRequest.ReportPassed {
+				continue
+			}
+
+			e.writeOutputAudit(URL, item)
+			if !item.Passed {
+				result.GotResults = true
+			}
+		}
+	}
+
+	e.log.Host(reqURL).Verbosef("Sent audit requests to %s", reqURL)
+
+	return result
+}
+
+// Close closes the audit executer for a template.
+func (e *AuditExecuter) Close() {}
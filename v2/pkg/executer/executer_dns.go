@@ -1,17 +1,23 @@
 package executer
 
 import (
-	"fmt"
-	"os"
 	"regexp"
+	"strings"
 
+	"github.com/miekg/dns"
 	"github.com/pkg/errors"
-	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/debugwriter"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
 	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
 	retryabledns "github.com/projectdiscovery/retryabledns"
 )
@@ -28,6 +34,15 @@ type DNSExecuter struct {
 	template      *templates.Template
 	dnsRequest    *requests.DNSRequest
 	writer        *bufwriter.Writer
+	scheduler     *scheduler.Scheduler
+	dryRun        bool
+	debugWriter   *debugwriter.Writer
+	format        FormatOptions
+	summary       *summary.Summary
+	report        *report.Report
+	errorLog      *errorlog.Log
+	ignoreList    *ignore.List
+	log           *logging.Entry
 
 	colorizer   colorizer.NucleiColorizer
 	decolorizer *regexp.Regexp
@@ -50,6 +65,15 @@ type DNSOptions struct {
 	Template      *templates.Template
 	DNSRequest    *requests.DNSRequest
 	Writer        *bufwriter.Writer
+	Scheduler     *scheduler.Scheduler
+	DryRun        bool
+	DebugWriter   *debugwriter.Writer
+	Format        FormatOptions
+	Summary       *summary.Summary
+	Report        *report.Report
+	ErrorLog      *errorlog.Log
+	IgnoreList    *ignore.List
+	Logger        logging.Logger
 
 	Colorizer   colorizer.NucleiColorizer
 	Decolorizer *regexp.Regexp
@@ -71,6 +95,15 @@ func NewDNSExecuter(options *DNSOptions) *DNSExecuter {
 		coloredOutput: options.ColoredOutput,
 		colorizer:     options.Colorizer,
 		decolorizer:   options.Decolorizer,
+		scheduler:     options.Scheduler,
+		dryRun:        options.DryRun,
+		debugWriter:   options.DebugWriter,
+		format:        options.Format,
+		summary:       options.Summary,
+		report:        options.Report,
+		errorLog:      options.ErrorLog,
+		ignoreList:    options.IgnoreList,
+		log:           logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
 	}
 
 	return executer
@@ -78,6 +111,13 @@ func NewDNSExecuter(options *DNSOptions) *DNSExecuter {
 
 // ExecuteDNS executes the DNS request on a URL
 func (e *DNSExecuter) ExecuteDNS(p progress.IProgress, reqURL string) (result Result) {
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
 	// Parse the URL and return domain if URL.
 	var domain string
 	if isURL(reqURL) {
@@ -96,40 +136,95 @@ func (e *DNSExecuter) ExecuteDNS(p progress.IProgress, reqURL string) (result Re
 		return
 	}
 
+	var debugID uint64
+
 	if e.debug {
-		gologger.Infof("Dumped DNS request for %s (%s)\n\n", reqURL, e.template.ID)
-		fmt.Fprintf(os.Stderr, "%s\n", compiledRequest.String())
+		debugID = e.debugWriter.NextID()
+		e.debugWriter.Dump(debugID, "DNS request", e.template.ID, reqURL, &debugwriter.Meta{TemplatePath: e.template.GetPath()}, []byte(compiledRequest.String()))
 	}
 
-	// Send the request to the target servers
-	resp, err := e.dnsClient.Do(compiledRequest)
-	if err != nil {
-		result.Error = errors.Wrap(err, "could not send dns request")
-
-		p.Drop(1)
+	// in dry run mode the request is only printed
+	if e.dryRun {
+		e.log.Host(reqURL).Silentf("[%s] [%s] %s\n%s\n", e.template.ID, "dns", reqURL, compiledRequest.String())
+		p.Update()
 
 		return
 	}
 
+	e.scheduler.Wait(hostFromURL(reqURL))
+	e.summary.Request()
+
+	// values of the response available to dsl matchers
+	data := make(map[string]interface{})
+
+	var resp *dns.Msg
+
+	if e.dnsRequest.IsZoneTransfer() {
+		var nameservers []string
+
+		resp, nameservers, err = transferZone(e.dnsClient, compiledRequest)
+		if err != nil {
+			result.Error = errors.Wrap(err, "could not transfer dns zone")
+
+			p.Drop(1)
+
+			return
+		}
+
+		data["axfr"] = len(nameservers) > 0
+		data["axfr_nameservers"] = strings.Join(nameservers, " ")
+	} else {
+		// Send the request to the target servers
+		resp, err = e.dnsClient.Do(compiledRequest)
+		if err != nil {
+			result.Error = errors.Wrap(err, "could not send dns request")
+
+			p.Drop(1)
+
+			return
+		}
+	}
+
 	p.Update()
 
-	gologger.Verbosef("Sent for [%s] to %s\n", "dns-request", e.template.ID, reqURL)
+	e.log.Host(reqURL).Verbosef("Sent dns request to %s", reqURL)
 
 	if e.debug {
-		gologger.Infof("Dumped DNS response for %s (%s)\n\n", reqURL, e.template.ID)
-		fmt.Fprintf(os.Stderr, "%s\n", resp.String())
+		e.debugWriter.Dump(debugID, "DNS response", e.template.ID, reqURL, &debugwriter.Meta{TemplatePath: e.template.GetPath()}, []byte(resp.String()))
+	}
+
+	if e.dnsRequest.Wildcard {
+		e.summary.Request()
+
+		wildcard, wildcardAnswer, err := detectWildcard(e.dnsClient, compiledRequest, resp)
+		if err != nil {
+			e.log.Host(domain).Warningf("Could not detect wildcard for %s: %s", domain, err)
+		}
+
+		data["wildcard"] = wildcard
+		data["wildcard_answer"] = wildcardAnswer
 	}
 
 	matcherCondition := e.dnsRequest.GetMatchersCondition()
 
-	for _, matcher := range e.dnsRequest.Matchers {
+	// matchers matched with the weighted condition
+	var weightedMatches []bool
+	if matcherCondition == matchers.WeightedCondition {
+		weightedMatches = make([]bool, len(e.dnsRequest.Matchers))
+	}
+
+	for i, matcher := range e.dnsRequest.Matchers {
 		// Check if the matcher matched
-		if !matcher.MatchDNS(resp) {
+		if !matcher.MatchDNS(resp, data) {
 			// If the condition is AND we haven't matched, return.
 			if matcherCondition == matchers.ANDCondition {
 				return
 			}
 		} else {
+			if weightedMatches != nil {
+				weightedMatches[i] = true
+			}
+
 			// If the matcher has matched, and its an OR
 			// write the first output then move to next matcher.
 			if matcherCondition == matchers.ORCondition && len(e.dnsRequest.Extractors) == 0 {
@@ -139,6 +234,11 @@ func (e *DNSExecuter) ExecuteDNS(p progress.IProgress, reqURL string) (result Re
 		}
 	}
 
+	// The weights of the matched matchers must reach the threshold
+	if matcherCondition == matchers.WeightedCondition && matchers.WeightedScore(e.dnsRequest.Matchers, weightedMatches) < e.dnsRequest.MatchersThreshold {
+		return
+	}
+
 	// All matchers have successfully completed so now start with the
 	// next task which is extraction of input from matchers.
 	var extractorResults []string
@@ -151,9 +251,9 @@ func (e *DNSExecuter) ExecuteDNS(p progress.IProgress, reqURL string) (result Re
 		}
 	}
 
-	// Write a final string of output if matcher type is
-	// AND or if we have extractors for the mechanism too.
-	if len(e.dnsRequest.Extractors) > 0 || matcherCondition == matchers.ANDCondition {
+	// Write a final string of output if matcher type is AND, weighted
+	// or if we have extractors for the mechanism too.
+	if len(e.dnsRequest.Extractors) > 0 || matcherCondition == matchers.ANDCondition || matcherCondition == matchers.WeightedCondition {
 		e.writeOutputDNS(domain, compiledRequest, resp, nil, extractorResults)
 
 		result.GotResults = true
@@ -8,24 +8,49 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
-	"net/http/cookiejar"
 	"net/http/httputil"
 	"net/url"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
-	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/debugwriter"
 	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/attribution"
+	"github.com/projectdiscovery/nuclei/v2/pkg/autothrottle"
+	"github.com/projectdiscovery/nuclei/v2/pkg/baseline"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cache"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cassette"
 	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/cookies"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/globalratelimiter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/metrics"
+	"github.com/projectdiscovery/nuclei/v2/pkg/oob"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/resolver"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scancontext"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/screenshot"
+	"github.com/projectdiscovery/nuclei/v2/pkg/secrets"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/targets"
 	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tlsprofile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/trust"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tunnel"
+	"github.com/projectdiscovery/nuclei/v2/pkg/useragent"
+	"github.com/projectdiscovery/nuclei/v2/pkg/vulndb"
 	"github.com/projectdiscovery/rawhttp"
 	"github.com/projectdiscovery/retryablehttp-go"
 	"github.com/remeh/sizedwaitgroup"
@@ -51,11 +76,50 @@ type HTTPExecuter struct {
 	bulkHTTPRequest *requests.BulkHTTPRequest
 	writer          *bufwriter.Writer
 	customHeaders   requests.CustomHeaders
-	CookieJar       *cookiejar.Jar
+	CookieJar       *cookies.Jar
 
 	colorizer        colorizer.NucleiColorizer
 	decolorizer      *regexp.Regexp
 	stopAtFirstMatch bool
+	scheduler        *scheduler.Scheduler
+	autoThrottle     *autothrottle.AutoThrottle
+	responseCache    *cache.Cache
+	dryRun           bool
+	debugWriter      *debugwriter.Writer
+	format           FormatOptions
+	summary          *summary.Summary
+	report           *report.Report
+	errorLog         *errorlog.Log
+	ignoreList       *ignore.List
+	log              *logging.Entry
+	verifyMatches    int
+	screenshotter    *screenshot.Screenshotter
+	credentials      *targets.Credentials
+	timeout          time.Duration
+	connections      *connectionPool
+	baselines        *baseline.Baselines
+	history          *responseHistory
+	recordHistory    bool
+	vulnDB           *vulndb.Database
+	scanContext      *scancontext.Context
+	contextValues    []string
+	tunnel           *tunnel.Dialer
+	trust            *trust.Store
+	tlsProfile       *tlsprofile.Profile
+	resolver         *resolver.Resolver
+	retryIPs         bool
+	scanAllIPs       bool
+	dedupAliases     bool
+	attribution      *attribution.Attribution
+	ctx              context.Context
+	maxResponseSize  int64
+	rateLimiter      globalratelimiter.RateLimiter
+	metrics          *metrics.Metrics
+	budget           *budget.Budget
+	oob              *oob.Client
+	secrets          *secrets.Scanner
+	cassette         *cassette.Cassette
+	recording        bool
 }
 
 // HTTPOptions contains configuration options for the HTTP executer.
@@ -64,6 +128,7 @@ type HTTPOptions struct {
 	JSON             bool
 	JSONRequests     bool
 	CookieReuse      bool
+	Cookies          *cookies.Options
 	ColoredOutput    bool
 	Template         *templates.Template
 	BulkHTTPRequest  *requests.BulkHTTPRequest
@@ -72,11 +137,56 @@ type HTTPOptions struct {
 	Retries          int
 	ProxyURL         string
 	ProxySocksURL    string
+	ProxyAuth        string
 	CustomHeaders    requests.CustomHeaders
-	CookieJar        *cookiejar.Jar
+	CookieJar        *cookies.Jar
 	Colorizer        *colorizer.NucleiColorizer
 	Decolorizer      *regexp.Regexp
 	StopAtFirstMatch bool
+	Scheduler        *scheduler.Scheduler
+	AutoThrottle     *autothrottle.AutoThrottle
+	ResponseCache    *cache.Cache
+	DryRun           bool
+	DebugWriter      *debugwriter.Writer
+	Format           FormatOptions
+	Summary          *summary.Summary
+	Report           *report.Report
+	ErrorLog         *errorlog.Log
+	IgnoreList       *ignore.List
+	Logger           logging.Logger
+	VerifyMatches    int
+	PayloadSampling  *generators.Sampling
+	Screenshotter    *screenshot.Screenshotter
+	Credentials      *targets.Credentials
+	Baselines        *baseline.Baselines
+	VulnDB           *vulndb.Database
+	ScanContext      *scancontext.Context
+	ContextValues    []string
+	TrustStore       *trust.Store
+	TLSProfile       *tlsprofile.Profile
+	Resolver         *resolver.Resolver
+	RetryIPs         bool
+	ScanAllIPs       bool
+	Cassettes        *cassette.Library
+	DedupAliases     bool
+	Attribution      *attribution.Attribution
+	UserAgents       *useragent.Picker
+	// Context cancels the requests of the executer, none by default
+	Context context.Context
+	// MaxResponseSize bounds the bytes read of the response bodies
+	MaxResponseSize int64
+	// RateLimiter limits the requests to the targets, the limiter shared by
+	// the executers without one
+	RateLimiter globalratelimiter.RateLimiter
+	// Metrics collects the sizes of the responses of the template
+	Metrics *metrics.Metrics
+	// Budget caps the requests sent by the template
+	Budget *budget.Budget
+	// OOB collects the out-of-band interactions of the requests
+	OOB *oob.Client
+	// Secrets detects the secrets leaked by the responses, independently
+	// of the matchers of the template
+	Secrets *secrets.Scanner
 }
 
 // NewHTTPExecuter creates a new HTTP executer from a template
@@ -94,24 +204,76 @@ func NewHTTPExecuter(options *HTTPOptions) (*HTTPExecuter, error) {
 		return nil, err
 	}
 
+	// the raw requests are tunneled through the proxies by the executer
+	proxyTunnel, err := tunnel.New(&tunnel.Options{
+		ProxyURL: options.ProxyURL,
+		Auth:     options.ProxyAuth,
+		SocksURL: options.ProxySocksURL,
+		Timeout:  time.Duration(options.Timeout) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the template can enforce the chains and trust its own roots
+	trustStore, err := options.TrustStore.With(options.BulkHTTPRequest.TLSVerify, options.BulkHTTPRequest.TLSCABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.BulkHTTPRequest.HTTP3 {
+		if newHTTP3Transport == nil {
+			return nil, errors.New("http3 requests need nuclei built with the http3 tag")
+		}
+
+		if options.ProxyURL != "" || options.ProxySocksURL != "" {
+			return nil, errors.New("http3 requests can't be proxied")
+		}
+	}
+
+	tlsProfile, err := options.TLSProfile.With(options.BulkHTTPRequest.TLSMinVersion, options.BulkHTTPRequest.TLSMaxVersion, options.BulkHTTPRequest.TLSCipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	// the responses of the template are recorded or replayed
+	recorded, err := options.Cassettes.Cassette(options.Template.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the HTTP Client
-	client := makeHTTPClient(proxyURL, options)
+	client := makeHTTPClient(proxyURL, proxyTunnel, trustStore, tlsProfile, options)
 	// nolint:bodyclose // false positive there is no body to close yet
 	client.CheckRetry = retryablehttp.HostSprayRetryPolicy()
 
+	// the templates get jars of their own, seeded with the imported cookies
 	if options.CookieJar != nil {
 		client.HTTPClient.Jar = options.CookieJar
-	} else if options.CookieReuse {
-		jar, err := cookiejar.New(nil)
-		if err != nil {
-			return nil, err
-		}
+	} else if jar := cookies.NewJar(options.Cookies, options.CookieReuse); jar != nil {
 		client.HTTPClient.Jar = jar
 	}
 
+	if options.PayloadSampling != nil {
+		options.BulkHTTPRequest.SetPayloadSampling(options.PayloadSampling)
+	}
+
+	options.BulkHTTPRequest.SetUserAgents(options.UserAgents)
+	options.BulkHTTPRequest.SetOOB(options.OOB)
+
 	// initiate raw http client
 	rawClient := rawhttp.NewClient(rawhttp.DefaultOptions)
 
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	rateLimiter := options.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = globalratelimiter.Default()
+	}
+
 	executer := &HTTPExecuter{
 		debug:            options.Debug,
 		jsonOutput:       options.JSON,
@@ -127,6 +289,45 @@ func NewHTTPExecuter(options *HTTPOptions) (*HTTPExecuter, error) {
 		colorizer:        *options.Colorizer,
 		decolorizer:      options.Decolorizer,
 		stopAtFirstMatch: options.StopAtFirstMatch,
+		scheduler:        options.Scheduler,
+		autoThrottle:     options.AutoThrottle,
+		responseCache:    options.ResponseCache,
+		dryRun:           options.DryRun,
+		debugWriter:      options.DebugWriter,
+		format:           options.Format,
+		summary:          options.Summary,
+		report:           options.Report,
+		errorLog:         options.ErrorLog,
+		ignoreList:       options.IgnoreList,
+		log:              logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		verifyMatches:    options.VerifyMatches,
+		screenshotter:    options.Screenshotter,
+		credentials:      options.Credentials,
+		timeout:          time.Duration(options.Timeout) * time.Second,
+		connections:      newConnectionPool(),
+		baselines:        options.Baselines,
+		history:          newResponseHistory(),
+		recordHistory:    hasDiffMatchers(options.BulkHTTPRequest.Matchers),
+		vulnDB:           options.VulnDB,
+		scanContext:      options.ScanContext,
+		contextValues:    options.ContextValues,
+		tunnel:           proxyTunnel,
+		trust:            trustStore,
+		tlsProfile:       tlsProfile,
+		resolver:         options.Resolver,
+		retryIPs:         options.RetryIPs,
+		scanAllIPs:       options.ScanAllIPs,
+		dedupAliases:     options.DedupAliases,
+		attribution:      options.Attribution,
+		ctx:              ctx,
+		maxResponseSize:  options.MaxResponseSize,
+		rateLimiter:      rateLimiter,
+		metrics:          options.Metrics,
+		budget:           options.Budget,
+		oob:              options.OOB,
+		secrets:          options.Secrets,
+		cassette:         recorded,
+		recording:        options.Cassettes.Recording(),
 	}
 
 	return executer, nil
@@ -135,7 +336,7 @@ func NewHTTPExecuter(options *HTTPOptions) (*HTTPExecuter, error) {
 func (e *HTTPExecuter) ExecuteParallelHTTP(p progress.IProgress, reqURL string) (result Result) {
 	result.Matches = make(map[string]interface{})
 	result.Extractions = make(map[string]interface{})
-	dynamicvalues := make(map[string]interface{})
+	dynamicvalues := e.scanContext.Values(reqURL)
 
 	// verify if the URL is already being processed
 	if e.bulkHTTPRequest.HasGenerator(reqURL) {
@@ -148,27 +349,41 @@ func (e *HTTPExecuter) ExecuteParallelHTTP(p progress.IProgress, reqURL string)
 	// Workers that keeps enqueuing new requests
 	maxWorkers := e.bulkHTTPRequest.Threads
 	swg := sizedwaitgroup.New(maxWorkers)
-	for e.bulkHTTPRequest.Next(reqURL) && !result.Done {
-		request, err := e.bulkHTTPRequest.MakeHTTPRequest(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
+	for e.bulkHTTPRequest.Next(reqURL) && !result.stopped() && e.ctx.Err() == nil && e.withinBudget(p, reqURL, remaining) {
+		httpRequests, err := e.bulkHTTPRequest.MakeHTTPRequests(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
 		if err != nil {
-			result.Error = err
+			result.fail(e.failed(reqURL, err))
 			p.Drop(remaining)
-		} else {
+		}
+
+		// the injected copies of a request are taken from the budget too
+		for i, request := range httpRequests {
+			if i > 0 && !e.withinBudget(p, reqURL, remaining) {
+				break
+			}
+
 			swg.Add()
 			go func(httpRequest *requests.HTTPRequest) {
 				defer swg.Done()
 
-				globalratelimiter.Take(reqURL)
+				e.waitForTurn(reqURL)
 
 				// If the request was built correctly then execute it
-				err = e.handleHTTP(reqURL, httpRequest, dynamicvalues, &result)
-				if err != nil {
-					result.Error = errors.Wrap(err, "could not handle http request")
+				if err := e.handleHTTP(reqURL, httpRequest, dynamicvalues, &result); err != nil {
+					result.fail(e.failed(reqURL, errors.Wrap(err, "could not handle http request")))
 					p.Drop(remaining)
 				}
+
+				// Stop enqueuing new payloads at first valid result if requested by the template
+				if e.bulkHTTPRequest.StopAtFirstMatch {
+					result.Lock()
+					result.Done = result.GotResults
+					result.Unlock()
+				}
 			}(request)
 		}
 		e.bulkHTTPRequest.Increment(reqURL)
+		e.attackDelay()
 	}
 
 	swg.Wait()
@@ -179,7 +394,7 @@ func (e *HTTPExecuter) ExecuteParallelHTTP(p progress.IProgress, reqURL string)
 func (e *HTTPExecuter) ExecuteTurboHTTP(p progress.IProgress, reqURL string) (result Result) {
 	result.Matches = make(map[string]interface{})
 	result.Extractions = make(map[string]interface{})
-	dynamicvalues := make(map[string]interface{})
+	dynamicvalues := e.scanContext.Values(reqURL)
 
 	// verify if the URL is already being processed
 	if e.bulkHTTPRequest.HasGenerator(reqURL) {
@@ -210,31 +425,44 @@ func (e *HTTPExecuter) ExecuteTurboHTTP(p progress.IProgress, reqURL string) (re
 	}
 
 	swg := sizedwaitgroup.New(maxWorkers)
-	for e.bulkHTTPRequest.Next(reqURL) && !result.Done {
-		request, err := e.bulkHTTPRequest.MakeHTTPRequest(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
+	for e.bulkHTTPRequest.Next(reqURL) && !result.stopped() && e.ctx.Err() == nil && e.withinBudget(p, reqURL, remaining) {
+		httpRequests, err := e.bulkHTTPRequest.MakeHTTPRequests(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
 		if err != nil {
-			result.Error = err
+			result.fail(e.failed(reqURL, err))
 			p.Drop(remaining)
-		} else {
+		}
+
+		// the injected copies of a request are taken from the budget too
+		for i, request := range httpRequests {
+			if i > 0 && !e.withinBudget(p, reqURL, remaining) {
+				break
+			}
+
 			swg.Add()
 			go func(httpRequest *requests.HTTPRequest) {
 				defer swg.Done()
 
-				// HTTP pipelining ignores rate limit
+				e.waitForTurn(reqURL)
 
 				// If the request was built correctly then execute it
-				request.PipelineClient = pipeclient
-				err = e.handleHTTP(reqURL, httpRequest, dynamicvalues, &result)
-				if err != nil {
-					result.Error = errors.Wrap(err, "could not handle http request")
+				httpRequest.PipelineClient = pipeclient
+				if err := e.handleHTTP(reqURL, httpRequest, dynamicvalues, &result); err != nil {
+					result.fail(e.failed(reqURL, errors.Wrap(err, "could not handle http request")))
 					p.Drop(remaining)
 				}
-				request.PipelineClient = nil
+				httpRequest.PipelineClient = nil
 
+				// Stop enqueuing new payloads at first valid result if requested by the template
+				if e.bulkHTTPRequest.StopAtFirstMatch {
+					result.Lock()
+					result.Done = result.GotResults
+					result.Unlock()
+				}
 			}(request)
 		}
 
 		e.bulkHTTPRequest.Increment(reqURL)
+		e.attackDelay()
 	}
 
 	swg.Wait()
@@ -244,6 +472,23 @@ func (e *HTTPExecuter) ExecuteTurboHTTP(p progress.IProgress, reqURL string) (re
 
 // ExecuteHTTP executes the HTTP request on a URL
 func (e *HTTPExecuter) ExecuteHTTP(p progress.IProgress, reqURL string) (result Result) {
+	defer func() {
+		e.connections.close(reqURL)
+		e.history.clear(reqURL)
+
+		if result.Error != nil {
+			e.summary.Error()
+		}
+	}()
+
+	// the values published by the other templates must be available
+	if !e.scanContext.Has(reqURL, e.contextValues) {
+		e.log.Host(reqURL).Verbosef("Skipped %s missing published values", reqURL)
+		p.Drop(e.bulkHTTPRequest.GetRequestCount())
+
+		return
+	}
+
 	// verify if pipeline was requested
 	if e.bulkHTTPRequest.Pipeline {
 		return e.ExecuteTurboHTTP(p, reqURL)
@@ -255,7 +500,7 @@ func (e *HTTPExecuter) ExecuteHTTP(p progress.IProgress, reqURL string) (result
 
 	result.Matches = make(map[string]interface{})
 	result.Extractions = make(map[string]interface{})
-	dynamicvalues := make(map[string]interface{})
+	dynamicvalues := e.scanContext.Values(reqURL)
 
 	// verify if the URL is already being processed
 	if e.bulkHTTPRequest.HasGenerator(reqURL) {
@@ -265,141 +510,388 @@ func (e *HTTPExecuter) ExecuteHTTP(p progress.IProgress, reqURL string) (result
 	remaining := e.bulkHTTPRequest.GetRequestCount()
 	e.bulkHTTPRequest.CreateGenerator(reqURL)
 
-	for e.bulkHTTPRequest.Next(reqURL) && !result.Done {
-		httpRequest, err := e.bulkHTTPRequest.MakeHTTPRequest(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
+	built := 0
+
+	for e.bulkHTTPRequest.Next(reqURL) && !result.Done && e.ctx.Err() == nil && e.withinBudget(p, reqURL, remaining) {
+		httpRequests, err := e.bulkHTTPRequest.MakeHTTPRequests(reqURL, dynamicvalues, e.bulkHTTPRequest.Current(reqURL))
 		if err != nil {
-			result.Error = err
+			result.Error = e.failed(reqURL, err)
 			p.Drop(remaining)
-		} else {
-			globalratelimiter.Take(reqURL)
+		}
+
+		// the injected copies of a request are taken from the budget too
+		for i, httpRequest := range httpRequests {
+			if i > 0 && !e.withinBudget(p, reqURL, remaining) {
+				break
+			}
+
+			built++
+			e.waitForTurn(reqURL)
 			// If the request was built correctly then execute it
 			err = e.handleHTTP(reqURL, httpRequest, dynamicvalues, &result)
 			if err != nil {
-				result.Error = errors.Wrap(err, "could not handle http request")
+				result.Error = e.failed(reqURL, errors.Wrap(err, "could not handle http request"))
 				p.Drop(remaining)
 			}
 		}
 
 		// Check if has to stop processing at first valid result
-		if e.stopAtFirstMatch && result.GotResults {
+		if (e.stopAtFirstMatch || e.bulkHTTPRequest.StopAtFirstMatch) && result.GotResults {
+			if len(result.Meta) > 0 {
+				e.log.Host(reqURL).Infof("Found valid payload values for %s: %s", reqURL, metaToString(result.Meta))
+			}
 			p.Drop(remaining)
 			break
 		}
 
+		e.attackDelay()
+
 		// move always forward with requests
 		e.bulkHTTPRequest.Increment(reqURL)
 		p.Update()
 		remaining--
 	}
 
-	gologger.Verbosef("Sent for [%s] to %s\n", "http-request", e.template.ID, reqURL)
+	if e.dryRun {
+		e.log.Host(reqURL).Infof("Built %d requests for %s (dry run)", built, reqURL)
+	} else {
+		e.log.Host(reqURL).Verbosef("Sent http requests to %s", reqURL)
+	}
 
 	return result
 }
 
 func (e *HTTPExecuter) handleHTTP(reqURL string, request *requests.HTTPRequest, dynamicvalues map[string]interface{}, result *Result) error {
 	e.setCustomHeaders(request)
+	e.setCredentialHeaders(reqURL, request)
+	e.annotateRequest(request)
+
+	host, ips := e.targetIPs(reqURL, request)
+	if len(ips) == 0 {
+		_, err := e.sendHTTP(e.ctx, reqURL, request, dynamicvalues, result)
+		return err
+	}
+
+	// the request is sent to the next address of the host until it matches,
+	// only some backends of a load balanced fleet being vulnerable, or to
+	// all of them when scanning all the addresses
+	failed := 0
+
+	var err error
+
+	for _, ip := range ips {
+		var matched bool
+
+		matched, err = e.sendHTTP(resolver.WithIP(e.ctx, host, ip), reqURL, request, dynamicvalues, result)
+		if matched && !e.scanAllIPs {
+			return nil
+		}
+
+		if err != nil {
+			failed++
+		}
+	}
+
+	if failed < len(ips) {
+		return nil
+	}
+
+	return err
+}
 
+// targetIPs returns the host of a request and its addresses to send the
+// request to, none to let the connections resolve the host
+func (e *HTTPExecuter) targetIPs(reqURL string, request *requests.HTTPRequest) (string, []string) {
+	// pipelined and reused connections are shared by the requests
+	if (!e.retryIPs && !e.scanAllIPs) || e.dryRun || request.Pipeline || request.ReuseConnection {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(reqURL)
+	if err != nil {
+		return "", nil
+	}
+
+	ips, err := e.resolver.LookupIPs(parsed.Hostname())
+	if err != nil || len(ips) < two {
+		return "", nil
+	}
+
+	return parsed.Hostname(), ips
+}
+
+// sendHTTP sends a request, to the address chosen in the context if any, and
+// runs the matchers and the extractors on its response, returning if the
+// request matched
+func (e *HTTPExecuter) sendHTTP(ctx context.Context, reqURL string, request *requests.HTTPRequest, dynamicvalues map[string]interface{}, result *Result) (bool, error) {
 	var (
-		resp *http.Response
-		err  error
+		resp    *http.Response
+		err     error
+		matched bool
 	)
 
+	log := e.log.Host(reqURL).Request(logging.RequestID())
+
+	// the redirects to the canary host are reported instead of followed
+	ctx = matchers.WithCanary(ctx, request.CanaryHost)
+
+	var debugID uint64
+
 	if e.debug {
 		dumpedRequest, err := requests.Dump(request, reqURL)
 		if err != nil {
-			return err
+			return false, err
 		}
 
-		gologger.Infof("Dumped HTTP request for %s (%s)\n\n", reqURL, e.template.ID)
-		fmt.Fprintf(os.Stderr, "%s", string(dumpedRequest))
+		debugID = e.debugWriter.NextID()
+		e.debugWriter.Dump(debugID, "HTTP request", e.template.ID, reqURL, e.debugMeta(request, 0), dumpedRequest)
+	}
+
+	// in dry run mode the request is only printed
+	if e.dryRun {
+		dumpedRequest, err := requests.Dump(request, reqURL)
+		if err != nil {
+			return false, err
+		}
+
+		log.Silentf("[%s] [%s] %s\n%s\n", e.template.ID, "http", reqURL, string(dumpedRequest))
+
+		return matched, nil
+	}
+
+	// only requests normalized by net/http can be safely cached
+	var cacheKey string
+
+	fromCache := false
+
+	if e.responseCache != nil && request.Request != nil && !request.Pipeline && !request.Unsafe && !request.ReuseConnection && resolver.IP(ctx) == "" {
+		dumpedRequest, dumpErr := requests.Dump(request, reqURL)
+		if dumpErr != nil {
+			return false, dumpErr
+		}
+
+		cacheKey = cache.Key(dumpedRequest)
 	}
 
 	timeStart := time.Now()
-	if request.Pipeline {
+	if e.replaying() {
+		// recorded response, keeping the original duration for time based matchers
+		var recordedDuration time.Duration
+
+		resp, recordedDuration, err = e.cassette.Replay(cassetteKey(request))
+		if err != nil {
+			return false, err
+		}
+
+		fromCache = true
+		timeStart = timeStart.Add(-recordedDuration)
+	} else if e.dialsRawRequests() && (request.Pipeline || request.Unsafe) {
+		// rawhttp can't use proxies nor configure tls, the raw request is
+		// sent on a connection opened by the executer instead
+		dumpedRequest, dumpErr := dumpWireRequest(request, reqURL)
+		if dumpErr != nil {
+			return false, dumpErr
+		}
+
+		e.summary.Request()
+		resp, err = e.doExactHTTP(ctx, reqURL, string(dumpedRequest))
+		if err != nil {
+			e.autoThrottle.Report(reqURL, time.Since(timeStart), nil, err)
+			return false, err
+		}
+	} else if request.Pipeline {
+		e.summary.Request()
 		resp, err = request.PipelineClient.DoRaw(request.RawRequest.Method, reqURL, request.RawRequest.Path, requests.ExpandMapValues(request.RawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.RawRequest.Data)))
 		if err != nil {
-			return err
+			return false, err
+		}
+	} else if request.ReuseConnection {
+		// connection of the previous request to the host
+		dumpedRequest, dumpErr := dumpWireRequest(request, reqURL)
+		if dumpErr != nil {
+			return false, dumpErr
+		}
+
+		e.summary.Request()
+		resp, err = e.doReusedHTTP(reqURL, dumpedRequest)
+		if err != nil {
+			e.autoThrottle.Report(reqURL, time.Since(timeStart), nil, err)
+			return false, err
+		}
+	} else if request.Exact {
+		// raw bytes on a plain connection
+		e.summary.Request()
+		resp, err = e.doExactHTTP(ctx, reqURL, request.RawRequest.Raw)
+		if err != nil {
+			e.autoThrottle.Report(reqURL, time.Since(timeStart), nil, err)
+			return false, err
 		}
 	} else if request.Unsafe {
 		// rawhttp
+		e.summary.Request()
 		// burp uses "\r\n" as new line character
-		request.RawRequest.Data = strings.ReplaceAll(request.RawRequest.Data, "\n", "\r\n")
+		if !request.RawRequest.BodyEncoded {
+			request.RawRequest.Data = crlf(request.RawRequest.Data)
+		}
 		options := e.rawHttpClient.Options
 		options.AutomaticContentLength = request.AutomaticContentLengthHeader
 		options.AutomaticHostHeader = request.AutomaticHostHeader
 		resp, err = e.rawHttpClient.DoRawWithOptions(request.RawRequest.Method, reqURL, request.RawRequest.Path, requests.ExpandMapValues(request.RawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.RawRequest.Data)), options)
 		if err != nil {
-			return err
+			e.autoThrottle.Report(reqURL, time.Since(timeStart), nil, err)
+			return false, err
 		}
+	} else if cached, cachedDuration, ok := e.responseCache.Get(cacheKey); ok {
+		// response cache, keeping the original duration for time based matchers
+		resp = cached
+		fromCache = true
+		timeStart = timeStart.Add(-cachedDuration)
 	} else {
-		// retryablehttp
+		// retryablehttp, recording the connection for the dsl
+		e.summary.Request()
+		request.Request.Request = withAttempts(matchers.WithTrustStore(matchers.WithConnectionTrace(request.Request.Request.WithContext(ctx)), e.trust))
+		// the pooled connections of the host may lead to another address
+		if resolver.IP(ctx) != "" {
+			request.Request.Close = true
+		}
 		resp, err = e.httpClient.Do(request.Request)
 		if err != nil {
 			if resp != nil {
 				resp.Body.Close()
 			}
-			return err
+			e.autoThrottle.Report(reqURL, time.Since(timeStart), resp, err)
+			return false, err
 		}
 	}
 	duration := time.Since(timeStart)
+	if request.CanaryHost != "" {
+		resp.Request = withCanaryRequest(resp.Request, reqURL, request.CanaryHost)
+	}
+
+	if !fromCache {
+		e.autoThrottle.Report(reqURL, duration, resp, nil)
+
+		if timings := matchers.RequestTimings(resp); timings != nil {
+			e.summary.Timings(timings.DNS, timings.Connect, timings.TLS, timings.TTFB)
+		}
+	}
 
 	if e.debug {
 		dumpedResponse, dumpErr := httputil.DumpResponse(resp, true)
 		if dumpErr != nil {
-			return errors.Wrap(dumpErr, "could not dump http response")
+			return false, errors.Wrap(dumpErr, "could not dump http response")
 		}
 
-		gologger.Infof("Dumped HTTP response for %s (%s)\n\n", reqURL, e.template.ID)
-		fmt.Fprintf(os.Stderr, "%s\n", string(dumpedResponse))
+		e.debugWriter.Dump(debugID, "HTTP response", e.template.ID, reqURL, e.debugMeta(request, attempts(resp)), dumpedResponse)
 	}
 
-	data, err := ioutil.ReadAll(resp.Body)
+	data, err := e.readBody(resp.Body)
 	if err != nil {
 		_, copyErr := io.Copy(ioutil.Discard, resp.Body)
 		if copyErr != nil {
 			resp.Body.Close()
-			return copyErr
+			return false, copyErr
 		}
 
 		resp.Body.Close()
 
-		return errors.Wrap(err, "could not read http body")
+		return false, errors.Wrap(err, "could not read http body")
 	}
 
 	resp.Body.Close()
 
+	if cacheKey != "" && !fromCache {
+		e.responseCache.Set(cacheKey, resp, data, duration)
+	}
+
+	if e.recording {
+		e.cassette.Record(cassetteKey(request), resp, data, duration)
+	}
+
 	// net/http doesn't automatically decompress the response body if an encoding has been specified by the user in the request
 	// so in case we have to manually do it
 	data, err = requests.HandleDecompression(request, data)
 	if err != nil {
-		return errors.Wrap(err, "could not decompress http body")
+		return false, errors.Wrap(err, "could not decompress http body")
 	}
 
+	e.metrics.Response(e.template.ID, len(data))
+
+	// the interactions triggered by the out-of-band payload of the request
+	interactions := e.oob.Wait(request.OOBID)
+
+	// the response to the same url without the headers and the body of the
+	// request, checking if the response to the request was cached
+	verification := e.verifyCache(reqURL, request)
+
 	// Convert response body from []byte to string with zero copy
 	body := unsafeToString(data)
 
 	headers := headersToString(resp.Header)
 	matcherCondition := e.bulkHTTPRequest.GetMatchersCondition()
 
-	for _, matcher := range e.bulkHTTPRequest.Matchers {
+	// the secrets leaked by the response are reported whatever the matchers
+	for _, secret := range e.secrets.Scan(hostFromURL(reqURL), body) {
+		e.writeOutputSecret(reqURL, secret)
+	}
+
+	// the values derived from the response are sent by the following requests
+	if err := e.bulkHTTPRequest.RunPostResponse(resp, body, headers, duration, dynamicvalues); err != nil {
+		log.Warningf("%s", err)
+	}
+
+	// the product and version tagged by the extractors
+	fingerprint := extractors.FingerprintHTTP(e.bulkHTTPRequest.Extractors, resp, body, headers, duration)
+	fingerprint.Vulnerabilities = e.vulnDB.Correlate(fingerprint.Product, fingerprint.Version)
+
+	// the requests are numbered in order for the diff matchers
+	if e.recordHistory {
+		e.history.add(reqURL, e.bulkHTTPRequest.Position(reqURL), baseline.NewFingerprint(resp.StatusCode, body, ""))
+	}
+
+	// matchers reproduced by the verification pass, computed at the first match
+	var reproduced []bool
+
+	// matchers matched with the weighted condition
+	var weightedMatches []bool
+	if matcherCondition == matchers.WeightedCondition {
+		weightedMatches = make([]bool, len(e.bulkHTTPRequest.Matchers))
+	}
+
+	for i, matcher := range e.bulkHTTPRequest.Matchers {
 		// Check if the matcher matched
-		if !matcher.Match(resp, body, headers, duration) {
+		if !e.match(matcher, reqURL, resp, body, headers, duration, fingerprint, interactions, verification) {
 			// If the condition is AND we haven't matched, try next request.
 			if matcherCondition == matchers.ANDCondition {
-				return nil
+				return matched, nil
 			}
 		} else {
+			if weightedMatches != nil {
+				weightedMatches[i] = true
+			}
+
 			// If the matcher has matched, and its an OR
 			// write the first output then move to next matcher.
 			if matcherCondition == matchers.ORCondition {
+				if e.verifyMatches > 0 {
+					if reproduced == nil {
+						reproduced = e.verifyMatch(reqURL, request)
+					}
+
+					if !reproduced[i] {
+						log.Verbosef("Discarded flaky match of %s on %s", matcher.Name, reqURL)
+						continue
+					}
+				}
+
 				result.Lock()
 				result.Matches[matcher.Name] = nil
 				// probably redundant but ensures we snapshot current payload values when matchers are valid
 				result.Meta = request.Meta
 				result.GotResults = true
 				result.Unlock()
-				e.writeOutputHTTP(request, resp, body, matcher, nil)
+				matched = true
+				e.writeOutputHTTP(request, resp, body, matcher, nil, fingerprint)
 			}
 		}
 	}
@@ -409,11 +901,22 @@ func (e *HTTPExecuter) handleHTTP(reqURL string, request *requests.HTTPRequest,
 	var extractorResults, outputExtractorResults []string
 
 	for _, extractor := range e.bulkHTTPRequest.Extractors {
-		for match := range extractor.Extract(resp, body, headers) {
+		var extracted map[string]struct{}
+		if extractor.GetPart() == extractors.InteractionPart {
+			extracted = extractor.ExtractInteractions(interactionsToMap(interactions))
+		} else {
+			extracted = extractor.Extract(resp, body, headers, duration)
+		}
+
+		for match := range extracted {
 			if _, ok := dynamicvalues[extractor.Name]; !ok {
 				dynamicvalues[extractor.Name] = match
 			}
 
+			if extractor.Publish {
+				e.scanContext.Set(reqURL, extractor.Name, match)
+			}
+
 			extractorResults = append(extractorResults, match)
 
 			if !extractor.Internal {
@@ -427,23 +930,262 @@ func (e *HTTPExecuter) handleHTTP(reqURL string, request *requests.HTTPRequest,
 		result.Unlock()
 	}
 
-	// Write a final string of output if matcher type is
-	// AND or if we have extractors for the mechanism too.
-	if len(outputExtractorResults) > 0 || matcherCondition == matchers.ANDCondition {
-		e.writeOutputHTTP(request, resp, body, nil, outputExtractorResults)
+	// All the matchers must reproduce for an AND condition
+	if matcherCondition == matchers.ANDCondition && e.verifyMatches > 0 && len(e.bulkHTTPRequest.Matchers) > 0 {
+		for _, ok := range e.verifyMatch(reqURL, request) {
+			if !ok {
+				log.Verbosef("Discarded flaky match on %s", reqURL)
+				return matched, nil
+			}
+		}
+	}
+
+	// The weights of the matched matchers must reach the threshold
+	if matcherCondition == matchers.WeightedCondition {
+		if e.verifyMatches > 0 && matchers.WeightedScore(e.bulkHTTPRequest.Matchers, weightedMatches) >= e.bulkHTTPRequest.MatchersThreshold {
+			for i, ok := range e.verifyMatch(reqURL, request) {
+				weightedMatches[i] = weightedMatches[i] && ok
+			}
+		}
+
+		if matchers.WeightedScore(e.bulkHTTPRequest.Matchers, weightedMatches) < e.bulkHTTPRequest.MatchersThreshold {
+			return matched, nil
+		}
+	}
+
+	// Write a final string of output if matcher type is AND, weighted
+	// or if we have extractors for the mechanism too.
+	if len(outputExtractorResults) > 0 || matcherCondition == matchers.ANDCondition || matcherCondition == matchers.WeightedCondition {
+		matched = true
+		e.writeOutputHTTP(request, resp, body, nil, outputExtractorResults, fingerprint)
 		result.Lock()
+		// snapshot the payload values which produced the final match
+		result.Meta = request.Meta
 		result.GotResults = true
 		result.Unlock()
 	}
 
-	return nil
+	return matched, nil
+}
+
+// debugMeta returns the metadata of a request for the debug dumps
+func (e *HTTPExecuter) debugMeta(request *requests.HTTPRequest, attempt int) *debugwriter.Meta {
+	return &debugwriter.Meta{
+		TemplatePath: e.template.GetPath(),
+		Index:        request.Index,
+		Attempt:      attempt,
+		Payloads:     request.Meta,
+	}
+}
+
+// match runs a matcher on a response, the baseline matchers comparing
+// it with the baseline of its host, the diff matchers with the response
+// to their reference request and the version matchers the detected version.
+func (e *HTTPExecuter) match(matcher *matchers.Matcher, reqURL string, resp *http.Response, body, headers string, duration time.Duration, fingerprint *extractors.Fingerprint, interactions []*oob.Interaction, verification *cacheVerification) bool {
+	switch matcher.GetPart() {
+	case matchers.InteractionPart:
+		raw, data := interactionsToMap(interactions)
+		return matcher.MatchInteractions(raw, data)
+	case matchers.VerificationPart:
+		if verification == nil {
+			return false
+		}
+
+		return matcher.Match(verification.resp, verification.body, verification.headers, verification.duration)
+	}
+
+	switch matcher.GetType() {
+	case matchers.VersionMatcher:
+		return matcher.MatchVersion(fingerprint.Version)
+	case matchers.BaselineMatcher:
+		path := ""
+		if resp.Request != nil {
+			path = resp.Request.URL.Path
+		}
+
+		return matcher.MatchBaseline(resp, body, path, e.baselines.Get(e.template.ID, reqURL))
+	case matchers.DiffMatcher:
+		return matcher.MatchDiff(resp, body, e.history.get(reqURL, matcher.GetReference()))
+	}
+
+	return matcher.Match(resp, body, headers, duration)
+}
+
+// hasDiffMatchers returns true if a diff matcher compares the responses
+func hasDiffMatchers(list []*matchers.Matcher) bool {
+	for _, matcher := range list {
+		if matcher.GetType() == matchers.DiffMatcher {
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifyMatch re-sends a matched request on fresh connections and
+// returns for each matcher if it matched in every attempt.
+func (e *HTTPExecuter) verifyMatch(reqURL string, request *requests.HTTPRequest) []bool {
+	reproduced := make([]bool, len(e.bulkHTTPRequest.Matchers))
+	for i := range reproduced {
+		reproduced[i] = true
+	}
+
+	// pipelined requests share their connections and can't be replayed alone
+	if request.Pipeline {
+		return reproduced
+	}
+
+	for attempt := 0; attempt < e.verifyMatches; attempt++ {
+		e.waitForTurn(reqURL)
+
+		resp, body, duration, err := e.resendHTTP(reqURL, request)
+		if err != nil {
+			e.log.Host(reqURL).Verbosef("Could not verify match on %s: %s", reqURL, err)
+
+			for i := range reproduced {
+				reproduced[i] = false
+			}
+
+			return reproduced
+		}
+
+		headers := headersToString(resp.Header)
+		fingerprint := extractors.FingerprintHTTP(e.bulkHTTPRequest.Extractors, resp, body, headers, duration)
+
+		for i, matcher := range e.bulkHTTPRequest.Matchers {
+			// the interactions of a payload are only received once, and the
+			// cache was already poisoned by the first request
+			if matcher.GetPart() == matchers.InteractionPart || matcher.GetPart() == matchers.VerificationPart {
+				continue
+			}
+
+			if reproduced[i] && !e.match(matcher, reqURL, resp, body, headers, duration, fingerprint, nil, nil) {
+				reproduced[i] = false
+			}
+		}
+	}
+
+	return reproduced
+}
+
+// readBody reads a response body, up to the maximum response size
+func (e *HTTPExecuter) readBody(body io.Reader) ([]byte, error) {
+	if e.maxResponseSize > 0 {
+		body = io.LimitReader(body, e.maxResponseSize)
+	}
+
+	return ioutil.ReadAll(body)
+}
+
+// resendHTTP sends again an already sent request bypassing the response cache
+func (e *HTTPExecuter) resendHTTP(reqURL string, request *requests.HTTPRequest) (*http.Response, string, time.Duration, error) {
+	var (
+		resp *http.Response
+		err  error
+	)
+
+	// the verification requests are taken from the budget too
+	if !e.budget.Take(e.template.ID) {
+		return nil, "", 0, errors.New("the request budget was exceeded")
+	}
+
+	e.summary.Request()
+
+	timeStart := time.Now()
+	if request.Exact {
+		resp, err = e.doExactHTTP(e.ctx, reqURL, request.RawRequest.Raw)
+	} else if e.dialsRawRequests() && request.Unsafe {
+		// sent on a connection opened by the executer, like the first request
+		dumpedRequest, dumpErr := dumpWireRequest(request, reqURL)
+		if dumpErr != nil {
+			return nil, "", 0, dumpErr
+		}
+
+		resp, err = e.doExactHTTP(e.ctx, reqURL, string(dumpedRequest))
+	} else if request.Unsafe {
+		// the raw request data was already converted to "\r\n" line endings
+		options := e.rawHttpClient.Options
+		options.AutomaticContentLength = request.AutomaticContentLengthHeader
+		options.AutomaticHostHeader = request.AutomaticHostHeader
+		resp, err = e.rawHttpClient.DoRawWithOptions(request.RawRequest.Method, reqURL, request.RawRequest.Path, requests.ExpandMapValues(request.RawRequest.Headers), ioutil.NopCloser(strings.NewReader(request.RawRequest.Data)), options)
+	} else {
+		// force a new connection for every attempt
+		e.httpClient.HTTPClient.CloseIdleConnections()
+		resp, err = e.httpClient.Do(request.Request)
+	}
+
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		return nil, "", 0, err
+	}
+
+	duration := time.Since(timeStart)
+	if request.CanaryHost != "" {
+		resp.Request = withCanaryRequest(resp.Request, reqURL, request.CanaryHost)
+	}
+
+	data, err := e.readBody(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, "", 0, errors.Wrap(err, "could not read http body")
+	}
+
+	data, err = requests.HandleDecompression(request, data)
+	if err != nil {
+		return nil, "", 0, errors.Wrap(err, "could not decompress http body")
+	}
+
+	return resp, string(data), duration, nil
+}
+
+// failed records the failure of a request to a target in the error log, each
+// failure being kept while the result only holds the last one
+func (e *HTTPExecuter) failed(reqURL string, err error) error {
+	e.errorLog.Add(e.template.ID, reqURL, err)
+
+	return err
+}
+
+// waitForTurn blocks until the next request to the host can be sent
+func (e *HTTPExecuter) waitForTurn(reqURL string) {
+	if e.dryRun {
+		return
+	}
+
+	e.rateLimiter.Take(reqURL)
+	e.autoThrottle.Take(reqURL)
+	e.scheduler.Wait(hostFromURL(reqURL))
+}
+
+// withinBudget reports whether the next request to a target fits in the
+// request budget, dropping the remaining requests otherwise
+func (e *HTTPExecuter) withinBudget(p progress.IProgress, reqURL string, remaining int64) bool {
+	if e.dryRun || e.budget.Take(e.template.ID) {
+		return true
+	}
+
+	e.log.Host(reqURL).Verbosef("Skipped the remaining requests to %s, the request budget was exceeded", reqURL)
+	p.Drop(remaining)
+
+	return false
+}
+
+// attackDelay waits for the delay between payload requests specified in the template, if any
+func (e *HTTPExecuter) attackDelay() {
+	if e.bulkHTTPRequest.AttackDelay > 0 && !e.dryRun {
+		time.Sleep(time.Duration(e.bulkHTTPRequest.AttackDelay) * time.Millisecond)
+	}
 }
 
 // Close closes the http executer for a template.
 func (e *HTTPExecuter) Close() {}
 
 // makeHTTPClient creates a http client
-func makeHTTPClient(proxyURL *url.URL, options *HTTPOptions) *retryablehttp.Client {
+func makeHTTPClient(proxyURL *url.URL, proxyTunnel *tunnel.Dialer, trustStore *trust.Store, tlsProfile *tlsprofile.Profile, options *HTTPOptions) *retryablehttp.Client {
 	// Multiple Host
 	retryablehttpOptions := retryablehttp.DefaultOptionsSpraying
 	disableKeepAlives := true
@@ -472,10 +1214,12 @@ func makeHTTPClient(proxyURL *url.URL, options *HTTPOptions) *retryablehttp.Clie
 		MaxIdleConns:        maxIdleConns,
 		MaxIdleConnsPerHost: maxIdleConnsPerHost,
 		MaxConnsPerHost:     maxConnsPerHost,
-		TLSClientConfig: &tls.Config{
+		TLSClientConfig: tlsProfile.Apply(&tls.Config{
 			Renegotiation:      tls.RenegotiateOnceAsClient,
-			InsecureSkipVerify: true,
-		},
+			RootCAs:            trustStore.Roots(),
+			InsecureSkipVerify: !trustStore.Verify(), // nolint:gosec // the chains are only verified when requested
+			Certificates:       options.Credentials.ClientCertificates(),
+		}),
 		DisableKeepAlives: disableKeepAlives,
 	}
 
@@ -501,22 +1245,52 @@ func makeHTTPClient(proxyURL *url.URL, options *HTTPOptions) *retryablehttp.Clie
 		}
 	}
 
-	if proxyURL != nil {
+	// the ntlm authentication is bound to the connection, which net/http
+	// doesn't support, so the requests are tunneled through the proxy
+	if proxyTunnel.Auth() == "ntlm" {
+		transport.DialContext = proxyTunnel.DialContext
+	} else if proxyURL != nil {
 		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 
+	// the connections are routed to the address chosen for their host, or
+	// to its cached addresses
+	transport.DialContext = options.Resolver.Dial(transport.DialContext)
+
+	var roundTripper http.RoundTripper = transport
+
+	// http/3 runs over quic, the tls configuration is shared with it
+	if options.BulkHTTPRequest.HTTP3 {
+		roundTripper = newHTTP3Transport(transport.TLSClientConfig)
+	}
+
 	return retryablehttp.NewWithHTTPClient(&http.Client{
-		Transport:     transport,
+		Transport:     countingTransport{roundTripper},
 		Timeout:       time.Duration(options.Timeout) * time.Second,
 		CheckRedirect: makeCheckRedirectFunc(followRedirects, maxRedirects),
 	}, retryablehttpOptions)
 }
 
-type checkRedirectFunc func(_ *http.Request, requests []*http.Request) error
+// withCanaryRequest returns the request of a response with the canary host
+// of the request sent, the raw clients not keeping the request
+func withCanaryRequest(req *http.Request, reqURL, canaryHost string) *http.Request {
+	if req == nil {
+		target, err := url.Parse(reqURL)
+		if err != nil {
+			return nil
+		}
+		req = &http.Request{URL: target}
+	}
+
+	return req.WithContext(matchers.WithCanary(req.Context(), canaryHost))
+}
+
+type checkRedirectFunc func(req *http.Request, requests []*http.Request) error
 
 func makeCheckRedirectFunc(followRedirects bool, maxRedirects int) checkRedirectFunc {
-	return func(_ *http.Request, requests []*http.Request) error {
-		if !followRedirects {
+	return func(req *http.Request, requests []*http.Request) error {
+		// the canary host is only requested by the targets
+		if !followRedirects || matchers.IsCanary(req) {
 			return http.ErrUseLastResponse
 		}
 
@@ -558,6 +1332,54 @@ func (e *HTTPExecuter) setCustomHeaders(r *requests.HTTPRequest) {
 	}
 }
 
+// annotateRequest adds the attribution headers and marker parameter of the
+// scan to a request, the exact raw requests being sent untouched
+func (e *HTTPExecuter) annotateRequest(r *requests.HTTPRequest) {
+	if e.attribution == nil || r.Exact {
+		return
+	}
+
+	names, values := e.attribution.Headers()
+
+	if r.RawRequest != nil {
+		// rawhttp
+		for _, name := range names {
+			r.RawRequest.Headers[name] = " " + values[name]
+		}
+		r.RawRequest.Path = e.attribution.MarkURL(r.RawRequest.Path)
+		r.RawRequest.FullURL = e.attribution.MarkURL(r.RawRequest.FullURL)
+
+		return
+	}
+
+	// retryablehttp
+	for _, name := range names {
+		r.Request.Header.Set(name, values[name])
+	}
+
+	if marked, err := url.Parse(e.attribution.MarkURL(r.Request.URL.String())); err == nil {
+		r.Request.URL = marked
+	}
+}
+
+// setCredentialHeaders sets the authentication headers of the target host
+func (e *HTTPExecuter) setCredentialHeaders(reqURL string, r *requests.HTTPRequest) {
+	parsed, err := url.Parse(reqURL)
+	if err != nil {
+		return
+	}
+
+	for headerName, headerValue := range e.credentials.Headers(parsed.Host) {
+		if r.RawRequest != nil {
+			// rawhttp
+			r.RawRequest.Headers[headerName] = headerValue
+		} else {
+			// retryablehttp
+			r.Request.Header.Set(headerName, headerValue)
+		}
+	}
+}
+
 type Result struct {
 	sync.Mutex
 	GotResults  bool
@@ -567,3 +1389,20 @@ type Result struct {
 	Extractions map[string]interface{}
 	Error       error
 }
+
+// stopped reports whether no more requests should be sent, the result being
+// shared by the request goroutines
+func (r *Result) stopped() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.Done
+}
+
+// fail records the error of a request, the result being shared by the
+// request goroutines
+func (r *Result) fail(err error) {
+	r.Lock()
+	r.Error = err
+	r.Unlock()
+}
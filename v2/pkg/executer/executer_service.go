@@ -0,0 +1,277 @@
+package executer
+
+import (
+	"net"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/debugwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/portscan"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scancontext"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/services"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/vulndb"
+)
+
+// defaultServiceTimeout is the default timeout in seconds of the service probes
+const defaultServiceTimeout = 10
+
+// ServiceExecuter is a client for probing a network
+// service of a target for a template.
+type ServiceExecuter struct {
+	coloredOutput  bool
+	debug          bool
+	jsonOutput     bool
+	jsonRequest    bool
+	template       *templates.Template
+	serviceRequest *requests.ServiceRequest
+	probeOptions   *services.Options
+	writer         *bufwriter.Writer
+	scheduler      *scheduler.Scheduler
+	dryRun         bool
+	debugWriter    *debugwriter.Writer
+	format         FormatOptions
+	summary        *summary.Summary
+	report         *report.Report
+	errorLog       *errorlog.Log
+	ignoreList     *ignore.List
+	log            *logging.Entry
+	portScan       *portscan.Results
+	vulnDB         *vulndb.Database
+	scanContext    *scancontext.Context
+
+	colorizer   colorizer.NucleiColorizer
+	decolorizer *regexp.Regexp
+}
+
+// ServiceOptions contains configuration options for the service executer.
+type ServiceOptions struct {
+	ColoredOutput  bool
+	Debug          bool
+	JSON           bool
+	JSONRequests   bool
+	Template       *templates.Template
+	ServiceRequest *requests.ServiceRequest
+	Writer         *bufwriter.Writer
+	Scheduler      *scheduler.Scheduler
+	DryRun         bool
+	DebugWriter    *debugwriter.Writer
+	Format         FormatOptions
+	Summary        *summary.Summary
+	Report         *report.Report
+	ErrorLog       *errorlog.Log
+	IgnoreList     *ignore.List
+	Logger         logging.Logger
+	PortScan       *portscan.Results
+	VulnDB         *vulndb.Database
+	ScanContext    *scancontext.Context
+
+	Colorizer   colorizer.NucleiColorizer
+	Decolorizer *regexp.Regexp
+}
+
+// NewServiceExecuter creates a new service executer from a template
+// and a service request.
+func NewServiceExecuter(options *ServiceOptions) *ServiceExecuter {
+	timeout := options.ServiceRequest.Timeout
+	if timeout <= 0 {
+		timeout = defaultServiceTimeout
+	}
+
+	return &ServiceExecuter{
+		coloredOutput:  options.ColoredOutput,
+		debug:          options.Debug,
+		jsonOutput:     options.JSON,
+		jsonRequest:    options.JSONRequests,
+		template:       options.Template,
+		serviceRequest: options.ServiceRequest,
+		probeOptions: &services.Options{
+			Timeout: time.Duration(timeout) * time.Second,
+			TLS:     options.ServiceRequest.TLS,
+			Values:  options.ServiceRequest.Options,
+		},
+		writer:      options.Writer,
+		scheduler:   options.Scheduler,
+		dryRun:      options.DryRun,
+		debugWriter: options.DebugWriter,
+		format:      options.Format,
+		summary:     options.Summary,
+		report:      options.Report,
+		errorLog:    options.ErrorLog,
+		ignoreList:  options.IgnoreList,
+		log:         logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		portScan:    options.PortScan,
+		vulnDB:      options.VulnDB,
+		scanContext: options.ScanContext,
+		colorizer:   options.Colorizer,
+		decolorizer: options.Decolorizer,
+	}
+}
+
+// ExecuteService probes the network service of a target
+func (e *ServiceExecuter) ExecuteService(p progress.IProgress, reqURL string) (result *Result) {
+	result = &Result{}
+
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
+	service := e.serviceRequest.Type
+	address := e.serviceRequest.GetAddress(reqURL, services.DefaultPort(service))
+
+	probes := e.serviceRequest.GetProbeOptions()
+	remaining := int64(len(probes))
+
+	// the ports found closed by the port scan aren't probed
+	if host, port, err := net.SplitHostPort(address); err == nil && e.portScan.IsClosed(host, port) {
+		p.Drop(remaining)
+
+		return
+	}
+
+	for _, values := range probes {
+		remaining--
+
+		// in dry run mode the probe is only printed
+		if e.dryRun {
+			e.log.Host(address).Silentf("[%s] [%s] %s %s %s\n", e.template.ID, "service", service, address, values["username"])
+			p.Update()
+
+			continue
+		}
+
+		e.scheduler.Wait(hostFromURL(reqURL))
+		e.summary.Request()
+
+		options := *e.probeOptions
+		options.Values = values
+
+		resp, err := services.Probe(service, address, &options)
+		if err != nil {
+			result.Error = errors.Wrapf(err, "could not probe %s service", service)
+
+			p.Drop(remaining + 1)
+
+			return
+		}
+
+		p.Update()
+
+		e.log.Host(address).Verbosef("Sent service requests to %s", address)
+
+		if e.debug {
+			e.debugWriter.Dump(e.debugWriter.NextID(), "Service response", e.template.ID, address, &debugwriter.Meta{TemplatePath: e.template.GetPath()}, []byte(resp.Raw))
+		}
+
+		if len(e.serviceRequest.Credentials) > 0 {
+			resp.Fields["username"] = values["username"]
+			resp.Fields["password"] = values["password"]
+		}
+
+		// the remaining credentials aren't tried once a login matched
+		if e.handleResponse(address, resp) {
+			result.GotResults = true
+
+			p.Drop(remaining)
+
+			break
+		}
+	}
+
+	return result
+}
+
+// handleResponse runs the matchers and the extractors on the response of
+// a probe, returning true if results were written.
+func (e *ServiceExecuter) handleResponse(address string, resp *services.Response) bool {
+	gotResults := false
+	matcherCondition := e.serviceRequest.GetMatchersCondition()
+
+	// the product and version tagged by the extractors
+	fingerprint := extractors.FingerprintService(e.serviceRequest.Extractors, resp.Raw, resp.Fields)
+	fingerprint.Vulnerabilities = e.vulnDB.Correlate(fingerprint.Product, fingerprint.Version)
+
+	// matchers matched with the weighted condition
+	var weightedMatches []bool
+	if matcherCondition == matchers.WeightedCondition {
+		weightedMatches = make([]bool, len(e.serviceRequest.Matchers))
+	}
+
+	for i, matcher := range e.serviceRequest.Matchers {
+		// Check if the matcher matched
+		var matched bool
+		if matcher.GetType() == matchers.VersionMatcher {
+			matched = matcher.MatchVersion(fingerprint.Version)
+		} else {
+			matched = matcher.MatchService(resp.Raw, resp.Fields)
+		}
+
+		if !matched {
+			// If the condition is AND we haven't matched, return.
+			if matcherCondition == matchers.ANDCondition {
+				return false
+			}
+		} else {
+			if weightedMatches != nil {
+				weightedMatches[i] = true
+			}
+
+			// If the matcher has matched, and its an OR
+			// write the first output then move to next matcher.
+			if matcherCondition == matchers.ORCondition && len(e.serviceRequest.Extractors) == 0 {
+				e.writeOutputService(address, resp, matcher, nil, fingerprint)
+				gotResults = true
+			}
+		}
+	}
+
+	// The weights of the matched matchers must reach the threshold
+	if matcherCondition == matchers.WeightedCondition && matchers.WeightedScore(e.serviceRequest.Matchers, weightedMatches) < e.serviceRequest.MatchersThreshold {
+		return false
+	}
+
+	// All matchers have successfully completed so now start with the
+	// next task which is extraction of input from matchers.
+	var extractorResults []string
+
+	for _, extractor := range e.serviceRequest.Extractors {
+		for match := range extractor.ExtractService(resp.Raw, resp.Fields) {
+			if extractor.Publish {
+				e.scanContext.Set(address, extractor.Name, match)
+			}
+
+			if !extractor.Internal {
+				extractorResults = append(extractorResults, match)
+			}
+		}
+	}
+
+	// Write a final string of output if matcher type is AND, weighted
+	// or if we have extractors for the mechanism too.
+	if len(e.serviceRequest.Extractors) > 0 || matcherCondition == matchers.ANDCondition || matcherCondition == matchers.WeightedCondition {
+		e.writeOutputService(address, resp, nil, extractorResults, fingerprint)
+
+		gotResults = true
+	}
+
+	return gotResults
+}
+
+// Close closes the service executer for a template.
+func (e *ServiceExecuter) Close() {}
@@ -0,0 +1,207 @@
+package executer
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/budget"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/globalratelimiter"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/smuggling"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+const (
+	// defaultSmugglingTimeout is the default number of seconds after which a probe is delayed
+	defaultSmugglingTimeout = 10
+	// defaultSmugglingConfirmations is the default number of confirmations of a delay
+	defaultSmugglingConfirmations = 1
+)
+
+// SmugglingExecuter is a client for detecting request smuggling
+// on a target for a template.
+type SmugglingExecuter struct {
+	coloredOutput    bool
+	jsonOutput       bool
+	jsonRequest      bool
+	template         *templates.Template
+	smugglingRequest *requests.SmugglingRequest
+	writer           *bufwriter.Writer
+	scheduler        *scheduler.Scheduler
+	dryRun           bool
+	format           FormatOptions
+	summary          *summary.Summary
+	report           *report.Report
+	errorLog         *errorlog.Log
+	ignoreList       *ignore.List
+	log              *logging.Entry
+	connector        *connector.Connector
+	rateLimiter      globalratelimiter.RateLimiter
+	budget           *budget.Budget
+
+	colorizer   colorizer.NucleiColorizer
+	decolorizer *regexp.Regexp
+}
+
+// SmugglingOptions contains configuration options for the smuggling executer.
+type SmugglingOptions struct {
+	ColoredOutput    bool
+	JSON             bool
+	JSONRequests     bool
+	Template         *templates.Template
+	SmugglingRequest *requests.SmugglingRequest
+	Writer           *bufwriter.Writer
+	Scheduler        *scheduler.Scheduler
+	DryRun           bool
+	Format           FormatOptions
+	Summary          *summary.Summary
+	Report           *report.Report
+	ErrorLog         *errorlog.Log
+	IgnoreList       *ignore.List
+	Logger           logging.Logger
+	// Connector opens the connections of the probes through the proxies
+	// and with the tls configuration of the scan
+	Connector *connector.Connector
+	// RateLimiter limits the probes to the targets, the limiter shared by
+	// the executers without one
+	RateLimiter globalratelimiter.RateLimiter
+	// Budget caps the probes sent by the template
+	Budget *budget.Budget
+
+	Colorizer   colorizer.NucleiColorizer
+	Decolorizer *regexp.Regexp
+}
+
+// NewSmugglingExecuter creates a new smuggling executer from a template
+// and a smuggling request.
+func NewSmugglingExecuter(options *SmugglingOptions) *SmugglingExecuter {
+	rateLimiter := options.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = globalratelimiter.Default()
+	}
+
+	return &SmugglingExecuter{
+		coloredOutput:    options.ColoredOutput,
+		jsonOutput:       options.JSON,
+		jsonRequest:      options.JSONRequests,
+		template:         options.Template,
+		smugglingRequest: options.SmugglingRequest,
+		writer:           options.Writer,
+		scheduler:        options.Scheduler,
+		dryRun:           options.DryRun,
+		format:           options.Format,
+		summary:          options.Summary,
+		report:           options.Report,
+		errorLog:         options.ErrorLog,
+		ignoreList:       options.IgnoreList,
+		log:              logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		connector:        options.Connector,
+		rateLimiter:      rateLimiter,
+		budget:           options.Budget,
+		colorizer:        options.Colorizer,
+		decolorizer:      options.Decolorizer,
+	}
+}
+
+// ExecuteSmuggling tests the smuggling techniques on a URL
+func (e *SmugglingExecuter) ExecuteSmuggling(p progress.IProgress, reqURL string) (result *Result) {
+	result = &Result{}
+
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
+	techniques := e.smugglingRequest.GetTechniques()
+	remaining := int64(len(techniques))
+
+	// smuggling probes need a scheme and a host
+	if !isURL(reqURL) {
+		p.Drop(remaining)
+		return
+	}
+
+	options := &smuggling.Options{
+		Method:        e.smugglingRequest.Method,
+		Path:          e.smugglingRequest.Path,
+		Timeout:       time.Duration(e.smugglingRequest.Timeout) * time.Second,
+		Confirmations: e.smugglingRequest.Confirmations,
+		Connector:     e.connector,
+		OnRequest: func() bool {
+			if !e.budget.Take(e.template.ID) {
+				return false
+			}
+
+			e.rateLimiter.Take(reqURL)
+			e.scheduler.Wait(hostFromURL(reqURL))
+			e.summary.Request()
+
+			return true
+		},
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultSmugglingTimeout * time.Second
+	}
+
+	if options.Confirmations <= 0 {
+		options.Confirmations = defaultSmugglingConfirmations
+	}
+
+	for _, technique := range techniques {
+		if e.dryRun {
+			e.log.Host(reqURL).Silentf("[%s] [%s] %s %s\n", e.template.ID, "smuggling", technique, reqURL)
+			p.Update()
+			remaining--
+
+			continue
+		}
+
+		finding, err := smuggling.Detect(reqURL, technique, options)
+		if err == smuggling.ErrStopped {
+			e.log.Host(reqURL).Verbosef("Skipped the remaining requests to %s, the request budget was exceeded", reqURL)
+			p.Drop(remaining)
+
+			return
+		}
+		if err != nil {
+			result.Error = errors.Wrap(err, "could not send smuggling probe")
+			p.Drop(remaining)
+
+			return
+		}
+
+		p.Update()
+		remaining--
+
+		if finding != nil {
+			e.writeOutputSmuggling(reqURL, finding)
+			result.GotResults = true
+
+			// further probes could poison the connections of other users
+			p.Drop(remaining)
+
+			break
+		}
+	}
+
+	e.log.Host(reqURL).Verbosef("Sent smuggling requests to %s", reqURL)
+
+	return result
+}
+
+// Close closes the smuggling executer for a template.
+func (e *SmugglingExecuter) Close() {}
@@ -0,0 +1,180 @@
+package executer
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/storage"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// defaultStorageTimeout is the default timeout in seconds of the storage requests
+const defaultStorageTimeout = 10
+
+// StorageExecuter is a client for checking the cloud storage
+// buckets of a target for a template.
+type StorageExecuter struct {
+	coloredOutput  bool
+	jsonOutput     bool
+	template       *templates.Template
+	storageRequest *requests.StorageRequest
+	checker        *storage.Checker
+	writer         *bufwriter.Writer
+	scheduler      *scheduler.Scheduler
+	dryRun         bool
+	format         FormatOptions
+	summary        *summary.Summary
+	report         *report.Report
+	errorLog       *errorlog.Log
+	ignoreList     *ignore.List
+	log            *logging.Entry
+
+	colorizer   colorizer.NucleiColorizer
+	decolorizer *regexp.Regexp
+}
+
+// StorageOptions contains configuration options for the storage executer.
+type StorageOptions struct {
+	ColoredOutput  bool
+	JSON           bool
+	Template       *templates.Template
+	StorageRequest *requests.StorageRequest
+	Writer         *bufwriter.Writer
+	Scheduler      *scheduler.Scheduler
+	DryRun         bool
+	Format         FormatOptions
+	Summary        *summary.Summary
+	Report         *report.Report
+	ErrorLog       *errorlog.Log
+	IgnoreList     *ignore.List
+	Logger         logging.Logger
+	// Connector provides the transport of the bucket requests, with the
+	// proxies and the tls configuration of the scan
+	Connector *connector.Connector
+
+	Colorizer   colorizer.NucleiColorizer
+	Decolorizer *regexp.Regexp
+}
+
+// NewStorageExecuter creates a new storage executer from a template
+// and a storage request.
+func NewStorageExecuter(options *StorageOptions) (*StorageExecuter, error) {
+	timeout := options.StorageRequest.Timeout
+	if timeout <= 0 {
+		timeout = defaultStorageTimeout
+	}
+
+	checker, err := storage.New(options.StorageRequest.Provider, &storage.Options{
+		Timeout:   time.Duration(timeout) * time.Second,
+		Transport: options.Connector.Transport(),
+		OnRequest: options.Summary.Request,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorageExecuter{
+		coloredOutput:  options.ColoredOutput,
+		jsonOutput:     options.JSON,
+		template:       options.Template,
+		storageRequest: options.StorageRequest,
+		checker:        checker,
+		writer:         options.Writer,
+		scheduler:      options.Scheduler,
+		dryRun:         options.DryRun,
+		format:         options.Format,
+		summary:        options.Summary,
+		report:         options.Report,
+		errorLog:       options.ErrorLog,
+		ignoreList:     options.IgnoreList,
+		log:            logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		colorizer:      options.Colorizer,
+		decolorizer:    options.Decolorizer,
+	}, nil
+}
+
+// ExecuteStorage runs the storage checks on the buckets of a target
+func (e *StorageExecuter) ExecuteStorage(p progress.IProgress, reqURL string) (result *Result) {
+	result = &Result{}
+
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
+	checks := e.storageRequest.Checks
+	remaining := e.storageRequest.GetRequestCount()
+
+	for _, bucket := range e.storageRequest.GetBuckets(reqURL) {
+		if e.dryRun {
+			for _, check := range checks {
+				e.log.Host(reqURL).Silentf("[%s] [%s] %s %s %s\n", e.template.ID, "storage", e.storageRequest.Provider, check, bucket)
+				p.Update()
+				remaining--
+			}
+
+			continue
+		}
+
+		e.scheduler.Wait(bucket)
+
+		state, err := e.checker.Probe(bucket)
+		if err != nil {
+			result.Error = errors.Wrap(err, "could not probe bucket")
+			p.Drop(remaining)
+
+			return
+		}
+
+		for _, check := range checks {
+			matchedURL, matched := state.URL, false
+
+			switch check {
+			case storage.CheckExists:
+				matched = state.Exists
+			case storage.CheckUnclaimed:
+				matched = !state.Exists
+			case storage.CheckList:
+				matched = state.Listable
+			case storage.CheckWrite:
+				if state.Exists {
+					e.scheduler.Wait(bucket)
+
+					matchedURL, matched, err = e.checker.Write(bucket)
+					if err != nil {
+						e.log.Host(reqURL).Warningf("Could not check write permission of %s: %s", bucket, err)
+					}
+				}
+			}
+
+			p.Update()
+			remaining--
+
+			if matched {
+				e.writeOutputStorage(bucket, check, matchedURL)
+				result.GotResults = true
+			}
+		}
+	}
+
+	e.log.Host(reqURL).Verbosef("Sent storage requests to %s", reqURL)
+
+	return result
+}
+
+// Close closes the storage executer for a template.
+func (e *StorageExecuter) Close() {}
@@ -0,0 +1,184 @@
+package executer
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/nuclei/v2/internal/bufwriter"
+	"github.com/projectdiscovery/nuclei/v2/internal/progress"
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+	"github.com/projectdiscovery/nuclei/v2/pkg/errorlog"
+	"github.com/projectdiscovery/nuclei/v2/pkg/ignore"
+	"github.com/projectdiscovery/nuclei/v2/pkg/logging"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/scheduler"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/takeover"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+// defaultTakeoverTimeout is the default timeout in seconds of the takeover http requests
+const defaultTakeoverTimeout = 10
+
+// TakeoverExecuter is a client for verifying the subdomain
+// takeover of a target for a template.
+type TakeoverExecuter struct {
+	coloredOutput   bool
+	jsonOutput      bool
+	template        *templates.Template
+	takeoverRequest *requests.TakeoverRequest
+	verifier        *takeover.Verifier
+	writer          *bufwriter.Writer
+	scheduler       *scheduler.Scheduler
+	dryRun          bool
+	format          FormatOptions
+	summary         *summary.Summary
+	report          *report.Report
+	errorLog        *errorlog.Log
+	ignoreList      *ignore.List
+	log             *logging.Entry
+
+	colorizer   colorizer.NucleiColorizer
+	decolorizer *regexp.Regexp
+}
+
+// TakeoverOptions contains configuration options for the takeover executer.
+type TakeoverOptions struct {
+	ColoredOutput   bool
+	JSON            bool
+	Template        *templates.Template
+	TakeoverRequest *requests.TakeoverRequest
+	Writer          *bufwriter.Writer
+	Scheduler       *scheduler.Scheduler
+	DryRun          bool
+	Format          FormatOptions
+	Summary         *summary.Summary
+	Report          *report.Report
+	ErrorLog        *errorlog.Log
+	IgnoreList      *ignore.List
+	Logger          logging.Logger
+	// Connector provides the transport of the fingerprint requests, with
+	// the proxies and the tls configuration of the scan
+	Connector *connector.Connector
+
+	Colorizer   colorizer.NucleiColorizer
+	Decolorizer *regexp.Regexp
+}
+
+// NewTakeoverExecuter creates a new takeover executer from a template
+// and a takeover request.
+func NewTakeoverExecuter(options *TakeoverOptions) (*TakeoverExecuter, error) {
+	fingerprints := takeover.DefaultFingerprints
+
+	if options.TakeoverRequest.Fingerprints != "" {
+		var err error
+
+		fingerprints, err = takeover.LoadFingerprints(options.TakeoverRequest.Fingerprints)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load takeover fingerprints")
+		}
+	}
+
+	if len(options.TakeoverRequest.Services) > 0 {
+		var selected []*takeover.Fingerprint
+
+		for _, fingerprint := range fingerprints {
+			for _, service := range options.TakeoverRequest.Services {
+				if strings.EqualFold(fingerprint.Service, service) {
+					selected = append(selected, fingerprint)
+					break
+				}
+			}
+		}
+
+		fingerprints = selected
+	}
+
+	timeout := options.TakeoverRequest.Timeout
+	if timeout <= 0 {
+		timeout = defaultTakeoverTimeout
+	}
+
+	verifier := takeover.New(&takeover.Options{
+		Fingerprints: fingerprints,
+		Resolvers:    DefaultResolvers,
+		Timeout:      time.Duration(timeout) * time.Second,
+		Transport:    options.Connector.Transport(),
+		OnRequest:    options.Summary.Request,
+	})
+
+	return &TakeoverExecuter{
+		coloredOutput:   options.ColoredOutput,
+		jsonOutput:      options.JSON,
+		template:        options.Template,
+		takeoverRequest: options.TakeoverRequest,
+		verifier:        verifier,
+		writer:          options.Writer,
+		scheduler:       options.Scheduler,
+		dryRun:          options.DryRun,
+		format:          options.Format,
+		summary:         options.Summary,
+		report:          options.Report,
+		errorLog:        options.ErrorLog,
+		ignoreList:      options.IgnoreList,
+		log:             logging.NewEntry(options.Logger, logging.Fields{logging.FieldTemplate: options.Template.ID}),
+		colorizer:       options.Colorizer,
+		decolorizer:     options.Decolorizer,
+	}, nil
+}
+
+// ExecuteTakeover verifies the subdomain takeover of a target
+func (e *TakeoverExecuter) ExecuteTakeover(p progress.IProgress, reqURL string) (result *Result) {
+	result = &Result{}
+
+	defer func() {
+		if result.Error != nil {
+			e.summary.Error()
+			e.errorLog.Add(e.template.ID, reqURL, result.Error)
+		}
+	}()
+
+	// Parse the URL and return domain if URL.
+	var domain string
+	if isURL(reqURL) {
+		domain = extractDomain(reqURL)
+	} else {
+		domain = reqURL
+	}
+
+	// in dry run mode the verification is only printed
+	if e.dryRun {
+		e.log.Host(reqURL).Silentf("[%s] [%s] %s\n", e.template.ID, "takeover", domain)
+		p.Update()
+
+		return
+	}
+
+	e.scheduler.Wait(hostFromURL(reqURL))
+
+	verdict, err := e.verifier.Verify(domain)
+	if err != nil {
+		result.Error = errors.Wrap(err, "could not verify takeover")
+		p.Drop(1)
+
+		return
+	}
+
+	p.Update()
+
+	e.log.Host(reqURL).Verbosef("Sent takeover request to %s", reqURL)
+
+	if verdict.Vulnerable {
+		e.writeOutputTakeover(verdict)
+		result.GotResults = true
+	}
+
+	return result
+}
+
+// Close closes the takeover executer for a template.
+func (e *TakeoverExecuter) Close() {}
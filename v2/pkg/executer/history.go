@@ -0,0 +1,48 @@
+package executer
+
+import (
+	"sync"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/baseline"
+)
+
+// responseHistory keeps the fingerprints of the responses to the requests
+// of a template per target for the diff matchers.
+type responseHistory struct {
+	mutex     sync.Mutex
+	responses map[string]map[int]*baseline.Fingerprint
+}
+
+// newResponseHistory creates a new empty response history
+func newResponseHistory() *responseHistory {
+	return &responseHistory{responses: make(map[string]map[int]*baseline.Fingerprint)}
+}
+
+// add stores the fingerprint of the response to a request of a target
+func (h *responseHistory) add(reqURL string, position int, fingerprint *baseline.Fingerprint) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	responses, ok := h.responses[reqURL]
+	if !ok {
+		responses = make(map[int]*baseline.Fingerprint)
+		h.responses[reqURL] = responses
+	}
+
+	responses[position] = fingerprint
+}
+
+// get returns the fingerprint of the response to a request of a target
+func (h *responseHistory) get(reqURL string, position int) *baseline.Fingerprint {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.responses[reqURL][position]
+}
+
+// clear removes the responses of a target
+func (h *responseHistory) clear(reqURL string) {
+	h.mutex.Lock()
+	delete(h.responses, reqURL)
+	h.mutex.Unlock()
+}
@@ -0,0 +1,10 @@
+package executer
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// newHTTP3Transport creates the quic transport of the templates requesting
+// http/3, nil unless nuclei is built with the http3 tag
+var newHTTP3Transport func(tlsConfig *tls.Config) http.RoundTripper
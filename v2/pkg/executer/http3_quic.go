@@ -0,0 +1,17 @@
+//go:build http3
+// +build http3
+
+package executer
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+func init() {
+	newHTTP3Transport = func(tlsConfig *tls.Config) http.RoundTripper {
+		return &http3.RoundTripper{TLSClientConfig: tlsConfig}
+	}
+}
@@ -1,23 +1,44 @@
 package executer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 	"unsafe"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
 )
 
 type jsonOutput struct {
-	Template         string   `json:"template"`
-	Type             string   `json:"type"`
-	Matched          string   `json:"matched"`
-	MatcherName      string   `json:"matcher_name,omitempty"`
-	ExtractedResults []string `json:"extracted_results,omitempty"`
-	Name             string   `json:"name"`
-	Severity         string   `json:"severity"`
-	Author           string   `json:"author"`
-	Description      string   `json:"description"`
-	Request          string   `json:"request,omitempty"`
-	Response         string   `json:"response,omitempty"`
+	Template         string                    `json:"template"`
+	Hash             string                    `json:"hash"`
+	Type             string                    `json:"type"`
+	Matched          string                    `json:"matched"`
+	MatcherName      string                    `json:"matcher_name,omitempty"`
+	ExtractedResults []string                  `json:"extracted_results,omitempty"`
+	Name             string                    `json:"name"`
+	Severity         string                    `json:"severity"`
+	Author           string                    `json:"author"`
+	Description      string                    `json:"description"`
+	Request          string                    `json:"request,omitempty"`
+	Response         string                    `json:"response,omitempty"`
+	Meta             map[string]interface{}    `json:"meta,omitempty"`
+	Classification   *templates.Classification `json:"classification,omitempty"`
+	Screenshot       string                    `json:"screenshot,omitempty"`
+	Fingerprint      *extractors.Fingerprint   `json:"fingerprint,omitempty"`
+	Labels           map[string]string         `json:"labels,omitempty"`
+	Protocol         string                    `json:"protocol,omitempty"`
+	IP               string                    `json:"ip,omitempty"`
+	TemplatePath     string                    `json:"template_path,omitempty"`
+	RequestIndex     *int                      `json:"request_index,omitempty"`
+	Attempt          int                       `json:"attempt,omitempty"`
+	Timings          *matchers.Timings         `json:"timings,omitempty"`
 }
 
 // unsafeToString converts byte slice to string with zero allocations
@@ -48,3 +69,43 @@ func headersToString(headers http.Header) string {
 
 	return builder.String()
 }
+
+// sortedMetaKeys returns the payload names of a request meta in a stable order
+func sortedMetaKeys(meta map[string]interface{}) []string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// metaToString converts the payload values of a request to a name=value list
+func metaToString(meta map[string]interface{}) string {
+	var metas []string
+
+	for _, name := range sortedMetaKeys(meta) {
+		metas = append(metas, fmt.Sprintf("%s=%v", name, meta[name]))
+	}
+
+	return strings.Join(metas, ",")
+}
+
+// hostFromURL returns the host of a URL, or the URL itself if it can't be parsed
+func hostFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+
+	return parsed.Host
+}
+
+// findingHash returns a stable identifier for a finding, used to
+// accept known findings in the scan gate and the ignore file.
+func findingHash(templateID, matcherName, matched string) string {
+	hash := sha256.Sum256([]byte(templateID + ":" + matcherName + ":" + matched))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,51 @@
+package executer
+
+import (
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/oob"
+)
+
+// interactionsToMap returns the raw requests of the out-of-band interactions
+// of a request, matched by the word, regex and binary matchers, and the
+// values of the dsl matchers and extractors: the number of interactions in
+// total and by protocol, their protocols and remote addresses separated by
+// spaces, their requests and the delay of the first one in seconds.
+func interactionsToMap(interactions []*oob.Interaction) (string, map[string]interface{}) {
+	requests := make([]string, 0, len(interactions))
+	protocols := make([]string, 0, len(interactions))
+	addresses := make([]string, 0, len(interactions))
+	seen := make(map[string]struct{})
+
+	data := map[string]interface{}{
+		"interactions":      len(interactions),
+		"dns_interactions":  0,
+		"http_interactions": 0,
+		"interaction_delay": 0.0,
+	}
+
+	for i, interaction := range interactions {
+		requests = append(requests, interaction.RawRequest)
+		protocols = append(protocols, interaction.Protocol)
+
+		if _, ok := seen[interaction.RemoteAddress]; !ok && interaction.RemoteAddress != "" {
+			seen[interaction.RemoteAddress] = struct{}{}
+			addresses = append(addresses, interaction.RemoteAddress)
+		}
+
+		count, _ := data[interaction.Protocol+"_interactions"].(int)
+		data[interaction.Protocol+"_interactions"] = count + 1
+
+		if i == 0 {
+			data["interaction_delay"] = interaction.Delay.Seconds()
+		}
+	}
+
+	raw := strings.Join(requests, "\n")
+
+	data["interaction_request"] = raw
+	data["interaction_protocol"] = strings.Join(protocols, " ")
+	data["interaction_remote_address"] = strings.Join(addresses, " ")
+
+	return raw, data
+}
@@ -0,0 +1,120 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/headeraudit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// The status of the audit items
+const (
+	auditPassed = "pass"
+	auditFailed = "fail"
+)
+
+// writeOutputAudit writes audit output to streams
+func (e *AuditExecuter) writeOutputAudit(URL string, item *headeraudit.Item) {
+	log := e.log.Host(URL)
+
+	if e.ignoreList.Ignored(e.template.ID, URL) {
+		log.Verbosef("Ignored finding for %s", URL)
+		return
+	}
+
+	status := auditFailed
+	if item.Passed {
+		status = auditPassed
+	}
+
+	hash := findingHash(e.template.ID, item.Check, URL)
+
+	// only the failed checks are findings, the passed ones being reported
+	// for the compliance of the targets
+	if !item.Passed {
+		e.summary.Finding(&summary.Record{
+			Hash:     hash,
+			Template: e.template.ID,
+			Severity: e.template.Info.Severity,
+			Host:     hostFromURL(URL),
+			Matched:  URL,
+			Labels:   e.format.Labels,
+		})
+	}
+
+	evidences := []string{status, item.Detail}
+	meta := map[string]interface{}{"profile": e.profile}
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "audit",
+		Matched:          URL,
+		MatcherName:      item.Check,
+		ExtractedResults: evidences,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:         e.template.ID,
+			TemplatePath:     e.template.GetPath(),
+			Hash:             hash,
+			Labels:           e.format.Labels,
+			Type:             "audit",
+			Matched:          URL,
+			MatcherName:      item.Check,
+			ExtractedResults: evidences,
+			Meta:             meta,
+			Name:             e.template.Info.Name,
+			Severity:         e.template.Info.Severity,
+			Author:           e.template.Info.Author,
+			Description:      e.template.Info.Description,
+			Classification:   e.template.Info.Classification,
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		MatcherName:      item.Check,
+		Type:             "audit",
+		Severity:         e.template.Info.Severity,
+		Matched:          URL,
+		ExtractedResults: evidences,
+		Meta:             meta,
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
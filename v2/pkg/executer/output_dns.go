@@ -1,27 +1,66 @@
 package executer
 
 import (
-	"strings"
-
 	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
 
 	jsoniter "github.com/json-iterator/go"
-	"github.com/projectdiscovery/gologger"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
 )
 
 // writeOutputDNS writes dns output to streams
 // nolint:interfacer // dns.Msg is out of current scope
 func (e *DNSExecuter) writeOutputDNS(domain string, req, resp *dns.Msg, matcher *matchers.Matcher, extractorResults []string) {
+	log := e.log.Host(domain)
+
+	if e.ignoreList.Ignored(e.template.ID, domain) {
+		log.Verbosef("Ignored finding for %s", domain)
+		return
+	}
+
+	var matcherName string
+	if matcher != nil {
+		matcherName = matcher.Name
+	}
+
+	hash := findingHash(e.template.ID, matcherName, domain)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     domain,
+		Matched:  domain,
+		Labels:   e.format.Labels,
+	})
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "dns",
+		Matched:          domain,
+		MatcherName:      matcherName,
+		ExtractedResults: extractorResults,
+		Request:          req.String(),
+		Response:         resp.String(),
+		Classification:   e.template.Info.Classification,
+	})
+
 	if e.jsonOutput {
 		output := jsonOutput{
-			Template:    e.template.ID,
-			Type:        "dns",
-			Matched:     domain,
-			Name:        e.template.Info.Name,
-			Severity:    e.template.Info.Severity,
-			Author:      e.template.Info.Author,
-			Description: e.template.Info.Description,
+			Template:       e.template.ID,
+			TemplatePath:   e.template.GetPath(),
+			Hash:           hash,
+			Labels:         e.format.Labels,
+			Type:           "dns",
+			Matched:        domain,
+			Name:           e.template.Info.Name,
+			Severity:       e.template.Info.Severity,
+			Author:         e.template.Info.Author,
+			Description:    e.template.Info.Description,
+			Classification: e.template.Info.Classification,
 		}
 
 		if matcher != nil && len(matcher.Name) > 0 {
@@ -39,63 +78,36 @@ func (e *DNSExecuter) writeOutputDNS(domain string, req, resp *dns.Msg, matcher
 
 		data, err := jsoniter.Marshal(output)
 		if err != nil {
-			gologger.Warningf("Could not marshal json output: %s\n", err)
+			log.Warningf("Could not marshal json output: %s", err)
 		}
 
-		gologger.Silentf("%s", string(data))
+		log.Silentf("%s", string(data))
 
 		if e.writer != nil {
 			if err := e.writer.Write(data); err != nil {
-				gologger.Errorf("Could not write output data: %s\n", err)
+				log.Errorf("Could not write output data: %s", err)
 				return
 			}
 		}
 		return
 	}
 
-	builder := &strings.Builder{}
-	colorizer := e.colorizer
-
-	builder.WriteRune('[')
-	builder.WriteString(colorizer.Colorizer.BrightGreen(e.template.ID).String())
-
-	if matcher != nil && len(matcher.Name) > 0 {
-		builder.WriteString(":")
-		builder.WriteString(colorizer.Colorizer.BrightGreen(matcher.Name).Bold().String())
-	}
-
-	builder.WriteString("] [")
-	builder.WriteString(colorizer.Colorizer.BrightBlue("dns").String())
-	builder.WriteString("] ")
-
-	if e.template.Info.Severity != "" {
-		builder.WriteString("[")
-		builder.WriteString(colorizer.GetColorizedSeverity(e.template.Info.Severity))
-		builder.WriteString("] ")
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		Type:             "dns",
+		Severity:         e.template.Info.Severity,
+		Matched:          domain,
+		ExtractedResults: extractorResults,
 	}
 
-	builder.WriteString(domain)
-
-	// If any extractors, write the results
-	if len(extractorResults) > 0 {
-		builder.WriteString(" [")
-
-		for i, result := range extractorResults {
-			builder.WriteString(colorizer.Colorizer.BrightCyan(result).String())
-
-			if i != len(extractorResults)-1 {
-				builder.WriteRune(',')
-			}
-		}
-
-		builder.WriteString("]")
+	if matcher != nil {
+		line.MatcherName = matcher.Name
 	}
 
-	builder.WriteRune('\n')
-
 	// Write output to screen as well as any output file
-	message := builder.String()
-	gologger.Silentf("%s", message)
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
 
 	if e.writer != nil {
 		if e.coloredOutput {
@@ -103,7 +115,7 @@ func (e *DNSExecuter) writeOutputDNS(domain string, req, resp *dns.Msg, matcher
 		}
 
 		if err := e.writer.WriteString(message); err != nil {
-			gologger.Errorf("Could not write output data: %s\n", err)
+			log.Errorf("Could not write output data: %s", err)
 			return
 		}
 	}
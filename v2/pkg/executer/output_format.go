@@ -0,0 +1,165 @@
+package executer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/colorizer"
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+)
+
+// Verbosity is the level of detail of the results printed on screen
+type Verbosity int
+
+const (
+	// VerbositySilent prints results without any column alignment
+	VerbositySilent Verbosity = iota
+	// VerbosityDefault prints results with aligned columns
+	VerbosityDefault
+	// VerbosityVerbose also prints the time and name of the template
+	VerbosityVerbose
+	// VerbosityDebug prints the same details as verbose mode
+	VerbosityDebug
+)
+
+const (
+	severityWidth = len("critical")
+	typeWidth     = len("http")
+)
+
+// FormatOptions contains the configuration of the results formatting
+type FormatOptions struct {
+	// Verbosity is the level of detail of the output
+	Verbosity Verbosity
+	// Compact hides matcher names, extracted values and payloads
+	Compact bool
+	// IDWidth is the width of the template column used for alignment
+	IDWidth int
+	// Labels are the labels of the scan added to the results
+	Labels map[string]string
+}
+
+// outputLine contains the fields of a single result printed on screen
+type outputLine struct {
+	TemplateID       string
+	TemplateName     string
+	MatcherName      string
+	Type             string
+	Severity         string
+	Matched          string
+	IP               string
+	ExtractedResults []string
+	Meta             map[string]interface{}
+	Fingerprint      *extractors.Fingerprint
+}
+
+// formatOutputLine builds the console representation of a result
+func formatOutputLine(colorizer *colorizer.NucleiColorizer, options *FormatOptions, line *outputLine) string {
+	builder := &strings.Builder{}
+	align := options.Verbosity != VerbositySilent
+
+	if options.Verbosity >= VerbosityVerbose {
+		builder.WriteString("[")
+		builder.WriteString(colorizer.Colorizer.Gray(12, time.Now().Format("2006-01-02 15:04:05")).String())
+		builder.WriteString("] ")
+	}
+
+	// template id and matcher name
+	id := line.TemplateID
+	coloredID := colorizer.Colorizer.BrightGreen(line.TemplateID).String()
+
+	if !options.Compact && line.MatcherName != "" {
+		id += ":" + line.MatcherName
+		coloredID += ":" + colorizer.Colorizer.BrightGreen(line.MatcherName).Bold().String()
+	}
+
+	writeColumn(builder, id, coloredID, options.IDWidth, align)
+	writeColumn(builder, line.Type, colorizer.Colorizer.BrightBlue(line.Type).String(), typeWidth, align)
+
+	if line.Severity != "" {
+		writeColumn(builder, line.Severity, colorizer.GetColorizedSeverity(line.Severity), severityWidth, align)
+	}
+
+	// the line is printed as an argument of the format, so the % of the
+	// matched urls are kept as is
+	builder.WriteString(line.Matched)
+
+	// the address of the host the request was sent to, when chosen
+	if line.IP != "" {
+		builder.WriteString(" (")
+		builder.WriteString(line.IP)
+		builder.WriteString(")")
+	}
+
+	if options.Verbosity >= VerbosityVerbose && line.TemplateName != "" {
+		builder.WriteString(" (")
+		builder.WriteString(colorizer.Colorizer.Bold(line.TemplateName).String())
+		builder.WriteString(")")
+	}
+
+	if options.Compact {
+		builder.WriteRune('\n')
+		return builder.String()
+	}
+
+	// If any extractors, write the results
+	if len(line.ExtractedResults) > 0 {
+		builder.WriteString(" [")
+
+		for i, result := range line.ExtractedResults {
+			builder.WriteString(colorizer.Colorizer.BrightCyan(result).String())
+
+			if i != len(line.ExtractedResults)-1 {
+				builder.WriteRune(',')
+			}
+		}
+
+		builder.WriteString("]")
+	}
+
+	// write the detected product and version if any
+	if !line.Fingerprint.IsEmpty() {
+		detected := strings.TrimSpace(line.Fingerprint.Product + " " + line.Fingerprint.Version)
+
+		builder.WriteString(" [")
+		builder.WriteString(colorizer.Colorizer.BrightMagenta(detected).String())
+		builder.WriteString("]")
+
+		if len(line.Fingerprint.Vulnerabilities) > 0 {
+			builder.WriteString(" [potentially vulnerable to: ")
+			builder.WriteString(colorizer.Colorizer.BrightRed(strings.Join(line.Fingerprint.Vulnerabilities, ",")).String())
+			builder.WriteString("]")
+		}
+	}
+
+	// write meta if any
+	if len(line.Meta) > 0 {
+		builder.WriteString(" [")
+
+		var metas []string
+
+		for _, name := range sortedMetaKeys(line.Meta) {
+			value := fmt.Sprintf("%v", line.Meta[name])
+			metas = append(metas, colorizer.Colorizer.BrightYellow(name).Bold().String()+"="+colorizer.Colorizer.BrightYellow(value).String())
+		}
+
+		builder.WriteString(strings.Join(metas, ","))
+		builder.WriteString("]")
+	}
+
+	builder.WriteRune('\n')
+
+	return builder.String()
+}
+
+// writeColumn writes a bracketed column padded to width using the uncolored value length
+func writeColumn(builder *strings.Builder, value, colored string, width int, align bool) {
+	builder.WriteRune('[')
+	builder.WriteString(colored)
+	builder.WriteString("] ")
+
+	if align && len(value) < width {
+		builder.WriteString(strings.Repeat(" ", width-len(value)))
+	}
+}
@@ -3,16 +3,18 @@ package executer
 import (
 	"net/http"
 	"net/http/httputil"
-	"strings"
 
 	jsoniter "github.com/json-iterator/go"
-	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/resolver"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
 )
 
 // writeOutputHTTP writes http output to streams
-func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Response, body string, matcher *matchers.Matcher, extractorResults []string) {
+func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Response, body string, matcher *matchers.Matcher, extractorResults []string, fingerprint *extractors.Fingerprint) {
 	var URL string
 	// rawhttp
 	if req.RawRequest != nil {
@@ -23,15 +25,95 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 		URL = req.Request.URL.String()
 	}
 
+	log := e.log.Host(URL)
+
+	if e.ignoreList.Ignored(e.template.ID, URL) {
+		log.Verbosef("Ignored finding for %s", URL)
+		return
+	}
+
+	var matcherName string
+	if matcher != nil {
+		matcherName = matcher.Name
+	}
+
+	var ip string
+	if resp.Request != nil {
+		ip = resolver.IP(resp.Request.Context())
+	}
+
+	// the findings of every address are distinct when scanning all of them
+	hashed := URL
+	if e.scanAllIPs && ip != "" {
+		hashed = URL + " " + ip
+	}
+
+	hash := findingHash(e.template.ID, matcherName, hashed)
+	record := &summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     hostFromURL(URL),
+		Matched:  URL,
+		Labels:   e.format.Labels,
+	}
+
+	// the vhosts and cnames of an address answering identically are
+	// reported once, with the others as aliases
+	if e.dedupAliases && e.summary.Alias(aliasKey(e.template.ID, matcherName, URL, resp, body), record) {
+		log.Verbosef("Grouped finding on alias %s", URL)
+		return
+	}
+
+	e.summary.Finding(record)
+
+	screenshotPath, err := e.screenshotter.Capture(URL)
+	if err != nil {
+		log.Warningf("Could not capture screenshot of %s: %s", URL, err)
+	} else if screenshotPath != "" {
+		log.Verbosef("Saved screenshot of %s to %s", URL, screenshotPath)
+	}
+
+	if e.report != nil {
+		reported := &report.Finding{
+			Template:         e.template.ID,
+			Name:             e.template.Info.Name,
+			Severity:         e.template.Info.Severity,
+			Description:      e.template.Info.Description,
+			Type:             "http",
+			Matched:          URL,
+			MatcherName:      matcherName,
+			ExtractedResults: extractorResults,
+			Classification:   e.template.Info.Classification,
+		}
+
+		if dumpedRequest, err := requests.Dump(req, URL); err == nil {
+			reported.Request = string(dumpedRequest)
+		}
+
+		if dumpedResponse, err := httputil.DumpResponse(resp, false); err == nil {
+			reported.Response = string(dumpedResponse) + body
+		}
+
+		e.report.Add(reported)
+	}
+
 	if e.jsonOutput {
 		output := jsonOutput{
-			Template:    e.template.ID,
-			Type:        "http",
-			Matched:     URL,
-			Name:        e.template.Info.Name,
-			Severity:    e.template.Info.Severity,
-			Author:      e.template.Info.Author,
-			Description: e.template.Info.Description,
+			Template:       e.template.ID,
+			TemplatePath:   e.template.GetPath(),
+			Hash:           hash,
+			Labels:         e.format.Labels,
+			Type:           "http",
+			Protocol:       resp.Proto,
+			IP:             ip,
+			Matched:        URL,
+			Name:           e.template.Info.Name,
+			Severity:       e.template.Info.Severity,
+			Author:         e.template.Info.Author,
+			Description:    e.template.Info.Description,
+			Classification: e.template.Info.Classification,
+			Screenshot:     screenshotPath,
 		}
 
 		if matcher != nil && len(matcher.Name) > 0 {
@@ -42,11 +124,24 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 			output.ExtractedResults = extractorResults
 		}
 
+		if len(req.Meta) > 0 {
+			output.Meta = req.Meta
+		}
+
+		index := req.Index
+		output.RequestIndex = &index
+		output.Attempt = attempts(resp)
+		output.Timings = matchers.RequestTimings(resp)
+
+		if !fingerprint.IsEmpty() {
+			output.Fingerprint = fingerprint
+		}
+
 		// TODO: URL should be an argument
 		if e.jsonRequest {
 			dumpedRequest, err := requests.Dump(req, URL)
 			if err != nil {
-				gologger.Warningf("could not dump request: %s\n", err)
+				log.Warningf("could not dump request: %s", err)
 			} else {
 				output.Request = string(dumpedRequest)
 			}
@@ -54,7 +149,7 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 			dumpedResponse, err := httputil.DumpResponse(resp, false)
 
 			if err != nil {
-				gologger.Warningf("could not dump response: %s\n", err)
+				log.Warningf("could not dump response: %s", err)
 			} else {
 				output.Response = string(dumpedResponse) + body
 			}
@@ -63,14 +158,14 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 		data, err := jsoniter.Marshal(output)
 
 		if err != nil {
-			gologger.Warningf("Could not marshal json output: %s\n", err)
+			log.Warningf("Could not marshal json output: %s", err)
 		}
 
-		gologger.Silentf("%s", string(data))
+		log.Silentf("%s", string(data))
 
 		if e.writer != nil {
 			if err := e.writer.Write(data); err != nil {
-				gologger.Errorf("Could not write output data: %s\n", err)
+				log.Errorf("Could not write output data: %s", err)
 				return
 			}
 		}
@@ -78,65 +173,25 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 		return
 	}
 
-	builder := &strings.Builder{}
-	colorizer := e.colorizer
-
-	builder.WriteRune('[')
-	builder.WriteString(colorizer.Colorizer.BrightGreen(e.template.ID).String())
-
-	if matcher != nil && len(matcher.Name) > 0 {
-		builder.WriteString(":")
-		builder.WriteString(colorizer.Colorizer.BrightGreen(matcher.Name).Bold().String())
-	}
-
-	builder.WriteString("] [")
-	builder.WriteString(colorizer.Colorizer.BrightBlue("http").String())
-	builder.WriteString("] ")
-
-	if e.template.Info.Severity != "" {
-		builder.WriteString("[")
-		builder.WriteString(colorizer.GetColorizedSeverity(e.template.Info.Severity))
-		builder.WriteString("] ")
-	}
-
-	// Escape the URL by replacing all % with %%
-	escapedURL := strings.ReplaceAll(URL, "%", "%%")
-	builder.WriteString(escapedURL)
-
-	// If any extractors, write the results
-	if len(extractorResults) > 0 {
-		builder.WriteString(" [")
-
-		for i, result := range extractorResults {
-			builder.WriteString(colorizer.Colorizer.BrightCyan(result).String())
-
-			if i != len(extractorResults)-1 {
-				builder.WriteRune(',')
-			}
-		}
-
-		builder.WriteString("]")
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		Type:             "http",
+		Severity:         e.template.Info.Severity,
+		Matched:          URL,
+		IP:               ip,
+		ExtractedResults: extractorResults,
+		Meta:             req.Meta,
+		Fingerprint:      fingerprint,
 	}
 
-	// write meta if any
-	if len(req.Meta) > 0 {
-		builder.WriteString(" [")
-
-		var metas []string
-
-		for name, value := range req.Meta {
-			metas = append(metas, colorizer.Colorizer.BrightYellow(name).Bold().String()+"="+colorizer.Colorizer.BrightYellow(value.(string)).String())
-		}
-
-		builder.WriteString(strings.Join(metas, ","))
-		builder.WriteString("]")
+	if matcher != nil {
+		line.MatcherName = matcher.Name
 	}
 
-	builder.WriteRune('\n')
-
 	// Write output to screen as well as any output file
-	message := builder.String()
-	gologger.Silentf("%s", message)
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
 
 	if e.writer != nil {
 		if e.coloredOutput {
@@ -144,7 +199,7 @@ func (e *HTTPExecuter) writeOutputHTTP(req *requests.HTTPRequest, resp *http.Res
 		}
 
 		if err := e.writer.WriteString(message); err != nil {
-			gologger.Errorf("Could not write output data: %s\n", err)
+			log.Errorf("Could not write output data: %s", err)
 			return
 		}
 	}
@@ -0,0 +1,103 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/secrets"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// secretsTemplateID is the template id of the leaked secrets, which are
+// reported independently of the templates
+const secretsTemplateID = "secrets"
+
+// writeOutputSecret writes the output of a leaked secret to streams
+func (e *HTTPExecuter) writeOutputSecret(URL string, finding *secrets.Finding) {
+	log := e.log.Host(URL)
+
+	if e.ignoreList.Ignored(secretsTemplateID, URL) {
+		log.Verbosef("Ignored finding for %s", URL)
+		return
+	}
+
+	rule := finding.Rule
+
+	hash := findingHash(secretsTemplateID, rule.ID, finding.Secret)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: secretsTemplateID,
+		Severity: rule.Severity,
+		Host:     hostFromURL(URL),
+		Matched:  URL,
+		Labels:   e.format.Labels,
+	})
+
+	evidences := []string{finding.Secret}
+
+	e.report.Add(&report.Finding{
+		Template:         secretsTemplateID,
+		Name:             rule.Description,
+		Severity:         rule.Severity,
+		Type:             "secret",
+		Matched:          URL,
+		MatcherName:      rule.ID,
+		ExtractedResults: evidences,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:         secretsTemplateID,
+			Hash:             hash,
+			Labels:           e.format.Labels,
+			Type:             "secret",
+			Matched:          URL,
+			MatcherName:      rule.ID,
+			ExtractedResults: evidences,
+			Meta:             map[string]interface{}{"entropy": finding.Entropy, "found-by": e.template.ID},
+			Name:             rule.Description,
+			Severity:         rule.Severity,
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       secretsTemplateID,
+		TemplateName:     rule.Description,
+		MatcherName:      rule.ID,
+		Type:             "secret",
+		Severity:         rule.Severity,
+		Matched:          URL,
+		ExtractedResults: evidences,
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/services"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// writeOutputService writes service output to streams
+func (e *ServiceExecuter) writeOutputService(address string, resp *services.Response, matcher *matchers.Matcher, extractorResults []string, fingerprint *extractors.Fingerprint) {
+	log := e.log.Host(address)
+
+	if e.ignoreList.Ignored(e.template.ID, address) {
+		log.Verbosef("Ignored finding for %s", address)
+		return
+	}
+
+	var matcherName string
+	if matcher != nil {
+		matcherName = matcher.Name
+	}
+
+	hash := findingHash(e.template.ID, matcherName, address)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     address,
+		Matched:  address,
+		Labels:   e.format.Labels,
+	})
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "service",
+		Matched:          address,
+		MatcherName:      matcherName,
+		ExtractedResults: extractorResults,
+		Request:          e.serviceRequest.Type + " " + address,
+		Response:         resp.Raw,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:       e.template.ID,
+			TemplatePath:   e.template.GetPath(),
+			Hash:           hash,
+			Labels:         e.format.Labels,
+			Type:           "service",
+			Matched:        address,
+			Name:           e.template.Info.Name,
+			Severity:       e.template.Info.Severity,
+			Author:         e.template.Info.Author,
+			Description:    e.template.Info.Description,
+			Classification: e.template.Info.Classification,
+		}
+
+		if matcher != nil && len(matcher.Name) > 0 {
+			output.MatcherName = matcher.Name
+		}
+
+		if len(extractorResults) > 0 {
+			output.ExtractedResults = extractorResults
+		}
+
+		if !fingerprint.IsEmpty() {
+			output.Fingerprint = fingerprint
+		}
+
+		if e.jsonRequest {
+			output.Request = e.serviceRequest.Type + " " + address
+			output.Response = resp.Raw
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		Type:             "service",
+		Severity:         e.template.Info.Severity,
+		Matched:          address,
+		ExtractedResults: extractorResults,
+		Fingerprint:      fingerprint,
+	}
+
+	if matcher != nil {
+		line.MatcherName = matcher.Name
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/smuggling"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// writeOutputSmuggling writes smuggling output to streams
+func (e *SmugglingExecuter) writeOutputSmuggling(reqURL string, finding *smuggling.Finding) {
+	log := e.log.Host(reqURL)
+
+	if e.ignoreList.Ignored(e.template.ID, reqURL) {
+		log.Verbosef("Ignored finding for %s", reqURL)
+		return
+	}
+
+	hash := findingHash(e.template.ID, finding.Technique, reqURL)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     hostFromURL(reqURL),
+		Matched:  reqURL,
+		Labels:   e.format.Labels,
+	})
+
+	evidences := []string{
+		"control=" + finding.Control.String(),
+		"delayed=" + finding.Delayed.String(),
+	}
+
+	if finding.ConnectionClosed {
+		evidences = append(evidences, "connection-closed")
+	}
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "smuggling",
+		Matched:          reqURL,
+		MatcherName:      finding.Technique,
+		ExtractedResults: evidences,
+		Request:          finding.Probe,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:         e.template.ID,
+			TemplatePath:     e.template.GetPath(),
+			Hash:             hash,
+			Labels:           e.format.Labels,
+			Type:             "smuggling",
+			Matched:          reqURL,
+			MatcherName:      finding.Technique,
+			ExtractedResults: evidences,
+			Name:             e.template.Info.Name,
+			Severity:         e.template.Info.Severity,
+			Author:           e.template.Info.Author,
+			Description:      e.template.Info.Description,
+			Classification:   e.template.Info.Classification,
+		}
+
+		if e.jsonRequest {
+			output.Request = finding.Probe
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		MatcherName:      finding.Technique,
+		Type:             "smuggling",
+		Severity:         e.template.Info.Severity,
+		Matched:          reqURL,
+		ExtractedResults: evidences,
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// writeOutputStorage writes storage output to streams
+func (e *StorageExecuter) writeOutputStorage(bucket, check, matchedURL string) {
+	log := e.log.Host(matchedURL)
+
+	if e.ignoreList.Ignored(e.template.ID, matchedURL) {
+		log.Verbosef("Ignored finding for %s", matchedURL)
+		return
+	}
+
+	hash := findingHash(e.template.ID, check, bucket)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     hostFromURL(matchedURL),
+		Matched:  matchedURL,
+		Labels:   e.format.Labels,
+	})
+
+	evidences := []string{e.storageRequest.Provider + ":" + bucket}
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "storage",
+		Matched:          matchedURL,
+		MatcherName:      check,
+		ExtractedResults: evidences,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:         e.template.ID,
+			TemplatePath:     e.template.GetPath(),
+			Hash:             hash,
+			Labels:           e.format.Labels,
+			Type:             "storage",
+			Matched:          matchedURL,
+			MatcherName:      check,
+			ExtractedResults: evidences,
+			Name:             e.template.Info.Name,
+			Severity:         e.template.Info.Severity,
+			Author:           e.template.Info.Author,
+			Description:      e.template.Info.Description,
+			Classification:   e.template.Info.Classification,
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		MatcherName:      check,
+		Type:             "storage",
+		Severity:         e.template.Info.Severity,
+		Matched:          matchedURL,
+		ExtractedResults: evidences,
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
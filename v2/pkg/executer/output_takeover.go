@@ -0,0 +1,105 @@
+package executer
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/report"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/takeover"
+)
+
+// writeOutputTakeover writes takeover output to streams
+func (e *TakeoverExecuter) writeOutputTakeover(verdict *takeover.Result) {
+	log := e.log.Host(verdict.Host)
+
+	if e.ignoreList.Ignored(e.template.ID, verdict.Host) {
+		log.Verbosef("Ignored finding for %s", verdict.Host)
+		return
+	}
+
+	hash := findingHash(e.template.ID, verdict.Service, verdict.Host)
+	e.summary.Finding(&summary.Record{
+		Hash:     hash,
+		Template: e.template.ID,
+		Severity: e.template.Info.Severity,
+		Host:     verdict.Host,
+		Matched:  verdict.Host,
+		Labels:   e.format.Labels,
+	})
+
+	// the cname chain and the fingerprint are the evidences of the takeover
+	evidences := append([]string{}, verdict.CNames...)
+	if verdict.Fingerprint != "" {
+		evidences = append(evidences, verdict.Fingerprint)
+	}
+
+	e.report.Add(&report.Finding{
+		Template:         e.template.ID,
+		Name:             e.template.Info.Name,
+		Severity:         e.template.Info.Severity,
+		Description:      e.template.Info.Description,
+		Type:             "takeover",
+		Matched:          verdict.Host,
+		MatcherName:      verdict.Service,
+		ExtractedResults: evidences,
+		Classification:   e.template.Info.Classification,
+	})
+
+	if e.jsonOutput {
+		output := jsonOutput{
+			Template:         e.template.ID,
+			TemplatePath:     e.template.GetPath(),
+			Hash:             hash,
+			Labels:           e.format.Labels,
+			Type:             "takeover",
+			Matched:          verdict.Host,
+			MatcherName:      verdict.Service,
+			ExtractedResults: evidences,
+			Name:             e.template.Info.Name,
+			Severity:         e.template.Info.Severity,
+			Author:           e.template.Info.Author,
+			Description:      e.template.Info.Description,
+			Classification:   e.template.Info.Classification,
+		}
+
+		data, err := jsoniter.Marshal(output)
+		if err != nil {
+			log.Warningf("Could not marshal json output: %s", err)
+		}
+
+		log.Silentf("%s", string(data))
+
+		if e.writer != nil {
+			if err := e.writer.Write(data); err != nil {
+				log.Errorf("Could not write output data: %s", err)
+				return
+			}
+		}
+
+		return
+	}
+
+	line := &outputLine{
+		TemplateID:       e.template.ID,
+		TemplateName:     e.template.Info.Name,
+		MatcherName:      verdict.Service,
+		Type:             "takeover",
+		Severity:         e.template.Info.Severity,
+		Matched:          verdict.Host,
+		ExtractedResults: evidences,
+	}
+
+	// Write output to screen as well as any output file
+	message := formatOutputLine(&e.colorizer, &e.format, line)
+	log.Silentf("%s", message)
+
+	if e.writer != nil {
+		if e.coloredOutput {
+			message = e.decolorizer.ReplaceAllString(message, "")
+		}
+
+		if err := e.writer.WriteString(message); err != nil {
+			log.Errorf("Could not write output data: %s", err)
+			return
+		}
+	}
+}
@@ -3,6 +3,10 @@ package extractors
 import (
 	"fmt"
 	"regexp"
+
+	"github.com/Knetic/govaluate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
 )
 
 // CompileExtractors performs the initial setup operation on a extractor
@@ -24,6 +28,20 @@ func (e *Extractor) CompileExtractors() error {
 		e.regexCompiled = append(e.regexCompiled, compiled)
 	}
 
+	// Compile the dsl expressions
+	for _, dsl := range e.DSL {
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(dsl, generators.HelperFunctions())
+		if err != nil {
+			return fmt.Errorf("could not compile dsl: %s", dsl)
+		}
+
+		e.dslCompiled = append(e.dslCompiled, compiled)
+	}
+
+	if err := matchers.ValidateCookies(e.Cookies, e.Missing); err != nil {
+		return err
+	}
+
 	// Setup the part of the request to match, if any.
 	if e.Part != "" {
 		e.part, ok = PartTypes[e.Part]
@@ -34,5 +52,13 @@ func (e *Extractor) CompileExtractors() error {
 		e.part = BodyPart
 	}
 
+	if e.Publish && e.Name == "" {
+		return fmt.Errorf("published extractors must be named")
+	}
+
+	if e.Fingerprint != "" && !FingerprintFields[e.Fingerprint] {
+		return fmt.Errorf("unknown fingerprint field specified: %s", e.Fingerprint)
+	}
+
 	return nil
 }
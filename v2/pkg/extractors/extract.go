@@ -1,14 +1,19 @@
 package extractors
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
 )
 
 // Extract extracts response from the parts of request using a regex
-func (e *Extractor) Extract(resp *http.Response, body, headers string) map[string]struct{} {
+func (e *Extractor) Extract(resp *http.Response, body, headers string, duration time.Duration) map[string]struct{} {
 	switch e.extractorType {
+	case DSLExtractor:
+		return e.extractDSL(matchers.HTTPToMap(resp, body, headers, duration))
 	case RegexExtractor:
 		if e.part == BodyPart {
 			return e.extractRegex(body)
@@ -33,6 +38,8 @@ func (e *Extractor) Extract(resp *http.Response, body, headers string) map[strin
 		}
 
 		return e.extractCookieKVal(resp)
+	case CookieExtractor:
+		return e.extractCookies(resp)
 	}
 
 	return nil
@@ -50,6 +57,50 @@ func (e *Extractor) ExtractDNS(msg *dns.Msg) map[string]struct{} {
 	return nil
 }
 
+// ExtractService extracts from the response of a network service
+func (e *Extractor) ExtractService(raw string, data map[string]interface{}) map[string]struct{} {
+	switch e.extractorType {
+	case RegexExtractor:
+		return e.extractRegex(raw)
+	case DSLExtractor:
+		return e.extractDSL(matchers.ServiceToMap(raw, data))
+	}
+
+	return nil
+}
+
+// ExtractInteractions extracts from the out-of-band interactions received
+// for a request, the raw string containing their requests and the data the
+// values of the dsl expressions
+func (e *Extractor) ExtractInteractions(raw string, data map[string]interface{}) map[string]struct{} {
+	switch e.extractorType {
+	case RegexExtractor:
+		return e.extractRegex(raw)
+	case DSLExtractor:
+		return e.extractDSL(data)
+	}
+
+	return nil
+}
+
+// extractDSL extracts the non empty results of the dsl expressions
+func (e *Extractor) extractDSL(data map[string]interface{}) map[string]struct{} {
+	results := make(map[string]struct{})
+
+	for _, expression := range e.dslCompiled {
+		result, err := expression.Evaluate(data)
+		if err != nil || result == nil {
+			continue
+		}
+
+		if value := fmt.Sprint(result); value != "" {
+			results[value] = struct{}{}
+		}
+	}
+
+	return results
+}
+
 // extractRegex extracts text from a corpus and returns it
 func (e *Extractor) extractRegex(corpus string) map[string]struct{} {
 	results := make(map[string]struct{})
@@ -93,3 +144,19 @@ func (e *Extractor) extractCookieKVal(r *http.Response) map[string]struct{} {
 
 	return results
 }
+
+// extractCookies extracts the names and the attributes of the cookies set
+// by a response, without their values
+func (e *Extractor) extractCookies(r *http.Response) map[string]struct{} {
+	results := make(map[string]struct{})
+
+	for _, cookie := range matchers.SelectCookies(r, e.Cookies) {
+		if len(e.Missing) > 0 && len(matchers.MissingCookieAttributes(cookie, e.Missing)) == 0 {
+			continue
+		}
+
+		results[matchers.DescribeCookie(cookie)] = struct{}{}
+	}
+
+	return results
+}
@@ -1,6 +1,10 @@
 package extractors
 
-import "regexp"
+import (
+	"regexp"
+
+	"github.com/Knetic/govaluate"
+)
 
 // Extractor is used to extract part of response using a regex.
 type Extractor struct {
@@ -21,6 +25,18 @@ type Extractor struct {
 	// KVal are the kval to be present in the response headers/cookies
 	KVal []string `yaml:"kval,omitempty"`
 
+	// Cookies are the name patterns of the cookies extracted by the cookie
+	// extractor, like *session*, all the cookies being extracted by default
+	Cookies []string `yaml:"cookies,omitempty"`
+	// Missing restricts the cookie extractor to the cookies set without one
+	// of the attributes (secure, httponly or samesite)
+	Missing []string `yaml:"missing,omitempty"`
+
+	// DSL are the dsl expressions whose results are extracted
+	DSL []string `yaml:"dsl,omitempty"`
+	// dslCompiled is the compiled variant
+	dslCompiled []*govaluate.EvaluableExpression
+
 	// Part is the part of the request to match
 	//
 	// By default, matching is performed in request body.
@@ -29,6 +45,12 @@ type Extractor struct {
 	part Part
 	// Internal defines if this is used internally
 	Internal bool `yaml:"internal,omitempty"`
+	// Fingerprint tags the extracted value as the product or the version
+	// of the detected software, for the version matchers and the output.
+	Fingerprint string `yaml:"fingerprint,omitempty"`
+	// Publish shares the first extracted value with the other templates
+	// run on the target, as a placeholder named after the extractor.
+	Publish bool `yaml:"publish,omitempty"`
 }
 
 // ExtractorType is the type of the extractor specified
@@ -39,12 +61,18 @@ const (
 	RegexExtractor ExtractorType = iota + 1
 	// KValExtractor extracts responses with key:value
 	KValExtractor
+	// DSLExtractor extracts the results of dsl expressions
+	DSLExtractor
+	// CookieExtractor extracts the names and the attributes of the cookies
+	CookieExtractor
 )
 
 // ExtractorTypes is an table for conversion of extractor type from string.
 var ExtractorTypes = map[string]ExtractorType{
-	"regex": RegexExtractor,
-	"kval":  KValExtractor,
+	"regex":  RegexExtractor,
+	"kval":   KValExtractor,
+	"dsl":    DSLExtractor,
+	"cookie": CookieExtractor,
 }
 
 // Part is the part of the request to match
@@ -57,13 +85,16 @@ const (
 	HeaderPart
 	// AllPart matches both response body and headers of the response.
 	AllPart
+	// InteractionPart extracts from the out-of-band interactions of the request.
+	InteractionPart
 )
 
 // PartTypes is an table for conversion of part type from string.
 var PartTypes = map[string]Part{
-	"body":   BodyPart,
-	"header": HeaderPart,
-	"all":    AllPart,
+	"body":        BodyPart,
+	"header":      HeaderPart,
+	"all":         AllPart,
+	"interaction": InteractionPart,
 }
 
 // GetPart returns the part of the matcher
@@ -0,0 +1,85 @@
+package extractors
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Fields of the fingerprint tagged by the extractors
+const (
+	ProductField = "product"
+	VersionField = "version"
+)
+
+// FingerprintFields contains the fingerprint fields extractors can tag
+var FingerprintFields = map[string]bool{
+	ProductField: true,
+	VersionField: true,
+}
+
+// Fingerprint contains the product and the version of the software
+// detected by the extractors tagging their results.
+type Fingerprint struct {
+	Product string `json:"product,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Vulnerabilities are the known vulnerabilities of the version
+	Vulnerabilities []string `json:"potential_vulnerabilities,omitempty"`
+}
+
+// IsEmpty returns true if nothing was detected
+func (f *Fingerprint) IsEmpty() bool {
+	return f == nil || (f.Product == "" && f.Version == "")
+}
+
+// tag sets the field tagged by an extractor to the first of its results
+func (f *Fingerprint) tag(extractor *Extractor, results map[string]struct{}) {
+	if len(results) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(results))
+	for value := range results {
+		values = append(values, value)
+	}
+
+	sort.Strings(values)
+
+	switch extractor.Fingerprint {
+	case ProductField:
+		if f.Product == "" {
+			f.Product = values[0]
+		}
+	case VersionField:
+		if f.Version == "" {
+			f.Version = values[0]
+		}
+	}
+}
+
+// FingerprintHTTP runs the extractors tagging the fingerprint on a http response
+func FingerprintHTTP(extractors []*Extractor, resp *http.Response, body, headers string, duration time.Duration) *Fingerprint {
+	fingerprint := &Fingerprint{}
+
+	for _, extractor := range extractors {
+		if extractor.Fingerprint != "" {
+			fingerprint.tag(extractor, extractor.Extract(resp, body, headers, duration))
+		}
+	}
+
+	return fingerprint
+}
+
+// FingerprintService runs the extractors tagging the fingerprint on the
+// response of a network service.
+func FingerprintService(extractors []*Extractor, raw string, data map[string]interface{}) *Fingerprint {
+	fingerprint := &Fingerprint{}
+
+	for _, extractor := range extractors {
+		if extractor.Fingerprint != "" {
+			fingerprint.tag(extractor, extractor.ExtractService(raw, data))
+		}
+	}
+
+	return fingerprint
+}
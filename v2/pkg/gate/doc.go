@@ -0,0 +1,3 @@
+// Package gate decides if a scan should fail based on the
+// severity of its findings, ignoring allowlisted findings.
+package gate
@@ -0,0 +1,106 @@
+package gate
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// severityRanks contains the ordering of the known severities
+var severityRanks = map[string]int{
+	"info":     1,
+	"low":      2,
+	"medium":   3,
+	"high":     4,
+	"critical": 5,
+}
+
+// operators contains the supported comparisons, longest first
+var operators = []string{">=", "==", ">", "="}
+
+// Gate fails a scan when findings at or above a severity are found
+type Gate struct {
+	operator  string
+	rank      int
+	allowlist map[string]struct{}
+}
+
+// New creates a new gate from an expression like severity>=high
+// and an optional allowlist file of accepted finding hashes.
+func New(expression, allowlistFile string) (*Gate, error) {
+	gate := &Gate{operator: ">=", allowlist: make(map[string]struct{})}
+
+	value := strings.ToLower(strings.ReplaceAll(expression, " ", ""))
+	value = strings.TrimPrefix(value, "severity")
+
+	for _, operator := range operators {
+		if strings.HasPrefix(value, operator) {
+			gate.operator = operator
+			value = strings.TrimPrefix(value, operator)
+
+			break
+		}
+	}
+
+	rank, ok := severityRanks[value]
+	if !ok {
+		return nil, fmt.Errorf("invalid severity in expression %s", expression)
+	}
+
+	gate.rank = rank
+
+	if allowlistFile != "" {
+		if err := gate.loadAllowlist(allowlistFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return gate, nil
+}
+
+// loadAllowlist reads a file of accepted finding hashes, one per line
+func (g *Gate) loadAllowlist(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		g.allowlist[text] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+// Fails returns true if a finding with a severity and hash fails the gate
+func (g *Gate) Fails(hash, severity string) bool {
+	if g == nil {
+		return false
+	}
+
+	if _, ok := g.allowlist[hash]; ok {
+		return false
+	}
+
+	rank, ok := severityRanks[strings.ToLower(severity)]
+	if !ok {
+		return false
+	}
+
+	switch g.operator {
+	case ">":
+		return rank > g.rank
+	case "=", "==":
+		return rank == g.rank
+	default:
+		return rank >= g.rank
+	}
+}
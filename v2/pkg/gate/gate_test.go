@@ -0,0 +1,73 @@
+package gate
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		operator   string
+		rank       int
+		err        bool
+	}{
+		{expression: "severity>=high", operator: ">=", rank: 4},
+		{expression: "severity > medium", operator: ">", rank: 3},
+		{expression: "severity==critical", operator: "==", rank: 5},
+		{expression: "Severity=Low", operator: "=", rank: 2},
+		{expression: "high", operator: ">=", rank: 4},
+		{expression: "severity>=urgent", err: true},
+		{expression: "severity<high", err: true},
+	}
+
+	for _, test := range tests {
+		gate, err := New(test.expression, "")
+		if test.err {
+			require.NotNil(t, err, "Could parse invalid expression %s", test.expression)
+			continue
+		}
+
+		require.Nil(t, err, "Could not parse valid expression %s", test.expression)
+		require.Equal(t, test.operator, gate.operator, "Could not parse operator of %s", test.expression)
+		require.Equal(t, test.rank, gate.rank, "Could not parse severity of %s", test.expression)
+	}
+}
+
+func TestFails(t *testing.T) {
+	file, err := ioutil.TempFile("", "allowlist")
+	require.Nil(t, err, "Could not create allowlist")
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString("# accepted findings\n\naccepted-hash\n")
+	require.Nil(t, err, "Could not write allowlist")
+	file.Close()
+
+	tests := []struct {
+		expression string
+		hash       string
+		severity   string
+		fails      bool
+	}{
+		{expression: "severity>=high", hash: "a", severity: "critical", fails: true},
+		{expression: "severity>=high", hash: "a", severity: "HIGH", fails: true},
+		{expression: "severity>=high", hash: "a", severity: "medium", fails: false},
+		{expression: "severity>high", hash: "a", severity: "high", fails: false},
+		{expression: "severity=medium", hash: "a", severity: "medium", fails: true},
+		{expression: "severity=medium", hash: "a", severity: "high", fails: false},
+		{expression: "severity>=info", hash: "a", severity: "unknown", fails: false},
+		{expression: "severity>=info", hash: "accepted-hash", severity: "critical", fails: false},
+	}
+
+	for _, test := range tests {
+		gate, err := New(test.expression, file.Name())
+		require.Nil(t, err, "Could not create gate %s", test.expression)
+		require.Equal(t, test.fails, gate.Fails(test.hash, test.severity), "Could not check %s finding against %s", test.severity, test.expression)
+	}
+
+	var gate *Gate
+	require.False(t, gate.Fails("a", "critical"), "Could fail with a nil gate")
+}
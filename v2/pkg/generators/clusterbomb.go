@@ -1,8 +1,8 @@
 package generators
 
 // ClusterbombGenerator Attack - Generate all possible combinations from an input map with all values listed
-// as slices of the same size
-func ClusterbombGenerator(payloads map[string][]string) (out chan map[string]interface{}) {
+// as slices of the same size, at most limit if greater than 0
+func ClusterbombGenerator(payloads map[string][]string, limit int) (out chan map[string]interface{}) {
 	out = make(chan map[string]interface{})
 
 	// generator
@@ -13,9 +13,9 @@ func ClusterbombGenerator(payloads map[string][]string) (out chan map[string]int
 
 		var parts [][]string
 
-		for name, wordlist := range payloads {
+		for _, name := range sortedNames(payloads) {
 			order = append(order, name)
-			parts = append(parts, wordlist)
+			parts = append(parts, payloads[name])
 		}
 
 		var n = 1
@@ -24,8 +24,13 @@ func ClusterbombGenerator(payloads map[string][]string) (out chan map[string]int
 		}
 
 		var at = make([]int, len(parts))
+
+		count := 0
 	loop:
 		for {
+			if limit > 0 && count >= limit {
+				break
+			}
 			// increment position counters
 			for i := len(parts) - 1; i >= 0; i-- {
 				if at[i] > 0 && at[i] >= len(parts[i]) {
@@ -45,6 +50,7 @@ func ClusterbombGenerator(payloads map[string][]string) (out chan map[string]int
 				}
 			}
 			out <- item
+			count++
 			at[len(parts)-1]++
 		}
 	}()
@@ -1,17 +1,28 @@
 package generators
 
 import (
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
 	"html"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Knetic/govaluate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/jwt"
+	"github.com/projectdiscovery/nuclei/v2/pkg/versions"
 )
 
 // HelperFunctions contains the dsl functions
@@ -119,6 +130,34 @@ func HelperFunctions() (functions map[string]govaluate.ExpressionFunction) {
 		return hex.EncodeToString(h.Sum(nil)), nil
 	}
 
+	// hmac(algorithm, data, secret) returns the hex encoded hmac of data
+	// with md5, sha1, sha256 or sha512
+	functions["hmac"] = func(args ...interface{}) (interface{}, error) {
+		if len(args) != 3 {
+			return nil, errors.New("hmac expects an algorithm, data and a secret")
+		}
+
+		var newHash func() hash.Hash
+
+		switch fmt.Sprint(args[0]) {
+		case "md5":
+			newHash = md5.New
+		case "sha1":
+			newHash = sha1.New
+		case "sha256":
+			newHash = sha256.New
+		case "sha512":
+			newHash = sha512.New
+		default:
+			return nil, fmt.Errorf("unknown hmac algorithm %s", fmt.Sprint(args[0]))
+		}
+
+		h := hmac.New(newHash, []byte(fmt.Sprint(args[2])))
+		_, _ = h.Write([]byte(fmt.Sprint(args[1])))
+
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
 	functions["sha1"] = func(args ...interface{}) (interface{}, error) {
 		h := sha1.New()
 		_, err := h.Write([]byte(args[0].(string)))
@@ -144,5 +183,192 @@ func HelperFunctions() (functions map[string]govaluate.ExpressionFunction) {
 		return compiled.MatchString(args[1].(string)), nil
 	}
 
+	functions["regex_count"] = func(args ...interface{}) (interface{}, error) {
+		compiled, err := regexp.Compile(args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		return float64(len(compiled.FindAllStringIndex(args[1].(string), -1))), nil
+	}
+
+	// regex_extract returns the first group of the first match, or the match without groups
+	functions["regex_extract"] = func(args ...interface{}) (interface{}, error) {
+		compiled, err := regexp.Compile(args[0].(string))
+		if err != nil {
+			return nil, err
+		}
+
+		match := compiled.FindStringSubmatch(args[1].(string))
+		switch len(match) {
+		case 0:
+			return "", nil
+		case 1:
+			return match[0], nil
+		}
+
+		return match[1], nil
+	}
+
+	// numbers
+	functions["to_number"] = func(args ...interface{}) (interface{}, error) {
+		if number, ok := args[0].(float64); ok {
+			return number, nil
+		}
+
+		number, err := strconv.ParseFloat(strings.TrimSpace(args[0].(string)), 64)
+		if err != nil {
+			return float64(0), nil
+		}
+
+		return number, nil
+	}
+
+	// versions
+	functions["compare_versions"] = func(args ...interface{}) (interface{}, error) {
+		return versions.Satisfies(args[0].(string), args[1].(string))
+	}
+
+	// jwt
+	functions["jwt_alg"] = func(args ...interface{}) (interface{}, error) {
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return token.Algorithm(), nil
+	}
+
+	// jwt_header(token, field) returns a field of the header of a token
+	functions["jwt_header"] = func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("jwt_header expects a token and a field")
+		}
+
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return jwtValue(token.HeaderField(fmt.Sprint(args[1]))), nil
+	}
+
+	// jwt_claim(token, claim) returns a claim of the payload of a token
+	functions["jwt_claim"] = func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("jwt_claim expects a token and a claim")
+		}
+
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return jwtValue(token.Claim(fmt.Sprint(args[1]))), nil
+	}
+
+	// jwt_none(token) returns the token with the none algorithm and
+	// without signature
+	functions["jwt_none"] = func(args ...interface{}) (interface{}, error) {
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return token.Unsigned(), nil
+	}
+
+	// jwt_sign(token, secret) returns the token signed with a secret
+	functions["jwt_sign"] = func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("jwt_sign expects a token and a secret")
+		}
+
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return token.Sign(fmt.Sprint(args[1])), nil
+	}
+
+	// jwt_verify(token, secret) returns true if the token is signed with a secret
+	functions["jwt_verify"] = func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("jwt_verify expects a token and a secret")
+		}
+
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		return token.Verify(fmt.Sprint(args[1])), nil
+	}
+
+	// jwt_crack(token, wordlist) returns the secret of a token among the
+	// secrets of a wordlist, the built-in jwt-secrets by default, or an
+	// empty string
+	functions["jwt_crack"] = func(args ...interface{}) (interface{}, error) {
+		token, err := jwt.Parse(fmt.Sprint(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		wordlist := BuiltinPrefix + "jwt-secrets"
+		if len(args) > 1 {
+			wordlist = fmt.Sprint(args[1])
+		}
+
+		secret, _ := token.Crack(jwtSecrets(wordlist))
+
+		return secret, nil
+	}
+
+	// time
+	functions["unix_time"] = func(args ...interface{}) (interface{}, error) {
+		return float64(time.Now().Unix()), nil
+	}
+
 	return functions
 }
+
+// jwtWordlists caches the wordlists of the secrets cracking the tokens,
+// loaded once for all the responses
+var jwtWordlists sync.Map
+
+// jwtSecrets returns the secrets of a built-in wordlist or a file
+func jwtSecrets(wordlist string) []string {
+	if secrets, ok := jwtWordlists.Load(wordlist); ok {
+		return secrets.([]string)
+	}
+
+	var secrets []string
+	if strings.HasPrefix(wordlist, BuiltinPrefix) {
+		secrets = builtinWordlists[strings.TrimPrefix(wordlist, BuiltinPrefix)]
+	} else {
+		secrets = LoadFile(wordlist)
+	}
+
+	jwtWordlists.Store(wordlist, secrets)
+
+	return secrets
+}
+
+// jwtValue returns a field of a token as a dsl value, the objects and the
+// arrays being json encoded and the missing fields empty
+func jwtValue(value interface{}) interface{} {
+	switch value.(type) {
+	case nil:
+		return ""
+	case string, float64, bool:
+		return value
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Knetic/govaluate"
+)
+
+// EncoderVariable is the name of the payload value inside encoder expressions
+const EncoderVariable = "payload"
+
+// Encoder transforms a payload value before it is substituted in a request
+type Encoder struct {
+	expression *govaluate.EvaluableExpression
+}
+
+// CompileEncoder compiles an encoder definition. A bare helper function
+// name like url_encode is applied to the payload, anything else is
+// evaluated as a DSL expression with the value available as payload.
+func CompileEncoder(definition string) (*Encoder, error) {
+	definition = strings.TrimSpace(definition)
+	if _, ok := HelperFunctions()[definition]; ok {
+		definition = fmt.Sprintf("%s(%s)", definition, EncoderVariable)
+	}
+
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(definition, HelperFunctions())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encoder{expression: expression}, nil
+}
+
+// Encode applies the encoder to a value, returning the value unchanged
+// if the expression could not be evaluated.
+func (e *Encoder) Encode(value string) string {
+	result, err := e.expression.Evaluate(map[string]interface{}{EncoderVariable: value})
+	if err != nil {
+		return value
+	}
+
+	return fmt.Sprint(result)
+}
+
+// EncodePayloads applies the encoders declared for each payload, in order,
+// to all of its values.
+func EncodePayloads(payloads map[string][]string, encoders map[string][]*Encoder) map[string][]string {
+	if len(encoders) == 0 {
+		return payloads
+	}
+
+	encoded := make(map[string][]string, len(payloads))
+
+	for name, values := range payloads {
+		chain, ok := encoders[name]
+		if !ok {
+			encoded[name] = values
+			continue
+		}
+
+		encodedValues := make([]string, len(values))
+
+		for i, value := range values {
+			for _, encoder := range chain {
+				value = encoder.Encode(value)
+			}
+
+			encodedValues[i] = value
+		}
+
+		encoded[name] = encodedValues
+	}
+
+	return encoded
+}
@@ -0,0 +1,55 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// HostPrefix is the prefix of the payloads referencing a wordlist discovered for each target
+const HostPrefix = "host:"
+
+// hostWordlistNames contains the wordlists that can be discovered for a target
+var hostWordlistNames = map[string]bool{
+	"paths": true,
+}
+
+// hostWordlists contains the wordlists discovered for each target
+var hostWordlists = struct {
+	sync.RWMutex
+	wordlists map[string]map[string][]string
+}{wordlists: make(map[string]map[string][]string)}
+
+// IsHostWordlist reports if a payload references a wordlist discovered for each target
+func IsHostWordlist(payload string) bool {
+	return strings.HasPrefix(payload, HostPrefix)
+}
+
+// ValidateHostWordlist checks that a host wordlist reference is known
+func ValidateHostWordlist(payload string) error {
+	if !hostWordlistNames[strings.TrimPrefix(payload, HostPrefix)] {
+		return fmt.Errorf("unknown host wordlist %s", payload)
+	}
+
+	return nil
+}
+
+// SetHostWordlist sets the values of a wordlist discovered for a target
+func SetHostWordlist(target, name string, values []string) {
+	hostWordlists.Lock()
+	defer hostWordlists.Unlock()
+
+	if hostWordlists.wordlists[target] == nil {
+		hostWordlists.wordlists[target] = make(map[string][]string)
+	}
+
+	hostWordlists.wordlists[target][name] = values
+}
+
+// HostWordlist returns the values of a host wordlist reference for a target
+func HostWordlist(target, payload string) []string {
+	hostWordlists.RLock()
+	defer hostWordlists.RUnlock()
+
+	return hostWordlists.wordlists[target][strings.TrimPrefix(payload, HostPrefix)]
+}
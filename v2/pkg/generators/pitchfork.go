@@ -1,8 +1,8 @@
 package generators
 
 // PitchforkGenerator Attack - Generate positional combinations from an input map with all values listed
-// as slices of the same size
-func PitchforkGenerator(payloads map[string][]string) (out chan map[string]interface{}) {
+// as slices of the same size, at most limit if greater than 0
+func PitchforkGenerator(payloads map[string][]string, limit int) (out chan map[string]interface{}) {
 	out = make(chan map[string]interface{})
 
 	size := 0
@@ -20,6 +20,10 @@ func PitchforkGenerator(payloads map[string][]string) (out chan map[string]inter
 		}
 	}
 
+	if limit > 0 && size > limit {
+		size = limit
+	}
+
 	// generator
 	go func() {
 		defer close(out)
@@ -0,0 +1,62 @@
+package generators
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Sampling bounds the payload permutations generated for a request
+type Sampling struct {
+	// Limit is the maximum number of permutations generated, 0 for no limit
+	Limit int
+	// Random shuffles the payload values so the permutations are sampled
+	// randomly instead of taken in wordlist order
+	Random bool
+	// Seed is the seed of the random sampling
+	Seed int64
+}
+
+// GetLimit returns the maximum number of permutations, 0 for no limit
+func (s *Sampling) GetLimit() int {
+	if s == nil {
+		return 0
+	}
+
+	return s.Limit
+}
+
+// Shuffle returns the payloads with their values in a random order
+// deterministic for the sampling seed.
+func (s *Sampling) Shuffle(payloads map[string][]string) map[string][]string {
+	if s == nil || !s.Random {
+		return payloads
+	}
+
+	random := rand.New(rand.NewSource(s.Seed))
+	shuffled := make(map[string][]string, len(payloads))
+
+	for _, name := range sortedNames(payloads) {
+		values := make([]string, len(payloads[name]))
+		copy(values, payloads[name])
+
+		random.Shuffle(len(values), func(i, j int) {
+			values[i], values[j] = values[j], values[i]
+		})
+
+		shuffled[name] = values
+	}
+
+	return shuffled
+}
+
+// sortedNames returns the payload names in a stable order
+func sortedNames(payloads map[string][]string) []string {
+	names := make([]string, 0, len(payloads))
+	for name := range payloads {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
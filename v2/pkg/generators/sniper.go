@@ -1,18 +1,25 @@
 package generators
 
-// SniperGenerator Attack - Generate sequential combinations
-func SniperGenerator(payloads map[string][]string) (out chan map[string]interface{}) {
+// SniperGenerator Attack - Generate sequential combinations, at most limit if greater than 0
+func SniperGenerator(payloads map[string][]string, limit int) (out chan map[string]interface{}) {
 	out = make(chan map[string]interface{})
 
 	// generator
 	go func() {
 		defer close(out)
 
-		for name, wordlist := range payloads {
-			for _, value := range wordlist {
+		count := 0
+
+		for _, name := range sortedNames(payloads) {
+			for _, value := range payloads[name] {
+				if limit > 0 && count >= limit {
+					return
+				}
+
 				element := CopyMapWithDefaultValue(payloads, "")
 				element[name] = value
 				out <- element
+				count++
 			}
 		}
 	}()
@@ -16,6 +16,11 @@ func LoadPayloads(payloads map[string]interface{}) map[string][]string {
 	for name, payload := range payloads {
 		switch pt := payload.(type) {
 		case string:
+			// host wordlists are only known once the targets are discovered
+			if IsHostWordlist(pt) {
+				continue
+			}
+
 			elements := strings.Split(pt, "\n")
 			if len(elements) >= two {
 				loadedPayloads[name] = elements
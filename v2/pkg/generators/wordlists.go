@@ -0,0 +1,124 @@
+package generators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/sso"
+)
+
+// BuiltinPrefix is the prefix of the payloads referencing a built-in wordlist
+const BuiltinPrefix = "builtin:"
+
+// downloadTimeout is the maximum time to download a remote wordlist
+const downloadTimeout = 30 * time.Second
+
+// builtinWordlists contains the wordlists bundled with nuclei
+var builtinWordlists = map[string][]string{
+	"usernames": {
+		"admin", "administrator", "root", "user", "test", "guest", "operator", "support", "manager", "demo",
+	},
+	"passwords": {
+		"admin", "password", "123456", "12345678", "root", "test", "guest", "changeme", "default", "letmein",
+		"qwerty", "password1", "admin123", "toor", "pass",
+	},
+	"backup-extensions": {
+		".bak", ".old", ".orig", ".backup", "~", ".swp", ".save", ".tmp", ".zip", ".tar.gz",
+	},
+	"http-methods": {
+		"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS", "TRACE", "CONNECT", "PROPFIND",
+	},
+	"oidc-metadata": sso.OIDCPaths,
+	"saml-metadata": sso.SAMLPaths,
+	"jwt-secrets": {
+		"secret", "secretkey", "secret-key", "secret_key", "your-256-bit-secret", "your-384-bit-secret",
+		"your-512-bit-secret", "jwt", "jwtsecret", "jwt-secret", "jwt_secret", "key", "changeme", "password",
+		"123456", "admin", "default", "test", "qwerty", "mysecret", "supersecret", "s3cr3t", "shhhhh",
+		"token", "private", "HS256", "gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr9C",
+	},
+}
+
+// downloadMutex serializes the downloads so a wordlist shared by templates is fetched once
+var downloadMutex sync.Mutex
+
+// BuiltinWordlist returns a built-in wordlist as a multiline string payload
+func BuiltinWordlist(name string) (string, error) {
+	wordlist, ok := builtinWordlists[strings.TrimPrefix(name, BuiltinPrefix)]
+	if !ok {
+		return "", fmt.Errorf("unknown built-in wordlist %s", name)
+	}
+
+	return strings.Join(wordlist, "\n"), nil
+}
+
+// IsRemoteWordlist checks if a payload references a remote wordlist
+func IsRemoteWordlist(payload string) bool {
+	return strings.HasPrefix(payload, "http://") || strings.HasPrefix(payload, "https://")
+}
+
+// DownloadWordlist downloads a remote wordlist in the user cache directory,
+// reusing the cached copy if any, and returns the path of the file.
+func DownloadWordlist(URL string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	directory := path.Join(cacheDir, "nuclei", "wordlists")
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(URL))
+	filename := path.Join(directory, hex.EncodeToString(hash[:])+".txt")
+
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+
+	if FileExists(filename) {
+		return filename, nil
+	}
+
+	client := &http.Client{Timeout: downloadTimeout}
+
+	resp, err := client.Get(URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d for wordlist %s", resp.StatusCode, URL)
+	}
+
+	// write to a temporary file first so an interrupted download isn't cached
+	tmpFile, err := ioutil.TempFile(directory, "download-*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+
+		return "", err
+	}
+
+	tmpFile.Close()
+
+	if err := os.Rename(tmpFile.Name(), filename); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+
+	return filename, nil
+}
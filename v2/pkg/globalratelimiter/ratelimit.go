@@ -6,37 +6,35 @@ import (
 	"go.uber.org/ratelimit"
 )
 
-var defaultrwmutex sync.RWMutex
-var defaultGlobalRateLimiter GlobalRateLimiter = GlobalRateLimiter{ratesLimiters: make(map[string]ratelimit.Limiter)}
+// RateLimiter blocks the requests to a target until they can be sent
+type RateLimiter interface {
+	// Take blocks until the next request to a target can be sent
+	Take(k string)
+}
 
-type GlobalRateLimiter struct {
-	sync.RWMutex
-	ratesLimiters map[string]ratelimit.Limiter
+var defaultGlobalRateLimiter = New()
+
+// Default returns the rate limiter shared by the package functions
+func Default() *GlobalRateLimiter {
+	return defaultGlobalRateLimiter
 }
 
 func Add(k string, rateLimit int) {
-	defaultrwmutex.Lock()
-	defer defaultrwmutex.Unlock()
-
-	if rateLimit > 0 {
-		defaultGlobalRateLimiter.ratesLimiters[k] = ratelimit.New(rateLimit)
-	} else {
-		defaultGlobalRateLimiter.ratesLimiters[k] = ratelimit.NewUnlimited()
-	}
+	defaultGlobalRateLimiter.Add(k, rateLimit)
 }
 
 func Take(k string) {
-	defaultrwmutex.RLock()
-	defer defaultrwmutex.RUnlock()
-
-	defaultGlobalRateLimiter.ratesLimiters[k].Take()
+	defaultGlobalRateLimiter.Take(k)
 }
 
 func Del(k string, rateLimit int) {
-	defaultrwmutex.Lock()
-	defer defaultrwmutex.Unlock()
+	defaultGlobalRateLimiter.Del(k, rateLimit)
+}
 
-	delete(defaultGlobalRateLimiter.ratesLimiters, k)
+// GlobalRateLimiter limits the rate of the requests of every target
+type GlobalRateLimiter struct {
+	sync.RWMutex
+	ratesLimiters map[string]ratelimit.Limiter
 }
 
 func New() *GlobalRateLimiter {
@@ -56,11 +54,16 @@ func (grl *GlobalRateLimiter) Add(k string, rateLimit int) {
 	}
 }
 
+// Take blocks until the next request to a target can be sent, the targets
+// without a limit being unlimited
 func (grl *GlobalRateLimiter) Take(k string) {
 	grl.RLock()
-	defer grl.RUnlock()
+	limiter, ok := grl.ratesLimiters[k]
+	grl.RUnlock()
 
-	grl.ratesLimiters[k].Take()
+	if ok {
+		limiter.Take()
+	}
 }
 
 func (grl *GlobalRateLimiter) Del(k string, rateLimit int) {
@@ -0,0 +1,344 @@
+package headeraudit
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHSTSMaxAge is the minimum max-age in seconds of the
+// Strict-Transport-Security headers, one year
+const DefaultHSTSMaxAge = 31536000
+
+// maxBodySize is the maximum size of the responses drained before closing them
+const maxBodySize = 64 * 1024
+
+// versionPattern matches the versions disclosed by the server headers
+var versionPattern = regexp.MustCompile(`\d+\.\d+`)
+
+// unsafeReferrerPolicies are the referrer policies sending the full urls
+// to other origins
+var unsafeReferrerPolicies = map[string]bool{
+	"unsafe-url":                 true,
+	"no-referrer-when-downgrade": true,
+}
+
+// Options contains the configuration of the auditor
+type Options struct {
+	// Checks contains the checks of the policy, in the order of their items
+	Checks []string
+	// HSTSMaxAge is the minimum max-age of the Strict-Transport-Security headers
+	HSTSMaxAge int
+	// Timeout is the timeout of the http requests
+	Timeout time.Duration
+	// Transport is the transport of the http requests, the default one
+	// when nil
+	Transport http.RoundTripper
+	// OnRequest is optionally called for every request sent
+	OnRequest func()
+}
+
+// Item is the result of a check of the policy for a response
+type Item struct {
+	// Check is the name of the check
+	Check string
+	// Passed reports if the response complies with the check
+	Passed bool
+	// Detail describes the header values or the cookies failing the check
+	Detail string
+}
+
+// Auditor audits the responses of urls against a policy
+type Auditor struct {
+	options    *Options
+	httpClient *http.Client
+}
+
+// New creates an auditor
+func New(options *Options) *Auditor {
+	if options.HSTSMaxAge <= 0 {
+		options.HSTSMaxAge = DefaultHSTSMaxAge
+	}
+
+	return &Auditor{
+		options: options,
+		httpClient: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: options.Transport,
+		},
+	}
+}
+
+// Audit requests a url, following its redirects, and returns the items of
+// the checks of the policy for the final response
+func (a *Auditor) Audit(URL string) ([]*Item, error) {
+	req, err := http.NewRequest(http.MethodGet, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.options.OnRequest != nil {
+		a.options.OnRequest()
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxBodySize))
+	resp.Body.Close()
+
+	return a.Evaluate(resp), nil
+}
+
+// Evaluate returns the items of the checks of the policy for a response.
+// The checks not applying to the response, like the cookie checks of the
+// responses without cookies, have no item.
+func (a *Auditor) Evaluate(resp *http.Response) []*Item {
+	https := resp.Request != nil && resp.Request.URL.Scheme == "https"
+	cookies := resp.Cookies()
+
+	items := make([]*Item, 0, len(a.options.Checks))
+	for _, check := range a.options.Checks {
+		var item *Item
+
+		switch check {
+		case CheckHSTS, CheckHSTSSubdomains:
+			// the browsers ignore the header sent over http
+			if !https {
+				continue
+			}
+			item = a.checkHSTS(check, resp.Header)
+		case CheckCSP:
+			item = checkPresent(check, resp.Header, "Content-Security-Policy")
+		case CheckCSPUnsafe:
+			item = checkCSPUnsafe(resp.Header)
+		case CheckFrameOptions:
+			item = checkFrameOptions(resp.Header)
+		case CheckContentTypeOptions:
+			value := resp.Header.Get("X-Content-Type-Options")
+			item = &Item{Check: check, Passed: strings.EqualFold(strings.TrimSpace(value), "nosniff"), Detail: describe("X-Content-Type-Options", value)}
+		case CheckReferrerPolicy:
+			item = checkReferrerPolicy(resp.Header)
+		case CheckPermissionsPolicy:
+			item = checkPresent(check, resp.Header, "Permissions-Policy")
+		case CheckServerDisclosure:
+			item = checkServerDisclosure(resp.Header)
+		case CheckCookieSecure, CheckCookieHTTPOnly, CheckCookieSameSite:
+			if len(cookies) == 0 {
+				continue
+			}
+			item = checkCookies(check, cookies)
+		default:
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// describe returns the detail of a header value
+func describe(name, value string) string {
+	if value == "" {
+		return "missing " + name
+	}
+
+	return name + ": " + value
+}
+
+// checkPresent checks that a header is set
+func checkPresent(check string, header http.Header, name string) *Item {
+	value := header.Get(name)
+
+	return &Item{Check: check, Passed: value != "", Detail: describe(name, value)}
+}
+
+// checkHSTS checks the max-age or the subdomains of the
+// Strict-Transport-Security header
+func (a *Auditor) checkHSTS(check string, header http.Header) *Item {
+	value := header.Get("Strict-Transport-Security")
+	item := &Item{Check: check, Detail: describe("Strict-Transport-Security", value)}
+
+	if value == "" {
+		return item
+	}
+
+	maxAge, subdomains := -1, false
+	for _, directive := range strings.Split(value, ";") {
+		directive = strings.TrimSpace(directive)
+		parts := strings.SplitN(directive, "=", 2)
+
+		switch strings.ToLower(parts[0]) {
+		case "max-age":
+			if len(parts) == 2 {
+				if age, err := strconv.Atoi(strings.Trim(parts[1], `"`)); err == nil {
+					maxAge = age
+				}
+			}
+		case "includesubdomains":
+			subdomains = true
+		}
+	}
+
+	if check == CheckHSTSSubdomains {
+		item.Passed = subdomains
+	} else {
+		item.Passed = maxAge >= a.options.HSTSMaxAge
+	}
+
+	return item
+}
+
+// cspDirectives returns the sources of the directives of the content
+// security policies of a response
+func cspDirectives(header http.Header) map[string][]string {
+	directives := make(map[string][]string)
+
+	for _, policy := range header.Values("Content-Security-Policy") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(directive)
+			if len(fields) == 0 {
+				continue
+			}
+
+			name := strings.ToLower(fields[0])
+			if _, ok := directives[name]; !ok {
+				directives[name] = fields[1:]
+			}
+		}
+	}
+
+	return directives
+}
+
+// checkCSPUnsafe checks that the content security policy doesn't allow
+// inline scripts, eval or scripts from any source
+func checkCSPUnsafe(header http.Header) *Item {
+	directives := cspDirectives(header)
+
+	sources, ok := directives["script-src"]
+	if !ok {
+		sources, ok = directives["default-src"]
+	}
+
+	if !ok {
+		return &Item{Check: CheckCSPUnsafe, Detail: "missing script-src and default-src directives"}
+	}
+
+	var unsafe []string
+	nonced := false
+	for _, source := range sources {
+		lower := strings.ToLower(source)
+		if strings.HasPrefix(lower, "'nonce-") || strings.HasPrefix(lower, "'sha") || lower == "'strict-dynamic'" {
+			nonced = true
+		}
+	}
+
+	for _, source := range sources {
+		switch strings.ToLower(source) {
+		case "'unsafe-inline'":
+			// the browsers ignore unsafe-inline along with nonces or hashes
+			if !nonced {
+				unsafe = append(unsafe, source)
+			}
+		case "'unsafe-eval'", "*", "data:", "http:", "https:":
+			unsafe = append(unsafe, source)
+		}
+	}
+
+	if len(unsafe) > 0 {
+		return &Item{Check: CheckCSPUnsafe, Detail: "unsafe script sources " + strings.Join(unsafe, " ")}
+	}
+
+	return &Item{Check: CheckCSPUnsafe, Passed: true, Detail: "script sources " + strings.Join(sources, " ")}
+}
+
+// checkFrameOptions checks that the response can only be framed by
+// trusted origins
+func checkFrameOptions(header http.Header) *Item {
+	if ancestors, ok := cspDirectives(header)["frame-ancestors"]; ok {
+		for _, source := range ancestors {
+			if source == "*" {
+				return &Item{Check: CheckFrameOptions, Detail: "frame-ancestors allows any origin"}
+			}
+		}
+
+		return &Item{Check: CheckFrameOptions, Passed: true, Detail: "frame-ancestors " + strings.Join(ancestors, " ")}
+	}
+
+	value := strings.TrimSpace(header.Get("X-Frame-Options"))
+	passed := strings.EqualFold(value, "DENY") || strings.EqualFold(value, "SAMEORIGIN")
+
+	return &Item{Check: CheckFrameOptions, Passed: passed, Detail: describe("X-Frame-Options", value)}
+}
+
+// checkReferrerPolicy checks that the referrer policy doesn't send the
+// full urls to other origins
+func checkReferrerPolicy(header http.Header) *Item {
+	value := header.Get("Referrer-Policy")
+	item := &Item{Check: CheckReferrerPolicy, Detail: describe("Referrer-Policy", value)}
+
+	if value == "" {
+		return item
+	}
+
+	// the browsers use the last policy they support
+	policies := strings.Split(value, ",")
+	last := strings.ToLower(strings.TrimSpace(policies[len(policies)-1]))
+	item.Passed = !unsafeReferrerPolicies[last]
+
+	return item
+}
+
+// checkServerDisclosure checks that the server headers don't disclose
+// versions
+func checkServerDisclosure(header http.Header) *Item {
+	var disclosed []string
+
+	for _, name := range []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"} {
+		if value := header.Get(name); value != "" && versionPattern.MatchString(value) {
+			disclosed = append(disclosed, name+": "+value)
+		}
+	}
+
+	if len(disclosed) > 0 {
+		return &Item{Check: CheckServerDisclosure, Detail: strings.Join(disclosed, ", ")}
+	}
+
+	return &Item{Check: CheckServerDisclosure, Passed: true, Detail: "no versions disclosed"}
+}
+
+// checkCookies checks an attribute of the cookies set by the response
+func checkCookies(check string, cookies []*http.Cookie) *Item {
+	var failed []string
+
+	for _, cookie := range cookies {
+		var passed bool
+
+		switch check {
+		case CheckCookieSecure:
+			passed = cookie.Secure
+		case CheckCookieHTTPOnly:
+			passed = cookie.HttpOnly
+		case CheckCookieSameSite:
+			passed = cookie.SameSite == http.SameSiteLaxMode || cookie.SameSite == http.SameSiteStrictMode
+		}
+
+		if !passed {
+			failed = append(failed, cookie.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return &Item{Check: check, Detail: fmt.Sprintf("cookies %s", strings.Join(failed, ", "))}
+	}
+
+	return &Item{Check: check, Passed: true, Detail: fmt.Sprintf("%d cookies", len(cookies))}
+}
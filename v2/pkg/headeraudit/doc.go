@@ -0,0 +1,4 @@
+// Package headeraudit audits the security headers and the cookies of the
+// responses of hosts against policy profiles, reporting a pass or fail
+// item for each check of the profile.
+package headeraudit
@@ -0,0 +1,143 @@
+package headeraudit
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Header checks
+const (
+	// CheckHSTS reports the https responses without a Strict-Transport-Security
+	// header, or with a max-age below the minimum
+	CheckHSTS = "hsts"
+	// CheckHSTSSubdomains reports the Strict-Transport-Security headers not
+	// including the subdomains
+	CheckHSTSSubdomains = "hsts-subdomains"
+	// CheckCSP reports the responses without a Content-Security-Policy header
+	CheckCSP = "csp"
+	// CheckCSPUnsafe reports the content security policies allowing inline
+	// scripts, eval or any source for the scripts
+	CheckCSPUnsafe = "csp-unsafe"
+	// CheckFrameOptions reports the responses which can be framed by any
+	// origin, without X-Frame-Options nor a frame-ancestors policy
+	CheckFrameOptions = "frame-options"
+	// CheckContentTypeOptions reports the responses without
+	// X-Content-Type-Options: nosniff
+	CheckContentTypeOptions = "content-type-options"
+	// CheckReferrerPolicy reports the responses without a Referrer-Policy
+	// header, or leaking the full urls to other origins
+	CheckReferrerPolicy = "referrer-policy"
+	// CheckPermissionsPolicy reports the responses without a
+	// Permissions-Policy header
+	CheckPermissionsPolicy = "permissions-policy"
+	// CheckServerDisclosure reports the Server and X-Powered-By headers
+	// disclosing the versions of the software
+	CheckServerDisclosure = "server-disclosure"
+	// CheckCookieSecure reports the cookies set without the Secure attribute
+	CheckCookieSecure = "cookie-secure"
+	// CheckCookieHTTPOnly reports the cookies set without the HttpOnly attribute
+	CheckCookieHTTPOnly = "cookie-httponly"
+	// CheckCookieSameSite reports the cookies set without a SameSite
+	// attribute, or with SameSite=None
+	CheckCookieSameSite = "cookie-samesite"
+)
+
+// Policy profiles
+const (
+	// ProfileBaseline checks the headers expected from every web application
+	ProfileBaseline = "baseline"
+	// ProfileStrict checks the baseline and the hardening headers
+	ProfileStrict = "strict"
+)
+
+// DefaultProfile is the profile of the audits not naming one
+const DefaultProfile = ProfileBaseline
+
+// Checks contains the supported header checks
+var Checks = map[string]bool{
+	CheckHSTS:               true,
+	CheckHSTSSubdomains:     true,
+	CheckCSP:                true,
+	CheckCSPUnsafe:          true,
+	CheckFrameOptions:       true,
+	CheckContentTypeOptions: true,
+	CheckReferrerPolicy:     true,
+	CheckPermissionsPolicy:  true,
+	CheckServerDisclosure:   true,
+	CheckCookieSecure:       true,
+	CheckCookieHTTPOnly:     true,
+	CheckCookieSameSite:     true,
+}
+
+// Profiles contains the checks of the policy profiles, in the order of
+// their items
+var Profiles = map[string][]string{
+	ProfileBaseline: {
+		CheckHSTS,
+		CheckCSP,
+		CheckFrameOptions,
+		CheckContentTypeOptions,
+		CheckCookieSecure,
+		CheckCookieHTTPOnly,
+	},
+	ProfileStrict: {
+		CheckHSTS,
+		CheckHSTSSubdomains,
+		CheckCSP,
+		CheckCSPUnsafe,
+		CheckFrameOptions,
+		CheckContentTypeOptions,
+		CheckReferrerPolicy,
+		CheckPermissionsPolicy,
+		CheckServerDisclosure,
+		CheckCookieSecure,
+		CheckCookieHTTPOnly,
+		CheckCookieSameSite,
+	},
+}
+
+// ProfileNames returns the names of the policy profiles
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Policy returns the checks of a profile with the checks added to it and
+// without the checks excluded from it
+func Policy(profile string, added, excluded []string) ([]string, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	checks, ok := Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown audit profile %s", profile)
+	}
+
+	skipped := make(map[string]bool, len(excluded))
+	for _, check := range append(append([]string{}, added...), excluded...) {
+		if !Checks[check] {
+			return nil, fmt.Errorf("unknown audit check %s", check)
+		}
+	}
+	for _, check := range excluded {
+		skipped[check] = true
+	}
+
+	policy := make([]string, 0, len(checks)+len(added))
+	for _, check := range append(append([]string{}, checks...), added...) {
+		if skipped[check] {
+			continue
+		}
+		skipped[check] = true
+
+		policy = append(policy, check)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,3 @@
+// Package ignore suppresses known and accepted findings
+// before they are written to any output.
+package ignore
@@ -0,0 +1,140 @@
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// dateLayout is the layout of the expiry dates of the rules
+const dateLayout = "2006-01-02"
+
+// Rule suppresses the findings of a template on matching targets
+type Rule struct {
+	// Template is the id of the template whose findings are suppressed
+	Template string `yaml:"template"`
+	// Host optionally restricts the rule to a single host
+	Host string `yaml:"host,omitempty"`
+	// Pattern optionally restricts the rule to matched values matching a regex
+	Pattern string `yaml:"pattern,omitempty"`
+	// Expires optionally sets the date (YYYY-MM-DD) after which the rule is no longer applied
+	Expires string `yaml:"expires,omitempty"`
+	// Reason describes why the finding was accepted
+	Reason string `yaml:"reason,omitempty"`
+
+	pattern *regexp.Regexp
+	expires time.Time
+}
+
+// List is a list of rules suppressing findings
+type List struct {
+	rules []*Rule
+}
+
+// Load reads a yaml file containing a list of ignore rules
+func Load(file string) (*List, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []*Rule
+
+	err = yaml.NewDecoder(f).Decode(&rules)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if rule.Template == "" {
+			return nil, errors.New("ignore rule without template")
+		}
+
+		if rule.Pattern != "" {
+			rule.pattern, err = regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern for template %s: %s", rule.Template, err)
+			}
+		}
+
+		if rule.Expires != "" {
+			rule.expires, err = time.Parse(dateLayout, rule.Expires)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expiry date for template %s: %s", rule.Template, err)
+			}
+		}
+	}
+
+	return &List{rules: rules}, nil
+}
+
+// Ignored returns true if the finding of a template on a matched value is suppressed
+func (l *List) Ignored(templateID, matched string) bool {
+	if l == nil {
+		return false
+	}
+
+	now := time.Now()
+
+	for _, rule := range l.rules {
+		if rule.Template != templateID {
+			continue
+		}
+
+		if rule.expired(now) {
+			continue
+		}
+
+		if rule.Host != "" && rule.Host != hostOf(matched) {
+			continue
+		}
+
+		if rule.pattern != nil && !rule.pattern.MatchString(matched) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Expired returns the rules whose expiry date has passed
+func (l *List) Expired() []*Rule {
+	if l == nil {
+		return nil
+	}
+
+	var expired []*Rule
+
+	now := time.Now()
+
+	for _, rule := range l.rules {
+		if rule.expired(now) {
+			expired = append(expired, rule)
+		}
+	}
+
+	return expired
+}
+
+// expired returns true if the rule expiry day has passed
+func (r *Rule) expired(now time.Time) bool {
+	return !r.expires.IsZero() && now.After(r.expires.AddDate(0, 0, 1))
+}
+
+// hostOf returns the host of a matched value, which is either an URL or a domain
+func hostOf(matched string) string {
+	parsed, err := url.Parse(matched)
+	if err != nil || parsed.Host == "" {
+		return matched
+	}
+
+	return parsed.Hostname()
+}
@@ -0,0 +1,95 @@
+package ignore
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeRules writes the rules to a temporary file, returning its path
+func writeRules(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "ignore")
+	require.Nil(t, err, "Could not create ignore file")
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+	require.Nil(t, err, "Could not write ignore file")
+
+	return file.Name()
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		rules   int
+		err     bool
+	}{
+		{name: "valid", content: "- template: a\n- template: b\n  host: example.com\n  pattern: admin\n  expires: 2020-01-01\n", rules: 2},
+		{name: "empty", content: "[]\n", rules: 0},
+		{name: "missing template", content: "- host: example.com\n", err: true},
+		{name: "invalid pattern", content: "- template: a\n  pattern: \"(\"\n", err: true},
+		{name: "invalid date", content: "- template: a\n  expires: 01/02/2020\n", err: true},
+		{name: "invalid yaml", content: "template: a\n", err: true},
+	}
+
+	for _, test := range tests {
+		file := writeRules(t, test.content)
+
+		list, err := Load(file)
+		os.Remove(file)
+
+		if test.err {
+			require.NotNil(t, err, "Could load invalid %s file", test.name)
+			continue
+		}
+
+		require.Nil(t, err, "Could not load valid %s file", test.name)
+		require.Len(t, list.rules, test.rules, "Could not load rules of %s file", test.name)
+	}
+
+	_, err := Load("/non/existent/ignore.yaml")
+	require.NotNil(t, err, "Could load a missing file")
+}
+
+func TestIgnored(t *testing.T) {
+	future := time.Now().AddDate(1, 0, 0).Format(dateLayout)
+
+	file := writeRules(t, "- template: host\n  host: example.com\n"+
+		"- template: pattern\n  pattern: /admin\n"+
+		"- template: expired\n  expires: 2000-01-01\n"+
+		"- template: valid\n  expires: "+future+"\n")
+	defer os.Remove(file)
+
+	list, err := Load(file)
+	require.Nil(t, err, "Could not load ignore file")
+
+	tests := []struct {
+		template string
+		matched  string
+		ignored  bool
+	}{
+		{template: "host", matched: "https://example.com/path", ignored: true},
+		{template: "host", matched: "example.com", ignored: true},
+		{template: "host", matched: "https://other.com/path", ignored: false},
+		{template: "pattern", matched: "https://example.com/admin", ignored: true},
+		{template: "pattern", matched: "https://example.com/", ignored: false},
+		{template: "expired", matched: "https://example.com/", ignored: false},
+		{template: "valid", matched: "https://example.com/", ignored: true},
+		{template: "unknown", matched: "https://example.com/", ignored: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.ignored, list.Ignored(test.template, test.matched), "Could not check %s on %s", test.template, test.matched)
+	}
+
+	expired := list.Expired()
+	require.Len(t, expired, 1, "Could not get expired rules")
+	require.Equal(t, "expired", expired[0].Template, "Could not get expired rule")
+
+	var nilList *List
+	require.False(t, nilList.Ignored("host", "example.com"), "Could ignore with a nil list")
+}
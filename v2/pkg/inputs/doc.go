@@ -0,0 +1,3 @@
+// Package inputs imports scan targets from API descriptions
+// like OpenAPI documents and Postman collections.
+package inputs
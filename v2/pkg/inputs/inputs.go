@@ -0,0 +1,74 @@
+package inputs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Endpoint is a concrete request target derived from an API description
+type Endpoint struct {
+	// Method is the http method of the endpoint
+	Method string
+	// URL is the url of the endpoint with its parameters filled with stubs
+	URL string
+	// Headers contains the headers sent to the endpoint, including authentication
+	Headers map[string]string
+}
+
+// CommonHeaders returns the headers sent with the same value to every endpoint
+func CommonHeaders(endpoints []Endpoint) map[string]string {
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	common := make(map[string]string)
+	for name, value := range endpoints[0].Headers {
+		common[name] = value
+	}
+
+	for _, endpoint := range endpoints[1:] {
+		for name, value := range common {
+			if endpoint.Headers[name] != value {
+				delete(common, name)
+			}
+		}
+	}
+
+	return common
+}
+
+// buildURL joins a base url and a path, setting the query parameters
+func buildURL(base, path string, query map[string]string) (string, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", fmt.Errorf("%s is not an absolute url", parsed)
+	}
+
+	if len(query) > 0 {
+		values := parsed.Query()
+		for name, value := range query {
+			values.Set(name, value)
+		}
+
+		parsed.RawQuery = values.Encode()
+	}
+
+	return parsed.String(), nil
+}
+
+// sortEndpoints sorts the endpoints so the imported targets are stable
+func sortEndpoints(endpoints []Endpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].URL == endpoints[j].URL {
+			return endpoints[i].Method < endpoints[j].Method
+		}
+
+		return endpoints[i].URL < endpoints[j].URL
+	})
+}
@@ -0,0 +1,253 @@
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// openAPIDocument contains the parts of a Swagger 2.0 or OpenAPI 3 document used to build endpoints
+type openAPIDocument struct {
+	Swagger    string                      `yaml:"swagger"`
+	Host       string                      `yaml:"host"`
+	BasePath   string                      `yaml:"basePath"`
+	Schemes    []string                    `yaml:"schemes"`
+	Servers    []openAPIServer             `yaml:"servers"`
+	Paths      map[string]openAPIPathItem  `yaml:"paths"`
+	Parameters map[string]openAPIParameter `yaml:"parameters"`
+	Components openAPIComponents           `yaml:"components"`
+}
+
+type openAPIServer struct {
+	URL string `yaml:"url"`
+}
+
+type openAPIComponents struct {
+	Parameters map[string]openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+	Options    *openAPIOperation  `yaml:"options"`
+	Head       *openAPIOperation  `yaml:"head"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+}
+
+type openAPIOperation struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIParameter struct {
+	Ref     string         `yaml:"$ref"`
+	Name    string         `yaml:"name"`
+	In      string         `yaml:"in"`
+	Type    string         `yaml:"type"`
+	Example interface{}    `yaml:"example"`
+	Default interface{}    `yaml:"default"`
+	Enum    []interface{}  `yaml:"enum"`
+	Schema  *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Type    string        `yaml:"type"`
+	Example interface{}   `yaml:"example"`
+	Default interface{}   `yaml:"default"`
+	Enum    []interface{} `yaml:"enum"`
+}
+
+// OpenAPI reads a Swagger 2.0 or OpenAPI 3 document, in json or yaml, and returns
+// an endpoint for each operation with its path and query parameters filled with stubs.
+// The base url overrides the servers of the document and resolves relative ones.
+func OpenAPI(file, base string) ([]Endpoint, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &openAPIDocument{}
+
+	err = yaml.Unmarshal(data, document)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(document.Paths) == 0 {
+		return nil, errors.New("no paths found in the document")
+	}
+
+	servers, err := document.servers(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+
+	for path, item := range document.Paths {
+		for method, operation := range item.operations() {
+			pathParameters := make(map[string]string)
+			query := make(map[string]string)
+
+			parameters := append(append([]openAPIParameter{}, item.Parameters...), operation.Parameters...)
+
+			for _, parameter := range parameters {
+				parameter = document.resolve(parameter)
+
+				switch parameter.In {
+				case "path":
+					pathParameters[parameter.Name] = parameter.stub()
+				case "query":
+					query[parameter.Name] = parameter.stub()
+				}
+			}
+
+			concretePath := path
+			for name, value := range pathParameters {
+				concretePath = strings.ReplaceAll(concretePath, "{"+name+"}", value)
+			}
+
+			for _, server := range servers {
+				endpointURL, err := buildURL(server, concretePath, query)
+				if err != nil {
+					return nil, err
+				}
+
+				endpoints = append(endpoints, Endpoint{Method: method, URL: endpointURL})
+			}
+		}
+	}
+
+	sortEndpoints(endpoints)
+
+	return endpoints, nil
+}
+
+// servers returns the base urls the paths of the document are relative to
+func (d *openAPIDocument) servers(base string) ([]string, error) {
+	base = strings.TrimSuffix(base, "/")
+
+	// Swagger 2.0 describes a single host with its schemes
+	if d.Swagger != "" {
+		if base != "" {
+			return []string{base + d.BasePath}, nil
+		}
+
+		if d.Host == "" {
+			return nil, errors.New("no host in the document, a base url is required")
+		}
+
+		schemes := d.Schemes
+		if len(schemes) == 0 {
+			schemes = []string{"https"}
+		}
+
+		var servers []string
+		for _, scheme := range schemes {
+			servers = append(servers, fmt.Sprintf("%s://%s%s", scheme, d.Host, d.BasePath))
+		}
+
+		return servers, nil
+	}
+
+	var servers []string
+
+	for _, server := range d.Servers {
+		switch {
+		case strings.HasPrefix(server.URL, "http://"), strings.HasPrefix(server.URL, "https://"):
+			if base != "" {
+				continue
+			}
+			servers = append(servers, server.URL)
+		case base != "":
+			servers = append(servers, base+"/"+strings.TrimPrefix(server.URL, "/"))
+		}
+	}
+
+	if len(servers) == 0 {
+		if base == "" {
+			return nil, errors.New("no absolute server url in the document, a base url is required")
+		}
+
+		servers = append(servers, base)
+	}
+
+	return servers, nil
+}
+
+// resolve returns the parameter a local reference points to
+func (d *openAPIDocument) resolve(parameter openAPIParameter) openAPIParameter {
+	switch {
+	case strings.HasPrefix(parameter.Ref, "#/parameters/"):
+		return d.Parameters[strings.TrimPrefix(parameter.Ref, "#/parameters/")]
+	case strings.HasPrefix(parameter.Ref, "#/components/parameters/"):
+		return d.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+	}
+
+	return parameter
+}
+
+// operations returns the operations of a path by http method
+func (p openAPIPathItem) operations() map[string]*openAPIOperation {
+	operations := make(map[string]*openAPIOperation)
+
+	for method, operation := range map[string]*openAPIOperation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+
+	return operations
+}
+
+// stub returns a value for a parameter from its example, default,
+// enumeration or type, in this order.
+func (p openAPIParameter) stub() string {
+	example, def, enum, typ := p.Example, p.Default, p.Enum, p.Type
+	if p.Schema != nil {
+		if example == nil {
+			example = p.Schema.Example
+		}
+		if def == nil {
+			def = p.Schema.Default
+		}
+		if len(enum) == 0 {
+			enum = p.Schema.Enum
+		}
+		if typ == "" {
+			typ = p.Schema.Type
+		}
+	}
+
+	switch {
+	case example != nil:
+		return fmt.Sprint(example)
+	case def != nil:
+		return fmt.Sprint(def)
+	case len(enum) > 0:
+		return fmt.Sprint(enum[0])
+	}
+
+	switch typ {
+	case "integer", "number":
+		return "1"
+	case "boolean":
+		return "true"
+	default:
+		return "test"
+	}
+}
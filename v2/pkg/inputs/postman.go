@@ -0,0 +1,221 @@
+package inputs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// postmanVariable matches the {{variable}} references of a collection
+var postmanVariable = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// postmanCollection is a Postman v2 collection
+type postmanCollection struct {
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Auth    *postmanAuth    `json:"auth"`
+	Request *postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	URL    interface{}       `json:"url"`
+	Auth   *postmanAuth      `json:"auth"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer"`
+	Basic  []postmanKeyValue `json:"basic"`
+	APIKey []postmanKeyValue `json:"apikey"`
+}
+
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+	Enabled  *bool       `json:"enabled"`
+}
+
+// postmanEnvironment is a Postman environment export
+type postmanEnvironment struct {
+	Values []postmanKeyValue `json:"values"`
+}
+
+// Postman reads a Postman v2 collection, and an optional environment, and returns an
+// endpoint for each request with the variables resolved and the authentication
+// inherited from the folders and collection turned into headers.
+func Postman(collectionFile, environmentFile string) ([]Endpoint, error) {
+	collection := &postmanCollection{}
+	if err := readJSON(collectionFile, collection); err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]string)
+	for _, variable := range collection.Variable {
+		if variable.enabled() {
+			variables[variable.Key] = fmt.Sprint(variable.Value)
+		}
+	}
+
+	// the environment overrides the collection variables
+	if environmentFile != "" {
+		environment := &postmanEnvironment{}
+		if err := readJSON(environmentFile, environment); err != nil {
+			return nil, err
+		}
+
+		for _, variable := range environment.Values {
+			if variable.enabled() {
+				variables[variable.Key] = fmt.Sprint(variable.Value)
+			}
+		}
+	}
+
+	resolve := func(value string) string {
+		return postmanVariable.ReplaceAllStringFunc(value, func(match string) string {
+			if resolved, ok := variables[postmanVariable.FindStringSubmatch(match)[1]]; ok {
+				return resolved
+			}
+			return match
+		})
+	}
+
+	var endpoints []Endpoint
+
+	var walk func(items []postmanItem, auth *postmanAuth)
+	walk = func(items []postmanItem, auth *postmanAuth) {
+		for _, item := range items {
+			itemAuth := auth
+			if item.Auth != nil {
+				itemAuth = item.Auth
+			}
+
+			if item.Request == nil {
+				walk(item.Item, itemAuth)
+				continue
+			}
+
+			if item.Request.Auth != nil {
+				itemAuth = item.Request.Auth
+			}
+
+			endpoint := Endpoint{
+				Method:  strings.ToUpper(item.Request.Method),
+				URL:     resolve(item.Request.rawURL()),
+				Headers: make(map[string]string),
+			}
+
+			if endpoint.Method == "" {
+				endpoint.Method = http.MethodGet
+			}
+
+			for _, header := range item.Request.Header {
+				if header.enabled() {
+					endpoint.Headers[header.Key] = resolve(fmt.Sprint(header.Value))
+				}
+			}
+
+			if name, value := itemAuth.header(); name != "" {
+				endpoint.Headers[name] = resolve(value)
+			}
+
+			// requests still referencing unknown variables can't be sent
+			if postmanVariable.MatchString(endpoint.URL) || endpoint.URL == "" {
+				continue
+			}
+
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	walk(collection.Item, collection.Auth)
+
+	if len(endpoints) == 0 {
+		return nil, errors.New("no resolvable requests found in the collection")
+	}
+
+	sortEndpoints(endpoints)
+
+	return endpoints, nil
+}
+
+// readJSON decodes a json file
+func readJSON(file string, v interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	return jsoniter.Unmarshal(data, v)
+}
+
+// rawURL returns the url of a request, described either as a string or an object
+func (r *postmanRequest) rawURL() string {
+	switch u := r.URL.(type) {
+	case string:
+		return u
+	case map[string]interface{}:
+		if raw, ok := u["raw"].(string); ok {
+			return raw
+		}
+	}
+
+	return ""
+}
+
+// header returns the header sending the credentials of an authentication
+func (a *postmanAuth) header() (name, value string) {
+	if a == nil {
+		return "", ""
+	}
+
+	switch a.Type {
+	case "bearer":
+		return "Authorization", "Bearer " + postmanValue(a.Bearer, "token")
+	case "basic":
+		credentials := postmanValue(a.Basic, "username") + ":" + postmanValue(a.Basic, "password")
+		return "Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	case "apikey":
+		// api keys sent in the query can't be expressed as a header
+		if postmanValue(a.APIKey, "in") == "query" {
+			return "", ""
+		}
+
+		return postmanValue(a.APIKey, "key"), postmanValue(a.APIKey, "value")
+	}
+
+	return "", ""
+}
+
+// enabled reports if a key value pair is active
+func (kv postmanKeyValue) enabled() bool {
+	if kv.Enabled != nil {
+		return *kv.Enabled
+	}
+
+	return !kv.Disabled
+}
+
+// postmanValue returns the value of a key in a list of key value pairs
+func postmanValue(values []postmanKeyValue, key string) string {
+	for _, kv := range values {
+		if kv.Key == key {
+			return fmt.Sprint(kv.Value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,4 @@
+// Package jarm computes the JARM fingerprint of a tls server from its
+// answers to ten crafted client hellos, along with the JA3S fingerprint
+// of its first server hello.
+package jarm
@@ -0,0 +1,438 @@
+package jarm
+
+import (
+	"crypto/md5" // nolint:gosec // ja3s fingerprints are md5 digests
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxResponse is the size of the server responses read, as the reference implementation
+const maxResponse = 1484
+
+// emptyJARM is the fingerprint of servers not answering any probe
+var emptyJARM = strings.Repeat("0", 62)
+
+// Result contains the fingerprints of a server
+type Result struct {
+	// JARM is the jarm fingerprint of the server
+	JARM string
+	// JA3S is the ja3s fingerprint of the server hello answering the first probe
+	JA3S string
+	// Raw contains the raw results of each probe
+	Raw []string
+}
+
+// Fingerprint sends the probes to a server over the connections returned
+// by dial, host being sent as the server name.
+func Fingerprint(dial func() (net.Conn, error), host string) (*Result, error) {
+	result := &Result{}
+
+	answered := false
+
+	for i := range probes {
+		conn, err := dial()
+		if err != nil {
+			// the server is unreachable rather than refusing the probe
+			if i == 0 {
+				return nil, err
+			}
+
+			result.Raw = append(result.Raw, "|||")
+
+			continue
+		}
+
+		data, err := exchange(conn, buildClientHello(&probes[i], host))
+		conn.Close()
+
+		if err != nil {
+			result.Raw = append(result.Raw, "|||")
+			continue
+		}
+
+		raw := parseServerHello(data)
+		result.Raw = append(result.Raw, raw)
+
+		if raw != "|||" {
+			answered = true
+
+			if result.JA3S == "" {
+				result.JA3S = ja3s(data)
+			}
+		}
+	}
+
+	if !answered {
+		result.JARM = emptyJARM
+		return result, nil
+	}
+
+	result.JARM = hash(result.Raw)
+
+	return result, nil
+}
+
+// exchange sends a client hello and reads the beginning of the response
+func exchange(conn net.Conn, clientHello []byte) ([]byte, error) {
+	if _, err := conn.Write(clientHello); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, maxResponse)
+
+	n, err := io.ReadAtLeast(conn, data, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	// read the whole record when it fits the buffer
+	expected := 5 + int(binary.BigEndian.Uint16(data[3:5]))
+	if expected > maxResponse {
+		expected = maxResponse
+	}
+
+	if n < expected {
+		m, err := io.ReadAtLeast(conn, data[n:], expected-n)
+		n += m
+
+		if err != nil && n < expected {
+			return data[:n], nil
+		}
+	}
+
+	return data[:n], nil
+}
+
+// buildClientHello encodes the client hello of a probe
+func buildClientHello(p *probe, host string) []byte {
+	recordVersion, helloVersion := p.version, p.version
+	if p.version == 0x0304 {
+		recordVersion, helloVersion = 0x0301, 0x0303
+	}
+
+	hello := uint16Bytes(helloVersion)
+	hello = append(hello, randomBytes(32)...)
+
+	// session id
+	hello = append(hello, 32)
+	hello = append(hello, randomBytes(32)...)
+
+	ciphers := ciphersOf(p)
+	hello = append(hello, uint16Bytes(uint16(len(ciphers)))...)
+	hello = append(hello, ciphers...)
+
+	// null compression
+	hello = append(hello, 0x01, 0x00)
+	hello = append(hello, extensionsOf(p, host)...)
+
+	handshake := append([]byte{0x01, 0x00}, uint16Bytes(uint16(len(hello)))...)
+	handshake = append(handshake, hello...)
+
+	record := append([]byte{0x16}, uint16Bytes(recordVersion)...)
+	record = append(record, uint16Bytes(uint16(len(handshake)))...)
+
+	return append(record, handshake...)
+}
+
+// ciphersOf returns the encoded cipher list of a probe
+func ciphersOf(p *probe) []byte {
+	var list [][]byte
+
+	for _, cipher := range allCiphers {
+		if p.noTLS13 && cipher >= 0x1301 && cipher <= 0x1305 {
+			continue
+		}
+
+		list = append(list, uint16Bytes(cipher))
+	}
+
+	list = reorder(list, p.cipherOrder)
+
+	if p.grease {
+		list = append([][]byte{randomGrease()}, list...)
+	}
+
+	return concat(list)
+}
+
+// extensionsOf returns the encoded extensions of a probe
+func extensionsOf(p *probe, host string) []byte {
+	var extensions []byte
+
+	if p.grease {
+		extensions = append(extensions, randomGrease()...)
+		extensions = append(extensions, 0x00, 0x00)
+	}
+
+	// server name
+	extensions = append(extensions, 0x00, 0x00)
+	extensions = append(extensions, uint16Bytes(uint16(len(host)+5))...)
+	extensions = append(extensions, uint16Bytes(uint16(len(host)+3))...)
+	extensions = append(extensions, 0x00)
+	extensions = append(extensions, uint16Bytes(uint16(len(host)))...)
+	extensions = append(extensions, host...)
+
+	// extended master secret, max fragment length, renegotiation info,
+	// supported groups, ec point formats and session ticket
+	extensions = append(extensions, 0x00, 0x17, 0x00, 0x00)
+	extensions = append(extensions, 0x00, 0x01, 0x00, 0x01, 0x01)
+	extensions = append(extensions, 0xff, 0x01, 0x00, 0x01, 0x00)
+	extensions = append(extensions, 0x00, 0x0a, 0x00, 0x0a, 0x00, 0x08, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18, 0x00, 0x19)
+	extensions = append(extensions, 0x00, 0x0b, 0x00, 0x02, 0x01, 0x00)
+	extensions = append(extensions, 0x00, 0x23, 0x00, 0x00)
+
+	// application layer protocols
+	protocols := alpns
+	if p.rareALPN {
+		protocols = rareALPNs
+	}
+
+	var encodedProtocols [][]byte
+	for _, protocol := range protocols {
+		encodedProtocols = append(encodedProtocols, append([]byte{byte(len(protocol))}, protocol...))
+	}
+
+	alpn := concat(reorder(encodedProtocols, p.versionOrder))
+	extensions = append(extensions, 0x00, 0x10)
+	extensions = append(extensions, uint16Bytes(uint16(len(alpn)+2))...)
+	extensions = append(extensions, uint16Bytes(uint16(len(alpn)))...)
+	extensions = append(extensions, alpn...)
+
+	// encrypt then mac and signature algorithms
+	extensions = append(extensions, 0x00, 0x16, 0x00, 0x00)
+	extensions = append(extensions, 0x00, 0x0d, 0x00, 0x14, 0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01, 0x02, 0x01)
+
+	if p.support != supportNone {
+		extensions = append(extensions, keyShare(p.grease)...)
+
+		// psk key exchange modes
+		extensions = append(extensions, 0x00, 0x2d, 0x00, 0x02, 0x01, 0x01)
+
+		if p.version == 0x0304 || p.support == support12 {
+			extensions = append(extensions, supportedVersions(p)...)
+		}
+	}
+
+	return append(uint16Bytes(uint16(len(extensions))), extensions...)
+}
+
+// keyShare returns an x25519 key share extension with a random key
+func keyShare(grease bool) []byte {
+	var share []byte
+
+	if grease {
+		share = append(randomGrease(), 0x00, 0x01, 0x00)
+	}
+
+	share = append(share, 0x00, 0x1d, 0x00, 0x20)
+	share = append(share, randomBytes(32)...)
+
+	extension := []byte{0x00, 0x33}
+	extension = append(extension, uint16Bytes(uint16(len(share)+2))...)
+	extension = append(extension, uint16Bytes(uint16(len(share)))...)
+
+	return append(extension, share...)
+}
+
+// supportedVersions returns the supported versions extension of a probe
+func supportedVersions(p *probe) []byte {
+	versions := [][]byte{{0x03, 0x01}, {0x03, 0x02}, {0x03, 0x03}}
+	if p.support != support12 {
+		versions = append(versions, []byte{0x03, 0x04})
+	}
+
+	versions = reorder(versions, p.versionOrder)
+
+	var list []byte
+	if p.grease {
+		list = randomGrease()
+	}
+
+	list = append(list, concat(versions)...)
+
+	extension := []byte{0x00, 0x2b}
+	extension = append(extension, uint16Bytes(uint16(len(list)+1))...)
+	extension = append(extension, byte(len(list)))
+
+	return append(extension, list...)
+}
+
+// parseServerHello returns the raw result of a probe, the selected cipher,
+// version, protocol and extensions of the server hello
+func parseServerHello(data []byte) string {
+	if len(data) < 44 || data[0] != 0x16 || data[5] != 0x02 {
+		return "|||"
+	}
+
+	length := int(binary.BigEndian.Uint16(data[3:5]))
+	counter := int(data[43])
+
+	if len(data) < counter+46 {
+		return "|||"
+	}
+
+	cipher := hex.EncodeToString(data[counter+44 : counter+46])
+	version := hex.EncodeToString(data[9:11])
+
+	return cipher + "|" + version + "|" + extensionInfo(data, counter, length)
+}
+
+// extensionInfo returns the selected protocol and the extension types of a server hello
+func extensionInfo(data []byte, counter, length int) string {
+	if len(data) < counter+53 || data[counter+47] == 11 {
+		return "|"
+	}
+
+	if string(data[counter+50:counter+53]) == "\x0e\xac\x0b" || (len(data) >= 85 && string(data[82:85]) == "\x0f\xf0\x0b") {
+		return "|"
+	}
+
+	if counter+42 >= length {
+		return "|"
+	}
+
+	count := 49 + counter
+	maximum := int(binary.BigEndian.Uint16(data[counter+47:counter+49])) + count - 1
+
+	var types []string
+
+	alpn := ""
+
+	for count < maximum && count+4 <= len(data) {
+		extensionType := data[count : count+2]
+		extensionLength := int(binary.BigEndian.Uint16(data[count+2 : count+4]))
+
+		var value []byte
+		if count+4+extensionLength <= len(data) {
+			value = data[count+4 : count+4+extensionLength]
+		}
+
+		if extensionType[0] == 0x00 && extensionType[1] == 0x10 && alpn == "" && len(value) > 3 {
+			alpn = string(value[3:])
+		}
+
+		types = append(types, hex.EncodeToString(extensionType))
+		count += extensionLength + 4
+	}
+
+	return alpn + "|" + strings.Join(types, "-")
+}
+
+// hash computes the fingerprint from the raw results of the probes
+func hash(raw []string) string {
+	var fuzzy, extensions strings.Builder
+
+	for _, result := range raw {
+		components := strings.Split(result, "|")
+		if len(components) != 4 {
+			components = []string{"", "", "", ""}
+		}
+
+		fuzzy.WriteString(cipherCode(components[0]))
+		fuzzy.WriteString(versionCode(components[1]))
+		extensions.WriteString(components[2])
+		extensions.WriteString(components[3])
+	}
+
+	digest := sha256.Sum256([]byte(extensions.String()))
+
+	return fuzzy.String() + hex.EncodeToString(digest[:])[:32]
+}
+
+// cipherCode encodes a selected cipher as its position in the cipher list
+func cipherCode(cipher string) string {
+	if cipher == "" {
+		return "00"
+	}
+
+	count := 1
+	for _, known := range hashCiphers {
+		if hex.EncodeToString(uint16Bytes(known)) == cipher {
+			break
+		}
+
+		count++
+	}
+
+	return fmt.Sprintf("%02x", count)
+}
+
+// versionCode encodes a selected version as a letter
+func versionCode(version string) string {
+	if len(version) < 4 {
+		return "0"
+	}
+
+	minor, err := strconv.Atoi(version[3:4])
+	if err != nil || minor > 5 {
+		return "0"
+	}
+
+	return string("abcdef"[minor])
+}
+
+// ja3s computes the ja3s fingerprint of a server hello
+func ja3s(data []byte) string {
+	if len(data) < 44 {
+		return ""
+	}
+
+	counter := int(data[43])
+	if len(data) < counter+49 {
+		return ""
+	}
+
+	version := binary.BigEndian.Uint16(data[9:11])
+	cipher := binary.BigEndian.Uint16(data[counter+44 : counter+46])
+
+	var types []string
+
+	count := counter + 49
+	end := count + int(binary.BigEndian.Uint16(data[counter+47:counter+49]))
+
+	for count+4 <= end && count+4 <= len(data) {
+		types = append(types, strconv.Itoa(int(binary.BigEndian.Uint16(data[count:count+2]))))
+		count += 4 + int(binary.BigEndian.Uint16(data[count+2:count+4]))
+	}
+
+	digest := md5.Sum([]byte(fmt.Sprintf("%d,%d,%s", version, cipher, strings.Join(types, "-")))) // nolint:gosec // ja3s fingerprints are md5 digests
+
+	return hex.EncodeToString(digest[:])
+}
+
+func uint16Bytes(value uint16) []byte {
+	return []byte{byte(value >> 8), byte(value)}
+}
+
+func concat(list [][]byte) []byte {
+	var data []byte
+	for _, item := range list {
+		data = append(data, item...)
+	}
+
+	return data
+}
+
+func randomBytes(n int) []byte {
+	data := make([]byte, n)
+	_, _ = rand.Read(data)
+
+	return data
+}
+
+func randomGrease() []byte {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(greaseValues))))
+	if err != nil {
+		return uint16Bytes(greaseValues[0])
+	}
+
+	return uint16Bytes(greaseValues[index.Int64()])
+}
@@ -0,0 +1,114 @@
+package jarm
+
+// Cipher orders of the probes
+const (
+	orderForward    = "FORWARD"
+	orderReverse    = "REVERSE"
+	orderTopHalf    = "TOP_HALF"
+	orderBottomHalf = "BOTTOM_HALF"
+	orderMiddleOut  = "MIDDLE_OUT"
+)
+
+// Version support extensions of the probes
+const (
+	support12   = "1.2_SUPPORT"
+	support13   = "1.3_SUPPORT"
+	supportNone = "NO_SUPPORT"
+)
+
+// probe describes one of the client hellos of the fingerprint
+type probe struct {
+	version      uint16
+	noTLS13      bool
+	cipherOrder  string
+	grease       bool
+	rareALPN     bool
+	support      string
+	versionOrder string
+}
+
+// probes contains the client hellos of the fingerprint in order
+var probes = []probe{
+	{version: 0x0303, cipherOrder: orderForward, support: support12, versionOrder: orderReverse},
+	{version: 0x0303, cipherOrder: orderReverse, support: support12, versionOrder: orderForward},
+	{version: 0x0303, cipherOrder: orderTopHalf, support: supportNone, versionOrder: orderForward},
+	{version: 0x0303, cipherOrder: orderBottomHalf, rareALPN: true, support: supportNone, versionOrder: orderForward},
+	{version: 0x0303, cipherOrder: orderMiddleOut, grease: true, rareALPN: true, support: supportNone, versionOrder: orderReverse},
+	{version: 0x0302, cipherOrder: orderForward, support: supportNone, versionOrder: orderForward},
+	{version: 0x0304, cipherOrder: orderForward, support: support13, versionOrder: orderReverse},
+	{version: 0x0304, cipherOrder: orderReverse, support: support13, versionOrder: orderForward},
+	{version: 0x0304, noTLS13: true, cipherOrder: orderForward, support: support13, versionOrder: orderForward},
+	{version: 0x0304, cipherOrder: orderMiddleOut, grease: true, support: support13, versionOrder: orderReverse},
+}
+
+// allCiphers contains the ciphers offered by the probes
+var allCiphers = []uint16{
+	0x0016, 0x0033, 0x0067, 0xc09e, 0xc0a2, 0x009e, 0x0039, 0x006b, 0xc09f, 0xc0a3, 0x009f, 0x0045, 0x00be, 0x0088,
+	0x00c4, 0x009a, 0xc008, 0xc009, 0xc023, 0xc0ac, 0xc0ae, 0xc02b, 0xc00a, 0xc024, 0xc0ad, 0xc0af, 0xc02c, 0xc072,
+	0xc073, 0xcca9, 0x1302, 0x1301, 0xcc14, 0xc007, 0xc012, 0xc013, 0xc027, 0xc02f, 0xc014, 0xc028, 0xc030, 0xc060,
+	0xc061, 0xc076, 0xc077, 0xcca8, 0x1305, 0x1304, 0x1303, 0xcc13, 0xc011, 0x000a, 0x002f, 0x003c, 0xc09c, 0xc0a0,
+	0x009c, 0x0035, 0x003d, 0xc09d, 0xc0a1, 0x009d, 0x0041, 0x00ba, 0x0084, 0x00c0, 0x0007, 0x0004, 0x0005,
+}
+
+// hashCiphers contains the ciphers in the order used to encode the selected cipher
+var hashCiphers = []uint16{
+	0x0004, 0x0005, 0x0007, 0x000a, 0x0016, 0x002f, 0x0033, 0x0035, 0x0039, 0x003c, 0x003d, 0x0041, 0x0045, 0x0067,
+	0x006b, 0x0084, 0x0088, 0x009a, 0x009c, 0x009d, 0x009e, 0x009f, 0x00ba, 0x00be, 0x00c0, 0x00c4, 0xc007, 0xc008,
+	0xc009, 0xc00a, 0xc011, 0xc012, 0xc013, 0xc014, 0xc023, 0xc024, 0xc027, 0xc028, 0xc02b, 0xc02c, 0xc02f, 0xc030,
+	0xc060, 0xc061, 0xc072, 0xc073, 0xc076, 0xc077, 0xc09c, 0xc09d, 0xc09e, 0xc09f, 0xc0a0, 0xc0a1, 0xc0a2, 0xc0a3,
+	0xc0ac, 0xc0ad, 0xc0ae, 0xc0af, 0xcc13, 0xcc14, 0xcca8, 0xcca9, 0x1301, 0x1302, 0x1303, 0x1304, 0x1305,
+}
+
+// alpns and rareALPNs contain the protocols offered by the probes
+var (
+	alpns     = []string{"http/0.9", "http/1.0", "http/1.1", "spdy/1", "spdy/2", "spdy/3", "h2", "h2c", "hq"}
+	rareALPNs = []string{"http/0.9", "http/1.0", "spdy/1", "spdy/2", "spdy/3", "h2c", "hq"}
+)
+
+// greaseValues contains the reserved values sent to test the tolerance of servers
+var greaseValues = []uint16{
+	0x0a0a, 0x1a1a, 0x2a2a, 0x3a3a, 0x4a4a, 0x5a5a, 0x6a6a, 0x7a7a,
+	0x8a8a, 0x9a9a, 0xaaaa, 0xbaba, 0xcaca, 0xdada, 0xeaea, 0xfafa,
+}
+
+// reorder reorders the encoded items of a list like the ciphers of a probe
+func reorder(list [][]byte, order string) [][]byte {
+	var output [][]byte
+
+	switch order {
+	case orderReverse:
+		for i := len(list) - 1; i >= 0; i-- {
+			output = append(output, list[i])
+		}
+	case orderBottomHalf:
+		if len(list)%2 == 1 {
+			output = append(output, list[len(list)/2+1:]...)
+		} else {
+			output = append(output, list[len(list)/2:]...)
+		}
+	case orderTopHalf:
+		if len(list)%2 == 1 {
+			output = append(output, list[len(list)/2])
+		}
+
+		output = append(output, reorder(reorder(list, orderReverse), orderBottomHalf)...)
+	case orderMiddleOut:
+		middle := len(list) / 2
+
+		if len(list)%2 == 1 {
+			output = append(output, list[middle])
+
+			for i := 1; i <= middle; i++ {
+				output = append(output, list[middle+i], list[middle-i])
+			}
+		} else {
+			for i := 1; i <= middle; i++ {
+				output = append(output, list[middle-1+i], list[middle-i])
+			}
+		}
+	default:
+		output = append(output, list...)
+	}
+
+	return output
+}
@@ -0,0 +1,4 @@
+// Package jwt decodes the json web tokens found in the responses, forges
+// their unsigned variants and verifies their hmac signatures against
+// secrets, for the templates of jwt misconfigurations.
+package jwt
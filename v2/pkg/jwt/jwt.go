@@ -0,0 +1,184 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"regexp"
+	"strings"
+)
+
+// tokenPattern matches the json web tokens, whose header and payload are
+// base64url encoded json objects
+var tokenPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]*`)
+
+// hmacAlgorithms contains the hashes of the hmac algorithms
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// Token is a decoded json web token
+type Token struct {
+	// Raw is the encoded token
+	Raw string
+	// Header contains the fields of the header, like alg and kid
+	Header map[string]interface{}
+	// Payload contains the claims of the token
+	Payload map[string]interface{}
+	// Signature is the decoded signature, empty for the unsigned tokens
+	Signature []byte
+
+	// signingInput is the encoded header and payload the signature signs
+	signingInput string
+	// encodedPayload is the payload as encoded in the token, kept by the
+	// forged tokens
+	encodedPayload string
+}
+
+// Find returns the json web tokens found in a corpus, in order
+func Find(corpus string) []string {
+	return tokenPattern.FindAllString(corpus, -1)
+}
+
+// Parse decodes a json web token, without verifying its signature
+func Parse(raw string) (*Token, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "Bearer ")
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("the token is not made of three parts")
+	}
+
+	token := &Token{Raw: raw, signingInput: parts[0] + "." + parts[1], encodedPayload: parts[1]}
+
+	if err := decodeJSON(parts[0], &token.Header); err != nil {
+		return nil, errors.New("could not decode the header of the token")
+	}
+
+	if err := decodeJSON(parts[1], &token.Payload); err != nil {
+		return nil, errors.New("could not decode the payload of the token")
+	}
+
+	signature, err := decode(parts[2])
+	if err != nil {
+		return nil, errors.New("could not decode the signature of the token")
+	}
+	token.Signature = signature
+
+	return token, nil
+}
+
+// decode decodes a base64url part of a token, with or without padding
+func decode(part string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+}
+
+// decodeJSON decodes a base64url json object of a token
+func decodeJSON(part string, value *map[string]interface{}) error {
+	data, err := decode(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+// encodeJSON encodes a json object of a token
+func encodeJSON(value map[string]interface{}) string {
+	data, _ := json.Marshal(value)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Algorithm returns the alg field of the header of the token
+func (t *Token) Algorithm() string {
+	algorithm, _ := t.Header["alg"].(string)
+
+	return algorithm
+}
+
+// HeaderField returns a field of the header, nil if missing
+func (t *Token) HeaderField(name string) interface{} {
+	return t.Header[name]
+}
+
+// Claim returns a claim of the payload, nil if missing
+func (t *Token) Claim(name string) interface{} {
+	return t.Payload[name]
+}
+
+// Unsigned returns the token with the none algorithm and without signature,
+// accepted by the servers not enforcing the algorithm of their tokens
+func (t *Token) Unsigned() string {
+	header := make(map[string]interface{}, len(t.Header))
+	for name, value := range t.Header {
+		header[name] = value
+	}
+	header["alg"] = "none"
+
+	return encodeJSON(header) + "." + t.encodedPayload + "."
+}
+
+// Sign returns the token signed with a secret, with its hmac algorithm or
+// HS256 for the tokens of the other algorithms
+func (t *Token) Sign(secret string) string {
+	algorithm := t.Algorithm()
+	if _, ok := hmacAlgorithms[algorithm]; !ok {
+		algorithm = "HS256"
+	}
+
+	signingInput := t.signingInput
+	if algorithm != t.Algorithm() {
+		header := make(map[string]interface{}, len(t.Header))
+		for name, value := range t.Header {
+			header[name] = value
+		}
+		header["alg"] = algorithm
+
+		signingInput = encodeJSON(header) + "." + t.encodedPayload
+	}
+
+	signature := sign(algorithm, signingInput, secret)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// sign returns the hmac signature of an input
+func sign(algorithm, input, secret string) []byte {
+	h := hmac.New(hmacAlgorithms[algorithm], []byte(secret))
+	_, _ = h.Write([]byte(input))
+
+	return h.Sum(nil)
+}
+
+// Verify returns true if the token is signed with a secret, only the
+// tokens of the hmac algorithms being verified
+func (t *Token) Verify(secret string) bool {
+	if _, ok := hmacAlgorithms[t.Algorithm()]; !ok {
+		return false
+	}
+
+	return hmac.Equal(t.Signature, sign(t.Algorithm(), t.signingInput, secret))
+}
+
+// Crack returns the secret of the token among candidate secrets
+func (t *Token) Crack(secrets []string) (string, bool) {
+	if _, ok := hmacAlgorithms[t.Algorithm()]; !ok {
+		return "", false
+	}
+
+	for _, secret := range secrets {
+		if t.Verify(secret) {
+			return secret, true
+		}
+	}
+
+	return "", false
+}
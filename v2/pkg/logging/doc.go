@@ -0,0 +1,3 @@
+// Package logging routes the events of the executers to a logger, with the
+// template, the host and the request they were logged for.
+package logging
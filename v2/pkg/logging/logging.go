@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// Level is the level of a log entry
+type Level int
+
+// The levels of the log entries, from the most to the least important. The
+// silent entries are the output of the scan, printed at every verbosity.
+const (
+	LevelSilent Level = iota
+	LevelError
+	LevelWarning
+	LevelInfo
+	LevelVerbose
+	LevelDebug
+)
+
+var levelNames = map[Level]string{
+	LevelSilent:  "silent",
+	LevelError:   "error",
+	LevelWarning: "warning",
+	LevelInfo:    "info",
+	LevelVerbose: "verbose",
+	LevelDebug:   "debug",
+}
+
+// String returns the name of the level
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// The names of the fields of the log entries
+const (
+	FieldTemplate  = "template"
+	FieldHost      = "host"
+	FieldRequestID = "request-id"
+)
+
+// Fields contains the structured fields of a log entry
+type Fields map[string]string
+
+// Logger receives the log entries of the executers
+type Logger interface {
+	// Log logs a message with its fields
+	Log(level Level, fields Fields, message string)
+}
+
+var requestCount uint64
+
+// debug is set when the default logger prints the debug entries
+var debug int32
+
+// RequestID returns an id unique in the process identifying a request in
+// the log entries
+func RequestID() string {
+	return strconv.FormatUint(atomic.AddUint64(&requestCount, 1), 10)
+}
+
+// Default returns the logger printing the entries with gologger
+func Default() Logger {
+	return gologgerLogger{}
+}
+
+// SetDebug sets whether the default logger prints the debug entries, which
+// are discarded by default
+func SetDebug(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+
+	atomic.StoreInt32(&debug, value)
+}
+
+// Nop returns a logger discarding the entries
+func Nop() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(level Level, fields Fields, message string) {}
+
+// gologgerLogger prints the entries prefixed by their template, as the
+// label of the verbose ones. The debug entries are printed on stderr with
+// the debug option only.
+type gologgerLogger struct{}
+
+func (gologgerLogger) Log(level Level, fields Fields, message string) {
+	if level == LevelDebug && atomic.LoadInt32(&debug) == 0 {
+		return
+	}
+
+	template := fields[FieldTemplate]
+
+	if level == LevelVerbose {
+		gologger.Verbosef("%s", template, message)
+		return
+	}
+
+	if template != "" && level != LevelSilent {
+		message = "[" + template + "] " + message
+	}
+
+	switch level {
+	case LevelError:
+		gologger.Errorf("%s", message)
+	case LevelWarning:
+		gologger.Warningf("%s", message)
+	case LevelInfo:
+		gologger.Infof("%s", message)
+	case LevelDebug:
+		gologger.Debugf("%s", message)
+	default:
+		gologger.Silentf("%s", message)
+	}
+}
+
+// Entry logs messages with a set of fields
+type Entry struct {
+	logger Logger
+	fields Fields
+}
+
+// NewEntry returns an entry logging to a logger with fields, to the default
+// logger when it is nil
+func NewEntry(logger Logger, fields Fields) *Entry {
+	if logger == nil {
+		logger = Default()
+	}
+
+	return &Entry{logger: logger, fields: fields}
+}
+
+// With returns a copy of the entry with a field set
+func (e *Entry) With(key, value string) *Entry {
+	fields := make(Fields, len(e.fields)+1)
+	for k, v := range e.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+
+	return &Entry{logger: e.logger, fields: fields}
+}
+
+// Host returns a copy of the entry for a host
+func (e *Entry) Host(host string) *Entry {
+	return e.With(FieldHost, host)
+}
+
+// Request returns a copy of the entry for a request
+func (e *Entry) Request(requestID string) *Entry {
+	return e.With(FieldRequestID, requestID)
+}
+
+func (e *Entry) log(level Level, format string, args ...interface{}) {
+	e.logger.Log(level, e.fields, fmt.Sprintf(format, args...))
+}
+
+// Silentf logs the output of the scan
+func (e *Entry) Silentf(format string, args ...interface{}) {
+	e.log(LevelSilent, format, args...)
+}
+
+// Errorf logs an error
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	e.log(LevelError, format, args...)
+}
+
+// Warningf logs a warning
+func (e *Entry) Warningf(format string, args ...interface{}) {
+	e.log(LevelWarning, format, args...)
+}
+
+// Infof logs an informational message
+func (e *Entry) Infof(format string, args ...interface{}) {
+	e.log(LevelInfo, format, args...)
+}
+
+// Verbosef logs a message shown in the verbose mode
+func (e *Entry) Verbosef(format string, args ...interface{}) {
+	e.log(LevelVerbose, format, args...)
+}
+
+// Debugf logs a debug message
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	e.log(LevelDebug, format, args...)
+}
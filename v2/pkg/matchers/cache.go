@@ -0,0 +1,62 @@
+package matchers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// cacheStatusHeaders are the headers reporting if the responses were
+// served by the caches and cdns setting them
+var cacheStatusHeaders = []string{
+	"X-Cache",
+	"X-Cache-Status",
+	"Cf-Cache-Status",
+	"Cache-Status",
+	"X-Proxy-Cache",
+	"X-Drupal-Cache",
+	"X-Varnish-Cache",
+	"X-Rack-Cache",
+	"Cdn-Cache",
+}
+
+// CacheStatus returns the values of the cache status headers of a response
+func CacheStatus(resp *http.Response) string {
+	var status []string
+
+	for _, header := range cacheStatusHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			status = append(status, value)
+		}
+	}
+
+	return strings.Join(status, " ")
+}
+
+// CacheAge returns the age in seconds of a cached response, zero when the
+// response has no age
+func CacheAge(resp *http.Response) int {
+	age, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Age")))
+	if err != nil || age < 0 {
+		return 0
+	}
+
+	return age
+}
+
+// CacheHit returns true if a response was served from a cache, from its
+// age or its cache status headers
+func CacheHit(resp *http.Response) bool {
+	if CacheAge(resp) > 0 {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(CacheStatus(resp)), "hit")
+}
+
+// cacheToMap adds the cache status of a response to a dsl map
+func cacheToMap(resp *http.Response, m map[string]interface{}) {
+	m["cache_status"] = CacheStatus(resp)
+	m["cache_age"] = CacheAge(resp)
+	m["cache_hit"] = CacheHit(resp)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Knetic/govaluate"
 	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/versions"
 )
 
 // CompileMatchers performs the initial setup operation on a matcher
@@ -38,10 +39,32 @@ func (m *Matcher) CompileMatchers() error {
 		m.dslCompiled = append(m.dslCompiled, compiled)
 	}
 
+	for _, constraint := range m.Versions {
+		if err := versions.Validate(constraint); err != nil {
+			return fmt.Errorf("could not compile version constraint: %s", constraint)
+		}
+	}
+
+	if err := ValidateCookies(m.Cookies, m.Missing); err != nil {
+		return err
+	}
+
+	if m.Count < 0 {
+		return fmt.Errorf("invalid matcher count specified: %d", m.Count)
+	}
+
+	if m.Similarity < 0 || m.Similarity > 1 {
+		return fmt.Errorf("invalid baseline similarity specified: %v", m.Similarity)
+	}
+
+	if m.Reference < 0 {
+		return fmt.Errorf("invalid diff reference specified: %d", m.Reference)
+	}
+
 	// Setup the condition type, if any.
 	if m.Condition != "" {
 		m.condition, ok = ConditionTypes[m.Condition]
-		if !ok {
+		if !ok || m.condition == WeightedCondition {
 			return fmt.Errorf("unknown condition specified: %s", m.Condition)
 		}
 	} else {
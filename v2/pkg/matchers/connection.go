@@ -0,0 +1,233 @@
+package matchers
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/trust"
+)
+
+// connectionInfoKey is the context key of the connection details of a request
+type connectionInfoKey struct{}
+
+// connectionInfo contains the details of the connection used by a request
+// and the times of the phases of the request
+type connectionInfo struct {
+	mutex         sync.Mutex
+	remoteAddress string
+	times         phaseTimes
+}
+
+// phaseTimes contains the times of the phases of a request
+type phaseTimes struct {
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+// Timings contains the durations of the phases of a request in
+// milliseconds, the phases skipped by a reused connection being zero
+type Timings struct {
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	TLS     float64 `json:"tls"`
+	TTFB    float64 `json:"ttfb"`
+}
+
+// trustStoreKey is the context key of the trust store of a request
+type trustStoreKey struct{}
+
+// tlsVersions contains the names of the tls versions
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "tls1.0",
+	tls.VersionTLS11: "tls1.1",
+	tls.VersionTLS12: "tls1.2",
+	tls.VersionTLS13: "tls1.3",
+}
+
+// WithConnectionTrace returns a copy of the request recording the address of
+// the connection used to send it, made available to the dsl as remote_ip,
+// and the timings of its phases, made available as dns_time, connect_time,
+// tls_time and ttfb.
+func WithConnectionTrace(req *http.Request) *http.Request {
+	info := &connectionInfo{}
+
+	// the times of the last attempt are kept when the request is retried
+	record := func(field *time.Time) {
+		info.mutex.Lock()
+		*field = time.Now()
+		info.mutex.Unlock()
+	}
+
+	ctx := context.WithValue(req.Context(), connectionInfoKey{}, info)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			info.mutex.Lock()
+			info.times = phaseTimes{start: time.Now()}
+			info.mutex.Unlock()
+		},
+		GotConn: func(conn httptrace.GotConnInfo) {
+			info.mutex.Lock()
+			info.remoteAddress = conn.Conn.RemoteAddr().String()
+			info.mutex.Unlock()
+		},
+		DNSStart:          func(httptrace.DNSStartInfo) { record(&info.times.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { record(&info.times.dnsDone) },
+		TLSHandshakeStart: func() { record(&info.times.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { record(&info.times.tlsDone) },
+		ConnectStart: func(string, string) {
+			// the first of the addresses dialed in parallel
+			info.mutex.Lock()
+			if info.times.connectStart.IsZero() {
+				info.times.connectStart = time.Now()
+			}
+			info.mutex.Unlock()
+		},
+		ConnectDone:          func(string, string, error) { record(&info.times.connectDone) },
+		GotFirstResponseByte: func() { record(&info.times.firstByte) },
+	})
+
+	return req.WithContext(ctx)
+}
+
+// RequestTimings returns the timings of the request of a response, nil if
+// the request wasn't traced
+func RequestTimings(resp *http.Response) *Timings {
+	if resp == nil || resp.Request == nil {
+		return nil
+	}
+
+	info, ok := resp.Request.Context().Value(connectionInfoKey{}).(*connectionInfo)
+	if !ok {
+		return nil
+	}
+
+	info.mutex.Lock()
+	defer info.mutex.Unlock()
+
+	times := info.times
+	if times.start.IsZero() {
+		return nil
+	}
+
+	return &Timings{
+		DNS:     milliseconds(times.dnsStart, times.dnsDone),
+		Connect: milliseconds(times.connectStart, times.connectDone),
+		TLS:     milliseconds(times.tlsStart, times.tlsDone),
+		TTFB:    milliseconds(times.start, times.firstByte),
+	}
+}
+
+// milliseconds returns the milliseconds between two times, zero if the
+// phase didn't happen
+func milliseconds(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+
+	return float64(end.Sub(start).Microseconds()) / 1000
+}
+
+// WithTrustStore returns a copy of the request verifying the certificate
+// chain of its response against the roots of the store, made available to
+// the dsl as cert_verified and cert_error.
+func WithTrustStore(req *http.Request, store *trust.Store) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), trustStoreKey{}, store))
+}
+
+// verifyChain verifies the certificate chain of a response, against the
+// system roots unless the request has a trust store
+func verifyChain(resp *http.Response) error {
+	// already verified by the handshake when the chains are enforced
+	if len(resp.TLS.VerifiedChains) > 0 {
+		return nil
+	}
+
+	var (
+		host  string
+		store *trust.Store
+	)
+
+	if resp.Request != nil {
+		store, _ = resp.Request.Context().Value(trustStoreKey{}).(*trust.Store)
+		if resp.Request.URL != nil {
+			host = resp.Request.URL.Hostname()
+		}
+	}
+
+	return store.VerifyChain(resp.TLS.PeerCertificates, host)
+}
+
+// RemoteIP returns the ip address a response was received from, if traced
+func RemoteIP(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+
+	info, ok := resp.Request.Context().Value(connectionInfoKey{}).(*connectionInfo)
+	if !ok {
+		return ""
+	}
+
+	info.mutex.Lock()
+	defer info.mutex.Unlock()
+
+	host, _, err := net.SplitHostPort(info.remoteAddress)
+	if err != nil {
+		return info.remoteAddress
+	}
+
+	return host
+}
+
+// connectionToMap adds the transport details of a response to a dsl map
+func connectionToMap(resp *http.Response, m map[string]interface{}) {
+	m["http_version"] = resp.Proto
+	m["remote_ip"] = RemoteIP(resp)
+
+	// in seconds like the duration, zero for the untraced requests
+	timings := RequestTimings(resp)
+	if timings == nil {
+		timings = &Timings{}
+	}
+	m["dns_time"] = timings.DNS / 1000
+	m["connect_time"] = timings.Connect / 1000
+	m["tls_time"] = timings.TLS / 1000
+	m["ttfb"] = timings.TTFB / 1000
+
+	if resp.TLS == nil {
+		return
+	}
+
+	m["tls_version"] = tlsVersions[resp.TLS.Version]
+	m["cipher"] = tls.CipherSuiteName(resp.TLS.CipherSuite)
+
+	if len(resp.TLS.PeerCertificates) > 0 {
+		cert := resp.TLS.PeerCertificates[0]
+
+		// dates are unix timestamps to be compared with unix_time()
+		m["cert_not_after"] = float64(cert.NotAfter.Unix())
+		m["cert_not_before"] = float64(cert.NotBefore.Unix())
+		m["cert_common_name"] = cert.Subject.CommonName
+		m["cert_issuer"] = cert.Issuer.CommonName
+
+		// verified even when the chains aren't enforced, to match on them
+		m["cert_verified"] = true
+		m["cert_error"] = ""
+
+		if err := verifyChain(resp); err != nil {
+			m["cert_verified"] = false
+			m["cert_error"] = err.Error()
+		}
+	}
+}
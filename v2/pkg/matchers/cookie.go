@@ -0,0 +1,158 @@
+package matchers
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// The attributes of the cookies checked by the cookie matchers
+const (
+	// CookieSecure is the Secure attribute, restricting a cookie to https
+	CookieSecure = "secure"
+	// CookieHTTPOnly is the HttpOnly attribute, hiding a cookie from the scripts
+	CookieHTTPOnly = "httponly"
+	// CookieSameSite is the SameSite attribute, Lax or Strict, keeping a
+	// cookie from the cross-site requests
+	CookieSameSite = "samesite"
+)
+
+// CookieAttributes contains the attributes checked by the cookie matchers
+var CookieAttributes = map[string]bool{CookieSecure: true, CookieHTTPOnly: true, CookieSameSite: true}
+
+// defaultCookieAttributes are the attributes checked when none is given
+var defaultCookieAttributes = []string{CookieSecure, CookieHTTPOnly, CookieSameSite}
+
+// ValidateCookies checks the name patterns and the attributes of the cookie
+// matchers and extractors
+func ValidateCookies(patterns, attributes []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid cookie pattern specified: %s", pattern)
+		}
+	}
+
+	for _, attribute := range attributes {
+		if !CookieAttributes[attribute] {
+			return fmt.Errorf("unknown cookie attribute specified: %s", attribute)
+		}
+	}
+
+	return nil
+}
+
+// SelectCookies returns the cookies set by a response whose names match
+// one of the patterns, like *session*, or all the cookies without patterns
+func SelectCookies(resp *http.Response, patterns []string) []*http.Cookie {
+	cookies := resp.Cookies()
+	if len(patterns) == 0 {
+		return cookies
+	}
+
+	var selected []*http.Cookie
+	for _, cookie := range cookies {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, cookie.Name); matched {
+				selected = append(selected, cookie)
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+// HasCookieAttribute returns true if a cookie is set with an attribute. A
+// cookie has the samesite attribute only with SameSite=Lax or Strict, the
+// cookies with SameSite=None being sent with the cross-site requests.
+func HasCookieAttribute(cookie *http.Cookie, attribute string) bool {
+	switch attribute {
+	case CookieSecure:
+		return cookie.Secure
+	case CookieHTTPOnly:
+		return cookie.HttpOnly
+	case CookieSameSite:
+		return cookie.SameSite == http.SameSiteLaxMode || cookie.SameSite == http.SameSiteStrictMode
+	}
+
+	return false
+}
+
+// MissingCookieAttributes returns the attributes a cookie is set without,
+// the secure, httponly and samesite attributes being checked by default
+func MissingCookieAttributes(cookie *http.Cookie, attributes []string) []string {
+	if len(attributes) == 0 {
+		attributes = defaultCookieAttributes
+	}
+
+	var missing []string
+	for _, attribute := range attributes {
+		if !HasCookieAttribute(cookie, attribute) {
+			missing = append(missing, attribute)
+		}
+	}
+
+	return missing
+}
+
+// DescribeCookie returns the name and the attributes of a cookie, without
+// its value
+func DescribeCookie(cookie *http.Cookie) string {
+	parts := []string{cookie.Name}
+
+	if cookie.Domain != "" {
+		parts = append(parts, "Domain="+cookie.Domain)
+	}
+	if cookie.Path != "" {
+		parts = append(parts, "Path="+cookie.Path)
+	}
+	if cookie.MaxAge != 0 {
+		parts = append(parts, "Max-Age="+strconv.Itoa(cookie.MaxAge))
+	}
+	if !cookie.Expires.IsZero() {
+		parts = append(parts, "Expires="+cookie.Expires.UTC().Format(http.TimeFormat))
+	}
+	if cookie.Secure {
+		parts = append(parts, "Secure")
+	}
+	if cookie.HttpOnly {
+		parts = append(parts, "HttpOnly")
+	}
+
+	switch cookie.SameSite {
+	case http.SameSiteLaxMode:
+		parts = append(parts, "SameSite=Lax")
+	case http.SameSiteStrictMode:
+		parts = append(parts, "SameSite=Strict")
+	case http.SameSiteNoneMode:
+		parts = append(parts, "SameSite=None")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// matchCookies matches the responses setting a selected cookie without the
+// attributes of the matcher, any of them with the or condition and all of
+// them with the and condition
+func (m *Matcher) matchCookies(resp *http.Response) bool {
+	attributes := m.Missing
+	if len(attributes) == 0 {
+		attributes = defaultCookieAttributes
+	}
+
+	for _, cookie := range SelectCookies(resp, m.Cookies) {
+		missing := MissingCookieAttributes(cookie, attributes)
+
+		if m.condition == ANDCondition && len(missing) == len(attributes) {
+			return true
+		}
+
+		if m.condition == ORCondition && len(missing) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
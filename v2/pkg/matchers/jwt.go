@@ -0,0 +1,37 @@
+package matchers
+
+import (
+	"encoding/json"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/jwt"
+)
+
+// jwtToMap adds the first json web token of the headers or the body of a
+// response to a dsl map, with its algorithm and its decoded header and
+// claims, the values being empty without a token. The names differ from
+// the jwt functions, which the dsl can't use as values.
+func jwtToMap(headers, body string, m map[string]interface{}) {
+	m["jwt"], m["jwt_algorithm"], m["jwt_headers"], m["jwt_claims"] = "", "", "", ""
+
+	tokens := jwt.Find(headers)
+	if len(tokens) == 0 {
+		tokens = jwt.Find(body)
+	}
+
+	for _, raw := range tokens {
+		token, err := jwt.Parse(raw)
+		if err != nil {
+			continue
+		}
+
+		header, _ := json.Marshal(token.Header)
+		payload, _ := json.Marshal(token.Payload)
+
+		m["jwt"] = token.Raw
+		m["jwt_algorithm"] = token.Algorithm()
+		m["jwt_headers"] = string(header)
+		m["jwt_claims"] = string(payload)
+
+		return
+	}
+}
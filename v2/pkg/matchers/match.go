@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/miekg/dns"
+	"github.com/projectdiscovery/nuclei/v2/pkg/baseline"
+	"github.com/projectdiscovery/nuclei/v2/pkg/versions"
 )
 
 // Match matches a http response again a given matcher
@@ -45,14 +47,76 @@ func (m *Matcher) Match(resp *http.Response, body, headers string, duration time
 		}
 	case DSLMatcher:
 		// Match complex query
-		return m.isNegative(m.matchDSL(httpToMap(resp, body, headers, duration)))
+		return m.isNegative(m.matchDSL(HTTPToMap(resp, body, headers, duration)))
+	case CookieMatcher:
+		return m.isNegative(m.matchCookies(resp))
 	}
 
 	return false
 }
 
-// MatchDNS matches a dns response against a given matcher
-func (m *Matcher) MatchDNS(msg *dns.Msg) bool {
+// MatchBaseline matches a http response dissimilar from the response of
+// its host to a random path. Responses are matched when the host has no
+// baseline.
+func (m *Matcher) MatchBaseline(resp *http.Response, body, path string, fingerprint *baseline.Fingerprint) bool {
+	if fingerprint == nil {
+		return m.isNegative(true)
+	}
+
+	similarity := baseline.NewFingerprint(resp.StatusCode, body, path).Similarity(fingerprint)
+
+	return m.isNegative(similarity < m.GetSimilarity())
+}
+
+// MatchDiff matches a http response different from the reference response
+// to another request of the template. Responses aren't matched without a
+// reference response.
+func (m *Matcher) MatchDiff(resp *http.Response, body string, reference *baseline.Fingerprint) bool {
+	if reference == nil {
+		return false
+	}
+
+	similarity := baseline.NewFingerprint(resp.StatusCode, body, "").Similarity(reference)
+
+	return m.isNegative(similarity < m.GetSimilarity())
+}
+
+// MatchVersion matches the version detected by the fingerprint extractors
+// against the version constraints.
+func (m *Matcher) MatchVersion(version string) bool {
+	if version == "" {
+		return false
+	}
+
+	for i, constraint := range m.Versions {
+		satisfied, _ := versions.Satisfies(version, constraint)
+		if !satisfied {
+			// If we are in an AND request and a match failed,
+			// return false as the AND condition fails on any single mismatch.
+			if m.condition == ANDCondition {
+				return m.isNegative(false)
+			}
+			// Continue with the flow since its an OR Condition.
+			continue
+		}
+
+		// If the condition was an OR, return on the first match.
+		if m.condition == ORCondition {
+			return m.isNegative(true)
+		}
+
+		// If we are at the end of the constraints, return with true
+		if len(m.Versions)-1 == i {
+			return m.isNegative(true)
+		}
+	}
+
+	return m.isNegative(false)
+}
+
+// MatchDNS matches a dns response against a given matcher. The data
+// contains additional values available to dsl expressions.
+func (m *Matcher) MatchDNS(msg *dns.Msg, data map[string]interface{}) bool {
 	switch m.matcherType {
 	// [WIP] add dns status code matcher
 	case SizeMatcher:
@@ -68,7 +132,44 @@ func (m *Matcher) MatchDNS(msg *dns.Msg) bool {
 		return m.matchBinary(msg.String())
 	case DSLMatcher:
 		// Match complex query
-		return m.matchDSL(dnsToMap(msg))
+		return m.matchDSL(dnsToMap(msg, data))
+	}
+
+	return false
+}
+
+// MatchService matches the response of a network service against a given
+// matcher, the data containing the fields of the response.
+func (m *Matcher) MatchService(raw string, data map[string]interface{}) bool {
+	switch m.matcherType {
+	case SizeMatcher:
+		return m.isNegative(m.matchSizeCode(len(raw)))
+	case WordsMatcher:
+		return m.isNegative(m.matchWords(raw))
+	case RegexMatcher:
+		return m.isNegative(m.matchRegex(raw))
+	case BinaryMatcher:
+		return m.isNegative(m.matchBinary(raw))
+	case DSLMatcher:
+		return m.isNegative(m.matchDSL(ServiceToMap(raw, data)))
+	}
+
+	return false
+}
+
+// MatchInteractions matches the out-of-band interactions received for a
+// request, the raw string containing their requests and the data the
+// values of the dsl expressions.
+func (m *Matcher) MatchInteractions(raw string, data map[string]interface{}) bool {
+	switch m.matcherType {
+	case WordsMatcher:
+		return m.isNegative(m.matchWords(raw))
+	case RegexMatcher:
+		return m.isNegative(m.matchRegex(raw))
+	case BinaryMatcher:
+		return m.isNegative(m.matchBinary(raw))
+	case DSLMatcher:
+		return m.isNegative(m.matchDSL(data))
 	}
 
 	return false
@@ -113,7 +214,7 @@ func (m *Matcher) matchWords(corpus string) bool {
 	// Iterate over all the words accepted as valid
 	for i, word := range m.Words {
 		// Continue if the word doesn't match
-		if !strings.Contains(corpus, word) {
+		if !strings.Contains(corpus, word) || (m.Count > 1 && strings.Count(corpus, word) < m.Count) {
 			// If we are in an AND request and a match failed,
 			// return false as the AND condition fails on any single mismatch.
 			if m.condition == ANDCondition {
@@ -142,7 +243,7 @@ func (m *Matcher) matchRegex(corpus string) bool {
 	// Iterate over all the regexes accepted as valid
 	for i, regex := range m.regexCompiled {
 		// Continue if the regex doesn't match
-		if !regex.MatchString(corpus) {
+		if !regex.MatchString(corpus) || (m.Count > 1 && len(regex.FindAllStringIndex(corpus, m.Count)) < m.Count) {
 			// If we are in an AND request and a match failed,
 			// return false as the AND condition fails on any single mismatch.
 			if m.condition == ANDCondition {
@@ -1,6 +1,7 @@
 package matchers
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -28,3 +29,39 @@ func TestORCondition(t *testing.T) {
 	matched = m.matchWords("c")
 	require.False(t, matched, "Could match invalid OR condition")
 }
+
+func TestWeightedScore(t *testing.T) {
+	all := []*Matcher{{Weight: 3}, {Weight: 1}, {}}
+
+	score := WeightedScore(all, []bool{true, false, false})
+	require.Equal(t, 0.6, score, "Could not compute weighted score")
+
+	score = WeightedScore(all, []bool{false, true, true})
+	require.Equal(t, 0.4, score, "Could not compute weighted score with default weight")
+}
+
+func TestCount(t *testing.T) {
+	m := &Matcher{condition: ORCondition, Words: []string{"a"}, Count: 2}
+
+	matched := m.matchWords("a b a")
+	require.True(t, matched, "Could not match words occurring enough times")
+
+	matched = m.matchWords("a b")
+	require.False(t, matched, "Could match words not occurring enough times")
+}
+
+func TestCookieMatcher(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Set-Cookie": {
+		"sessionid=1; Path=/; Secure; HttpOnly; SameSite=None",
+		"theme=dark; Path=/",
+	}}}
+
+	m := &Matcher{condition: ORCondition, Cookies: []string{"session*"}, Missing: []string{CookieSecure, CookieSameSite}}
+	require.True(t, m.matchCookies(resp), "Could not match cookie without samesite")
+
+	m = &Matcher{condition: ANDCondition, Cookies: []string{"session*"}, Missing: []string{CookieSecure, CookieSameSite}}
+	require.False(t, m.matchCookies(resp), "Could match cookie with secure in AND condition")
+
+	m = &Matcher{condition: ANDCondition}
+	require.True(t, m.matchCookies(resp), "Could not match cookie without any attribute")
+}
@@ -23,12 +23,24 @@ type Matcher struct {
 	Words []string `yaml:"words,omitempty"`
 	// Regex are the regex pattern required to be present in the response
 	Regex []string `yaml:"regex,omitempty"`
+	// Count is the minimum number of occurrences of each word or regex
+	//
+	// By default, a single occurrence is required.
+	Count int `yaml:"count,omitempty"`
 	// regexCompiled is the compiled variant
 	regexCompiled []*regexp.Regexp
 	// Binary are the binary characters required to be present in the response
 	Binary []string `yaml:"binary,omitempty"`
 	// DSL are the dsl queries
 	DSL []string `yaml:"dsl,omitempty"`
+	// Cookies are the name patterns of the cookies checked by the cookie
+	// matcher, like *session*, all the cookies being checked by default
+	Cookies []string `yaml:"cookies,omitempty"`
+	// Missing are the attributes the cookies are matched without (secure,
+	// httponly or samesite), all of them by default
+	Missing []string `yaml:"missing,omitempty"`
+	// Versions are the constraints on the detected version like >=1.2, <1.4.7
+	Versions []string `yaml:"versions,omitempty"`
 	// dslCompiled is the compiled variant
 	dslCompiled []*govaluate.EvaluableExpression
 
@@ -49,8 +61,29 @@ type Matcher struct {
 	// Negative specifies if the match should be reversed
 	// It will only match if the condition is not true.
 	Negative bool `yaml:"negative,omitempty"`
+
+	// Weight is the confidence given to the matcher with the weighted condition
+	//
+	// By default, the weight is 1.
+	Weight float64 `yaml:"weight,omitempty"`
+
+	// Similarity is the maximum similarity of the response with the
+	// response of its host to a random path for the baseline matcher,
+	// or with the reference response for the diff matcher.
+	//
+	// By default, the similarity is 0.9.
+	Similarity float64 `yaml:"similarity,omitempty"`
+
+	// Reference is the request whose latest response is compared with
+	// the response by the diff matcher, starting from 1.
+	//
+	// By default, the response of the first request is the reference.
+	Reference int `yaml:"reference,omitempty"`
 }
 
+// defaultSimilarity is the maximum similarity with the baseline by default
+const defaultSimilarity = 0.9
+
 // MatcherType is the type of the matcher specified
 type MatcherType = int
 
@@ -67,16 +100,28 @@ const (
 	SizeMatcher
 	// DSLMatcher matches based upon dsl syntax
 	DSLMatcher
+	// BaselineMatcher matches responses dissimilar from the soft 404 baseline
+	BaselineMatcher
+	// DiffMatcher matches responses different from the response to another request
+	DiffMatcher
+	// VersionMatcher matches the detected version against constraints
+	VersionMatcher
+	// CookieMatcher matches the cookies set without security attributes
+	CookieMatcher
 )
 
 // MatcherTypes is an table for conversion of matcher type from string.
 var MatcherTypes = map[string]MatcherType{
-	"status": StatusMatcher,
-	"size":   SizeMatcher,
-	"word":   WordsMatcher,
-	"regex":  RegexMatcher,
-	"binary": BinaryMatcher,
-	"dsl":    DSLMatcher,
+	"status":   StatusMatcher,
+	"size":     SizeMatcher,
+	"word":     WordsMatcher,
+	"regex":    RegexMatcher,
+	"binary":   BinaryMatcher,
+	"dsl":      DSLMatcher,
+	"baseline": BaselineMatcher,
+	"diff":     DiffMatcher,
+	"version":  VersionMatcher,
+	"cookie":   CookieMatcher,
 }
 
 // ConditionType is the type of condition for matcher
@@ -87,12 +132,16 @@ const (
 	ANDCondition ConditionType = iota + 1
 	// ORCondition matches responses with AND condition in arguments.
 	ORCondition
+	// WeightedCondition matches responses when the weights of the matched
+	// matchers reach a threshold. It can only be used between matchers.
+	WeightedCondition
 )
 
 // ConditionTypes is an table for conversion of condition type from string.
 var ConditionTypes = map[string]ConditionType{
-	"and": ANDCondition,
-	"or":  ORCondition,
+	"and":      ANDCondition,
+	"or":       ORCondition,
+	"weighted": WeightedCondition,
 }
 
 // Part is the part of the request to match
@@ -105,13 +154,20 @@ const (
 	HeaderPart
 	// AllPart matches both response body and headers of the response.
 	AllPart
+	// InteractionPart matches the out-of-band interactions of the request.
+	InteractionPart
+	// VerificationPart matches the response verifying that the response of
+	// the request was cached.
+	VerificationPart
 )
 
 // PartTypes is an table for conversion of part type from string.
 var PartTypes = map[string]Part{
-	"body":   BodyPart,
-	"header": HeaderPart,
-	"all":    AllPart,
+	"body":         BodyPart,
+	"header":       HeaderPart,
+	"all":          AllPart,
+	"interaction":  InteractionPart,
+	"verification": VerificationPart,
 }
 
 // GetPart returns the part of the matcher
@@ -119,6 +175,59 @@ func (m *Matcher) GetPart() Part {
 	return m.part
 }
 
+// GetType returns the type of the matcher
+func (m *Matcher) GetType() MatcherType {
+	return m.matcherType
+}
+
+// GetSimilarity returns the maximum similarity with the baseline
+func (m *Matcher) GetSimilarity() float64 {
+	if m.Similarity <= 0 {
+		return defaultSimilarity
+	}
+
+	return m.Similarity
+}
+
+// GetReference returns the position of the reference request of the diff matcher
+func (m *Matcher) GetReference() int {
+	if m.Reference <= 0 {
+		return 0
+	}
+
+	return m.Reference - 1
+}
+
+// GetWeight returns the weight of the matcher
+func (m *Matcher) GetWeight() float64 {
+	if m.Weight <= 0 {
+		return 1
+	}
+
+	return m.Weight
+}
+
+// WeightedScore returns the ratio between the weights of the matched
+// matchers and the weights of all the matchers.
+func WeightedScore(matchers []*Matcher, matched []bool) float64 {
+	var total, score float64
+
+	for i, matcher := range matchers {
+		weight := matcher.GetWeight()
+		total += weight
+
+		if i < len(matched) && matched[i] {
+			score += weight
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return score / total
+}
+
 // isNegative reverts the results of the match if the matcher
 // is of type negative.
 func (m *Matcher) isNegative(data bool) bool {
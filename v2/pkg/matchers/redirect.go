@@ -0,0 +1,86 @@
+package matchers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// canaryHostKey is the context key of the canary host of a request
+type canaryHostKey struct{}
+
+// WithCanary returns a context of the requests sending a canary host, whose
+// redirects to it are made available to the dsl as canary_redirect
+func WithCanary(ctx context.Context, host string) context.Context {
+	if host == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, canaryHostKey{}, strings.ToLower(host))
+}
+
+// IsCanary returns true if a request is sent to the canary host of the
+// request it was redirected from
+func IsCanary(req *http.Request) bool {
+	return req != nil && req.URL != nil && isCanaryHost(req.Context(), req.URL.Hostname())
+}
+
+// isCanaryHost returns true if a host name is the canary host of a context
+// or one of its subdomains
+func isCanaryHost(ctx context.Context, host string) bool {
+	canary, _ := ctx.Value(canaryHostKey{}).(string)
+	if canary == "" {
+		return false
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	return host == canary || strings.HasSuffix(host, "."+canary)
+}
+
+// RedirectChain returns the urls of the redirect chain of a response, from
+// the url requested to the location of the response when it redirects
+// without being followed
+func RedirectChain(resp *http.Response) []string {
+	var chain []string
+
+	// the requests of the followed redirects keep the response redirecting
+	for req := resp.Request; req != nil && req.URL != nil; {
+		chain = append([]string{req.URL.String()}, chain...)
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest {
+		if location, err := resp.Location(); err == nil {
+			chain = append(chain, location.String())
+		}
+	}
+
+	return chain
+}
+
+// redirectToMap adds the redirect chain of a response to a dsl map
+func redirectToMap(resp *http.Response, m map[string]interface{}) {
+	chain := RedirectChain(resp)
+
+	m["redirect_chain"] = strings.Join(chain, " ")
+	m["redirects"] = 0
+	m["canary_redirect"] = false
+
+	if len(chain) == 0 || resp.Request == nil {
+		return
+	}
+	m["redirects"] = len(chain) - 1
+
+	// the canary is only reached by a redirect, not by the url requested
+	for _, link := range chain[1:] {
+		parsed, err := url.Parse(link)
+		if err == nil && isCanaryHost(resp.Request.Context(), parsed.Hostname()) {
+			m["canary_redirect"] = true
+		}
+	}
+}
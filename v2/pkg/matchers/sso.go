@@ -0,0 +1,75 @@
+package matchers
+
+import (
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/sso"
+)
+
+// ssoToMap adds the openid connect discovery document or the saml
+// metadata of a response body to a dsl map, the lists being separated by
+// spaces and the values being empty or false for the other responses
+func ssoToMap(body string, m map[string]interface{}) {
+	m["oidc"] = false
+	m["oidc_issuer"] = ""
+	m["oidc_endpoints"] = ""
+	m["oidc_insecure_endpoints"] = ""
+	m["oidc_registration_endpoint"] = ""
+	m["oidc_signing_algorithms"] = ""
+	m["oidc_response_types"] = ""
+	m["oidc_grant_types"] = ""
+	m["oidc_auth_methods"] = ""
+
+	m["saml"] = false
+	m["saml_entity_id"] = ""
+	m["saml_idp"] = false
+	m["saml_sp"] = false
+	m["saml_endpoints"] = ""
+	m["saml_bindings"] = ""
+	m["saml_want_authn_requests_signed"] = false
+	m["saml_authn_requests_signed"] = false
+	m["saml_want_assertions_signed"] = false
+	m["saml_certificates"] = 0
+	m["saml_cert_not_after"] = float64(0)
+
+	if sso.IsOIDC(body) {
+		if metadata, err := sso.ParseOIDC(body); err == nil {
+			m["oidc"] = true
+			m["oidc_issuer"] = metadata.Issuer
+			m["oidc_endpoints"] = strings.Join(metadata.Endpoints(), " ")
+			m["oidc_insecure_endpoints"] = strings.Join(metadata.InsecureEndpoints(), " ")
+			m["oidc_registration_endpoint"] = metadata.RegistrationEndpoint
+			m["oidc_signing_algorithms"] = strings.Join(metadata.SigningAlgorithms, " ")
+			m["oidc_response_types"] = strings.Join(metadata.ResponseTypes, " ")
+			m["oidc_grant_types"] = strings.Join(metadata.GrantTypes, " ")
+			m["oidc_auth_methods"] = strings.Join(metadata.TokenEndpointAuthMethods, " ")
+		}
+
+		return
+	}
+
+	if !sso.IsSAML(body) {
+		return
+	}
+
+	metadata, err := sso.ParseSAML(body)
+	if err != nil {
+		return
+	}
+
+	m["saml"] = true
+	m["saml_entity_id"] = metadata.EntityID
+	m["saml_idp"] = metadata.IdentityProvider
+	m["saml_sp"] = metadata.ServiceProvider
+	m["saml_endpoints"] = strings.Join(metadata.Endpoints, " ")
+	m["saml_bindings"] = strings.Join(metadata.Bindings, " ")
+	m["saml_want_authn_requests_signed"] = metadata.WantAuthnRequestsSigned
+	m["saml_authn_requests_signed"] = metadata.AuthnRequestsSigned
+	m["saml_want_assertions_signed"] = metadata.WantAssertionsSigned
+	m["saml_certificates"] = len(metadata.Certificates)
+
+	// a unix timestamp to be compared with unix_time()
+	if notAfter := metadata.CertificateNotAfter(); !notAfter.IsZero() {
+		m["saml_cert_not_after"] = float64(notAfter.Unix())
+	}
+}
@@ -10,11 +10,14 @@ import (
 	"github.com/miekg/dns"
 )
 
-func httpToMap(resp *http.Response, body, headers string, duration time.Duration) (m map[string]interface{}) {
+// HTTPToMap converts a http response to a map usable in dsl expressions
+func HTTPToMap(resp *http.Response, body, headers string, duration time.Duration) (m map[string]interface{}) {
 	m = make(map[string]interface{})
 
 	m["content_length"] = resp.ContentLength
 	m["status_code"] = resp.StatusCode
+	// always defined, even when the header is missing
+	m["content_type"] = ""
 
 	for k, v := range resp.Header {
 		k = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(k, "-", "_")))
@@ -31,11 +34,21 @@ func httpToMap(resp *http.Response, body, headers string, duration time.Duration
 	// Converts duration to seconds (floating point) for DSL syntax
 	m["duration"] = duration.Seconds()
 
+	connectionToMap(resp, m)
+	redirectToMap(resp, m)
+	cacheToMap(resp, m)
+	jwtToMap(headers, body, m)
+	ssoToMap(body, m)
+
 	return m
 }
 
-func dnsToMap(msg *dns.Msg) (m map[string]interface{}) {
-	m = make(map[string]interface{})
+func dnsToMap(msg *dns.Msg, data map[string]interface{}) (m map[string]interface{}) {
+	m = make(map[string]interface{}, len(data))
+
+	for k, v := range data {
+		m[k] = v
+	}
 
 	m["rcode"] = msg.Rcode
 
@@ -71,3 +84,16 @@ func dnsToMap(msg *dns.Msg) (m map[string]interface{}) {
 
 	return m
 }
+
+// ServiceToMap converts the response of a network service to a map usable in dsl expressions
+func ServiceToMap(raw string, data map[string]interface{}) (m map[string]interface{}) {
+	m = make(map[string]interface{}, len(data)+1)
+
+	for k, v := range data {
+		m[k] = v
+	}
+
+	m["raw"] = raw
+
+	return m
+}
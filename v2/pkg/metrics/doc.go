@@ -0,0 +1,3 @@
+// Package metrics collects the statistics of every template across a scan,
+// flagging the templates matching most of the targets as likely broken.
+package metrics
@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// DefaultSuspiciousMatchRate is the match rate above which a template is
+// flagged as suspicious
+const DefaultSuspiciousMatchRate = 0.95
+
+// minSuspiciousExecutions is the number of executions of a template below
+// which its match rate isn't significant
+const minSuspiciousExecutions = 10
+
+// Metrics collects the statistics of the templates of a scan
+type Metrics struct {
+	suspiciousMatchRate float64
+
+	mutex     sync.Mutex
+	templates map[string]*counters
+}
+
+// counters contains the statistics of a template
+type counters struct {
+	executions    uint64
+	matches       uint64
+	errors        uint64
+	responses     uint64
+	responseBytes uint64
+}
+
+// Template contains the statistics of a template across a scan
+type Template struct {
+	Template            string  `json:"template"`
+	Executions          uint64  `json:"executions"`
+	Matches             uint64  `json:"matches"`
+	Errors              uint64  `json:"errors"`
+	MatchRate           float64 `json:"match_rate"`
+	ErrorRate           float64 `json:"error_rate"`
+	Responses           uint64  `json:"responses"`
+	AverageResponseSize uint64  `json:"average_response_size"`
+	// Suspicious is set when the template matched most of the targets
+	Suspicious bool `json:"suspicious,omitempty"`
+}
+
+// New creates the metrics of a scan flagging the templates matching more
+// than a rate of the targets, none when the rate is 0
+func New(suspiciousMatchRate float64) *Metrics {
+	return &Metrics{
+		suspiciousMatchRate: suspiciousMatchRate,
+		templates:           make(map[string]*counters),
+	}
+}
+
+// counters returns the statistics of a template, the mutex being held
+func (m *Metrics) counters(template string) *counters {
+	c, ok := m.templates[template]
+	if !ok {
+		c = &counters{}
+		m.templates[template] = c
+	}
+
+	return c
+}
+
+// Execution records an execution of a template on a target
+func (m *Metrics) Execution(template string, matched, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	c := m.counters(template)
+	c.executions++
+	if matched {
+		c.matches++
+	}
+	if failed {
+		c.errors++
+	}
+}
+
+// Response records the size of a response received by a template
+func (m *Metrics) Response(template string, size int) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	c := m.counters(template)
+	c.responses++
+	c.responseBytes += uint64(size)
+}
+
+// Templates returns the statistics of the executed templates, the highest
+// match rates first
+func (m *Metrics) Templates() []*Template {
+	if m == nil {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	templates := make([]*Template, 0, len(m.templates))
+	for id, c := range m.templates {
+		if c.executions == 0 {
+			continue
+		}
+
+		template := &Template{
+			Template:   id,
+			Executions: c.executions,
+			Matches:    c.matches,
+			Errors:     c.errors,
+			MatchRate:  float64(c.matches) / float64(c.executions),
+			ErrorRate:  float64(c.errors) / float64(c.executions),
+			Responses:  c.responses,
+		}
+		if c.responses > 0 {
+			template.AverageResponseSize = c.responseBytes / c.responses
+		}
+
+		template.Suspicious = m.suspiciousMatchRate > 0 &&
+			c.executions >= minSuspiciousExecutions &&
+			template.MatchRate >= m.suspiciousMatchRate
+
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].MatchRate != templates[j].MatchRate {
+			return templates[i].MatchRate > templates[j].MatchRate
+		}
+
+		return templates[i].Template < templates[j].Template
+	})
+
+	return templates
+}
+
+// WriteJSON writes the statistics of the templates as json to a file
+func WriteJSON(file string, templates []*Template) error {
+	data, err := jsoniter.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0644)
+}
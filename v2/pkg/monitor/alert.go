@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// slackHost is the host of the slack incoming webhooks, which expect a
+// text message instead of the changes
+const slackHost = "hooks.slack.com"
+
+// Alerter posts the changes of the monitored findings to a webhook
+type Alerter struct {
+	url    string
+	slack  bool
+	client *http.Client
+}
+
+// NewAlerter creates an alerter posting to a webhook url. The changes are
+// posted as json, or as a text message to the slack webhooks.
+func NewAlerter(webhook string, timeout time.Duration) (*Alerter, error) {
+	parsed, err := url.Parse(webhook)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook url %s", webhook)
+	}
+
+	return &Alerter{
+		url:    webhook,
+		slack:  parsed.Hostname() == slackHost,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Alert posts the changes to the webhook
+func (a *Alerter) Alert(changes *Changes) error {
+	if a == nil {
+		return nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	if a.slack {
+		data, err = jsoniter.Marshal(map[string]string{"text": changes.Text()})
+	} else {
+		data, err = jsoniter.Marshal(changes)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := a.client.Post(a.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Text returns a human readable summary of the changes
+func (c *Changes) Text() string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintf(builder, "Nuclei monitoring run of %s: %d new, %d resolved findings\n",
+		c.Time.Format(time.RFC3339), len(c.New), len(c.Resolved))
+
+	writeRecords(builder, "New", c.New)
+	writeRecords(builder, "Resolved", c.Resolved)
+
+	return builder.String()
+}
+
+func writeRecords(builder *strings.Builder, title string, records []*summary.Record) {
+	if len(records) == 0 {
+		return
+	}
+
+	fmt.Fprintf(builder, "%s:\n", title)
+
+	for _, record := range records {
+		fmt.Fprintf(builder, "  [%s] [%s] %s\n", record.Template, record.Severity, record.Matched)
+	}
+}
@@ -0,0 +1,4 @@
+// Package monitor implements the continuous monitoring mode, re-running the
+// scans on a schedule, keeping the history of their findings and alerting
+// on the findings that appeared or were resolved since the previous run.
+package monitor
@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// maxRuns is the number of runs kept in the history
+const maxRuns = 100
+
+// Run contains the findings of a monitoring run
+type Run struct {
+	Time     time.Time         `json:"time"`
+	Findings []*summary.Record `json:"findings"`
+}
+
+// History contains the previous runs of a monitored scan
+type History struct {
+	Runs []*Run `json:"runs"`
+}
+
+// Changes are the findings that appeared or were resolved between two runs
+type Changes struct {
+	Time     time.Time         `json:"time"`
+	New      []*summary.Record `json:"new"`
+	Resolved []*summary.Record `json:"resolved"`
+}
+
+// LoadHistory reads the history from a file, returning an empty history if
+// the file does not exist yet.
+func LoadHistory(file string) (*History, error) {
+	history := &History{}
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := jsoniter.Unmarshal(data, history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Save writes the history to a file
+func (h *History) Save(file string) error {
+	data, err := jsoniter.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// Last returns the latest run, or nil if there are none
+func (h *History) Last() *Run {
+	if len(h.Runs) == 0 {
+		return nil
+	}
+
+	return h.Runs[len(h.Runs)-1]
+}
+
+// Add appends a run to the history, dropping the oldest runs over the limit
+// and returning the changes since the previous run. The first run has no
+// changes as its findings are the baseline.
+func (h *History) Add(run *Run) *Changes {
+	previous := h.Last()
+
+	h.Runs = append(h.Runs, run)
+	if len(h.Runs) > maxRuns {
+		h.Runs = h.Runs[len(h.Runs)-maxRuns:]
+	}
+
+	changes := &Changes{Time: run.Time}
+	if previous == nil {
+		return changes
+	}
+
+	changes.New = difference(run.Findings, previous.Findings)
+	changes.Resolved = difference(previous.Findings, run.Findings)
+
+	return changes
+}
+
+// Empty returns true if no finding appeared or was resolved
+func (c *Changes) Empty() bool {
+	return len(c.New) == 0 && len(c.Resolved) == 0
+}
+
+// difference returns the findings of a missing from b, sorted by template
+// and matched target.
+func difference(a, b []*summary.Record) []*summary.Record {
+	hashes := make(map[string]struct{}, len(b))
+	for _, record := range b {
+		hashes[record.Hash] = struct{}{}
+	}
+
+	var records []*summary.Record
+
+	for _, record := range a {
+		if _, ok := hashes[record.Hash]; !ok {
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Template != records[j].Template {
+			return records[i].Template < records[j].Template
+		}
+
+		return records[i].Matched < records[j].Matched
+	})
+
+	return records
+}
@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	cronFields = 5
+	// maxScheduleSearch bounds the search of the next run of a schedule
+	// never matching, like the 31st of february.
+	maxScheduleSearch = 4 * 366 * 24 * time.Hour
+)
+
+// Schedule is a cron like schedule of the scans
+type Schedule struct {
+	every time.Duration
+
+	minutes  []bool
+	hours    []bool
+	days     []bool
+	months   []bool
+	weekdays []bool
+	// anyDay and anyWeekday report if the days fields are unrestricted,
+	// as restricting both of them matches either of them like cron does.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// descriptors are the shorthands of the common schedules
+var descriptors = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// ParseSchedule parses a 5 fields cron expression (minute, hour, day of
+// month, month, day of week) supporting lists, ranges and steps, one of the
+// @hourly, @daily, @weekly and @monthly shorthands or an @every interval
+// like @every 30m.
+func ParseSchedule(value string) (*Schedule, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(value, "@every ")))
+		if err != nil || every < time.Minute {
+			return nil, fmt.Errorf("invalid schedule interval %s (It should be at least 1m)", value)
+		}
+
+		return &Schedule{every: every}, nil
+	}
+
+	if expression, ok := descriptors[value]; ok {
+		value = expression
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) != cronFields {
+		return nil, fmt.Errorf("invalid schedule %s (It should be a cron expression like */30 * * * *)", value)
+	}
+
+	schedule := &Schedule{
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+
+	var err error
+	if schedule.minutes, err = parseField(fields[0], 0, 59); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = parseField(fields[1], 0, 23); err != nil {
+		return nil, err
+	}
+	if schedule.days, err = parseField(fields[2], 1, 31); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = parseField(fields[3], 1, 12); err != nil {
+		return nil, err
+	}
+	// sunday is either 0 or 7
+	if schedule.weekdays, err = parseField(fields[4], 0, 7); err != nil {
+		return nil, err
+	}
+	schedule.weekdays[0] = schedule.weekdays[0] || schedule.weekdays[7]
+
+	return schedule, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps
+// between min and max.
+func parseField(field string, min, max int) ([]bool, error) {
+	values := make([]bool, max+1)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in schedule field %s", field)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid value in schedule field %s", field)
+			}
+
+			end = start
+			if len(bounds) == 2 {
+				if end, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, fmt.Errorf("invalid range in schedule field %s", field)
+				}
+			} else if step > 1 {
+				// a value with a step runs until the maximum like cron
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("schedule field %s out of range %d-%d", field, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+// Next returns the time of the first run after t, or the zero time if the
+// schedule never runs.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every)
+	}
+
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.Add(maxScheduleSearch); next.Before(limit); next = next.Add(time.Minute) {
+		if s.matches(next) {
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+// matches returns true if the schedule runs at the minute of t
+func (s *Schedule) matches(t time.Time) bool {
+	if !s.minutes[t.Minute()] || !s.hours[t.Hour()] || !s.months[t.Month()] {
+		return false
+	}
+
+	day, weekday := s.days[t.Day()], s.weekdays[t.Weekday()]
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// discordMaxBatch is the discord limit of embeds per message
+const discordMaxBatch = 10
+
+// discord posts the findings as colored embeds to a discord webhook
+type discord struct {
+	webhook string
+	client  *http.Client
+}
+
+type discordMessage struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+func newDiscord(webhook string, client *http.Client) (Provider, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return &discord{webhook: webhook, client: client}, nil
+}
+
+// Send posts a batch of findings
+func (d *discord) Send(findings []*summary.Record) error {
+	message := &discordMessage{Content: title(findings)}
+
+	for _, finding := range findings {
+		message.Embeds = append(message.Embeds, discordEmbed{
+			Title:       "[" + finding.Severity + "] " + finding.Template,
+			Description: finding.Matched,
+			Color:       severityColors[finding.Severity],
+		})
+	}
+
+	return postJSON(d.client, d.webhook, message)
+}
+
+// MaxBatch is the maximum number of findings per message
+func (d *discord) MaxBatch() int {
+	return discordMaxBatch
+}
@@ -0,0 +1,3 @@
+// Package notify posts formatted summaries of the findings to chat
+// webhooks like slack, discord and microsoft teams, or sends them by email.
+package notify
@@ -0,0 +1,216 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// severityRanks contains the ordering of the known severities
+var severityRanks = map[string]int{
+	"info":     1,
+	"low":      2,
+	"medium":   3,
+	"high":     4,
+	"critical": 5,
+}
+
+// severityColors are the colors of the findings by severity
+var severityColors = map[string]int{
+	"critical": 0xd00000,
+	"high":     0xe8590c,
+	"medium":   0xf2c94c,
+	"low":      0x2f80ed,
+	"info":     0x828282,
+}
+
+// Provider posts batches of findings to a chat service
+type Provider interface {
+	// Send posts a batch of findings
+	Send(findings []*summary.Record) error
+	// MaxBatch is the maximum number of findings per message
+	MaxBatch() int
+}
+
+// Providers creates the supported providers from their webhook url
+var Providers = map[string]func(webhook string, client *http.Client) (Provider, error){
+	"slack":   newSlack,
+	"discord": newDiscord,
+	"teams":   newTeams,
+	"email":   newEmail,
+}
+
+// Options contains the configuration of the notifications
+type Options struct {
+	// Webhooks are the providers webhooks as provider=url values, the email
+	// provider taking a smtp url instead
+	Webhooks []string
+	// Severity is the minimum severity of the findings notified
+	Severity string
+	// Timeout is the timeout of each posted message
+	Timeout time.Duration
+}
+
+// Notifier sends the findings at or above a severity to the providers
+type Notifier struct {
+	providers []Provider
+	rank      int
+}
+
+// New creates a notifier from the options, returning nil without webhooks
+func New(options *Options) (*Notifier, error) {
+	if len(options.Webhooks) == 0 {
+		return nil, nil
+	}
+
+	rank, ok := severityRanks[strings.ToLower(options.Severity)]
+	if !ok {
+		return nil, fmt.Errorf("invalid notification severity %s", options.Severity)
+	}
+
+	notifier := &Notifier{rank: rank}
+	client := &http.Client{Timeout: options.Timeout}
+
+	for _, webhook := range options.Webhooks {
+		parts := strings.SplitN(webhook, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid notification webhook %s (It should be provider=url)", webhook)
+		}
+
+		newProvider, ok := Providers[strings.ToLower(parts[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown notification provider %s", parts[0])
+		}
+
+		provider, err := newProvider(parts[1], client)
+		if err != nil {
+			return nil, err
+		}
+
+		notifier.providers = append(notifier.providers, provider)
+	}
+
+	return notifier, nil
+}
+
+// Notify sends the findings at or above the severity threshold, most
+// severe first and without duplicates, in as few messages as possible.
+func (n *Notifier) Notify(findings []*summary.Record) error {
+	if n == nil {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(findings))
+
+	var notified []*summary.Record
+
+	for _, finding := range findings {
+		if _, ok := seen[finding.Hash]; ok || severityRanks[finding.Severity] < n.rank {
+			continue
+		}
+
+		seen[finding.Hash] = struct{}{}
+		notified = append(notified, finding)
+	}
+
+	if len(notified) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(notified, func(i, j int) bool {
+		if severityRanks[notified[i].Severity] != severityRanks[notified[j].Severity] {
+			return severityRanks[notified[i].Severity] > severityRanks[notified[j].Severity]
+		}
+
+		return notified[i].Template < notified[j].Template
+	})
+
+	var errs []string
+
+	for _, provider := range n.providers {
+		for start := 0; start < len(notified); start += provider.MaxBatch() {
+			end := start + provider.MaxBatch()
+			if end > len(notified) {
+				end = len(notified)
+			}
+
+			if err := provider.Send(notified[start:end]); err != nil {
+				errs = append(errs, err.Error())
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not send notifications: %s", strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// title returns the headline of a batch of findings
+func title(findings []*summary.Record) string {
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+
+	var parts []string
+
+	for _, severity := range []string{"critical", "high", "medium", "low", "info"} {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+
+	headline := fmt.Sprintf("Nuclei found %d findings (%s)", len(findings), strings.Join(parts, ", "))
+
+	// the findings of a scan share its labels
+	if labels := summary.FormatLabels(findings[0].Labels); labels != "" {
+		headline += " [" + labels + "]"
+	}
+
+	return headline
+}
+
+// color returns the color of the severity of a finding as a hex string
+func color(severity string) string {
+	return fmt.Sprintf("#%06x", severityColors[severity])
+}
+
+// validateWebhook checks that a chat webhook is a http url
+func validateWebhook(webhook string) error {
+	parsed, err := url.Parse(webhook)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid notification webhook url %s", webhook)
+	}
+
+	return nil
+}
+
+// postJSON posts a json payload to a webhook
+func postJSON(client *http.Client, webhook string, payload interface{}) error {
+	data, err := jsoniter.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
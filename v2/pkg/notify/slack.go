@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// slackMaxBatch keeps the messages under the slack attachments limit
+const slackMaxBatch = 50
+
+// slack posts the findings as colored attachments to a slack webhook
+type slack struct {
+	webhook string
+	client  *http.Client
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+func newSlack(webhook string, client *http.Client) (Provider, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return &slack{webhook: webhook, client: client}, nil
+}
+
+// Send posts a batch of findings
+func (s *slack) Send(findings []*summary.Record) error {
+	message := &slackMessage{Text: title(findings)}
+
+	for _, finding := range findings {
+		message.Attachments = append(message.Attachments, slackAttachment{
+			Color: color(finding.Severity),
+			Title: "[" + finding.Severity + "] " + finding.Template,
+			Text:  finding.Matched,
+		})
+	}
+
+	return postJSON(s.client, s.webhook, message)
+}
+
+// MaxBatch is the maximum number of findings per message
+func (s *slack) MaxBatch() int {
+	return slackMaxBatch
+}
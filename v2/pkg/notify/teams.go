@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"net/http"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// teamsMaxBatch is the teams limit of sections per message card
+const teamsMaxBatch = 10
+
+// teams posts the findings as message card sections to a teams webhook.
+// The card takes the color of its most severe finding.
+type teams struct {
+	webhook string
+	client  *http.Client
+}
+
+type teamsCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	ActivityTitle string `json:"activityTitle"`
+	Text          string `json:"text"`
+}
+
+func newTeams(webhook string, client *http.Client) (Provider, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return &teams{webhook: webhook, client: client}, nil
+}
+
+// Send posts a batch of findings, sorted by decreasing severity
+func (t *teams) Send(findings []*summary.Record) error {
+	card := &teamsCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: color(findings[0].Severity),
+		Summary:    title(findings),
+		Title:      title(findings),
+	}
+
+	for _, finding := range findings {
+		card.Sections = append(card.Sections, teamsSection{
+			ActivityTitle: "[" + finding.Severity + "] " + finding.Template,
+			Text:          finding.Matched,
+		})
+	}
+
+	return postJSON(t.client, t.webhook, card)
+}
+
+// MaxBatch is the maximum number of findings per message
+func (t *teams) MaxBatch() int {
+	return teamsMaxBatch
+}
@@ -0,0 +1,165 @@
+package oob
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// DefaultCollaboratorServer is the polling server of the public Burp
+// Collaborator
+const DefaultCollaboratorServer = "polling.burpcollaborator.net"
+
+// CollaboratorOptions contains the configuration of a Burp Collaborator
+type CollaboratorOptions struct {
+	// Server is the polling server, as a host or an url
+	Server string
+	// BIID is the secret identifying the interactions of the client
+	BIID string
+	// Payload is the payload host generated by Burp for the biid
+	Payload string
+}
+
+// Collaborator polls the interactions of a Burp Collaborator server
+type Collaborator struct {
+	pollURL string
+	payload string
+	client  *http.Client
+}
+
+// NewCollaborator creates a provider polling a Burp Collaborator server for
+// the interactions of a biid
+func NewCollaborator(options *CollaboratorOptions) (*Collaborator, error) {
+	if options.BIID == "" || options.Payload == "" {
+		return nil, errors.New("the biid and the payload of the collaborator are required")
+	}
+
+	server := options.Server
+	if server == "" {
+		server = DefaultCollaboratorServer
+	}
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+
+	parsed, err := url.Parse(server)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collaborator server %s: %s", options.Server, err)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/burpresults"
+	parsed.RawQuery = url.Values{"biid": []string{options.BIID}}.Encode()
+
+	return &Collaborator{
+		pollURL: parsed.String(),
+		payload: options.Payload,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Domain returns the payload host, whose subdomains are reported
+func (c *Collaborator) Domain() string {
+	return c.payload
+}
+
+// Poll returns the interactions received since the previous poll, the
+// collaborator returning each interaction once
+func (c *Collaborator) Poll() ([]*Interaction, error) {
+	resp, err := c.client.Get(c.pollURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("collaborator returned status %d", resp.StatusCode)
+	}
+
+	var results struct {
+		Responses []*collaboratorResponse `json:"responses"`
+	}
+	if err := jsoniter.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("could not decode collaborator results: %s", err)
+	}
+
+	interactions := make([]*Interaction, 0, len(results.Responses))
+	for _, response := range results.Responses {
+		interactions = append(interactions, response.interaction())
+	}
+
+	return interactions, nil
+}
+
+// collaboratorResponse is an interaction returned by the polling api, the
+// requests being encoded in base64
+type collaboratorResponse struct {
+	Protocol string `json:"protocol"`
+	ClientIP string `json:"clientIp"`
+	Time     string `json:"time"`
+	Data     struct {
+		SubDomain    string `json:"subDomain"`
+		RawRequest   string `json:"rawRequest"`
+		Request      string `json:"request"`
+		Conversation string `json:"conversation"`
+	} `json:"data"`
+}
+
+// interaction converts a response of the polling api to an interaction
+func (r *collaboratorResponse) interaction() *Interaction {
+	interaction := &Interaction{
+		Protocol:      strings.ToLower(r.Protocol),
+		RemoteAddress: r.ClientIP,
+		Host:          r.Data.SubDomain,
+	}
+
+	if milliseconds, err := strconv.ParseInt(r.Time, 10, 64); err == nil {
+		interaction.Timestamp = time.Unix(0, milliseconds*int64(time.Millisecond))
+	}
+
+	for _, encoded := range []string{r.Data.Request, r.Data.RawRequest, r.Data.Conversation} {
+		if encoded == "" {
+			continue
+		}
+
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			interaction.RawRequest = string(decoded)
+			break
+		}
+	}
+
+	if interaction.Host == "" {
+		interaction.Host = requestHost(interaction.RawRequest)
+	}
+
+	return interaction
+}
+
+// requestHost returns the host header of a raw http request
+func requestHost(raw string) string {
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+
+		if name := strings.SplitN(line, ":", 2); len(name) == 2 && strings.EqualFold(strings.TrimSpace(name[0]), "host") {
+			host := strings.TrimSpace(name[1])
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				return h
+			}
+
+			return host
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,4 @@
+// Package oob correlates the out-of-band interactions received by a
+// callback server, like the dns lookups and http requests triggered by the
+// payloads of the templates, with the requests that sent the payloads.
+package oob
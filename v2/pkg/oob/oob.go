@@ -0,0 +1,265 @@
+package oob
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// DefaultWait is the default time waited for the interactions of a request
+const DefaultWait = 5 * time.Second
+
+// canaryDomain is the reserved domain of the canary hosts generated
+// without a provider, never resolving
+const canaryDomain = "canary.invalid"
+
+// pollInterval is the interval at which the interactions are polled
+const pollInterval = time.Second
+
+// Interaction is a callback received by the server of a provider
+type Interaction struct {
+	// Protocol is the protocol of the interaction, like dns, http or smtp
+	Protocol string `json:"protocol"`
+	// Host is the host name the interaction was received for
+	Host string `json:"host"`
+	// RemoteAddress is the address the interaction was received from
+	RemoteAddress string `json:"remote_address"`
+	// RawRequest contains the request received
+	RawRequest string `json:"raw_request"`
+	// Timestamp is the time the interaction was received
+	Timestamp time.Time `json:"timestamp"`
+	// Delay is the time between the creation of the host of the request
+	// and the reception of the interaction by the client
+	Delay time.Duration `json:"-"`
+}
+
+// Provider polls the interactions received by a callback server for the
+// subdomains of its domain
+type Provider interface {
+	// Domain returns the domain whose subdomains are reported
+	Domain() string
+	// Poll returns the interactions received since the previous poll
+	Poll() ([]*Interaction, error)
+}
+
+// Client generates the unique hosts sent in the payloads of the requests
+// and collects the interactions received for them
+type Client struct {
+	provider Provider
+	domain   string
+	wait     time.Duration
+
+	mutex sync.Mutex
+	// pending contains the creation time of the hosts waiting for interactions
+	pending      map[string]time.Time
+	interactions map[string][]*Interaction
+	received     chan struct{}
+	done         chan struct{}
+}
+
+// New creates a client of a provider polling its interactions until the
+// client is closed, waiting for the interactions of each request up to a
+// duration
+func New(provider Provider, wait time.Duration) *Client {
+	if wait <= 0 {
+		wait = DefaultWait
+	}
+
+	c := &Client{
+		provider:     provider,
+		domain:       strings.ToLower(strings.Trim(provider.Domain(), ".")),
+		wait:         wait,
+		pending:      make(map[string]time.Time),
+		interactions: make(map[string][]*Interaction),
+		received:     make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+
+	go c.poll()
+
+	return c
+}
+
+// Host returns a new unique host under the domain of the provider, with
+// the id correlating its interactions
+func (c *Client) Host() (id, host string) {
+	if c == nil {
+		return "", ""
+	}
+
+	id = newID()
+
+	c.mutex.Lock()
+	c.pending[id] = time.Now()
+	c.mutex.Unlock()
+
+	return id, id + "." + c.domain
+}
+
+// CanaryHost returns a new unique host for the canary urls of the open
+// redirect and ssrf payloads. Without a provider the host is under a
+// reserved domain, only detecting the redirects to it.
+func (c *Client) CanaryHost() (id, host string) {
+	if c == nil {
+		return "", newID() + "." + canaryDomain
+	}
+
+	return c.Host()
+}
+
+// RebindHost returns the host of an id resolving to the callback server,
+// then to an internal address. The id is created when it is empty, and
+// waits again for its interactions otherwise, for the requests of a
+// target to share the host.
+func (c *Client) RebindHost(id string, ip net.IP) (string, string, error) {
+	if _, ok := c.provider.(*Remote); !ok {
+		return "", "", errors.New("only the self-hosted callback servers serve the rebinding hosts")
+	}
+
+	if id == "" {
+		id = newID()
+	}
+
+	c.mutex.Lock()
+	if _, ok := c.pending[id]; !ok {
+		c.pending[id] = time.Now()
+	}
+	c.mutex.Unlock()
+
+	return id, RebindLabel(ip) + "." + id + "." + c.domain, nil
+}
+
+// newID returns a new random id of the hosts
+func newID() string {
+	data := make([]byte, 8)
+	_, _ = rand.Read(data)
+
+	return hex.EncodeToString(data)
+}
+
+// Wait returns the interactions received for the host of an id, waiting
+// for the first one up to the wait duration of the client, then one more
+// poll for the following ones
+func (c *Client) Wait(id string) []*Interaction {
+	if c == nil || id == "" {
+		return nil
+	}
+
+	deadline := time.NewTimer(c.wait)
+	defer deadline.Stop()
+
+	for {
+		c.mutex.Lock()
+		received := len(c.interactions[id]) > 0
+		notify := c.received
+		c.mutex.Unlock()
+
+		if received {
+			break
+		}
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return c.release(id)
+		case <-c.done:
+			return c.release(id)
+		}
+	}
+
+	select {
+	case <-time.After(pollInterval):
+	case <-c.done:
+	}
+
+	return c.release(id)
+}
+
+// release returns the interactions of an id and forgets it
+func (c *Client) release(id string) []*Interaction {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	interactions := c.interactions[id]
+	delete(c.interactions, id)
+	delete(c.pending, id)
+
+	return interactions
+}
+
+// poll collects the interactions of the provider until the client is closed
+func (c *Client) poll() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+
+		interactions, err := c.provider.Poll()
+		if err != nil {
+			gologger.Warningf("Could not poll the interactions: %s\n", err)
+			continue
+		}
+
+		c.dispatch(interactions)
+	}
+}
+
+// dispatch stores the interactions of the pending hosts, waking up the
+// requests waiting for them
+func (c *Client) dispatch(interactions []*Interaction) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var dispatched bool
+
+	for _, interaction := range interactions {
+		id := c.correlationID(interaction.Host)
+		created, ok := c.pending[id]
+		if !ok {
+			continue
+		}
+
+		interaction.Delay = time.Since(created)
+
+		c.interactions[id] = append(c.interactions[id], interaction)
+		dispatched = true
+	}
+
+	if dispatched {
+		close(c.received)
+		c.received = make(chan struct{})
+	}
+}
+
+// correlationID returns the label preceding the domain of the provider in
+// a host name, the payloads possibly adding their own labels before it
+func (c *Client) correlationID(host string) string {
+	host = strings.ToLower(strings.Trim(host, "."))
+	if !strings.HasSuffix(host, "."+c.domain) {
+		return ""
+	}
+
+	labels := strings.Split(strings.TrimSuffix(host, "."+c.domain), ".")
+
+	return labels[len(labels)-1]
+}
+
+// Close stops polling the interactions
+func (c *Client) Close() {
+	if c == nil {
+		return
+	}
+
+	close(c.done)
+}
@@ -0,0 +1,53 @@
+package oob
+
+import (
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+// DefaultRebindAfter is the default number of address queries of a
+// rebinding host answered with the address of the callback server
+const DefaultRebindAfter = 1
+
+// rebindPrefix starts the label of a rebinding host encoding the internal
+// address it resolves to
+const rebindPrefix = "rebind-"
+
+// maxRebindHosts is the number of the rebinding hosts whose queries are
+// counted by a callback server before the counts are reset
+const maxRebindHosts = 10000
+
+// RebindLabel returns the label of a rebinding host resolving to an
+// internal address, the hex encoded bytes of the address
+func RebindLabel(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	return rebindPrefix + hex.EncodeToString(ip)
+}
+
+// rebindAddress returns the internal address of a rebinding host of a
+// domain, nil when the host is not a rebinding host
+func rebindAddress(host, domain string) net.IP {
+	host = strings.ToLower(strings.Trim(host, "."))
+	if !strings.HasSuffix(host, "."+domain) {
+		return nil
+	}
+
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."+domain), ".") {
+		if !strings.HasPrefix(label, rebindPrefix) {
+			continue
+		}
+
+		data, err := hex.DecodeString(strings.TrimPrefix(label, rebindPrefix))
+		if err != nil || (len(data) != net.IPv4len && len(data) != net.IPv6len) {
+			return nil
+		}
+
+		return net.IP(data)
+	}
+
+	return nil
+}
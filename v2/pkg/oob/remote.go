@@ -0,0 +1,107 @@
+package oob
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// RemoteOptions contains the configuration of the client of a callback
+// server
+type RemoteOptions struct {
+	// URL is the url of the polling api of the server
+	URL string
+	// Token authenticates the client
+	Token string
+}
+
+// Remote polls the interactions of a self-hosted callback server
+type Remote struct {
+	pollURL string
+	token   string
+	domain  string
+	next    uint64
+	client  *http.Client
+}
+
+// NewRemote creates a provider polling a callback server, from the
+// interactions received after its creation
+func NewRemote(options *RemoteOptions) (*Remote, error) {
+	if options.URL == "" || options.Token == "" {
+		return nil, errors.New("the url and the token of the callback server are required")
+	}
+
+	parsed, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid callback server %s: %s", options.URL, err)
+	}
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + InteractionsPath
+
+	r := &Remote{
+		pollURL: parsed.String(),
+		token:   options.Token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	results, err := r.fetch("")
+	if err != nil {
+		return nil, fmt.Errorf("could not reach callback server %s: %s", options.URL, err)
+	}
+	r.domain = results.Domain
+	r.next = results.Next
+
+	return r, nil
+}
+
+// fetch requests the interactions received from a sequence number
+func (r *Remote) fetch(since string) (*serverResults, error) {
+	pollURL := r.pollURL
+	if since != "" {
+		pollURL += "?since=" + since
+	}
+
+	req, err := http.NewRequest(http.MethodGet, pollURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("callback server returned status %d", resp.StatusCode)
+	}
+
+	var results serverResults
+	if err := jsoniter.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("could not decode interactions: %s", err)
+	}
+
+	return &results, nil
+}
+
+// Domain returns the domain of the callback server
+func (r *Remote) Domain() string {
+	return r.domain
+}
+
+// Poll returns the interactions received since the previous poll
+func (r *Remote) Poll() ([]*Interaction, error) {
+	results, err := r.fetch(strconv.FormatUint(r.next, 10))
+	if err != nil {
+		return nil, err
+	}
+	r.next = results.Next
+
+	return results.Interactions, nil
+}
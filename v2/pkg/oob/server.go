@@ -0,0 +1,322 @@
+package oob
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/miekg/dns"
+)
+
+// The default addresses of the listeners of a callback server
+const (
+	DefaultDNSAddress  = ":53"
+	DefaultHTTPAddress = ":80"
+	DefaultAPIAddress  = ":8000"
+)
+
+// InteractionsPath is the path of the polling api of a callback server
+const InteractionsPath = "/interactions"
+
+// maxStoredInteractions is the number of the latest interactions kept by
+// a callback server until they are polled
+const maxStoredInteractions = 10000
+
+// defaultTTL is the ttl of the records answered by a callback server
+const defaultTTL = 60
+
+// ServerOptions contains the configuration of a callback server
+type ServerOptions struct {
+	// Domain is the domain delegated to the server
+	Domain string
+	// IP is the public address of the server answered for the domain
+	IP string
+	// DNSAddress is the address of the dns listener, on udp and tcp
+	DNSAddress string
+	// HTTPAddress is the address of the http listener receiving the callbacks
+	HTTPAddress string
+	// APIAddress is the address of the polling api
+	APIAddress string
+	// Token authenticates the clients of the polling api
+	Token string
+	// RebindAfter is the number of address queries of a rebinding host
+	// answered with the address of the server before its internal address
+	RebindAfter int
+}
+
+// Server receives the dns and http callbacks for the subdomains of a
+// domain, polled by the scans through an authenticated api
+type Server struct {
+	options *ServerOptions
+	domain  string
+	ip      net.IP
+
+	mutex        sync.Mutex
+	interactions []*Interaction
+	// first is the sequence number of the first stored interaction
+	first uint64
+	// rebindQueries counts the address queries of the rebinding hosts
+	rebindQueries map[string]int
+
+	dnsServers  []*dns.Server
+	httpServers []*http.Server
+}
+
+// serverResults is the response of the polling api
+type serverResults struct {
+	Domain       string         `json:"domain"`
+	Next         uint64         `json:"next"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// NewServer creates a callback server for a domain
+func NewServer(options *ServerOptions) (*Server, error) {
+	if options.Domain == "" {
+		return nil, errors.New("the domain of the callback server is required")
+	}
+
+	if options.Token == "" {
+		return nil, errors.New("the token of the callback server is required")
+	}
+
+	ip := net.ParseIP(options.IP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address of the callback server: %s", options.IP)
+	}
+
+	if options.RebindAfter <= 0 {
+		options.RebindAfter = DefaultRebindAfter
+	}
+
+	s := &Server{
+		options:       options,
+		domain:        strings.ToLower(strings.Trim(options.Domain, ".")),
+		ip:            ip,
+		rebindQueries: make(map[string]int),
+	}
+
+	for _, network := range []string{"udp", "tcp"} {
+		s.dnsServers = append(s.dnsServers, &dns.Server{
+			Addr:    defaultString(options.DNSAddress, DefaultDNSAddress),
+			Net:     network,
+			Handler: dns.HandlerFunc(s.serveDNS),
+		})
+	}
+
+	api := http.NewServeMux()
+	api.HandleFunc(InteractionsPath, s.serveInteractions)
+
+	s.httpServers = []*http.Server{
+		{Addr: defaultString(options.HTTPAddress, DefaultHTTPAddress), Handler: http.HandlerFunc(s.serveHTTP)},
+		{Addr: defaultString(options.APIAddress, DefaultAPIAddress), Handler: api},
+	}
+
+	return s, nil
+}
+
+// defaultString returns a value or its default when it is empty
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// ListenAndServe serves the callbacks and the polling api until the server
+// is closed or one of the listeners fails
+func (s *Server) ListenAndServe() error {
+	errs := make(chan error, len(s.dnsServers)+len(s.httpServers))
+
+	for _, server := range s.dnsServers {
+		go func(server *dns.Server) {
+			errs <- server.ListenAndServe()
+		}(server)
+	}
+
+	for _, server := range s.httpServers {
+		go func(server *http.Server) {
+			errs <- server.ListenAndServe()
+		}(server)
+	}
+
+	err := <-errs
+	s.Close()
+
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// Close stops the listeners of the server
+func (s *Server) Close() {
+	for _, server := range s.dnsServers {
+		_ = server.Shutdown()
+	}
+
+	for _, server := range s.httpServers {
+		_ = server.Close()
+	}
+}
+
+// inDomain returns true if a host name is the domain or one of its
+// subdomains
+func (s *Server) inDomain(host string) bool {
+	host = strings.ToLower(strings.Trim(host, "."))
+
+	return host == s.domain || strings.HasSuffix(host, "."+s.domain)
+}
+
+// record stores an interaction received for a subdomain
+func (s *Server) record(interaction *Interaction) {
+	interaction.Timestamp = time.Now()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.interactions = append(s.interactions, interaction)
+	if len(s.interactions) > maxStoredInteractions {
+		dropped := len(s.interactions) - maxStoredInteractions
+		s.interactions = s.interactions[dropped:]
+		s.first += uint64(dropped)
+	}
+}
+
+// serveDNS answers the queries for the domain with the address of the
+// server, recording the queries of its subdomains
+func (s *Server) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	reply := new(dns.Msg)
+	reply.SetReply(req)
+
+	if len(req.Question) == 0 || !s.inDomain(req.Question[0].Name) {
+		reply.Rcode = dns.RcodeRefused
+		_ = w.WriteMsg(reply)
+		return
+	}
+
+	question := req.Question[0]
+	reply.Authoritative = true
+
+	if strings.Trim(strings.ToLower(question.Name), ".") != s.domain {
+		s.record(&Interaction{
+			Protocol:      "dns",
+			Host:          strings.Trim(question.Name, "."),
+			RemoteAddress: remoteHost(w.RemoteAddr().String()),
+			RawRequest:    req.String(),
+		})
+	}
+
+	ip, ttl := s.ip, uint32(defaultTTL)
+	if internal := rebindAddress(question.Name, s.domain); internal != nil && (question.Qtype == dns.TypeA || question.Qtype == dns.TypeAAAA) {
+		// the zero ttl makes the resolvers query the rebinding host again
+		ttl = 0
+		if s.rebindQuery(question) > s.options.RebindAfter {
+			ip = internal
+		}
+	}
+
+	header := dns.RR_Header{Name: question.Name, Rrtype: question.Qtype, Class: dns.ClassINET, Ttl: ttl}
+
+	switch {
+	case question.Qtype == dns.TypeA && ip.To4() != nil:
+		reply.Answer = append(reply.Answer, &dns.A{Hdr: header, A: ip.To4()})
+	case question.Qtype == dns.TypeAAAA && ip.To4() == nil:
+		reply.Answer = append(reply.Answer, &dns.AAAA{Hdr: header, AAAA: ip})
+	case question.Qtype == dns.TypeNS:
+		reply.Answer = append(reply.Answer, &dns.NS{Hdr: header, Ns: dns.Fqdn("ns1." + s.domain)})
+	default:
+		reply.Ns = append(reply.Ns, &dns.SOA{
+			Hdr:    dns.RR_Header{Name: dns.Fqdn(s.domain), Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: defaultTTL},
+			Ns:     dns.Fqdn("ns1." + s.domain),
+			Mbox:   dns.Fqdn("hostmaster." + s.domain),
+			Serial: 1,
+			Minttl: defaultTTL,
+		})
+	}
+
+	_ = w.WriteMsg(reply)
+}
+
+// rebindQuery counts an address query of a rebinding host, returning the
+// number of the queries of its type. The types are counted apart for the
+// clients querying both at once.
+func (s *Server) rebindQuery(question dns.Question) int {
+	key := strings.ToLower(strings.Trim(question.Name, ".")) + " " + strconv.Itoa(int(question.Qtype))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.rebindQueries[key]; !ok && len(s.rebindQueries) >= maxRebindHosts {
+		s.rebindQueries = make(map[string]int)
+	}
+	s.rebindQueries[key]++
+
+	return s.rebindQueries[key]
+}
+
+// serveHTTP records the http requests to the subdomains of the domain
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if s.inDomain(host) {
+		raw, _ := httputil.DumpRequest(r, true)
+		s.record(&Interaction{
+			Protocol:      "http",
+			Host:          host,
+			RemoteAddress: remoteHost(r.RemoteAddr),
+			RawRequest:    string(raw),
+		})
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// serveInteractions returns the interactions received from the sequence
+// number of the since parameter, and the sequence number of the next one.
+// Without the parameter only the next sequence number is returned.
+func (s *Server) serveInteractions(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.options.Token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	s.mutex.Lock()
+	results := &serverResults{
+		Domain: s.domain,
+		Next:   s.first + uint64(len(s.interactions)),
+	}
+
+	if since, err := strconv.ParseUint(r.URL.Query().Get("since"), 10, 64); err == nil && since < results.Next {
+		if since < s.first {
+			since = s.first
+		}
+		results.Interactions = append(results.Interactions, s.interactions[since-s.first:]...)
+	}
+	s.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = jsoniter.NewEncoder(w).Encode(results)
+}
+
+// remoteHost returns the host of a remote address
+func remoteHost(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+
+	return address
+}
@@ -0,0 +1,3 @@
+// Package portscan finds the open tcp ports of the targets with a connect
+// scan, so the services listening on non standard ports are scanned too.
+package portscan
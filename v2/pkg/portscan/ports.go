@@ -0,0 +1,84 @@
+package portscan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TopPorts contains the most common open tcp ports, most common first
+var TopPorts = []int{
+	80, 23, 443, 21, 22, 25, 3389, 110, 445, 139, 143, 53, 135, 3306, 8080, 1723, 111, 995, 993, 5900,
+	1025, 587, 8888, 199, 1720, 465, 548, 113, 81, 6001, 10000, 514, 5060, 179, 1026, 2000, 8443, 8000, 32768, 554,
+	26, 1433, 49152, 2001, 515, 8008, 49154, 1027, 5666, 646, 5000, 5631, 631, 49153, 8081, 2049, 88, 79, 5800, 106,
+	2121, 1110, 49155, 6000, 513, 990, 5357, 427, 49156, 543, 544, 5101, 144, 7, 389, 8009, 3128, 444, 9999, 5009,
+	7070, 5190, 3000, 5432, 1900, 3986, 13, 1029, 9, 5051, 6646, 49157, 1028, 873, 1755, 2717, 4899, 9100, 119, 37,
+	// ports of the services probed by the templates
+	6379, 27017, 9200, 11211, 1883, 5672, 2375, 2376, 6443, 10250, 5601, 9000, 8086, 15672, 5984, 50070, 8161, 61616, 636, 9443,
+}
+
+// Parse parses a list of ports and port ranges like 80,443,8000-8100
+func Parse(spec string) ([]int, error) {
+	seen := make(map[int]struct{})
+
+	var ports []int
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		low, high := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			low, high = part[:i], part[i+1:]
+		}
+
+		first, err := parsePort(low)
+		if err != nil {
+			return nil, err
+		}
+
+		last, err := parsePort(high)
+		if err != nil {
+			return nil, err
+		}
+
+		if last < first {
+			return nil, fmt.Errorf("invalid port range %s", part)
+		}
+
+		for port := first; port <= last; port++ {
+			if _, ok := seen[port]; !ok {
+				seen[port] = struct{}{}
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	sort.Ints(ports)
+
+	return ports, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %s", value)
+	}
+
+	return port, nil
+}
+
+// Top returns the n most common ports
+func Top(n int) []int {
+	if n > len(TopPorts) {
+		n = len(TopPorts)
+	}
+
+	ports := append([]int{}, TopPorts[:n]...)
+	sort.Ints(ports)
+
+	return ports
+}
@@ -0,0 +1,128 @@
+package portscan
+
+import (
+	"crypto/tls"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/remeh/sizedwaitgroup"
+)
+
+// Scanner runs connect scans
+type Scanner struct {
+	timeout     time.Duration
+	concurrency int
+}
+
+// New creates a scanner connecting concurrency ports at once
+func New(timeout time.Duration, concurrency int) *Scanner {
+	return &Scanner{timeout: timeout, concurrency: concurrency}
+}
+
+// Scan returns the open ports of a host among the given ports
+func (s *Scanner) Scan(host string, ports []int) []int {
+	var mutex sync.Mutex
+
+	var open []int
+
+	swg := sizedwaitgroup.New(s.concurrency)
+
+	for _, port := range ports {
+		swg.Add()
+
+		go func(port int) {
+			defer swg.Done()
+
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), s.timeout)
+			if err != nil {
+				return
+			}
+			conn.Close()
+
+			mutex.Lock()
+			open = append(open, port)
+			mutex.Unlock()
+		}(port)
+	}
+
+	swg.Wait()
+
+	sort.Ints(open)
+
+	return open
+}
+
+// IsTLS returns true if the service listening on a port negotiates tls
+func (s *Scanner) IsTLS(host string, port int) bool {
+	dialer := &net.Dialer{Timeout: s.timeout}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, strconv.Itoa(port)), &tls.Config{InsecureSkipVerify: true}) // nolint:gosec // only the protocol is detected
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
+
+// Results contains the outcome of the scans of the hosts
+type Results struct {
+	mutex   sync.RWMutex
+	scanned map[int]struct{}
+	open    map[string]map[int]struct{}
+}
+
+// NewResults creates the results of scans of the given ports
+func NewResults(ports []int) *Results {
+	scanned := make(map[int]struct{}, len(ports))
+	for _, port := range ports {
+		scanned[port] = struct{}{}
+	}
+
+	return &Results{scanned: scanned, open: make(map[string]map[int]struct{})}
+}
+
+// Add records the open ports of a host
+func (r *Results) Add(host string, ports []int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	open := make(map[int]struct{}, len(ports))
+	for _, port := range ports {
+		open[port] = struct{}{}
+	}
+
+	r.open[host] = open
+}
+
+// IsClosed returns true if a port of a host was scanned and found closed.
+// Ports which weren't scanned are never reported closed.
+func (r *Results) IsClosed(host, port string) bool {
+	if r == nil {
+		return false
+	}
+
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if _, ok := r.scanned[number]; !ok {
+		return false
+	}
+
+	open, ok := r.open[host]
+	if !ok {
+		return false
+	}
+
+	_, isOpen := open[number]
+
+	return !isOpen
+}
@@ -0,0 +1,96 @@
+package recommend
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// signature detects a technology from a header, a cookie or the body of
+// the responses, the values being matched case insensitively
+type signature struct {
+	technology string
+	header     string
+	value      string
+	cookie     string
+	body       string
+}
+
+// signatures contains the technologies detected from the responses
+var signatures = []signature{
+	{technology: "nginx", header: "Server", value: "nginx"},
+	{technology: "apache", header: "Server", value: "apache"},
+	{technology: "iis", header: "Server", value: "microsoft-iis"},
+	{technology: "tomcat", header: "Server", value: "tomcat"},
+	{technology: "tomcat", body: "apache tomcat"},
+	{technology: "jetty", header: "Server", value: "jetty"},
+	{technology: "weblogic", body: "weblogic"},
+	{technology: "php", header: "X-Powered-By", value: "php"},
+	{technology: "php", cookie: "phpsessid"},
+	{technology: "asp.net", header: "X-Powered-By", value: "asp.net"},
+	{technology: "asp.net", header: "X-AspNet-Version"},
+	{technology: "express", header: "X-Powered-By", value: "express"},
+	{technology: "java", cookie: "jsessionid"},
+	{technology: "django", cookie: "csrftoken"},
+	{technology: "laravel", cookie: "laravel_session"},
+	{technology: "wordpress", body: "/wp-content/"},
+	{technology: "wordpress", header: "Link", value: "/wp-json/"},
+	{technology: "drupal", header: "X-Generator", value: "drupal"},
+	{technology: "drupal", body: "drupal.settings"},
+	{technology: "joomla", body: "/media/jui/"},
+	{technology: "magento", body: "mage/cookies"},
+	{technology: "jenkins", header: "X-Jenkins"},
+	{technology: "gitlab", body: "gitlab"},
+	{technology: "grafana", body: "grafana"},
+	{technology: "jira", body: "jira"},
+	{technology: "confluence", header: "X-Confluence-Request-Time"},
+	{technology: "kibana", header: "Kbn-Name"},
+	{technology: "spring", body: "whitelabel error page"},
+	{technology: "phpmyadmin", body: "phpmyadmin"},
+	{technology: "sharepoint", header: "MicrosoftSharePointTeamServices"},
+	{technology: "citrix", body: "citrix"},
+	{technology: "cloudflare", header: "Server", value: "cloudflare"},
+}
+
+// Detect returns the sorted technologies detected in a response
+func Detect(resp *http.Response, body string) []string {
+	body = strings.ToLower(body)
+	detected := make(map[string]struct{})
+
+	for _, s := range signatures {
+		if matches(&s, resp, body) {
+			detected[s.technology] = struct{}{}
+		}
+	}
+
+	technologies := make([]string, 0, len(detected))
+	for technology := range detected {
+		technologies = append(technologies, technology)
+	}
+	sort.Strings(technologies)
+
+	return technologies
+}
+
+// matches returns true if a signature matches a response
+func matches(s *signature, resp *http.Response, body string) bool {
+	switch {
+	case s.header != "":
+		values, ok := resp.Header[http.CanonicalHeaderKey(s.header)]
+		if !ok {
+			return false
+		}
+
+		return s.value == "" || strings.Contains(strings.ToLower(strings.Join(values, " ")), s.value)
+	case s.cookie != "":
+		for _, cookie := range resp.Cookies() {
+			if strings.ToLower(cookie.Name) == s.cookie {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return strings.Contains(body, s.body)
+	}
+}
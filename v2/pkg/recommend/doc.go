@@ -0,0 +1,4 @@
+// Package recommend suggests the templates relevant to the technologies
+// detected on the targets, from their responses or from the fingerprints
+// of previous results, without running any template.
+package recommend
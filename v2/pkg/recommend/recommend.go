@@ -0,0 +1,135 @@
+package recommend
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Template is a template that can be recommended
+type Template struct {
+	ID   string
+	Tags string
+	Path string
+}
+
+// Recommendation contains the templates relevant to a technology
+type Recommendation struct {
+	Technology string   `json:"technology"`
+	Templates  []string `json:"templates"`
+	Paths      []string `json:"-"`
+}
+
+// Recommend returns the templates relevant to the technologies, the ones
+// tagged with a technology or having it in their id
+func Recommend(technologies []string, templates []*Template) []*Recommendation {
+	var recommendations []*Recommendation
+
+	for _, technology := range technologies {
+		token := normalize(technology)
+		recommendation := &Recommendation{Technology: token}
+
+		for _, template := range templates {
+			if relevant(template, token) {
+				recommendation.Templates = append(recommendation.Templates, template.ID)
+				recommendation.Paths = append(recommendation.Paths, template.Path)
+			}
+		}
+
+		if len(recommendation.Templates) > 0 {
+			recommendations = append(recommendations, recommendation)
+		}
+	}
+
+	return recommendations
+}
+
+// relevant returns true if a template is relevant to a technology
+func relevant(template *Template, technology string) bool {
+	for _, tag := range strings.Split(template.Tags, ",") {
+		if normalize(tag) == technology {
+			return true
+		}
+	}
+
+	for _, part := range strings.Split(strings.ToLower(template.ID), "-") {
+		if part == technology {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalize returns the token of a technology or a tag
+func normalize(value string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "-")
+}
+
+// finding contains the fields of the json results the technologies are
+// read from
+type finding struct {
+	Matched     string `json:"matched"`
+	MatcherName string `json:"matcher_name"`
+	Fingerprint *struct {
+		Product string `json:"product"`
+	} `json:"fingerprint"`
+}
+
+// FromResults reads the technologies detected on the hosts from the json
+// results of a previous scan, the products of the fingerprints and the
+// names of the matchers of the technology detection templates
+func FromResults(reader io.Reader) (map[string][]string, error) {
+	detected := make(map[string]map[string]struct{})
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var result finding
+		if err := jsoniter.Unmarshal(scanner.Bytes(), &result); err != nil {
+			continue
+		}
+
+		host := result.Matched
+		if parsed, err := url.Parse(result.Matched); err == nil && parsed.Host != "" {
+			host = parsed.Host
+		}
+
+		if detected[host] == nil {
+			detected[host] = make(map[string]struct{})
+		}
+
+		// the products named after their vendor are detected by word too
+		if result.Fingerprint != nil && result.Fingerprint.Product != "" {
+			product := normalize(result.Fingerprint.Product)
+			detected[host][product] = struct{}{}
+
+			for _, word := range strings.Split(product, "-") {
+				detected[host][word] = struct{}{}
+			}
+		}
+
+		if result.MatcherName != "" {
+			detected[host][normalize(result.MatcherName)] = struct{}{}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	technologies := make(map[string][]string, len(detected))
+	for host, names := range detected {
+		for name := range names {
+			technologies[host] = append(technologies[host], name)
+		}
+		sort.Strings(technologies[host])
+	}
+
+	return technologies, nil
+}
@@ -0,0 +1,3 @@
+// Package report collects the findings of a scan with their evidences and
+// renders them as a html or pdf report for the stakeholders.
+package report
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// chartBarHeight and chartWidth are the dimensions of the severity chart
+const (
+	chartBarHeight = 28
+	chartWidth     = 400
+)
+
+// severities are the known severities from the most to the least critical
+var severities = []string{"critical", "high", "medium", "low", "info", "unknown"}
+
+// severityColors are the colors of the severities in the report
+var severityColors = map[string]string{
+	"critical": "#d00000",
+	"high":     "#e8590c",
+	"medium":   "#f2c94c",
+	"low":      "#2f80ed",
+	"info":     "#828282",
+	"unknown":  "#bdbdbd",
+}
+
+// page contains the data rendered by the report template
+type page struct {
+	Generated string
+	Labels    string
+	Stats     *summary.Report
+	Hosts     int
+	Bars      []bar
+	Height    int
+	Findings  []*Finding
+}
+
+// bar is a severity of the chart
+type bar struct {
+	Severity string
+	Color    template.CSS
+	Count    int
+	Y        int
+	Width    int
+}
+
+func newPage(findings []*Finding, stats *summary.Report) *page {
+	p := &page{
+		Generated: time.Now().Format(time.RFC1123),
+		Stats:     stats,
+		Hosts:     len(stats.Hosts),
+		Findings:  append([]*Finding{}, findings...),
+	}
+
+	ranks := make(map[string]int, len(severities))
+	for i, severity := range severities {
+		ranks[severity] = i
+	}
+
+	// the most severe findings first
+	sort.SliceStable(p.Findings, func(i, j int) bool {
+		if p.Findings[i].Severity != p.Findings[j].Severity {
+			return rank(ranks, p.Findings[i].Severity) < rank(ranks, p.Findings[j].Severity)
+		}
+
+		return p.Findings[i].Template < p.Findings[j].Template
+	})
+
+	var max int
+	for _, count := range stats.Severities {
+		if count > max {
+			max = count
+		}
+	}
+
+	for _, severity := range severities {
+		count, ok := stats.Severities[severity]
+		if !ok {
+			continue
+		}
+
+		p.Bars = append(p.Bars, bar{
+			Severity: severity,
+			Color:    template.CSS(severityColors[severity]),
+			Count:    count,
+			Y:        len(p.Bars) * chartBarHeight,
+			Width:    count * chartWidth / max,
+		})
+	}
+
+	p.Height = len(p.Bars) * chartBarHeight
+
+	return p
+}
+
+// rank returns the rank of a severity, the unknown ones being last
+func rank(ranks map[string]int, severity string) int {
+	if r, ok := ranks[severity]; ok {
+		return r
+	}
+
+	return len(ranks)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"color": func(severity string) template.CSS {
+		if color, ok := severityColors[severity]; ok {
+			return template.CSS(color)
+		}
+
+		return template.CSS(severityColors["unknown"])
+	},
+	"join": func(values []string) string {
+		return strings.Join(values, ", ")
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Nuclei scan report</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; color: #222; margin: 40px; }
+h1 { margin-bottom: 0; }
+.generated { color: #888; margin-top: 4px; }
+.stats { display: flex; gap: 16px; margin: 24px 0; }
+.stat { border: 1px solid #ddd; border-radius: 6px; padding: 12px 20px; }
+.stat b { display: block; font-size: 24px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eee; vertical-align: top; }
+.severity { color: #fff; border-radius: 4px; padding: 2px 6px; font-size: 12px; text-transform: uppercase; }
+.finding { border: 1px solid #ddd; border-radius: 6px; padding: 12px 16px; margin: 16px 0; page-break-inside: avoid; }
+pre { background: #f6f6f6; padding: 8px; overflow-x: auto; white-space: pre-wrap; word-break: break-all; font-size: 12px; }
+</style>
+</head>
+<body>
+<h1>Nuclei scan report</h1>
+<p class="generated">Generated on {{.Generated}}{{if .Labels}} for {{.Labels}}{{end}}</p>
+
+<h2>Executive summary</h2>
+<div class="stats">
+<div class="stat"><b>{{.Stats.Findings}}</b>findings</div>
+<div class="stat"><b>{{.Hosts}}</b>affected hosts</div>
+<div class="stat"><b>{{.Stats.Requests}}</b>requests</div>
+<div class="stat"><b>{{.Stats.Duration}}</b>duration</div>
+</div>
+{{if .Bars}}<svg width="560" height="{{.Height}}">
+{{range .Bars}}<text x="0" y="{{.Y}}" dy="19" font-size="14">{{.Severity}}</text>
+<rect x="80" y="{{.Y}}" width="{{.Width}}" height="22" fill="{{.Color}}"></rect>
+<text x="{{.Width}}" y="{{.Y}}" dx="88" dy="16" font-size="13">{{.Count}}</text>
+{{end}}</svg>{{else}}<p>No findings were found.</p>{{end}}
+
+{{if .Findings}}<h2>Findings</h2>
+<table>
+<tr><th>Severity</th><th>Template</th><th>Matched</th></tr>
+{{range .Findings}}<tr><td><span class="severity" style="background: {{color .Severity}}">{{.Severity}}</span></td><td>{{.Template}}</td><td>{{.Matched}}</td></tr>
+{{end}}</table>
+
+<h2>Details</h2>
+{{range .Findings}}<div class="finding">
+<h3><span class="severity" style="background: {{color .Severity}}">{{.Severity}}</span> {{.Name}}</h3>
+<p><b>Template:</b> {{.Template}} ({{.Type}}){{if .MatcherName}}<br><b>Matcher:</b> {{.MatcherName}}{{end}}<br><b>Matched:</b> {{.Matched}}</p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{with .Classification}}<p>{{if .CVEID}}<b>CVE:</b> {{join .CVEID}}<br>{{end}}{{if .CWEID}}<b>CWE:</b> {{join .CWEID}}<br>{{end}}{{if .CVSSScore}}<b>CVSS:</b> {{.CVSSScore}}{{if .CVSSMetrics}} ({{.CVSSMetrics}}){{end}}<br>{{end}}{{if .EPSS}}<b>EPSS:</b> {{.EPSS}}{{end}}</p>{{end}}
+{{if .ExtractedResults}}<p><b>Extracted:</b></p><pre>{{range .ExtractedResults}}{{.}}
+{{end}}</pre>{{end}}
+{{if .Request}}<p><b>Request:</b></p><pre>{{.Request}}</pre>{{end}}
+{{if .Response}}<p><b>Response:</b></p><pre>{{.Response}}</pre>{{end}}
+</div>
+{{end}}{{end}}
+</body>
+</html>
+`))
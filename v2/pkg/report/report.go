@@ -0,0 +1,133 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+)
+
+const (
+	// maxEvidenceSize bounds the size of each request and response
+	// kept in the report
+	maxEvidenceSize = 16 * 1024
+	// printTimeout is the maximum time to print the report as pdf
+	printTimeout = 2 * time.Minute
+)
+
+// Finding is a result of the scan with its evidences
+type Finding struct {
+	Template         string
+	Name             string
+	Severity         string
+	Description      string
+	Type             string
+	Matched          string
+	MatcherName      string
+	ExtractedResults []string
+	Request          string
+	Response         string
+	// Classification is the vulnerability classification of the template, if any
+	Classification *templates.Classification
+}
+
+// Report collects the findings of a scan
+type Report struct {
+	labels map[string]string
+
+	mutex    sync.Mutex
+	findings []*Finding
+}
+
+// New creates a new empty report of a scan with optional labels
+func New(labels map[string]string) *Report {
+	return &Report{labels: labels}
+}
+
+// Add records a finding, truncating its evidences
+func (r *Report) Add(finding *Finding) {
+	if r == nil {
+		return
+	}
+
+	if finding.Severity == "" {
+		finding.Severity = "unknown"
+	}
+
+	finding.Request = truncate(finding.Request)
+	finding.Response = truncate(finding.Response)
+
+	r.mutex.Lock()
+	r.findings = append(r.findings, finding)
+	r.mutex.Unlock()
+}
+
+// Findings returns the findings recorded
+func (r *Report) Findings() []*Finding {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*Finding(nil), r.findings...)
+}
+
+// WriteHTML renders the report along with the statistics of the scan
+// as a standalone html file
+func (r *Report) WriteHTML(file string, stats *summary.Report) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	page := newPage(r.findings, stats)
+	page.Labels = summary.FormatLabels(r.labels)
+
+	return reportTemplate.Execute(f, page)
+}
+
+// WritePDF prints a html report as pdf with a headless chromium browser
+func WritePDF(htmlFile, pdfFile, browser string) error {
+	source, err := filepath.Abs(htmlFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), printTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, browser,
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--print-to-pdf="+pdfFile,
+		"file://"+filepath.ToSlash(source),
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, output)
+	}
+
+	return nil
+}
+
+// truncate bounds the size of an evidence
+func truncate(evidence string) string {
+	if len(evidence) <= maxEvidenceSize {
+		return evidence
+	}
+
+	return evidence[:maxEvidenceSize] + "\n[truncated]"
+}
@@ -0,0 +1,57 @@
+package requests
+
+import (
+	"net/url"
+	"strings"
+)
+
+// AuditRequest contains the security header audit of a template
+type AuditRequest struct {
+	// Paths contains the paths audited on every target, / by default
+	Paths []string `yaml:"path,omitempty"`
+	// Profile is the policy profile of the audit (baseline or strict)
+	Profile string `yaml:"profile,omitempty"`
+	// Checks contains the checks added to the profile
+	Checks []string `yaml:"checks,omitempty"`
+	// Exclude contains the checks of the profile which are skipped
+	Exclude []string `yaml:"exclude,omitempty"`
+	// HSTSMaxAge is the minimum max-age in seconds of the
+	// Strict-Transport-Security headers, one year by default
+	HSTSMaxAge int `yaml:"hsts-max-age,omitempty"`
+	// ReportPassed reports the passed checks along with the failed ones
+	ReportPassed bool `yaml:"report-passed,omitempty"`
+	// Timeout is the timeout in seconds of the http requests
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
+// GetURLs returns the urls audited for a target
+func (r *AuditRequest) GetURLs(target string) []string {
+	paths := r.Paths
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	base := strings.TrimSuffix(target, "/")
+	if parsed, err := url.Parse(target); err == nil && parsed.Host != "" {
+		base = parsed.Scheme + "://" + parsed.Host
+	}
+
+	urls := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		urls = append(urls, base+path)
+	}
+
+	return urls
+}
+
+// GetRequestCount returns the total number of requests the YAML rule will perform
+func (r *AuditRequest) GetRequestCount() int64 {
+	if len(r.Paths) == 0 {
+		return 1
+	}
+
+	return int64(len(r.Paths))
+}
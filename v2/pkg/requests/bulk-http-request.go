@@ -14,6 +14,8 @@ import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
 	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/oob"
+	"github.com/projectdiscovery/nuclei/v2/pkg/useragent"
 	"github.com/projectdiscovery/rawhttp"
 	retryablehttp "github.com/projectdiscovery/retryablehttp-go"
 )
@@ -23,6 +25,22 @@ const (
 	three = 3
 )
 
+// oobPlaceholder starts the placeholders of the out-of-band hosts,
+// {{oob-host}} and {{oob-url}}
+const oobPlaceholder = "{{oob-"
+
+// canaryPlaceholder starts the placeholders of the canary hosts,
+// {{canary-host}} and {{canary-url}}
+const canaryPlaceholder = "{{canary-"
+
+// rebindPlaceholder starts the placeholders of the rebinding hosts,
+// {{rebind-host}} and {{rebind-url}}
+const rebindPlaceholder = "{{rebind-"
+
+// rebindIDValue is the value keeping the id of the rebinding host of a
+// target between its requests
+const rebindIDValue = "rebind-id"
+
 var urlWithPortRgx = regexp.MustCompile(`{{BaseURL}}:(\d+)`)
 
 // BulkHTTPRequest contains a request to be made from a template
@@ -39,6 +57,10 @@ type BulkHTTPRequest struct {
 	attackType generators.Type
 	// Path contains the path/s for the request variables
 	Payloads map[string]interface{} `yaml:"payloads,omitempty"`
+	// PayloadEncoders contains the encoders applied in order to each value of a payload
+	PayloadEncoders map[string][]string `yaml:"payload-encoders,omitempty"`
+	// encoders contains the compiled payload encoders
+	encoders map[string][]*generators.Encoder
 	// Method is the request method, whether GET, POST, PUT, etc
 	Method string `yaml:"method"`
 	// Path contains the path/s for the request
@@ -53,6 +75,8 @@ type BulkHTTPRequest struct {
 	// MatchersCondition is the condition of the matchers
 	// whether to use AND or OR. Default is OR.
 	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+	// MatchersThreshold is the minimum ratio of matched weights for the weighted condition
+	MatchersThreshold float64 `yaml:"matchers-threshold,omitempty"`
 	// matchersCondition is internal condition for the matchers.
 	matchersCondition matchers.ConditionType
 	// Extractors contains the extraction mechanism for the request to identify
@@ -69,13 +93,75 @@ type BulkHTTPRequest struct {
 	PipelineMaxWorkers     int  `yaml:"pipeline-max-workers,omitempty"`
 	// Specify in order to skip request RFC normalization
 	Unsafe bool `yaml:"unsafe,omitempty"`
+	// Connection controls the connections used by the requests (close, keep-alive
+	// or reuse-previous to send all the requests to a host on a single connection)
+	Connection string `yaml:"connection,omitempty"`
+	// UnsafeExact sends raw requests byte for byte as written in the template, without
+	// line endings rewriting or header normalization (eg. request smuggling)
+	UnsafeExact bool `yaml:"unsafe-exact,omitempty"`
 	// DisableAutoHostname Enable/Disable Host header for unsafe raw requests
 	DisableAutoHostname bool `yaml:"disable-automatic-host-header,omitempty"`
 	// DisableAutoContentLength Enable/Disable Content-Length header for unsafe raw requests
 	DisableAutoContentLength bool `yaml:"disable-automatic-content-length-header,omitempty"`
 	Threads                  int  `yaml:"threads,omitempty"`
 	RateLimit                int  `yaml:"rate-limit,omitempty"`
-
+	// StopAtFirstMatch stops sending the payloads of this request to a host as soon as
+	// one of them matches (eg. default credentials bruteforce)
+	StopAtFirstMatch bool `yaml:"stop-at-first-match,omitempty"`
+	// AttackDelay is the optional delay in milliseconds between two payload requests
+	// to the same host, used to avoid account lockouts
+	AttackDelay int `yaml:"attack-delay,omitempty"`
+	// Signature optionally signs the requests for authenticated cloud provider apis
+	Signature *Signature `yaml:"signature,omitempty"`
+	// TLSVerify refuses the hosts with invalid certificate chains, whatever the
+	// verification of the scan
+	TLSVerify bool `yaml:"tls-verify,omitempty"`
+	// TLSCABundle is a PEM file of roots trusted in addition to the system ones,
+	// relative to the template directory
+	TLSCABundle string `yaml:"tls-ca-bundle,omitempty"`
+	// TLSMinVersion is the minimum tls version negotiated with the hosts (tls1.0 to tls1.3)
+	TLSMinVersion string `yaml:"tls-min-version,omitempty"`
+	// TLSMaxVersion is the maximum tls version negotiated with the hosts (tls1.0 to tls1.3)
+	TLSMaxVersion string `yaml:"tls-max-version,omitempty"`
+	// TLSCipherSuites are the IANA names of the cipher suites offered up to tls1.2
+	TLSCipherSuites []string `yaml:"tls-cipher-suites,omitempty"`
+	// HTTP3 sends the requests over quic, with nuclei built with the http3 tag
+	HTTP3 bool `yaml:"http3,omitempty"`
+	// BodyEncoding compresses the request bodies with gzip or deflate,
+	// setting their Content-Encoding header
+	BodyEncoding string `yaml:"body-encoding,omitempty"`
+	// ChunkSize sends the bodies of the unsafe raw requests with the chunked
+	// transfer encoding in chunks of this size
+	ChunkSize int `yaml:"chunk-size,omitempty"`
+	// ChunkExtension is appended to the sizes of the chunks, like ;name=value
+	ChunkExtension string `yaml:"chunk-extension,omitempty"`
+	// Trailers contains the trailer headers sent after the last chunk
+	Trailers map[string]string `yaml:"trailers,omitempty"`
+	// UserAgent overrides the strategy picking the user agent of the
+	// requests (fixed, random, browser or none)
+	UserAgent string `yaml:"user-agent,omitempty"`
+	// Hooks contains the dsl expressions run before the requests and after
+	// the responses
+	Hooks *Hooks `yaml:"hooks,omitempty"`
+	// CacheBuster is the query parameter added to the requests with a
+	// random value, keeping them apart from the responses cached for the
+	// other clients
+	CacheBuster string `yaml:"cache-buster,omitempty"`
+	// CacheVerify follows each request by a request of its url without its
+	// headers and body, matched by the matchers of the verification part to
+	// check if the response to the request was cached
+	CacheVerify bool `yaml:"cache-verify,omitempty"`
+	// Injection iterates a payload across the query parameters, the path
+	// segments and the headers of the requests
+	Injection *Injection `yaml:"injection,omitempty"`
+	// Rebind is the internal address the rebinding host of the requests
+	// resolves to once the target has resolved it to the callback server
+	Rebind string `yaml:"rebind,omitempty"`
+
+	// userAgents picks the user agents of the requests
+	userAgents *useragent.Picker
+	// oob generates the hosts of the out-of-band payloads
+	oob *oob.Client
 	// Internal Finite State Machine keeping track of scan process
 	gsfm *GeneratorFSM
 }
@@ -100,6 +186,11 @@ func (r *BulkHTTPRequest) SetAttackType(attack generators.Type) {
 	r.attackType = attack
 }
 
+// SetPayloadEncoders sets the compiled payload encoders
+func (r *BulkHTTPRequest) SetPayloadEncoders(encoders map[string][]*generators.Encoder) {
+	r.encoders = encoders
+}
+
 // GetRequestCount returns the total number of requests the YAML rule will perform
 func (r *BulkHTTPRequest) GetRequestCount() int64 {
 	return int64(len(r.Raw) | len(r.Path))
@@ -119,12 +210,75 @@ func (r *BulkHTTPRequest) MakeHTTPRequest(baseURL string, dynamicValues map[stri
 		"Hostname": hostname,
 	})
 
+	// every request sends its own out-of-band host to correlate the
+	// interactions received for it
+	var oobID, oobHost string
+	if r.oob != nil && r.usesPlaceholder(data, oobPlaceholder) {
+		oobID, oobHost = r.oob.Host()
+		values["oob-host"] = oobHost
+		values["oob-url"] = "http://" + oobHost
+	}
+
+	// the canary host is the out-of-band host of the request, the redirects
+	// to it being reported instead of followed
+	var canaryHost string
+	if r.usesPlaceholder(data, canaryPlaceholder) {
+		canaryHost = oobHost
+		if canaryHost == "" {
+			oobID, canaryHost = r.oob.CanaryHost()
+		}
+		values["canary-host"] = canaryHost
+		values["canary-url"] = "http://" + canaryHost + "/"
+	}
+
+	// the rebinding host is shared by the requests of a target, to make it
+	// resolve the host to the callback server then to the internal address
+	if r.oob != nil && r.Rebind != "" && r.usesPlaceholder(data, rebindPlaceholder) {
+		rebindID, _ := dynamicValues[rebindIDValue].(string)
+		rebindID, rebindHost, err := r.oob.RebindHost(rebindID, net.ParseIP(r.Rebind))
+		if err != nil {
+			return nil, err
+		}
+
+		dynamicValues[rebindIDValue] = rebindID
+		values["rebind-host"] = rebindHost
+		values["rebind-url"] = "http://" + rebindHost
+
+		if oobID == "" {
+			oobID = rebindID
+		}
+	}
+
+	var buster string
+	if r.CacheBuster != "" {
+		buster = newCacheBuster()
+		values[cacheBusterValue] = buster
+	}
+
+	var request *HTTPRequest
+
 	// if data contains \n it's a raw request
 	if strings.Contains(data, "\n") {
-		return r.makeHTTPRequestFromRaw(baseURL, data, values)
+		request, err = r.makeHTTPRequestFromRaw(baseURL, data, values)
+	} else {
+		request, err = r.makeHTTPRequestFromModel(data, values)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	return r.makeHTTPRequestFromModel(data, values)
+	request.ReuseConnection = r.Connection == ConnectionReusePrevious
+	request.Index = r.Position(baseURL)
+	request.OOBID = oobID
+	request.values = values
+
+	if buster != "" {
+		r.addCacheBuster(request, buster)
+	}
+	request.CanaryHost = canaryHost
+
+	return request, nil
 }
 
 // MakeHTTPRequestFromModel creates a *http.Request from a request template
@@ -148,7 +302,42 @@ func (r *BulkHTTPRequest) makeHTTPRequestFromModel(data string, values map[strin
 
 // InitGenerator initializes the generator
 func (r *BulkHTTPRequest) InitGenerator() {
-	r.gsfm = NewGeneratorFSM(r.attackType, r.Payloads, r.Path, r.Raw)
+	r.gsfm = NewGeneratorFSM(r.attackType, r.Payloads, r.encoders, r.Path, r.Raw)
+}
+
+// SetPayloadSampling bounds the payload permutations sent to each target
+func (r *BulkHTTPRequest) SetPayloadSampling(sampling *generators.Sampling) {
+	r.gsfm.SetSampling(sampling)
+}
+
+// SetOOB sets the client generating the hosts of the out-of-band payloads
+func (r *BulkHTTPRequest) SetOOB(client *oob.Client) {
+	r.oob = client
+}
+
+// usesPlaceholder returns true if a request of the template sends a
+// placeholder, like the out-of-band payloads
+func (r *BulkHTTPRequest) usesPlaceholder(data, placeholder string) bool {
+	if strings.Contains(data, placeholder) || strings.Contains(r.Body, placeholder) {
+		return true
+	}
+
+	if r.Injection != nil && strings.Contains(r.Injection.Payload, placeholder) {
+		return true
+	}
+
+	for _, value := range r.Headers {
+		if strings.Contains(value, placeholder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetUserAgents sets the picker of the user agents of the requests
+func (r *BulkHTTPRequest) SetUserAgents(userAgents *useragent.Picker) {
+	r.userAgents = userAgents
 }
 
 // CreateGenerator creates the generator
@@ -215,6 +404,11 @@ func (r *BulkHTTPRequest) handleRawWithPaylods(raw, baseURL string, values, genV
 	dynamicReplacer := newReplacer(dynamicValues)
 	raw = dynamicReplacer.Replace(raw)
 
+	// exact raw requests are not parsed, as they can be malformed on purpose
+	if r.UnsafeExact {
+		return &HTTPRequest{RawRequest: &RawRequest{FullURL: baseURL, Raw: raw}, Meta: genValues, Unsafe: true, Exact: true}, nil
+	}
+
 	rawRequest, err := r.parseRawRequest(raw, baseURL)
 	if err != nil {
 		return nil, err
@@ -222,7 +416,20 @@ func (r *BulkHTTPRequest) handleRawWithPaylods(raw, baseURL string, values, genV
 
 	// rawhttp
 	if r.Unsafe {
-		return &HTTPRequest{RawRequest: rawRequest, Meta: genValues, AutomaticHostHeader: !r.DisableAutoHostname, AutomaticContentLengthHeader: !r.DisableAutoContentLength, Unsafe: true}, nil
+		if err := r.runRawPreRequest(rawRequest, finValues); err != nil {
+			return nil, err
+		}
+
+		if err := r.encodeRawBody(rawRequest); err != nil {
+			return nil, err
+		}
+
+		if _, ok := rawRequest.Headers["Connection"]; !ok && (r.Connection == ConnectionClose || r.Connection == ConnectionKeepAlive) {
+			rawRequest.Headers["Connection"] = " " + r.Connection
+		}
+
+		// the length of the chunked bodies is given by their chunks
+		return &HTTPRequest{RawRequest: rawRequest, Meta: genValues, AutomaticHostHeader: !r.DisableAutoHostname, AutomaticContentLengthHeader: !r.DisableAutoContentLength && !r.chunked(), Unsafe: true}, nil
 	}
 
 	// retryablehttp
@@ -246,7 +453,10 @@ func (r *BulkHTTPRequest) handleRawWithPaylods(raw, baseURL string, values, genV
 
 func (r *BulkHTTPRequest) fillRequest(req *http.Request, values map[string]interface{}) (*retryablehttp.Request, error) {
 	// In case of multiple threads the underlying connection should remain open to allow reuse
-	if r.Threads <= 0 {
+	switch {
+	case r.Connection == ConnectionKeepAlive || r.Connection == ConnectionReusePrevious:
+		setHeader(req, "Connection", "keep-alive")
+	case r.Threads <= 0 || r.Connection == ConnectionClose:
 		setHeader(req, "Connection", "close")
 		req.Close = true
 	}
@@ -270,27 +480,59 @@ func (r *BulkHTTPRequest) fillRequest(req *http.Request, values map[string]inter
 		req.Close = true
 	}
 
-	setHeader(req, "User-Agent", "Nuclei - Open-source project (github.com/projectdiscovery/nuclei)")
+	r.setUserAgent(req)
 
 	// raw requests are left untouched
-	if len(r.Raw) > 0 {
-		return retryablehttp.FromRequest(req)
+	if len(r.Raw) == 0 {
+		setHeader(req, "Accept", "*/*")
+		setHeader(req, "Accept-Language", "en")
 	}
 
-	setHeader(req, "Accept", "*/*")
-	setHeader(req, "Accept-Language", "en")
+	if err := r.runHTTPPreRequest(req, values); err != nil {
+		return nil, err
+	}
+
+	if err := r.compressRequestBody(req); err != nil {
+		return nil, fmt.Errorf("could not compress request body: %s", err)
+	}
+
+	// the signature covers the final request
+	if r.Signature != nil {
+		if err := r.Signature.sign(req); err != nil {
+			return nil, fmt.Errorf("could not sign request: %s", err)
+		}
+	}
 
 	return retryablehttp.FromRequest(req)
 }
 
+const (
+	// ConnectionClose forces a new connection for every request
+	ConnectionClose = "close"
+	// ConnectionKeepAlive allows the connections to be reused
+	ConnectionKeepAlive = "keep-alive"
+	// ConnectionReusePrevious sends the requests to a host on the connection of the previous one
+	ConnectionReusePrevious = "reuse-previous"
+)
+
 // HTTPRequest is the basic HTTP request
 type HTTPRequest struct {
 	Request    *retryablehttp.Request
 	RawRequest *RawRequest
 	Meta       map[string]interface{}
+	// Index is the position of the request among the requests of the template
+	Index int
+	// OOBID correlates the out-of-band interactions of the request
+	OOBID string
+	// CanaryHost is the host of the canary urls of the request
+	CanaryHost string
+	// values are the values of the placeholders of the request
+	values map[string]interface{}
 
 	// flags
 	Unsafe                       bool
+	Exact                        bool
+	ReuseConnection              bool
 	Pipeline                     bool
 	AutomaticHostHeader          bool
 	AutomaticContentLengthHeader bool
@@ -300,6 +542,26 @@ type HTTPRequest struct {
 	PipelineClient               *rawhttp.PipelineClient
 }
 
+// setUserAgent sets the user agent picked for a request, and the headers
+// of its browser profile, unless the template sets it
+func (r *BulkHTTPRequest) setUserAgent(req *http.Request) {
+	if _, ok := req.Header["User-Agent"]; ok {
+		return
+	}
+
+	profile := r.userAgents.Pick(r.UserAgent, req.URL.Hostname())
+	if profile == nil {
+		// net/http sends its own user agent unless it is empty
+		req.Header.Set("User-Agent", "")
+		return
+	}
+
+	req.Header.Set("User-Agent", profile.UserAgent)
+	for name, value := range profile.Headers {
+		setHeader(req, name, value)
+	}
+}
+
 func setHeader(req *http.Request, name, value string) {
 	// Set some headers only if the header wasn't supplied by the user
 	if _, ok := req.Header[name]; !ok {
@@ -341,6 +603,10 @@ type RawRequest struct {
 	Path    string
 	Data    string
 	Headers map[string]string
+	// Raw contains the exact bytes of the request in unsafe exact mode
+	Raw string
+	// BodyEncoded reports a compressed or chunked body, sent as is
+	BodyEncoded bool
 }
 
 // parseRawRequest parses the raw request as supplied by the user
@@ -0,0 +1,49 @@
+package requests
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// cacheBusterValue is the value of the random cache buster of a request
+const cacheBusterValue = "cache-buster"
+
+// newCacheBuster returns a new random value of a cache buster
+func newCacheBuster() string {
+	data := make([]byte, 6)
+	_, _ = rand.Read(data)
+
+	return hex.EncodeToString(data)
+}
+
+// addCacheBuster adds the cache buster parameter to the query of a request
+func (r *BulkHTTPRequest) addCacheBuster(request *HTTPRequest, buster string) {
+	parameter := r.CacheBuster + "=" + buster
+
+	if request.RawRequest != nil {
+		path := appendQuery(request.RawRequest.Path, parameter)
+		request.RawRequest.FullURL = strings.TrimSuffix(request.RawRequest.FullURL, request.RawRequest.Path) + path
+		request.RawRequest.Path = path
+
+		return
+	}
+
+	if request.Request.URL.RawQuery == "" {
+		request.Request.URL.RawQuery = parameter
+	} else {
+		request.Request.URL.RawQuery += "&" + parameter
+	}
+}
+
+// appendQuery appends a parameter to the query of a path
+func appendQuery(path, parameter string) string {
+	switch {
+	case !strings.Contains(path, "?"):
+		return path + "?" + parameter
+	case strings.HasSuffix(path, "?") || strings.HasSuffix(path, "&"):
+		return path + parameter
+	default:
+		return path + "&" + parameter
+	}
+}
@@ -0,0 +1,71 @@
+package requests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// BodyEncodings contains the encodings the request bodies can be compressed with
+var BodyEncodings = map[string]bool{"gzip": true, "deflate": true}
+
+// compressBody compresses a request body with an encoding
+func compressBody(encoding string, body []byte) ([]byte, error) {
+	var (
+		buffer bytes.Buffer
+		writer io.WriteCloser
+	)
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	case "deflate":
+		// deflate is the zlib format in http, not raw deflate
+		writer = zlib.NewWriter(&buffer)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %s", encoding)
+	}
+
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// compressRequestBody compresses the body of a request, setting its
+// content encoding unless given by the template
+func (r *BulkHTTPRequest) compressRequestBody(req *http.Request) error {
+	if r.BodyEncoding == "" || req.Body == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	compressed, err := compressBody(r.BodyEncoding, body)
+	if err != nil {
+		return err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(compressed))
+	req.ContentLength = int64(len(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(compressed)), nil
+	}
+
+	setHeader(req, "Content-Encoding", r.BodyEncoding)
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package requests
 
 import (
+	"math/rand"
 	"strings"
 
 	"github.com/miekg/dns"
@@ -18,6 +19,9 @@ type DNSRequest struct {
 	Retries int    `yaml:"retries"`
 	// Raw contains a raw request
 	Raw string `yaml:"raw,omitempty"`
+	// Wildcard probes a random label next to the queried name and exposes
+	// whether the zone answers wildcard queries to the matchers.
+	Wildcard bool `yaml:"wildcard,omitempty"`
 
 	// Matchers contains the detection mechanism for the request to identify
 	// whether the request was successful
@@ -27,6 +31,8 @@ type DNSRequest struct {
 	// MatchersCondition is the condition of the matchers
 	// whether to use AND or OR. Default is OR.
 	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+	// MatchersThreshold is the minimum ratio of matched weights for the weighted condition
+	MatchersThreshold float64 `yaml:"matchers-threshold,omitempty"`
 	// Extractors contains the extraction mechanism for the request to identify
 	// and extract parts of the response.
 	Extractors []*extractors.Extractor `yaml:"extractors,omitempty"`
@@ -47,6 +53,11 @@ func (r *DNSRequest) GetRequestCount() int64 {
 	return 1
 }
 
+// IsZoneTransfer returns true if the request attempts an AXFR zone transfer
+func (r *DNSRequest) IsZoneTransfer() bool {
+	return toQType(r.Type) == dns.TypeAXFR
+}
+
 // MakeDNSRequest creates a *dns.Request from a request template
 func (r *DNSRequest) MakeDNSRequest(domain string) (*dns.Msg, error) {
 	domain = dns.Fqdn(domain)
@@ -89,6 +100,8 @@ func toQType(ttype string) (rtype uint16) {
 		rtype = dns.TypeTXT
 	case "AAAA":
 		rtype = dns.TypeAAAA
+	case "AXFR":
+		rtype = dns.TypeAXFR
 	default:
 		rtype = dns.TypeA
 	}
@@ -96,6 +109,31 @@ func toQType(ttype string) (rtype uint16) {
 	return
 }
 
+// WildcardName returns a random name in the same zone as the given name
+// which is used to detect wildcard records. The first label of the name is
+// replaced unless the name is a registrable domain or a TLD.
+func WildcardName(name string) string {
+	name = dns.Fqdn(name)
+
+	label := randomLabel()
+	if labels := strings.Split(strings.TrimSuffix(name, "."), "."); len(labels) > 2 {
+		return label + "." + strings.Join(labels[1:], ".") + "."
+	}
+
+	return label + "." + name
+}
+
+func randomLabel() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	label := make([]byte, 16)
+	for i := range label {
+		label[i] = letters[rand.Intn(len(letters))]
+	}
+
+	return string(label)
+}
+
 func toQClass(tclass string) (rclass uint16) {
 	tclass = strings.TrimSpace(strings.ToUpper(tclass))
 
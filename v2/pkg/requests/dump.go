@@ -13,5 +13,9 @@ func Dump(req *HTTPRequest, reqURL string) ([]byte, error) {
 		return httputil.DumpRequest(req.Request.Request, true)
 	}
 
+	if req.Exact {
+		return []byte(req.RawRequest.Raw), nil
+	}
+
 	return rawhttp.DumpRequestRaw(req.RawRequest.Method, reqURL, req.RawRequest.Path, ExpandMapValues(req.RawRequest.Headers), ioutil.NopCloser(strings.NewReader(req.RawRequest.Data)))
 }
@@ -29,23 +29,36 @@ type GeneratorFSM struct {
 	sync.RWMutex
 	payloads     map[string]interface{}
 	basePayloads map[string][]string
-	generator    func(payloads map[string][]string) (out chan map[string]interface{})
+	encoders     map[string][]*generators.Encoder
+	hostPayloads map[string]string
+	sampling     *generators.Sampling
+	generator    func(payloads map[string][]string, limit int) (out chan map[string]interface{})
 	Generators   map[string]*Generator
 	Type         generators.Type
 	Paths        []string
 	Raws         []string
 }
 
-func NewGeneratorFSM(typ generators.Type, payloads map[string]interface{}, paths, raws []string) *GeneratorFSM {
+func NewGeneratorFSM(typ generators.Type, payloads map[string]interface{}, encoders map[string][]*generators.Encoder, paths, raws []string) *GeneratorFSM {
 	var gsfm GeneratorFSM
 	gsfm.payloads = payloads
+	gsfm.encoders = encoders
 	gsfm.Paths = paths
 	gsfm.Raws = raws
 
 	if len(gsfm.payloads) > 0 {
 		// load payloads if not already done
 		if gsfm.basePayloads == nil {
-			gsfm.basePayloads = generators.LoadPayloads(gsfm.payloads)
+			gsfm.basePayloads = generators.EncodePayloads(generators.LoadPayloads(gsfm.payloads), gsfm.encoders)
+		}
+
+		// host wordlists are filled for each key
+		gsfm.hostPayloads = make(map[string]string)
+
+		for name, payload := range gsfm.payloads {
+			if wordlist, ok := payload.(string); ok && generators.IsHostWordlist(wordlist) {
+				gsfm.hostPayloads[name] = wordlist
+			}
 		}
 
 		generatorFunc := generators.SniperGenerator
@@ -67,6 +80,15 @@ func NewGeneratorFSM(typ generators.Type, payloads map[string]interface{}, paths
 	return &gsfm
 }
 
+// SetSampling bounds the payload permutations generated for each key
+func (gfsm *GeneratorFSM) SetSampling(sampling *generators.Sampling) {
+	gfsm.Lock()
+	defer gfsm.Unlock()
+
+	gfsm.sampling = sampling
+	gfsm.basePayloads = sampling.Shuffle(gfsm.basePayloads)
+}
+
 func (gfsm *GeneratorFSM) Add(key string) {
 	gfsm.Lock()
 	defer gfsm.Unlock()
@@ -144,7 +166,14 @@ func (gfsm *GeneratorFSM) InitOrSkip(key string) {
 		defer g.Unlock()
 
 		if g.gchan == nil {
-			g.gchan = gfsm.generator(gfsm.basePayloads)
+			payloads := gfsm.payloadsFor(key)
+			if payloads == nil {
+				// nothing was discovered for the key, no payloads to generate
+				g.gchan = make(chan map[string]interface{})
+				close(g.gchan)
+			} else {
+				g.gchan = gfsm.generator(payloads, gfsm.sampling.GetLimit())
+			}
 			g.state = running
 		}
 	}
@@ -162,8 +191,32 @@ func (gfsm *GeneratorFSM) Value(key string) map[string]interface{} {
 	return g.currentGeneratorValue
 }
 
+// payloadsFor returns the payloads of a key with its host wordlists
+// filled, nil if one of them is empty.
+func (gfsm *GeneratorFSM) payloadsFor(key string) map[string][]string {
+	if len(gfsm.hostPayloads) == 0 {
+		return gfsm.basePayloads
+	}
+
+	payloads := make(map[string][]string, len(gfsm.basePayloads)+len(gfsm.hostPayloads))
+	for name, values := range gfsm.basePayloads {
+		payloads[name] = values
+	}
+
+	for name, wordlist := range gfsm.hostPayloads {
+		values := generators.HostWordlist(key, wordlist)
+		if len(values) == 0 {
+			return nil
+		}
+
+		payloads[name] = generators.EncodePayloads(map[string][]string{name: values}, gfsm.encoders)[name]
+	}
+
+	return payloads
+}
+
 func (gfsm *GeneratorFSM) hasPayloads() bool {
-	return len(gfsm.basePayloads) > 0
+	return len(gfsm.basePayloads) > 0 || len(gfsm.hostPayloads) > 0
 }
 
 func (gfsm *GeneratorFSM) Next(key string) bool {
@@ -0,0 +1,238 @@
+package requests
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Knetic/govaluate"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+)
+
+// Hooks contains the dsl expressions run around the requests of a template
+type Hooks struct {
+	// PreRequest contains the hooks run on every request once it is built,
+	// before its body is compressed and it is signed
+	PreRequest []*Hook `yaml:"pre-request,omitempty"`
+	// PostResponse contains the hooks run on every response, setting dynamic
+	// values of the following requests
+	PostResponse []*Hook `yaml:"post-response,omitempty"`
+}
+
+// Hook sets a value to the result of a dsl expression
+type Hook struct {
+	// Set is the name of the value set. The pre-request hooks set a request
+	// header with header.<name> and replace the request body with body.
+	Set string `yaml:"set"`
+	// DSL is the expression evaluated
+	DSL string `yaml:"dsl"`
+
+	compiled *govaluate.EvaluableExpression
+}
+
+// hookHeaderPrefix prefixes the request headers set by the pre-request hooks
+const hookHeaderPrefix = "header."
+
+// hookBody is the request body set by the pre-request hooks
+const hookBody = "body"
+
+// Compile compiles the expressions of the hooks
+func (h *Hooks) Compile() error {
+	if h == nil {
+		return nil
+	}
+
+	for _, hook := range append(append([]*Hook(nil), h.PreRequest...), h.PostResponse...) {
+		if hook.Set == "" || hook.Set == hookHeaderPrefix {
+			return fmt.Errorf("hook without name for %s", hook.DSL)
+		}
+
+		compiled, err := govaluate.NewEvaluableExpressionWithFunctions(hook.DSL, generators.HelperFunctions())
+		if err != nil {
+			return fmt.Errorf("could not compile hook %s: %s", hook.Set, err)
+		}
+		hook.compiled = compiled
+	}
+
+	for _, hook := range h.PostResponse {
+		if strings.HasPrefix(hook.Set, hookHeaderPrefix) || hook.Set == hookBody {
+			return fmt.Errorf("the post-response hook %s can only set a value", hook.Set)
+		}
+	}
+
+	return nil
+}
+
+// evaluate evaluates a hook, returning its result as a string
+func (h *Hook) evaluate(values map[string]interface{}) (string, error) {
+	result, err := h.compiled.Evaluate(values)
+	if err != nil {
+		return "", fmt.Errorf("could not evaluate hook %s: %s", h.Set, err)
+	}
+
+	return fmt.Sprint(result), nil
+}
+
+// hookRequest is the part of a request the pre-request hooks can change
+type hookRequest struct {
+	method  string
+	url     string
+	path    string
+	host    string
+	headers map[string]string
+	body    string
+}
+
+// runPreRequest runs the pre-request hooks on a request, the values of the
+// request being available to the expressions along with the request
+// method, url, path, host and body and its headers named like the
+// response headers of the matchers. It returns the headers to set and
+// the new body, if any.
+func (h *Hooks) runPreRequest(request *hookRequest, values map[string]interface{}) (map[string]string, *string, error) {
+	variables := generators.CopyMap(values)
+	for name, value := range request.headers {
+		variables[strings.ToLower(strings.ReplaceAll(name, "-", "_"))] = value
+	}
+	variables["method"] = request.method
+	variables["url"] = request.url
+	variables["path"] = request.path
+	variables["host"] = request.host
+	variables["body"] = request.body
+
+	headers := make(map[string]string)
+
+	var body *string
+
+	for _, hook := range h.PreRequest {
+		result, err := hook.evaluate(variables)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case strings.HasPrefix(hook.Set, hookHeaderPrefix):
+			headers[strings.TrimPrefix(hook.Set, hookHeaderPrefix)] = result
+		case hook.Set == hookBody:
+			body = &result
+			variables["body"] = result
+		default:
+			variables[hook.Set] = result
+		}
+	}
+
+	return headers, body, nil
+}
+
+// runHTTPPreRequest runs the pre-request hooks on a http request
+func (r *BulkHTTPRequest) runHTTPPreRequest(req *http.Request, values map[string]interface{}) error {
+	if r.Hooks == nil || len(r.Hooks.PreRequest) == 0 {
+		return nil
+	}
+
+	request := &hookRequest{
+		method:  req.Method,
+		url:     req.URL.String(),
+		path:    req.URL.RequestURI(),
+		host:    req.URL.Host,
+		headers: make(map[string]string, len(req.Header)),
+	}
+
+	for name := range req.Header {
+		request.headers[name] = req.Header.Get(name)
+	}
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		request.body = string(data)
+		req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	}
+
+	headers, body, err := r.Hooks.runPreRequest(request, values)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if body != nil {
+		req.Body = ioutil.NopCloser(strings.NewReader(*body))
+		req.ContentLength = int64(len(*body))
+	}
+
+	return nil
+}
+
+// runRawPreRequest runs the pre-request hooks on an unsafe raw request
+func (r *BulkHTTPRequest) runRawPreRequest(rawRequest *RawRequest, values map[string]interface{}) error {
+	if r.Hooks == nil || len(r.Hooks.PreRequest) == 0 {
+		return nil
+	}
+
+	request := &hookRequest{
+		method:  rawRequest.Method,
+		url:     rawRequest.FullURL,
+		path:    rawRequest.Path,
+		headers: make(map[string]string, len(rawRequest.Headers)),
+		body:    rawRequest.Data,
+	}
+
+	for name, value := range rawRequest.Headers {
+		value = strings.TrimSpace(value)
+		if strings.EqualFold(name, "Host") {
+			request.host = value
+		}
+		request.headers[name] = value
+	}
+
+	headers, body, err := r.Hooks.runPreRequest(request, values)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range headers {
+		for key := range rawRequest.Headers {
+			if strings.EqualFold(key, name) {
+				delete(rawRequest.Headers, key)
+			}
+		}
+		rawRequest.Headers[name] = " " + value
+	}
+
+	if body != nil {
+		rawRequest.Data = *body
+	}
+
+	return nil
+}
+
+// RunPostResponse runs the post-response hooks on a response, whose values
+// are those of the dsl matchers, setting the dynamic values of the following
+// requests
+func (r *BulkHTTPRequest) RunPostResponse(resp *http.Response, body, headers string, duration time.Duration, dynamicValues map[string]interface{}) error {
+	if r.Hooks == nil || len(r.Hooks.PostResponse) == 0 {
+		return nil
+	}
+
+	variables := generators.MergeMaps(dynamicValues, matchers.HTTPToMap(resp, body, headers, duration))
+
+	for _, hook := range r.Hooks.PostResponse {
+		result, err := hook.evaluate(variables)
+		if err != nil {
+			return err
+		}
+
+		variables[hook.Set] = result
+		dynamicValues[hook.Set] = result
+	}
+
+	return nil
+}
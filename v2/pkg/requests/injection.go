@@ -0,0 +1,424 @@
+package requests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	retryablehttp "github.com/projectdiscovery/retryablehttp-go"
+)
+
+// The kinds of the injection points of the requests
+const (
+	// InjectionQuery injects the payload in each query parameter
+	InjectionQuery = "query"
+	// InjectionPath injects the payload in each path segment
+	InjectionPath = "path"
+	// InjectionHeaders injects the payload in each header, and the host
+	InjectionHeaders = "headers"
+)
+
+// The modes of the injections
+const (
+	// InjectionAppend appends the payload to the values of the points
+	InjectionAppend = "append"
+	// InjectionReplace replaces the values of the points with the payload
+	InjectionReplace = "replace"
+)
+
+// injectionPointMeta is the metadata of the requests naming their
+// injection point
+const injectionPointMeta = "injection-point"
+
+// skippedInjectionHeaders are the headers framing the requests, never
+// injected
+var skippedInjectionHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+}
+
+// Injection iterates a payload across the injection points of the
+// requests, each request being sent once for each of its points
+type Injection struct {
+	// Payload is the value injected, with the placeholders of the request
+	Payload string `yaml:"payload"`
+	// Points are the kinds of the injection points: query, path or headers
+	Points []string `yaml:"points,omitempty"`
+	// Parameters are the query parameters added to the requests to be
+	// injected, like utm_content
+	Parameters []string `yaml:"parameters,omitempty"`
+	// Headers are the headers added to the requests to be injected, like
+	// X-Forwarded-Host
+	Headers []string `yaml:"headers,omitempty"`
+	// Mode appends the payload to the values of the points or replaces them
+	Mode string `yaml:"mode,omitempty"`
+	// Raw injects the payload in the query and the path as is, without
+	// url encoding it
+	Raw bool `yaml:"raw,omitempty"`
+}
+
+// injectionPoint is a place of a request the payload is injected in
+type injectionPoint struct {
+	kind string
+	// name is the name of the query parameter or the header
+	name string
+	// index is the position of the query parameter or the path segment,
+	// negative for the parameters added to the query
+	index int
+}
+
+// String returns the kind and the name or the position of the point
+func (p injectionPoint) String() string {
+	if p.kind == InjectionPath {
+		return p.kind + ":" + strconv.Itoa(p.index)
+	}
+
+	return p.kind + ":" + p.name
+}
+
+// Validate checks the points and the mode of an injection
+func (i *Injection) Validate() error {
+	if i == nil {
+		return nil
+	}
+
+	if i.Payload == "" {
+		return errors.New("the payload of the injection is required")
+	}
+
+	if len(i.Points) == 0 && len(i.Parameters) == 0 && len(i.Headers) == 0 {
+		return errors.New("the injection has no points")
+	}
+
+	for _, kind := range i.Points {
+		if kind != InjectionQuery && kind != InjectionPath && kind != InjectionHeaders {
+			return fmt.Errorf("unknown injection point %s", kind)
+		}
+	}
+
+	if i.Mode != "" && i.Mode != InjectionAppend && i.Mode != InjectionReplace {
+		return fmt.Errorf("unknown injection mode %s", i.Mode)
+	}
+
+	return nil
+}
+
+// has returns true if a kind of points is injected
+func (i *Injection) has(kind string) bool {
+	for _, point := range i.Points {
+		if point == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// points returns the injection points of a request from its path with the
+// query and the names of its headers
+func (i *Injection) points(path string, headers []string) []injectionPoint {
+	var points []injectionPoint
+
+	pathPart, query, _ := splitQuery(path)
+
+	if i.has(InjectionPath) {
+		for index := range pathSegments(pathPart) {
+			points = append(points, injectionPoint{kind: InjectionPath, index: index})
+		}
+	}
+
+	parameters := make(map[string]int)
+	for index, pair := range queryPairs(query) {
+		name := strings.SplitN(pair, "=", 2)[0]
+		if _, ok := parameters[name]; !ok {
+			parameters[name] = index
+		}
+
+		if i.has(InjectionQuery) {
+			points = append(points, injectionPoint{kind: InjectionQuery, name: name, index: index})
+		}
+	}
+
+	for _, name := range i.Parameters {
+		index, ok := parameters[name]
+		switch {
+		case ok && i.has(InjectionQuery):
+			continue
+		case !ok:
+			index = -1
+		}
+		points = append(points, injectionPoint{kind: InjectionQuery, name: name, index: index})
+	}
+
+	// the headers of the raw requests keep the case of the template
+	present := make(map[string]string)
+	for _, name := range headers {
+		present[http.CanonicalHeaderKey(name)] = name
+
+		if i.has(InjectionHeaders) && !skippedInjectionHeaders[http.CanonicalHeaderKey(name)] {
+			points = append(points, injectionPoint{kind: InjectionHeaders, name: name})
+		}
+	}
+
+	for _, name := range i.Headers {
+		existing, ok := present[http.CanonicalHeaderKey(name)]
+		switch {
+		case ok && i.has(InjectionHeaders):
+			continue
+		case ok:
+			name = existing
+		}
+		points = append(points, injectionPoint{kind: InjectionHeaders, name: name})
+	}
+
+	return points
+}
+
+// value returns the value of a point with the payload
+func (i *Injection) value(current, payload string) string {
+	if i.Mode == InjectionReplace {
+		return payload
+	}
+
+	return current + payload
+}
+
+// injectPath returns the path with the query of a request, with the
+// payload injected in a query parameter or a path segment
+func (i *Injection) injectPath(path string, point injectionPoint, payload string) string {
+	pathPart, query, hasQuery := splitQuery(path)
+
+	switch point.kind {
+	case InjectionPath:
+		if !i.Raw {
+			payload = url.PathEscape(payload)
+		}
+
+		segments := strings.Split(pathPart, "/")
+		index := 0
+		for k, segment := range segments {
+			if segment == "" {
+				continue
+			}
+
+			if index == point.index {
+				segments[k] = i.value(segment, payload)
+				break
+			}
+			index++
+		}
+		pathPart = strings.Join(segments, "/")
+	case InjectionQuery:
+		if !i.Raw {
+			payload = url.QueryEscape(payload)
+		}
+
+		pairs := queryPairs(query)
+		if point.index < 0 {
+			pairs = append(pairs, point.name+"="+payload)
+		} else {
+			parts := strings.SplitN(pairs[point.index], "=", 2)
+			current := ""
+			if len(parts) == 2 {
+				current = parts[1]
+			}
+			pairs[point.index] = parts[0] + "=" + i.value(current, payload)
+		}
+		query = strings.Join(pairs, "&")
+		hasQuery = true
+	}
+
+	if hasQuery {
+		return pathPart + "?" + query
+	}
+
+	return pathPart
+}
+
+// splitQuery splits the path of a request from its query
+func splitQuery(path string) (pathPart, query string, hasQuery bool) {
+	if index := strings.Index(path, "?"); index >= 0 {
+		return path[:index], path[index+1:], true
+	}
+
+	return path, "", false
+}
+
+// pathSegments returns the non empty segments of a path
+func pathSegments(path string) []string {
+	var segments []string
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments
+}
+
+// queryPairs returns the parameters of a query in order
+func queryPairs(query string) []string {
+	if query == "" {
+		return nil
+	}
+
+	return strings.Split(query, "&")
+}
+
+// Inject returns the copies of a request with the payload of the template
+// injected in each of its injection points, or the request itself without
+// injection. Each copy gets its own out-of-band host.
+func (r *BulkHTTPRequest) Inject(request *HTTPRequest) ([]*HTTPRequest, error) {
+	if r.Injection == nil {
+		return []*HTTPRequest{request}, nil
+	}
+
+	var (
+		path    string
+		headers []string
+		body    []byte
+	)
+
+	if request.RawRequest != nil {
+		path = request.RawRequest.Path
+		for name := range request.RawRequest.Headers {
+			headers = append(headers, name)
+		}
+	} else {
+		path = request.Request.URL.EscapedPath()
+		if request.Request.URL.RawQuery != "" || request.Request.URL.ForceQuery {
+			path += "?" + request.Request.URL.RawQuery
+		}
+
+		headers = append(headers, "Host")
+		for name := range request.Request.Header {
+			headers = append(headers, name)
+		}
+
+		var err error
+		if body, err = request.Request.BodyBytes(); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(headers)
+
+	points := r.Injection.points(path, headers)
+	injected := make([]*HTTPRequest, 0, len(points))
+
+	for _, point := range points {
+		values := generators.MergeMaps(request.values, request.Meta)
+
+		copied := *request
+		if r.oob != nil && strings.Contains(r.Injection.Payload, oobPlaceholder) {
+			var oobHost string
+			copied.OOBID, oobHost = r.oob.Host()
+			values["oob-host"] = oobHost
+			values["oob-url"] = "http://" + oobHost
+		}
+		payload := newReplacer(values).Replace(r.Injection.Payload)
+
+		copied.Meta = generators.CopyMap(request.Meta)
+		copied.Meta[injectionPointMeta] = point.String()
+
+		if request.RawRequest != nil {
+			copied.RawRequest = r.injectRaw(request.RawRequest, point, payload)
+		} else {
+			req, err := r.injectHTTP(request.Request, body, point, payload)
+			if err != nil {
+				return nil, err
+			}
+			copied.Request = req
+		}
+
+		injected = append(injected, &copied)
+	}
+
+	return injected, nil
+}
+
+// injectRaw returns a copy of a raw request with the payload injected in a
+// point
+func (r *BulkHTTPRequest) injectRaw(request *RawRequest, point injectionPoint, payload string) *RawRequest {
+	copied := *request
+	copied.Headers = make(map[string]string, len(request.Headers)+1)
+	for name, value := range request.Headers {
+		copied.Headers[name] = value
+	}
+
+	if point.kind != InjectionHeaders {
+		copied.Path = r.Injection.injectPath(request.Path, point, payload)
+		copied.FullURL = strings.TrimSuffix(copied.FullURL, request.Path) + copied.Path
+
+		return &copied
+	}
+
+	// the values of the raw headers keep the space following the colon
+	current, ok := copied.Headers[point.name]
+	if !ok || r.Injection.Mode == InjectionReplace {
+		current = " "
+	}
+	copied.Headers[point.name] = current + payload
+
+	return &copied
+}
+
+// injectHTTP returns a copy of a request with the payload injected in a
+// point
+func (r *BulkHTTPRequest) injectHTTP(request *retryablehttp.Request, body []byte, point injectionPoint, payload string) (*retryablehttp.Request, error) {
+	req := request.Request.Clone(request.Context())
+	req.Body = nil
+	if body != nil {
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	switch point.kind {
+	case InjectionHeaders:
+		if http.CanonicalHeaderKey(point.name) == "Host" {
+			if req.Host == "" {
+				req.Host = req.URL.Host
+			}
+			req.Host = r.Injection.value(req.Host, payload)
+			break
+		}
+
+		var current string
+		if values := req.Header[point.name]; len(values) > 0 {
+			current = values[0]
+		}
+		req.Header[point.name] = []string{r.Injection.value(current, payload)}
+	default:
+		path := req.URL.EscapedPath()
+		if req.URL.RawQuery != "" || req.URL.ForceQuery {
+			path += "?" + req.URL.RawQuery
+		}
+
+		parsed, err := url.ParseRequestURI(r.Injection.injectPath(path, point, payload))
+		if err != nil {
+			return nil, fmt.Errorf("could not inject %s: %s", point, err)
+		}
+		req.URL.Path, req.URL.RawPath = parsed.Path, parsed.RawPath
+		req.URL.RawQuery, req.URL.ForceQuery = parsed.RawQuery, parsed.ForceQuery
+	}
+
+	return retryablehttp.FromRequest(req)
+}
+
+// MakeHTTPRequests makes the request of the current payloads, once for
+// each injection point of the template
+func (r *BulkHTTPRequest) MakeHTTPRequests(baseURL string, dynamicValues map[string]interface{}, data string) ([]*HTTPRequest, error) {
+	request, err := r.MakeHTTPRequest(baseURL, dynamicValues, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Inject(request)
+}
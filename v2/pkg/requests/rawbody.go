@@ -0,0 +1,103 @@
+package requests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// chunked returns true if the bodies of the unsafe raw requests are sent
+// with the chunked transfer encoding
+func (r *BulkHTTPRequest) chunked() bool {
+	return r.ChunkSize > 0 || len(r.Trailers) > 0
+}
+
+// encodeRawBody compresses and chunks the body of an unsafe raw request as
+// requested by the template, setting the headers it didn't give. The line
+// endings of the body are converted beforehand, the encoded body being sent
+// as is.
+func (r *BulkHTTPRequest) encodeRawBody(rawRequest *RawRequest) error {
+	if r.BodyEncoding == "" && !r.chunked() {
+		return nil
+	}
+
+	// burp uses "\r\n" as new line character
+	data := strings.ReplaceAll(strings.ReplaceAll(rawRequest.Data, "\r\n", "\n"), "\n", "\r\n")
+
+	if r.BodyEncoding != "" && data != "" {
+		compressed, err := compressBody(r.BodyEncoding, []byte(data))
+		if err != nil {
+			return err
+		}
+
+		data = string(compressed)
+		setRawHeader(rawRequest, "Content-Encoding", r.BodyEncoding)
+	}
+
+	if r.chunked() {
+		data = r.chunk(data)
+		setRawHeader(rawRequest, "Transfer-Encoding", "chunked")
+
+		if len(r.Trailers) > 0 {
+			setRawHeader(rawRequest, "Trailer", strings.Join(sortedKeys(r.Trailers), ", "))
+		}
+	}
+
+	rawRequest.Data = data
+	rawRequest.BodyEncoded = true
+
+	return nil
+}
+
+// chunk returns a body in chunks of the chunk size, the whole body being
+// a single chunk without size, followed by the last chunk and the trailers
+func (r *BulkHTTPRequest) chunk(data string) string {
+	size := r.ChunkSize
+	if size <= 0 {
+		size = len(data)
+	}
+
+	var builder strings.Builder
+
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+
+		fmt.Fprintf(&builder, "%x%s\r\n%s\r\n", n, r.ChunkExtension, data[:n])
+		data = data[n:]
+	}
+
+	fmt.Fprintf(&builder, "0%s\r\n", r.ChunkExtension)
+
+	for _, name := range sortedKeys(r.Trailers) {
+		fmt.Fprintf(&builder, "%s: %s\r\n", name, r.Trailers[name])
+	}
+
+	builder.WriteString("\r\n")
+
+	return builder.String()
+}
+
+// setRawHeader sets a header of a raw request unless given by the template
+func setRawHeader(rawRequest *RawRequest, name, value string) {
+	for key := range rawRequest.Headers {
+		if strings.EqualFold(key, name) {
+			return
+		}
+	}
+
+	rawRequest.Headers[name] = " " + value
+}
+
+// sortedKeys returns the sorted keys of a map
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,105 @@
+package requests
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+)
+
+// ServiceRequest contains a network service probe to be made from a template
+type ServiceRequest struct {
+	// Type is the probed service
+	Type string `yaml:"type"`
+	// Port is the port of the service, the default port of the service if empty
+	Port string `yaml:"port,omitempty"`
+	// TLS connects to the service over tls
+	TLS bool `yaml:"tls,omitempty"`
+	// Timeout is the timeout in seconds of the probe
+	Timeout int `yaml:"timeout,omitempty"`
+	// Options contains the service specific options of the probe
+	Options map[string]string `yaml:"options,omitempty"`
+	// Credentials contains username:password pairs tried in order, the
+	// service being probed once per pair until the matchers match.
+	Credentials []string `yaml:"credentials,omitempty"`
+
+	// Matchers contains the detection mechanism for the request to identify
+	// whether the request was successful
+	Matchers []*matchers.Matcher `yaml:"matchers,omitempty"`
+	// matchersCondition is internal condition for the matchers.
+	matchersCondition matchers.ConditionType
+	// MatchersCondition is the condition of the matchers
+	// whether to use AND or OR. Default is OR.
+	MatchersCondition string `yaml:"matchers-condition,omitempty"`
+	// MatchersThreshold is the minimum ratio of matched weights for the weighted condition
+	MatchersThreshold float64 `yaml:"matchers-threshold,omitempty"`
+	// Extractors contains the extraction mechanism for the request to identify
+	// and extract parts of the response.
+	Extractors []*extractors.Extractor `yaml:"extractors,omitempty"`
+}
+
+// GetMatchersCondition returns the condition for the matcher
+func (r *ServiceRequest) GetMatchersCondition() matchers.ConditionType {
+	return r.matchersCondition
+}
+
+// SetMatchersCondition sets the condition for the matcher
+func (r *ServiceRequest) SetMatchersCondition(condition matchers.ConditionType) {
+	r.matchersCondition = condition
+}
+
+// GetRequestCount returns the total number of requests the YAML rule will perform
+func (r *ServiceRequest) GetRequestCount() int64 {
+	if len(r.Credentials) > 0 {
+		return int64(len(r.Credentials))
+	}
+
+	return 1
+}
+
+// GetProbeOptions returns the options of each probe, the username and
+// password options being set from each of the credentials.
+func (r *ServiceRequest) GetProbeOptions() []map[string]string {
+	if len(r.Credentials) == 0 {
+		return []map[string]string{r.Options}
+	}
+
+	probes := make([]map[string]string, 0, len(r.Credentials))
+
+	for _, credential := range r.Credentials {
+		options := make(map[string]string, len(r.Options)+2)
+		for k, v := range r.Options {
+			options[k] = v
+		}
+
+		parts := strings.SplitN(credential, ":", 2)
+		options["username"] = parts[0]
+
+		if len(parts) == 2 {
+			options["password"] = parts[1]
+		}
+
+		probes = append(probes, options)
+	}
+
+	return probes
+}
+
+// GetAddress returns the address of the service of a target
+func (r *ServiceRequest) GetAddress(target, defaultPort string) string {
+	host := target
+	if parsed, err := url.Parse(target); err == nil && parsed.Hostname() != "" {
+		host = parsed.Hostname()
+	} else if h, _, err := net.SplitHostPort(target); err == nil {
+		host = h
+	}
+
+	port := r.Port
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,64 @@
+package requests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/signer"
+)
+
+// SignatureAWS signs the requests with AWS Signature Version 4
+const SignatureAWS = "aws"
+
+// Signature contains the configuration of the signing of the requests
+// to cloud provider apis. The credentials default to the standard
+// environment variables of the provider.
+type Signature struct {
+	// Type is the signature algorithm, only aws is supported
+	Type string `yaml:"type"`
+	// Service is the name of the signed service (eg. s3, ec2)
+	Service string `yaml:"service"`
+	// Region is the region of the signed service
+	Region string `yaml:"region"`
+}
+
+// Validate checks the signature configuration
+func (s *Signature) Validate() error {
+	if s.Type != SignatureAWS {
+		return fmt.Errorf("unknown signature type %s", s.Type)
+	}
+
+	if s.Service == "" || s.Region == "" {
+		return errors.New("aws signatures require a service and a region")
+	}
+
+	return nil
+}
+
+// sign signs a request with the credentials of the environment
+func (s *Signature) sign(req *http.Request) error {
+	var body []byte
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+
+		body = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	return signer.SignAWS(req, body, &signer.AWSOptions{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       s.Region,
+		Service:      s.Service,
+	}, time.Now())
+}
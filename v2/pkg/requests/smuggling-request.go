@@ -0,0 +1,45 @@
+package requests
+
+import (
+	"github.com/projectdiscovery/nuclei/v2/pkg/smuggling"
+)
+
+// SmugglingRequest contains a request smuggling detection probe
+type SmugglingRequest struct {
+	// Method is the method of the probes, defaults to POST
+	Method string `yaml:"method,omitempty"`
+	// Path is the path of the probes, defaults to /
+	Path string `yaml:"path,omitempty"`
+	// Techniques contains the desync techniques to test (cl.te, te.cl), all by default
+	Techniques []string `yaml:"techniques,omitempty"`
+	// Timeout is the number of seconds after which a probe is considered delayed
+	Timeout int `yaml:"timeout,omitempty"`
+	// Confirmations is the number of times a delay is confirmed with a new probe pair
+	Confirmations int `yaml:"confirmations,omitempty"`
+}
+
+// GetTechniques returns the techniques to test in order
+func (r *SmugglingRequest) GetTechniques() []string {
+	if len(r.Techniques) == 0 {
+		return smuggling.Techniques
+	}
+
+	// keep the safe testing order whatever the template order is
+	var techniques []string
+
+	for _, technique := range smuggling.Techniques {
+		for _, requested := range r.Techniques {
+			if requested == technique {
+				techniques = append(techniques, technique)
+				break
+			}
+		}
+	}
+
+	return techniques
+}
+
+// GetRequestCount returns the total number of requests the YAML rule will perform
+func (r *SmugglingRequest) GetRequestCount() int64 {
+	return int64(len(r.GetTechniques()))
+}
@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"net/url"
+	"strings"
+)
+
+// StorageRequest contains the cloud storage bucket checks of a template
+type StorageRequest struct {
+	// Provider is the cloud storage provider (s3, gcs or azure)
+	Provider string `yaml:"provider"`
+	// Buckets contains the names of the buckets to check, azure buckets being
+	// named account/container. {{Hostname}} is replaced by the target host.
+	Buckets []string `yaml:"buckets"`
+	// Checks contains the checks run on every bucket (exists, unclaimed, list, write)
+	Checks []string `yaml:"checks"`
+	// Timeout is the timeout in seconds of each request
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
+// GetBuckets returns the names of the buckets to check for a target
+func (r *StorageRequest) GetBuckets(target string) []string {
+	hostname := target
+	if parsed, err := url.Parse(target); err == nil && parsed.Hostname() != "" {
+		hostname = parsed.Hostname()
+	}
+
+	replacer := strings.NewReplacer("{{Hostname}}", hostname)
+
+	buckets := make([]string, 0, len(r.Buckets))
+	for _, bucket := range r.Buckets {
+		buckets = append(buckets, replacer.Replace(bucket))
+	}
+
+	return buckets
+}
+
+// GetRequestCount returns the total number of requests the YAML rule will perform
+func (r *StorageRequest) GetRequestCount() int64 {
+	return int64(len(r.Buckets) * len(r.Checks))
+}
@@ -0,0 +1,16 @@
+package requests
+
+// TakeoverRequest contains the subdomain takeover verification of a template
+type TakeoverRequest struct {
+	// Services restricts the verification to some services of the fingerprints
+	Services []string `yaml:"services,omitempty"`
+	// Fingerprints is a json file replacing the default fingerprint database
+	Fingerprints string `yaml:"fingerprints,omitempty"`
+	// Timeout is the timeout in seconds of the http requests
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
+// GetRequestCount returns the total number of requests the YAML rule will perform
+func (r *TakeoverRequest) GetRequestCount() int64 {
+	return 1
+}
@@ -0,0 +1,4 @@
+// Package resolver resolves the addresses of the targets and routes the
+// connections to a chosen address of a host, the hostname being kept in
+// the Host header and the SNI of the requests.
+package resolver
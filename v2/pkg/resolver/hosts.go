@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// ParseHostsFile reads the addresses of the hosts from a file in the hosts
+// file format, an address followed by its hosts on each line, the
+// wildcards like *.example.com matching the subdomains of a domain
+func ParseHostsFile(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pins := make(map[string]string)
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if comment := strings.Index(text, "#"); comment != -1 {
+			text = text[:comment]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+			return nil, fmt.Errorf("invalid line %d in %s (It should be ip host...)", line, file)
+		}
+
+		for _, host := range fields[1:] {
+			pins[strings.ToLower(host)] = fields[0]
+		}
+	}
+
+	return pins, scanner.Err()
+}
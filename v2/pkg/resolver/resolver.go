@@ -0,0 +1,273 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ipKey is the context key of the address chosen for a host
+type ipKey struct{}
+
+// hostIP is the address chosen for the connections to a host
+type hostIP struct {
+	host string
+	ip   string
+}
+
+// Options contains the configuration of a resolver
+type Options struct {
+	// Pins maps the hosts to the addresses they always resolve to, the pin
+	// of the empty host applying to all the hosts
+	Pins map[string]string
+	// CacheTTL is the time the addresses of a host are cached for the
+	// connections, the connections resolving their hosts when zero
+	CacheTTL time.Duration
+	// CacheSize is the maximum number of hosts cached
+	CacheSize int
+}
+
+// Resolver resolves the addresses of the hosts, the pinned hosts always
+// resolving to their pinned address. The addresses are cached, shared by
+// all the executers.
+type Resolver struct {
+	mutex   sync.Mutex
+	entries map[string]*entry
+	pins    map[string]string
+	ttl     time.Duration
+	size    int
+}
+
+// entry contains the addresses of a host, ready once resolved
+type entry struct {
+	ready   chan struct{}
+	ips     []string
+	err     error
+	expires time.Time
+}
+
+// New creates a resolver
+func New(options *Options) *Resolver {
+	return &Resolver{
+		entries: make(map[string]*entry),
+		pins:    options.Pins,
+		ttl:     options.CacheTTL,
+		size:    options.CacheSize,
+	}
+}
+
+// Pinned returns true if hosts are pinned to addresses
+func (r *Resolver) Pinned() bool {
+	return r != nil && len(r.pins) > 0
+}
+
+// caching returns true if the connections use the cached addresses
+func (r *Resolver) caching() bool {
+	return r != nil && r.ttl > 0
+}
+
+// pin returns the address a host is pinned to, if any
+func (r *Resolver) pin(host string) string {
+	if !r.Pinned() {
+		return ""
+	}
+
+	host = strings.ToLower(host)
+	if ip, ok := r.pins[host]; ok {
+		return ip
+	}
+
+	// the wildcards pin the subdomains of a domain
+	for domain := host; strings.Contains(domain, "."); {
+		domain = domain[strings.Index(domain, ".")+1:]
+		if ip, ok := r.pins["*."+domain]; ok {
+			return ip
+		}
+	}
+
+	return r.pins[""]
+}
+
+// LookupIPs returns all the addresses of a host, resolved once for all the
+// concurrent lookups and cached until the cache ttl, or for the whole scan
+// without ttl
+func (r *Resolver) LookupIPs(host string) ([]string, error) {
+	if ip := r.pin(host); ip != "" {
+		return []string{ip}, nil
+	}
+
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	r.mutex.Lock()
+	e, ok := r.entries[host]
+	if ok && !e.expires.IsZero() && time.Now().After(e.expires) {
+		delete(r.entries, host)
+		ok = false
+	}
+
+	if ok {
+		r.mutex.Unlock()
+		<-e.ready
+
+		return e.ips, e.err
+	}
+
+	e = &entry{ready: make(chan struct{})}
+	r.evict()
+	r.entries[host] = e
+	r.mutex.Unlock()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(context.Background(), host)
+
+	r.mutex.Lock()
+	if err != nil {
+		// the failures are resolved again by the next lookups
+		e.err = err
+		delete(r.entries, host)
+	} else {
+		e.ips = make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			e.ips = append(e.ips, addr.IP.String())
+		}
+
+		if r.ttl > 0 {
+			e.expires = time.Now().Add(r.ttl)
+		}
+	}
+	r.mutex.Unlock()
+	close(e.ready)
+
+	return e.ips, e.err
+}
+
+// evict removes an expired entry, or the one expiring first, when the
+// cache is full
+func (r *Resolver) evict() {
+	if r.size <= 0 || len(r.entries) < r.size {
+		return
+	}
+
+	var (
+		oldest string
+		now    = time.Now()
+	)
+
+	for host, e := range r.entries {
+		select {
+		case <-e.ready:
+		default:
+			// being resolved
+			continue
+		}
+
+		if !e.expires.IsZero() && now.After(e.expires) {
+			oldest = host
+			break
+		}
+
+		if oldest == "" || e.expires.Before(r.entries[oldest].expires) {
+			oldest = host
+		}
+	}
+
+	if oldest != "" {
+		delete(r.entries, oldest)
+	}
+}
+
+// WithIP returns a context routing the connections to a host to an
+// address, unchanged without address
+func WithIP(ctx context.Context, host, ip string) context.Context {
+	if ip == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, ipKey{}, hostIP{host: host, ip: ip})
+}
+
+// IP returns the address chosen in a context, if any
+func IP(ctx context.Context) string {
+	chosen, _ := ctx.Value(ipKey{}).(hostIP)
+
+	return chosen.ip
+}
+
+// Address returns the address to dial for a host and port, with the host
+// replaced by the address chosen in the context for it or its pin
+func (r *Resolver) Address(ctx context.Context, address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+
+	if chosen, ok := ctx.Value(ipKey{}).(hostIP); ok && chosen.host == host {
+		return net.JoinHostPort(chosen.ip, port)
+	}
+
+	if ip := r.pin(host); ip != "" {
+		return net.JoinHostPort(ip, port)
+	}
+
+	return address
+}
+
+// DialFunc opens a connection to an address
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// Dial returns a dial function opening the connections to the address
+// chosen for the hosts or their pins, or else to their cached addresses,
+// the ipv4 ones first, until one accepts the connection
+func (r *Resolver) Dial(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		routed := r.Address(ctx, address)
+		if routed != address || !r.caching() {
+			return dial(ctx, network, routed)
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, address)
+		}
+
+		ips, err := r.LookupIPs(host)
+		if err != nil {
+			return nil, err
+		}
+
+		var conn net.Conn
+		for _, ip := range preferIPv4(ips) {
+			if conn, err = dial(ctx, network, net.JoinHostPort(ip, port)); err == nil {
+				return conn, nil
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		return nil, err
+	}
+}
+
+// preferIPv4 returns the addresses with the ipv4 ones first
+func preferIPv4(ips []string) []string {
+	sorted := make([]string, 0, len(ips))
+
+	for _, ip := range ips {
+		if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() != nil {
+			sorted = append(sorted, ip)
+		}
+	}
+
+	for _, ip := range ips {
+		if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+			sorted = append(sorted, ip)
+		}
+	}
+
+	return sorted
+}
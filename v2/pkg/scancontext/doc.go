@@ -0,0 +1,3 @@
+// Package scancontext stores the values published by the templates for
+// each target, so the other templates can use them as placeholders.
+package scancontext
@@ -0,0 +1,150 @@
+package scancontext
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Context contains the values published for each target
+type Context struct {
+	mutex     sync.RWMutex
+	values    map[string]map[string]interface{}
+	satisfied map[string]map[string]struct{}
+}
+
+// New creates a new empty scan context
+func New() *Context {
+	return &Context{
+		values:    make(map[string]map[string]interface{}),
+		satisfied: make(map[string]map[string]struct{}),
+	}
+}
+
+// key returns the host name of an input, shared by the urls and the
+// service addresses of the same target.
+func key(input string) string {
+	if !strings.Contains(input, "://") {
+		input = "//" + input
+	}
+
+	parsed, err := url.Parse(input)
+	if err != nil || parsed.Hostname() == "" {
+		return input
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+// Set publishes a value for the target of an input, the first value
+// published with a name being kept.
+func (c *Context) Set(input, name string, value interface{}) {
+	if c == nil {
+		return
+	}
+
+	target := key(input)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	values, ok := c.values[target]
+	if !ok {
+		values = make(map[string]interface{})
+		c.values[target] = values
+	}
+
+	if _, ok := values[name]; !ok {
+		values[name] = value
+	}
+}
+
+// Values returns a copy of the values published for the target of an input
+func (c *Context) Values(input string) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	if c == nil {
+		return values
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for name, value := range c.values[key(input)] {
+		values[name] = value
+	}
+
+	return values
+}
+
+// Has returns true if all the named values were published for the target of an input
+func (c *Context) Has(input string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	if c == nil {
+		return false
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	values := c.values[key(input)]
+
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Satisfy records that a template id or a capability matched on the
+// target of an input.
+func (c *Context) Satisfy(input string, names ...string) {
+	if c == nil {
+		return
+	}
+
+	target := key(input)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	satisfied, ok := c.satisfied[target]
+	if !ok {
+		satisfied = make(map[string]struct{})
+		c.satisfied[target] = satisfied
+	}
+
+	for _, name := range names {
+		satisfied[name] = struct{}{}
+	}
+}
+
+// Satisfied returns true if all the template ids or capabilities matched
+// on the target of an input.
+func (c *Context) Satisfied(input string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+
+	if c == nil {
+		return false
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	satisfied := c.satisfied[key(input)]
+
+	for _, name := range names {
+		if _, ok := satisfied[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,3 @@
+// Package scheduler implements delays between requests and
+// scan windows restricting when traffic can be sent.
+package scheduler
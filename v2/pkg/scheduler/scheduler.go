@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	two          = 2
+	minutesInDay = 24 * 60
+)
+
+// Scheduler controls the pace at which requests are sent to a host
+// and the time of the day during which traffic is allowed.
+type Scheduler struct {
+	delay       time.Duration
+	randomDelay time.Duration
+	window      *window
+
+	mutex sync.Mutex
+	// next contains the time at which the next request to each host can be sent
+	next map[string]time.Time
+}
+
+// window is a daily time frame expressed in minutes since midnight
+type window struct {
+	start int
+	end   int
+}
+
+// New creates a new scheduler from a fixed delay and a random delay in milliseconds
+// and an optional scan window in the HH:MM-HH:MM format (local time).
+func New(delay, randomDelay int, scanWindow string) (*Scheduler, error) {
+	scheduler := &Scheduler{
+		delay:       time.Duration(delay) * time.Millisecond,
+		randomDelay: time.Duration(randomDelay) * time.Millisecond,
+		next:        make(map[string]time.Time),
+	}
+
+	if scanWindow != "" {
+		w, err := parseWindow(scanWindow)
+		if err != nil {
+			return nil, err
+		}
+
+		scheduler.window = w
+	}
+
+	return scheduler, nil
+}
+
+// Wait blocks until the next request to a host can be sent, the delay
+// being kept between the requests to the same host only.
+func (s *Scheduler) Wait(host string) {
+	if s == nil {
+		return
+	}
+
+	s.waitForWindow()
+
+	if s.delay <= 0 && s.randomDelay <= 0 {
+		return
+	}
+
+	if wait := s.reserve(host, time.Now()); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// reserve books the next request slot of a host, returning how long to
+// wait for it
+func (s *Scheduler) reserve(host string, now time.Time) time.Duration {
+	delay := s.delay
+	if s.randomDelay > 0 {
+		// nolint:gosec // no need for a cryptographically secure source here
+		delay += time.Duration(rand.Int63n(int64(s.randomDelay)))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	wait := s.next[host].Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	s.next[host] = now.Add(wait + delay)
+
+	return wait
+}
+
+// waitForWindow pauses until the current local time falls in the scan window
+func (s *Scheduler) waitForWindow() {
+	if s.window == nil {
+		return
+	}
+
+	now := time.Now()
+	minute := now.Hour()*60 + now.Minute()
+
+	if s.window.contains(minute) {
+		return
+	}
+
+	wait := s.window.start - minute
+	if wait < 0 {
+		wait += minutesInDay
+	}
+
+	// align to the beginning of the minute
+	sleep := time.Duration(wait)*time.Minute - time.Duration(now.Second())*time.Second
+
+	gologger.Verbosef("Outside of the scan window, pausing for %s\n", "scheduler", sleep)
+	time.Sleep(sleep)
+}
+
+// contains returns true if the minute of the day is part of the window
+func (w *window) contains(minute int) bool {
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end
+	}
+
+	// the window spans over midnight
+	return minute >= w.start || minute < w.end
+}
+
+// parseWindow parses a HH:MM-HH:MM scan window
+func parseWindow(value string) (*window, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != two {
+		return nil, fmt.Errorf("invalid scan window %s (It should be HH:MM-HH:MM)", value)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if start == end {
+		return nil, fmt.Errorf("empty scan window %s", value)
+	}
+
+	return &window{start: start, end: end}, nil
+}
+
+// parseClock converts a HH:MM value to minutes since midnight
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != two {
+		return 0, fmt.Errorf("invalid time %s (It should be HH:MM)", value)
+	}
+
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil || hours < 0 || hours > 23 {
+		return 0, fmt.Errorf("invalid hour in %s", value)
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid minutes in %s", value)
+	}
+
+	return hours*60 + minutes, nil
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		value string
+		start int
+		end   int
+		err   bool
+	}{
+		{value: "09:00-17:30", start: 9 * 60, end: 17*60 + 30},
+		{value: "22:00 - 06:00", start: 22 * 60, end: 6 * 60},
+		{value: "00:00-23:59", start: 0, end: 23*60 + 59},
+		{value: "09:00", err: true},
+		{value: "09:00-17:00-18:00", err: true},
+		{value: "24:00-06:00", err: true},
+		{value: "09:60-10:00", err: true},
+		{value: "9-10", err: true},
+		{value: "10:00-10:00", err: true},
+	}
+
+	for _, test := range tests {
+		w, err := parseWindow(test.value)
+		if test.err {
+			require.NotNil(t, err, "Could parse invalid window %s", test.value)
+			continue
+		}
+
+		require.Nil(t, err, "Could not parse valid window %s", test.value)
+		require.Equal(t, &window{start: test.start, end: test.end}, w, "Could not parse window %s", test.value)
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	tests := []struct {
+		window   string
+		minute   int
+		contains bool
+	}{
+		{window: "09:00-17:00", minute: 9 * 60, contains: true},
+		{window: "09:00-17:00", minute: 12 * 60, contains: true},
+		{window: "09:00-17:00", minute: 17 * 60, contains: false},
+		{window: "09:00-17:00", minute: 8 * 60, contains: false},
+		{window: "22:00-06:00", minute: 23 * 60, contains: true},
+		{window: "22:00-06:00", minute: 60, contains: true},
+		{window: "22:00-06:00", minute: 6 * 60, contains: false},
+		{window: "22:00-06:00", minute: 12 * 60, contains: false},
+	}
+
+	for _, test := range tests {
+		w, err := parseWindow(test.window)
+		require.Nil(t, err, "Could not parse valid window %s", test.window)
+		require.Equal(t, test.contains, w.contains(test.minute), "Could not check minute %d of window %s", test.minute, test.window)
+	}
+}
+
+func TestReservePerHost(t *testing.T) {
+	s, err := New(100, 0, "")
+	require.Nil(t, err, "Could not create scheduler")
+
+	now := time.Now()
+
+	tests := []struct {
+		host string
+		now  time.Time
+		wait time.Duration
+	}{
+		{host: "a.example.com", now: now, wait: 0},
+		{host: "a.example.com", now: now, wait: 100 * time.Millisecond},
+		{host: "b.example.com", now: now, wait: 0},
+		{host: "a.example.com", now: now.Add(50 * time.Millisecond), wait: 150 * time.Millisecond},
+		{host: "b.example.com", now: now.Add(time.Second), wait: 0},
+	}
+
+	for i, test := range tests {
+		wait := s.reserve(test.host, test.now)
+		require.Equal(t, test.wait, wait, "Could not reserve request %d to %s", i, test.host)
+	}
+}
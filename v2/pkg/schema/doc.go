@@ -0,0 +1,4 @@
+// Package schema generates the json schema of the template format from the
+// structures of the templates and the workflows, for the editors
+// autocompletion and the external validation tools.
+package schema
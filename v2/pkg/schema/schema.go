@@ -0,0 +1,178 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/headeraudit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/services"
+	"github.com/projectdiscovery/nuclei/v2/pkg/smuggling"
+	"github.com/projectdiscovery/nuclei/v2/pkg/storage"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templates"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tlsprofile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/useragent"
+	"github.com/projectdiscovery/nuclei/v2/pkg/workflows"
+)
+
+// schemaRequired contains the required fields of the definitions
+var schemaRequired = map[string][]string{
+	"templates.Template": {"id", "info"},
+	"templates.Info":     {"name", "author"},
+	"workflows.Workflow": {"id", "info", "logic"},
+	"workflows.Info":     {"name", "author"},
+}
+
+// schemaEnums contains the values accepted by the fields of the
+// definitions, the values of the list fields applying to their items
+func schemaEnums() map[string][]string {
+	var tlsVersions []string
+	for version := range tlsprofile.Versions {
+		tlsVersions = append(tlsVersions, version)
+	}
+	sort.Strings(tlsVersions)
+
+	return map[string][]string{
+		"templates.Info.severity":                  templates.Severities(),
+		"templates.Info.intrusiveness":             templates.IntrusivenessClasses(),
+		"matchers.Matcher.type":                    keys(matchers.MatcherTypes),
+		"matchers.Matcher.condition":               keys(matchers.ConditionTypes),
+		"matchers.Matcher.part":                    keys(matchers.PartTypes),
+		"matchers.Matcher.missing":                 keys(matchers.CookieAttributes),
+		"extractors.Extractor.missing":             keys(matchers.CookieAttributes),
+		"extractors.Extractor.type":                keys(extractors.ExtractorTypes),
+		"extractors.Extractor.part":                keys(extractors.PartTypes),
+		"requests.BulkHTTPRequest.attack":          keys(generators.AttackTypes),
+		"requests.BulkHTTPRequest.connection":      {requests.ConnectionClose, requests.ConnectionKeepAlive, requests.ConnectionReusePrevious},
+		"requests.BulkHTTPRequest.tls-min-version": tlsVersions,
+		"requests.BulkHTTPRequest.tls-max-version": tlsVersions,
+		"requests.BulkHTTPRequest.body-encoding":   keys(requests.BodyEncodings),
+		"requests.BulkHTTPRequest.user-agent":      keys(useragent.Strategies),
+		"requests.DNSRequest.matchers-condition":   keys(matchers.ConditionTypes),
+		"requests.ServiceRequest.type":             services.Names(),
+		"requests.StorageRequest.provider":         keys(storage.Providers),
+		"requests.StorageRequest.checks":           keys(storage.Checks),
+		"requests.SmugglingRequest.techniques":     smuggling.Techniques,
+		"requests.AuditRequest.profile":            headeraudit.ProfileNames(),
+		"requests.AuditRequest.checks":             keys(headeraudit.Checks),
+		"requests.AuditRequest.exclude":            keys(headeraudit.Checks),
+	}
+}
+
+// Generate returns the json schema of the templates and the workflows,
+// generated from their structures so that it covers all their fields
+func Generate() ([]byte, error) {
+	generator := &schemaGenerator{enums: schemaEnums(), definitions: make(map[string]interface{})}
+
+	schema := map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "nuclei template",
+		"oneOf": []interface{}{
+			generator.schema(reflect.TypeOf(templates.Template{}), ""),
+			generator.schema(reflect.TypeOf(workflows.Workflow{}), ""),
+		},
+		"definitions": generator.definitions,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// schemaGenerator generates the schemas of the types, the structures being
+// added to the definitions
+type schemaGenerator struct {
+	enums       map[string][]string
+	definitions map[string]interface{}
+}
+
+// schema returns the schema of a type, field being the definition field it
+// is the type of, if any
+func (g *schemaGenerator) schema(t reflect.Type, field string) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	enum, hasEnum := g.enums[field]
+
+	switch t.Kind() {
+	case reflect.String:
+		if hasEnum {
+			return map[string]interface{}{"type": "string", "enum": enum}
+		}
+
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem(), field)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem(), "")}
+	case reflect.Struct:
+		return g.definition(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// definition adds the definition of a structure, returning its reference
+func (g *schemaGenerator) definition(t reflect.Type) map[string]interface{} {
+	name := t.String()
+	reference := map[string]interface{}{"$ref": "#/definitions/" + name}
+
+	if _, ok := g.definitions[name]; ok {
+		return reference
+	}
+
+	properties := make(map[string]interface{})
+	definition := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required, ok := schemaRequired[name]; ok {
+		definition["required"] = required
+	}
+
+	// added first for the recursive structures
+	g.definitions[name] = definition
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+
+		properties[key] = g.schema(field.Type, name+"."+key)
+	}
+
+	return reference
+}
+
+// keys returns the sorted keys of a map
+func keys(m interface{}) []string {
+	value := reflect.ValueOf(m)
+
+	names := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		names = append(names, key.String())
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,3 @@
+// Package screenshot captures the pages of matched findings
+// with a headless browser to give reports visual evidence.
+package screenshot
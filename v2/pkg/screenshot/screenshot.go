@@ -0,0 +1,134 @@
+package screenshot
+
+import (
+	"context"
+	"crypto/sha1" // nolint:gosec // only used to name the files
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// captureTimeout is the maximum time to load and capture a page
+const captureTimeout = 30 * time.Second
+
+// browsers contains the names of the chromium based browsers looked up in the path
+var browsers = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome"}
+
+// Screenshotter captures the pages of urls with a headless browser
+type Screenshotter struct {
+	browser   string
+	directory string
+	proxy     string
+
+	mutex    sync.Mutex
+	captured map[string]*capture
+}
+
+// capture is the screenshot of an url, taken once
+type capture struct {
+	once sync.Once
+	path string
+	err  error
+}
+
+// New creates a screenshotter writing the images to a directory, the
+// browser being looked up in the path when not provided. The pages are
+// loaded through the proxy if any, without its credentials as the browser
+// can't be given them on the command line.
+func New(directory, browser, proxy string) (*Screenshotter, error) {
+	browser, err := FindBrowser(browser)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	s := &Screenshotter{browser: browser, directory: directory, captured: make(map[string]*capture)}
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		proxyURL.User = nil
+		s.proxy = proxyURL.String()
+	}
+
+	return s, nil
+}
+
+// FindBrowser returns the browser if provided, or else the first chromium
+// based browser found in the path
+func FindBrowser(browser string) (string, error) {
+	if browser != "" {
+		return browser, nil
+	}
+
+	for _, name := range browsers {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", errors.New("no headless browser found, install chromium or provide its path")
+}
+
+// Capture takes a screenshot of an url and returns the path of the image,
+// the pages matched by several findings being captured once.
+func (s *Screenshotter) Capture(URL string) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	// the other urls are captured meanwhile
+	s.mutex.Lock()
+	c, ok := s.captured[URL]
+	if !ok {
+		c = &capture{}
+		s.captured[URL] = c
+	}
+	s.mutex.Unlock()
+
+	c.once.Do(func() {
+		c.path, c.err = s.capture(URL)
+	})
+
+	return c.path, c.err
+}
+
+// capture runs the headless browser to take a screenshot of an url
+func (s *Screenshotter) capture(URL string) (string, error) {
+	hash := sha1.Sum([]byte(URL)) // nolint:gosec // only used to name the files
+	path := filepath.Join(s.directory, hex.EncodeToString(hash[:])+".png")
+
+	ctx, cancel := context.WithTimeout(context.Background(), captureTimeout)
+	defer cancel()
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--ignore-certificate-errors",
+		"--hide-scrollbars",
+		"--window-size=1280,800",
+		"--screenshot=" + path,
+	}
+	if s.proxy != "" {
+		args = append(args, "--proxy-server="+s.proxy)
+	}
+
+	cmd := exec.CommandContext(ctx, s.browser, append(args, URL)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%s: %s", err, output)
+	}
+
+	return path, nil
+}
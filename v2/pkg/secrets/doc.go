@@ -0,0 +1,4 @@
+// Package secrets detects the keys and the tokens leaked in the responses
+// with a set of precompiled rules, the generic rules requiring a minimum
+// entropy of the secrets to discard the placeholders.
+package secrets
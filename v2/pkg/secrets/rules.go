@@ -0,0 +1,177 @@
+package secrets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Rule detects a kind of secrets
+type Rule struct {
+	// ID is the unique id of the rule, like aws-access-key-id
+	ID string `yaml:"id"`
+	// Description describes the secrets detected
+	Description string `yaml:"description"`
+	// Severity is the severity of the leak of the secrets
+	Severity string `yaml:"severity,omitempty"`
+	// Regex matches the secrets, the first group being the secret if any
+	Regex string `yaml:"regex"`
+	// Keywords are lowercase strings one of which must be present in the
+	// responses for the regex to be run on them
+	Keywords []string `yaml:"keywords,omitempty"`
+	// Entropy is the minimum shannon entropy of the secrets
+	Entropy float64 `yaml:"entropy,omitempty"`
+
+	regexCompiled *regexp.Regexp
+}
+
+// compile compiles the regex of a rule
+func (r *Rule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("the secret rule %s has no id", r.Regex)
+	}
+
+	compiled, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return fmt.Errorf("could not compile the regex of the secret rule %s: %s", r.ID, err)
+	}
+	r.regexCompiled = compiled
+
+	for i, keyword := range r.Keywords {
+		r.Keywords[i] = strings.ToLower(keyword)
+	}
+
+	if r.Severity == "" {
+		r.Severity = "high"
+	}
+
+	return nil
+}
+
+// DefaultRules returns the built-in rules
+func DefaultRules() []*Rule {
+	return []*Rule{
+		{
+			ID:          "aws-access-key-id",
+			Description: "AWS access key id",
+			Regex:       `\b((?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[A-Z0-9]{16})\b`,
+			Keywords:    []string{"akia", "asia", "agpa", "aida", "aroa", "aipa", "anpa", "anva"},
+			Entropy:     3,
+		},
+		{
+			ID:          "aws-secret-access-key",
+			Description: "AWS secret access key",
+			Severity:    "critical",
+			Regex:       `(?i)aws.{0,20}?(?:secret|private).{0,20}?['"]([A-Za-z0-9/+=]{40})['"]`,
+			Keywords:    []string{"aws"},
+			Entropy:     4,
+		},
+		{
+			ID:          "github-token",
+			Description: "GitHub token",
+			Severity:    "critical",
+			Regex:       `\b(gh[pousr]_[A-Za-z0-9]{36,255})\b`,
+			Keywords:    []string{"ghp_", "gho_", "ghu_", "ghs_", "ghr_"},
+		},
+		{
+			ID:          "github-fine-grained-token",
+			Description: "GitHub fine-grained personal access token",
+			Severity:    "critical",
+			Regex:       `\b(github_pat_[A-Za-z0-9_]{82})\b`,
+			Keywords:    []string{"github_pat_"},
+		},
+		{
+			ID:          "gitlab-token",
+			Description: "GitLab personal access token",
+			Severity:    "critical",
+			Regex:       `\b(glpat-[A-Za-z0-9_-]{20})\b`,
+			Keywords:    []string{"glpat-"},
+		},
+		{
+			ID:          "slack-token",
+			Description: "Slack token",
+			Regex:       `\b(xox[baprs]-[A-Za-z0-9-]{10,72})\b`,
+			Keywords:    []string{"xoxb-", "xoxa-", "xoxp-", "xoxr-", "xoxs-"},
+		},
+		{
+			ID:          "slack-webhook",
+			Description: "Slack incoming webhook",
+			Severity:    "medium",
+			Regex:       `(https://hooks\.slack\.com/services/T[A-Za-z0-9_]+/B[A-Za-z0-9_]+/[A-Za-z0-9_]+)`,
+			Keywords:    []string{"hooks.slack.com"},
+		},
+		{
+			ID:          "google-api-key",
+			Description: "Google API key",
+			Severity:    "medium",
+			Regex:       `\b(AIza[0-9A-Za-z_-]{35})\b`,
+			Keywords:    []string{"aiza"},
+		},
+		{
+			ID:          "stripe-secret-key",
+			Description: "Stripe live secret or restricted key",
+			Severity:    "critical",
+			Regex:       `\b((?:sk|rk)_live_[0-9a-zA-Z]{24,99})\b`,
+			Keywords:    []string{"sk_live_", "rk_live_"},
+		},
+		{
+			ID:          "sendgrid-api-key",
+			Description: "SendGrid API key",
+			Regex:       `\b(SG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43})\b`,
+			Keywords:    []string{"sg."},
+		},
+		{
+			ID:          "npm-token",
+			Description: "npm access token",
+			Severity:    "critical",
+			Regex:       `\b(npm_[A-Za-z0-9]{36})\b`,
+			Keywords:    []string{"npm_"},
+		},
+		{
+			ID:          "shopify-token",
+			Description: "Shopify access token",
+			Regex:       `\b(shp(?:at|ca|pa|ss)_[a-fA-F0-9]{32})\b`,
+			Keywords:    []string{"shpat_", "shpca_", "shppa_", "shpss_"},
+		},
+		{
+			ID:          "azure-storage-connection-string",
+			Description: "Azure storage account connection string",
+			Severity:    "critical",
+			Regex:       `(DefaultEndpointsProtocol=https?;AccountName=[a-z0-9]+;AccountKey=[A-Za-z0-9+/=]{88})`,
+			Keywords:    []string{"accountkey="},
+		},
+		{
+			ID:          "private-key",
+			Description: "Private key",
+			Severity:    "critical",
+			Regex:       `(-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----)`,
+			Keywords:    []string{"private key"},
+		},
+		{
+			ID:          "generic-api-key",
+			Description: "Generic api key, secret or password",
+			Severity:    "medium",
+			Regex:       `(?i)(?:api[_-]?key|api[_-]?secret|client[_-]?secret|access[_-]?token|auth[_-]?token|secret[_-]?key)["']?\s*[:=]\s*["']([A-Za-z0-9_\-+/=.]{16,128})["']`,
+			Keywords:    []string{"key", "secret", "token"},
+			Entropy:     3.5,
+		},
+	}
+}
+
+// LoadRules loads the rules of a yaml file
+func LoadRules(path string) ([]*Rule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []*Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("could not parse secret rules %s: %s", path, err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// maxSecretsPerRule is the maximum number of secrets of a rule reported for
+// a response, the responses listing many keys being reported once
+const maxSecretsPerRule = 10
+
+// Finding is a secret found in a response
+type Finding struct {
+	// Rule is the rule detecting the secret
+	Rule *Rule
+	// Secret is the leaked secret
+	Secret string
+	// Entropy is the shannon entropy of the secret
+	Entropy float64
+}
+
+// Scanner detects the secrets of the responses with a set of rules, each
+// secret being reported once per host
+type Scanner struct {
+	rules []*Rule
+
+	mutex sync.Mutex
+	seen  map[string]struct{}
+}
+
+// New creates a scanner with the built-in rules and the rules of a file,
+// replacing the built-in rules with the same ids
+func New(rulesFile string) (*Scanner, error) {
+	rules := DefaultRules()
+
+	if rulesFile != "" {
+		custom, err := LoadRules(rulesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		replaced := make(map[string]bool, len(custom))
+		for _, rule := range custom {
+			replaced[rule.ID] = true
+		}
+
+		kept := rules[:0]
+		for _, rule := range rules {
+			if !replaced[rule.ID] {
+				kept = append(kept, rule)
+			}
+		}
+		rules = append(kept, custom...)
+	}
+
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Scanner{rules: rules, seen: make(map[string]struct{})}, nil
+}
+
+// Rules returns the rules of the scanner
+func (s *Scanner) Rules() []*Rule {
+	if s == nil {
+		return nil
+	}
+
+	return s.rules
+}
+
+// Scan returns the secrets of a response of a host not reported yet, each
+// secret being reported by the first rule detecting it. The regexes are
+// only run on the responses containing one of their keywords.
+func (s *Scanner) Scan(host, body string) []*Finding {
+	if s == nil || body == "" {
+		return nil
+	}
+
+	lower := strings.ToLower(body)
+
+	// the secrets of the specific rules aren't reported by the generic ones
+	// following them
+	found := make(map[string]bool)
+
+	var findings []*Finding
+	for _, rule := range s.rules {
+		if !containsAny(lower, rule.Keywords) {
+			continue
+		}
+
+		for _, match := range rule.regexCompiled.FindAllStringSubmatch(body, maxSecretsPerRule) {
+			secret := match[0]
+			if len(match) > 1 && match[1] != "" {
+				secret = match[1]
+			}
+
+			entropy := Entropy(secret)
+			if entropy < rule.Entropy || found[secret] {
+				continue
+			}
+			found[secret] = true
+
+			if !s.first(host, rule.ID, secret) {
+				continue
+			}
+
+			findings = append(findings, &Finding{Rule: rule, Secret: secret, Entropy: entropy})
+		}
+	}
+
+	return findings
+}
+
+// first returns true the first time a secret is seen for a host
+func (s *Scanner) first(host, ruleID, secret string) bool {
+	key := host + "\x00" + ruleID + "\x00" + secret
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.seen[key]; ok {
+		return false
+	}
+	s.seen[key] = struct{}{}
+
+	return true
+}
+
+// containsAny returns true if a corpus contains one of the keywords, or
+// if there are no keywords
+func containsAny(corpus string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+
+	for _, keyword := range keywords {
+		if strings.Contains(corpus, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Entropy returns the shannon entropy of a string in bits per character
+func Entropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, char := range value {
+		counts[char]++
+	}
+
+	length := float64(len([]rune(value)))
+
+	var entropy float64
+	for _, count := range counts {
+		frequency := float64(count) / length
+		entropy -= frequency * math.Log2(frequency)
+	}
+
+	return entropy
+}
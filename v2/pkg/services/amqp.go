@@ -0,0 +1,230 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// AMQP frames
+const (
+	amqpFrameMethod = 1
+	amqpFrameEnd    = 0xce
+)
+
+// AMQP connection methods
+const (
+	amqpConnectionClass   = 10
+	amqpConnectionStart   = 10
+	amqpConnectionStartOk = 11
+	amqpConnectionTune    = 30
+	amqpConnectionClose   = 50
+)
+
+// maxAMQPFrame is the maximum size of the amqp frames read
+const maxAMQPFrame = 1024 * 1024
+
+// amqpProtocolHeader is the protocol header of amqp 0-9-1
+var amqpProtocolHeader = []byte("AMQP\x00\x00\x09\x01")
+
+// probeAMQP reads the connection start of an amqp 0-9-1 broker and logs
+// in with the PLAIN mechanism when the username option is set, or with the
+// ANONYMOUS mechanism when the broker offers it.
+//
+// The fields are amqp_product, amqp_version, amqp_platform, amqp_mechanisms,
+// amqp_login and amqp_anonymous, the anonymous login succeeding.
+func probeAMQP(conn net.Conn, host string, options *Options) (*Response, error) {
+	if _, err := conn.Write(amqpProtocolHeader); err != nil {
+		return nil, err
+	}
+
+	class, method, arguments, err := amqpReadMethod(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if class != amqpConnectionClass || method != amqpConnectionStart || len(arguments) < 2 {
+		return nil, fmt.Errorf("not an amqp broker")
+	}
+
+	properties, rest, err := amqpTable(arguments[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	mechanisms, _, err := amqpLongString(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+
+	for _, name := range []string{"product", "version", "platform"} {
+		response.Fields["amqp_"+name] = properties[name]
+		response.addLine("%s: %s", name, properties[name])
+	}
+
+	response.Fields["amqp_mechanisms"] = mechanisms
+	response.addLine("mechanisms: %s", mechanisms)
+
+	username := options.Values["username"]
+
+	var mechanism, credentials string
+
+	switch {
+	case username != "":
+		mechanism, credentials = "PLAIN", "\x00"+username+"\x00"+options.Values["password"]
+	case containsWord(mechanisms, "ANONYMOUS"):
+		mechanism = "ANONYMOUS"
+	default:
+		response.Fields["amqp_anonymous"] = false
+		return response, nil
+	}
+
+	// empty client properties, mechanism, response and locale
+	startOk := []byte{0x00, amqpConnectionClass, 0x00, amqpConnectionStartOk, 0x00, 0x00, 0x00, 0x00}
+	startOk = append(startOk, byte(len(mechanism)))
+	startOk = append(startOk, mechanism...)
+	startOk = append(startOk, amqpLongStringBytes(credentials)...)
+	startOk = append(startOk, 0x05)
+	startOk = append(startOk, "en_US"...)
+
+	if err := amqpWriteMethod(conn, startOk); err != nil {
+		return nil, err
+	}
+
+	// brokers refusing the login close the connection or the socket
+	class, method, _, err = amqpReadMethod(conn)
+	login := err == nil && class == amqpConnectionClass && method == amqpConnectionTune
+
+	response.Fields["amqp_login"] = login
+	response.Fields["amqp_anonymous"] = login && mechanism == "ANONYMOUS"
+	response.addLine("login %s: %v", mechanism, login)
+
+	return response, nil
+}
+
+// containsWord returns true if a space separated list contains a word
+func containsWord(list, word string) bool {
+	for _, item := range strings.Fields(list) {
+		if item == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+// amqpWriteMethod writes a method frame on the connection channel
+func amqpWriteMethod(conn net.Conn, payload []byte) error {
+	frame := []byte{amqpFrameMethod, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	binary.BigEndian.PutUint32(frame[3:], uint32(len(payload)))
+	frame = append(frame, payload...)
+
+	_, err := conn.Write(append(frame, amqpFrameEnd))
+
+	return err
+}
+
+// amqpReadMethod reads a method frame and returns its class, method and arguments
+func amqpReadMethod(conn net.Conn) (class, method uint16, arguments []byte, err error) {
+	header := make([]byte, 7)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, 0, nil, err
+	}
+
+	// brokers refusing the protocol version answer with their protocol header
+	if string(header[:4]) == "AMQP" {
+		return 0, 0, nil, fmt.Errorf("unsupported amqp protocol version")
+	}
+
+	size := binary.BigEndian.Uint32(header[3:])
+	if header[0] != amqpFrameMethod || size < 4 || size > maxAMQPFrame {
+		return 0, 0, nil, fmt.Errorf("invalid amqp frame")
+	}
+
+	payload := make([]byte, size+1)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return 0, 0, nil, err
+	}
+
+	if payload[size] != amqpFrameEnd {
+		return 0, 0, nil, fmt.Errorf("invalid amqp frame end")
+	}
+
+	return binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:]), payload[4:size], nil
+}
+
+// amqpLongStringBytes encodes a long string
+func amqpLongStringBytes(value string) []byte {
+	encoded := make([]byte, 4, 4+len(value))
+	binary.BigEndian.PutUint32(encoded, uint32(len(value)))
+
+	return append(encoded, value...)
+}
+
+// amqpLongString decodes a long string
+func amqpLongString(data []byte) (string, []byte, error) {
+	if len(data) < 4 {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+
+	length := int(binary.BigEndian.Uint32(data))
+	if length < 0 || len(data) < 4+length {
+		return "", nil, io.ErrUnexpectedEOF
+	}
+
+	return string(data[4 : 4+length]), data[4+length:], nil
+}
+
+// amqpFieldSizes contains the sizes of the fixed size field values
+var amqpFieldSizes = map[byte]int{
+	't': 1, 'b': 1, 'B': 1, 's': 2, 'u': 2, 'I': 4, 'i': 4,
+	'l': 8, 'f': 4, 'd': 8, 'D': 5, 'T': 8, 'V': 0,
+}
+
+// amqpTable decodes a field table, keeping its string values
+func amqpTable(data []byte) (map[string]string, []byte, error) {
+	content, rest, err := amqpLongString(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	table := make(map[string]string)
+	fields := []byte(content)
+
+	for len(fields) > 0 {
+		length := int(fields[0])
+		if len(fields) < 2+length {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+
+		name, kind := string(fields[1:1+length]), fields[1+length]
+		fields = fields[2+length:]
+
+		switch kind {
+		case 'S', 'x', 'F', 'A':
+			var value string
+
+			value, fields, err = amqpLongString(fields)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if kind == 'S' {
+				table[name] = value
+			}
+		default:
+			size, ok := amqpFieldSizes[kind]
+			if !ok || len(fields) < size {
+				return nil, nil, fmt.Errorf("invalid amqp field %s", name)
+			}
+
+			fields = fields[size:]
+		}
+	}
+
+	return table, rest, nil
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// BER classes
+const (
+	classUniversal   = 0x00
+	classApplication = 0x40
+	classContext     = 0x80
+)
+
+// BER universal tags
+const (
+	tagBoolean         = 0x01
+	tagInteger         = 0x02
+	tagBitString       = 0x03
+	tagOctetString     = 0x04
+	tagEnumerated      = 0x0a
+	tagSequence        = 0x10
+	tagSet             = 0x11
+	tagGeneralizedTime = 0x18
+	tagGeneralString   = 0x1b
+)
+
+// maxBERLength is the maximum length of the values read from a service
+const maxBERLength = 16 * 1024 * 1024
+
+var errBERLength = errors.New("invalid ber length")
+
+// berValue is a decoded BER value. Lengths are parsed leniently since
+// services like active directory use non minimal long form lengths.
+type berValue struct {
+	class    int
+	tag      int
+	compound bool
+	content  []byte
+}
+
+// berEncode encodes a value with the given identifier
+func berEncode(class, tag int, compound bool, content ...[]byte) []byte {
+	var data []byte
+	for _, c := range content {
+		data = append(data, c...)
+	}
+
+	identifier := byte(class | tag)
+	if compound {
+		identifier |= 0x20
+	}
+
+	encoded := []byte{identifier}
+
+	length := len(data)
+	if length < 0x80 {
+		encoded = append(encoded, byte(length))
+	} else {
+		var lengthBytes []byte
+		for ; length > 0; length >>= 8 {
+			lengthBytes = append([]byte{byte(length)}, lengthBytes...)
+		}
+
+		encoded = append(encoded, 0x80|byte(len(lengthBytes)))
+		encoded = append(encoded, lengthBytes...)
+	}
+
+	return append(encoded, data...)
+}
+
+// berSequence encodes a sequence of encoded values
+func berSequence(values ...[]byte) []byte {
+	return berEncode(classUniversal, tagSequence, true, values...)
+}
+
+// berExplicit encodes a value with an explicit context tag
+func berExplicit(tag int, value []byte) []byte {
+	return berEncode(classContext, tag, true, value)
+}
+
+// berInteger encodes an integer with the given universal tag
+func berInteger(tag int, value int64) []byte {
+	var content []byte
+
+	for {
+		content = append([]byte{byte(value)}, content...)
+
+		if (value < 0x80 && value >= -0x80) || len(content) == 8 {
+			break
+		}
+
+		value >>= 8
+	}
+
+	return berEncode(classUniversal, tag, false, content)
+}
+
+// berString encodes a string with the given universal tag
+func berString(tag int, value string) []byte {
+	return berEncode(classUniversal, tag, false, []byte(value))
+}
+
+// integer decodes the content of an integer value
+func (v *berValue) integer() int64 {
+	var value int64
+
+	for i, b := range v.content {
+		if i == 0 && b&0x80 != 0 {
+			value = -1
+		}
+
+		value = value<<8 | int64(b)
+	}
+
+	return value
+}
+
+// children decodes the values contained in a constructed value
+func (v *berValue) children() ([]*berValue, error) {
+	var values []*berValue
+
+	data := v.content
+
+	for len(data) > 0 {
+		value, rest, err := berDecode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+		data = rest
+	}
+
+	return values, nil
+}
+
+// child returns the child with the given context tag, if any
+func (v *berValue) child(tag int) *berValue {
+	children, err := v.children()
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range children {
+		if child.class == classContext && child.tag == tag {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// explicit returns the value wrapped in an explicit context tag
+func (v *berValue) explicit() *berValue {
+	children, err := v.children()
+	if err != nil || len(children) == 0 {
+		return nil
+	}
+
+	return children[0]
+}
+
+// berDecode decodes the first value of data
+func berDecode(data []byte) (*berValue, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	value := &berValue{class: int(data[0] & 0xc0), tag: int(data[0] & 0x1f), compound: data[0]&0x20 != 0}
+
+	length, offset := int(data[1]), 2
+	if length&0x80 != 0 {
+		count := length & 0x7f
+		if count == 0 || count > 4 || len(data) < 2+count {
+			return nil, nil, errBERLength
+		}
+
+		length = 0
+		for _, b := range data[2 : 2+count] {
+			length = length<<8 | int(b)
+		}
+
+		offset += count
+	}
+
+	if length < 0 || length > len(data)-offset {
+		return nil, nil, errBERLength
+	}
+
+	value.content = data[offset : offset+length]
+
+	return value, data[offset+length:], nil
+}
+
+// berRead reads a complete value from a stream
+func berRead(r *bufio.Reader) (*berValue, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := int(header[1])
+	if length&0x80 != 0 {
+		count := length & 0x7f
+		if count == 0 || count > 4 {
+			return nil, errBERLength
+		}
+
+		lengthBytes := make([]byte, count)
+		if _, err := io.ReadFull(r, lengthBytes); err != nil {
+			return nil, err
+		}
+
+		header = append(header, lengthBytes...)
+
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+
+	if length < 0 || length > maxBERLength {
+		return nil, errBERLength
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+
+	value, _, err := berDecode(append(header, content...))
+
+	return value, err
+}
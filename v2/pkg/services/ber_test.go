@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBERInteger(t *testing.T) {
+	tests := []struct {
+		value   int64
+		encoded []byte
+	}{
+		{value: 0, encoded: []byte{0x02, 0x01, 0x00}},
+		{value: 127, encoded: []byte{0x02, 0x01, 0x7f}},
+		{value: 128, encoded: []byte{0x02, 0x02, 0x00, 0x80}},
+		{value: 256, encoded: []byte{0x02, 0x02, 0x01, 0x00}},
+		{value: -1, encoded: []byte{0x02, 0x01, 0xff}},
+		{value: -129, encoded: []byte{0x02, 0x02, 0xff, 0x7f}},
+		{value: 0x12345678, encoded: []byte{0x02, 0x04, 0x12, 0x34, 0x56, 0x78}},
+	}
+
+	for _, test := range tests {
+		encoded := berInteger(tagInteger, test.value)
+		require.Equal(t, test.encoded, encoded, "Could not encode integer %d", test.value)
+
+		value, rest, err := berDecode(encoded)
+		require.Nil(t, err, "Could not decode integer %d", test.value)
+		require.Empty(t, rest, "Could not decode whole integer %d", test.value)
+		require.Equal(t, test.value, value.integer(), "Could not round-trip integer %d", test.value)
+	}
+}
+
+func TestBERDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		class    int
+		tag      int
+		compound bool
+		content  []byte
+		rest     []byte
+		err      bool
+	}{
+		{name: "short form", data: []byte{0x04, 0x02, 'h', 'i', 0xff}, class: classUniversal, tag: tagOctetString, content: []byte("hi"), rest: []byte{0xff}},
+		{name: "long form", data: []byte{0x30, 0x81, 0x01, 0x05}, class: classUniversal, tag: tagSequence, compound: true, content: []byte{0x05}},
+		{name: "non minimal long form", data: []byte{0x61, 0x84, 0x00, 0x00, 0x00, 0x01, 0x00}, class: classApplication, tag: 1, compound: true, content: []byte{0x00}},
+		{name: "context tag", data: []byte{0xa3, 0x00}, class: classContext, tag: 3, compound: true, content: []byte{}},
+		{name: "truncated header", data: []byte{0x04}, err: true},
+		{name: "truncated content", data: []byte{0x04, 0x05, 'h'}, err: true},
+		{name: "indefinite length", data: []byte{0x30, 0x80, 0x00, 0x00}, err: true},
+		{name: "oversized length", data: []byte{0x30, 0x85, 0x01, 0x00, 0x00, 0x00, 0x00}, err: true},
+	}
+
+	for _, test := range tests {
+		value, rest, err := berDecode(test.data)
+		if test.err {
+			require.NotNil(t, err, "Could decode invalid %s value", test.name)
+			continue
+		}
+
+		require.Nil(t, err, "Could not decode valid %s value", test.name)
+		require.Equal(t, test.class, value.class, "Could not decode class of %s value", test.name)
+		require.Equal(t, test.tag, value.tag, "Could not decode tag of %s value", test.name)
+		require.Equal(t, test.compound, value.compound, "Could not decode compound flag of %s value", test.name)
+		require.Equal(t, test.content, value.content, "Could not decode content of %s value", test.name)
+		require.Equal(t, len(test.rest), len(rest), "Could not decode rest of %s value", test.name)
+	}
+}
+
+func TestBERChildren(t *testing.T) {
+	long := strings.Repeat("a", 300)
+
+	encoded := berSequence(
+		berExplicit(0, berInteger(tagInteger, 5)),
+		berExplicit(2, berString(tagGeneralString, long)),
+		berString(tagOctetString, "plain"),
+	)
+
+	value, rest, err := berDecode(encoded)
+	require.Nil(t, err, "Could not decode sequence")
+	require.Empty(t, rest, "Could not decode whole sequence")
+
+	children, err := value.children()
+	require.Nil(t, err, "Could not decode children")
+	require.Len(t, children, 3, "Could not decode all children")
+
+	tests := []struct {
+		tag   int
+		found bool
+	}{
+		{tag: 0, found: true},
+		{tag: 1, found: false},
+		{tag: 2, found: true},
+	}
+
+	for _, test := range tests {
+		child := value.child(test.tag)
+		require.Equal(t, test.found, child != nil, "Could not look up child %d", test.tag)
+	}
+
+	require.Equal(t, int64(5), value.child(0).explicit().integer(), "Could not decode explicit integer")
+	require.Equal(t, long, string(value.child(2).explicit().content), "Could not decode explicit long string")
+
+	read, err := berRead(bufio.NewReader(bytes.NewReader(append(encoded, 0x00))))
+	require.Nil(t, err, "Could not read sequence from stream")
+	require.Equal(t, value, read, "Could not read same sequence from stream")
+
+	_, err = berRead(bufio.NewReader(bytes.NewReader(encoded[:len(encoded)-1])))
+	require.NotNil(t, err, "Could read truncated sequence from stream")
+}
@@ -0,0 +1,4 @@
+// Package services probes the network services exposed by a target,
+// like LDAP or Kerberos, and reports the fields of their responses to
+// the matchers.
+package services
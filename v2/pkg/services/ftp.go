@@ -0,0 +1,172 @@
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFTPListing is the maximum size of the directory listings read
+const maxFTPListing = 1024 * 1024
+
+// ftpPassiveAddress parses the port of a passive mode reply
+var ftpPassiveAddress = regexp.MustCompile(`\((\d+),(\d+),(\d+),(\d+),(\d+),(\d+)\)`)
+
+// probeFTP logs in to an ftp server, anonymously unless the username and
+// password options are set, and lists the working directory.
+//
+// The fields are ftp_banner, ftp_login_code, ftp_anonymous, the login
+// succeeding, and ftp_files, the names of the listed files one per line.
+func probeFTP(conn net.Conn, host string, options *Options) (*Response, error) {
+	username, password := options.Values["username"], options.Values["password"]
+	if username == "" {
+		username, password = "anonymous", "anonymous@example.com"
+	}
+
+	control := textproto.NewConn(conn)
+
+	_, banner, err := control.ReadResponse(220)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+	response.Fields["ftp_banner"] = banner
+	response.addLine("%s", banner)
+
+	code, message, err := ftpCommand(control, "USER %s", username)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == 331 {
+		code, message, err = ftpCommand(control, "PASS %s", password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response.Fields["ftp_login_code"] = code
+	response.Fields["ftp_anonymous"] = code == 230 && username == "anonymous"
+	response.addLine("%d %s", code, message)
+
+	if code != 230 {
+		return response, nil
+	}
+
+	listing, err := ftpList(control, conn, options)
+	if err != nil {
+		// the login result is still meaningful without the listing
+		response.addLine("listing failed: %s", err)
+		return response, nil
+	}
+
+	var files []string
+
+	for _, line := range strings.Split(listing, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+
+		response.addLine("%s", line)
+
+		if name := ftpFileName(line); name != "" {
+			files = append(files, name)
+		}
+	}
+
+	response.Fields["ftp_files"] = strings.Join(files, "\n")
+
+	return response, nil
+}
+
+// ftpCommand sends a command and returns its reply
+func ftpCommand(control *textproto.Conn, format string, args ...interface{}) (int, string, error) {
+	id, err := control.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+
+	control.StartResponse(id)
+	defer control.EndResponse(id)
+
+	return control.ReadResponse(0)
+}
+
+// ftpList lists the working directory over a passive data connection. The
+// data connection is made to the host of the control connection rather than
+// the advertised address.
+func ftpList(control *textproto.Conn, conn net.Conn, options *Options) (string, error) {
+	code, message, err := ftpCommand(control, "PASV")
+	if err != nil {
+		return "", err
+	}
+
+	matches := ftpPassiveAddress.FindStringSubmatch(message)
+	if code != 227 || matches == nil {
+		return "", fmt.Errorf("passive mode refused: %d %s", code, message)
+	}
+
+	high, _ := strconv.Atoi(matches[5])
+	low, _ := strconv.Atoi(matches[6])
+
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return "", err
+	}
+
+	data, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(high*256+low)), options.Timeout)
+	if err != nil {
+		return "", err
+	}
+	defer data.Close()
+
+	if options.TLS {
+		data = tls.Client(data, &tls.Config{InsecureSkipVerify: true}) // nolint:gosec // services are probed regardless of their certificate
+	}
+
+	if err := data.SetDeadline(time.Now().Add(options.Timeout)); err != nil {
+		return "", err
+	}
+
+	code, message, err = ftpCommand(control, "LIST")
+	if err != nil {
+		return "", err
+	}
+
+	if code != 125 && code != 150 {
+		return "", fmt.Errorf("listing refused: %d %s", code, message)
+	}
+
+	listing, err := ioutil.ReadAll(io.LimitReader(data, maxFTPListing))
+	if err != nil {
+		return "", err
+	}
+
+	// the transfer completion reply
+	_, _, _ = control.ReadResponse(0)
+
+	return string(listing), nil
+}
+
+// ftpFileName returns the name of the file of a unix or windows listing line
+func ftpFileName(line string) string {
+	fields := strings.Fields(line)
+
+	switch {
+	case len(fields) >= 9:
+		return strings.Join(fields[8:], " ")
+	case len(fields) >= 4:
+		return strings.Join(fields[3:], " ")
+	}
+
+	return ""
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"net"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/jarm"
+)
+
+// probeJARM computes the tls fingerprints of a server, the first probe
+// being sent over the established connection.
+//
+// The fields are jarm and ja3s, the fingerprint of the server hello
+// answering the first probe.
+func probeJARM(conn net.Conn, host string, options *Options) (*Response, error) {
+	address := conn.RemoteAddr().String()
+	first := conn
+
+	dial := func() (net.Conn, error) {
+		if first != nil {
+			c := first
+			first = nil
+
+			return nopCloser{c}, nil
+		}
+
+		c, err := net.DialTimeout("tcp", address, options.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		return c, c.SetDeadline(time.Now().Add(options.Timeout))
+	}
+
+	result, err := jarm.Fingerprint(dial, host)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+	response.Fields["jarm"] = result.JARM
+	response.Fields["ja3s"] = result.JA3S
+	response.addLine("jarm: %s", result.JARM)
+	response.addLine("ja3s: %s", result.JA3S)
+
+	for _, raw := range result.Raw {
+		response.addLine("%s", raw)
+	}
+
+	return response, nil
+}
+
+// nopCloser leaves the closing of a connection to its owner
+type nopCloser struct {
+	net.Conn
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
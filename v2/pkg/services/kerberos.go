@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kerberos message types
+const (
+	kerberosASReq    = 10
+	kerberosASRep    = 11
+	kerberosKRBError = 30
+)
+
+// kerberosErrors contains the names of the common kerberos error codes
+var kerberosErrors = map[int64]string{
+	6:  "KDC_ERR_C_PRINCIPAL_UNKNOWN",
+	7:  "KDC_ERR_S_PRINCIPAL_UNKNOWN",
+	14: "KDC_ERR_ETYPE_NOSUPP",
+	18: "KDC_ERR_CLIENT_REVOKED",
+	24: "KDC_ERR_PREAUTH_FAILED",
+	25: "KDC_ERR_PREAUTH_REQUIRED",
+	37: "KRB_AP_ERR_SKEW",
+	68: "KDC_ERR_WRONG_REALM",
+}
+
+// maxKerberosMessage is the maximum size of the kerberos responses read
+const maxKerberosMessage = 64 * 1024
+
+// probeKerberos sends an AS-REQ for a random principal to a kdc and reports
+// the error returned. The realm is the realm option, or is derived from the
+// host name.
+//
+// The fields are kerberos_error_code, kerberos_error, kerberos_realm and
+// kerberos_as_rep, reporting a kdc answering without pre-authentication.
+func probeKerberos(conn net.Conn, host string, options *Options) (*Response, error) {
+	realm := strings.ToUpper(options.Values["realm"])
+	if realm == "" {
+		realm = kerberosRealm(host)
+	}
+
+	if _, err := conn.Write(kerberosFrame(kerberosASRequest(realm, "nuclei"+strconv.Itoa(rand.Intn(1000000))))); err != nil {
+		return nil, err
+	}
+
+	var length uint32
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+
+	if length > maxKerberosMessage {
+		return nil, fmt.Errorf("kerberos message too large")
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return nil, err
+	}
+
+	message, _, err := berDecode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+	response.Fields["kerberos_as_rep"] = message.class == classApplication && message.tag == kerberosASRep
+
+	if message.class != classApplication || message.tag != kerberosKRBError {
+		response.addLine("kerberos message: %d", message.tag)
+		return response, nil
+	}
+
+	body := message.explicit()
+	if body == nil {
+		return nil, fmt.Errorf("invalid kerberos error")
+	}
+
+	if code := body.child(6); code != nil && code.explicit() != nil {
+		errorCode := code.explicit().integer()
+
+		response.Fields["kerberos_error_code"] = errorCode
+		response.Fields["kerberos_error"] = kerberosErrors[errorCode]
+		response.addLine("error: %d %s", errorCode, kerberosErrors[errorCode])
+	}
+
+	if realm := body.child(9); realm != nil && realm.explicit() != nil {
+		response.Fields["kerberos_realm"] = string(realm.explicit().content)
+		response.addLine("realm: %s", realm.explicit().content)
+	}
+
+	return response, nil
+}
+
+// kerberosRealm derives a realm from a host name, dropping its first label
+func kerberosRealm(host string) string {
+	if net.ParseIP(host) != nil {
+		return "WORKGROUP"
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) > 2 {
+		labels = labels[1:]
+	}
+
+	return strings.ToUpper(strings.Join(labels, "."))
+}
+
+// kerberosASRequest encodes an AS-REQ without pre-authentication
+func kerberosASRequest(realm, principal string) []byte {
+	principalName := func(nameType int64, names ...string) []byte {
+		var values [][]byte
+		for _, name := range names {
+			values = append(values, berString(tagGeneralString, name))
+		}
+
+		return berSequence(
+			berExplicit(0, berInteger(tagInteger, nameType)),
+			berExplicit(1, berSequence(values...)),
+		)
+	}
+
+	till := time.Now().Add(24 * time.Hour).UTC().Format("20060102150405Z")
+
+	body := berSequence(
+		// forwardable, renewable and renewable-ok
+		berExplicit(0, berEncode(classUniversal, tagBitString, false, []byte{0x00, 0x40, 0x80, 0x00, 0x10})),
+		berExplicit(1, principalName(1, principal)),
+		berExplicit(2, berString(tagGeneralString, realm)),
+		berExplicit(3, principalName(2, "krbtgt", realm)),
+		berExplicit(5, berString(tagGeneralizedTime, till)),
+		berExplicit(7, berInteger(tagInteger, int64(rand.Int31()))),
+		// aes256, aes128 and rc4
+		berExplicit(8, berSequence(berInteger(tagInteger, 18), berInteger(tagInteger, 17), berInteger(tagInteger, 23))),
+	)
+
+	return berEncode(classApplication, kerberosASReq, true, berSequence(
+		berExplicit(1, berInteger(tagInteger, 5)),
+		berExplicit(2, berInteger(tagInteger, kerberosASReq)),
+		berExplicit(4, body),
+	))
+}
+
+// kerberosFrame prefixes a message with its length for the tcp transport
+func kerberosFrame(message []byte) []byte {
+	frame := make([]byte, 4, 4+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+
+	return append(frame, message...)
+}
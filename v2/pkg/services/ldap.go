@@ -0,0 +1,189 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// LDAP protocol operations
+const (
+	ldapBindRequest       = 0
+	ldapBindResponse      = 1
+	ldapSearchRequest     = 3
+	ldapSearchResultEntry = 4
+	ldapSearchResultDone  = 5
+)
+
+// maxLDAPMessages is the maximum number of messages read for the rootDSE search
+const maxLDAPMessages = 16
+
+// rootDSEAttributes contains the attributes read from the rootDSE
+var rootDSEAttributes = []string{
+	"namingContexts",
+	"defaultNamingContext",
+	"rootDomainNamingContext",
+	"configurationNamingContext",
+	"schemaNamingContext",
+	"subschemaSubentry",
+	"dnsHostName",
+	"serverName",
+	"ldapServiceName",
+	"supportedLDAPVersion",
+	"supportedSASLMechanisms",
+	"supportedControl",
+	"supportedExtension",
+	"domainFunctionality",
+	"forestFunctionality",
+	"domainControllerFunctionality",
+	"isGlobalCatalogReady",
+	"vendorName",
+	"vendorVersion",
+}
+
+// probeLDAP binds anonymously to an ldap server and reads its rootDSE.
+//
+// The fields are ldap_bind_result, ldap_anonymous_bind, ldap_search_result
+// and every rootDSE attribute returned, lowercased and prefixed with ldap_.
+func probeLDAP(conn net.Conn, host string, options *Options) (*Response, error) {
+	response := newResponse()
+	reader := bufio.NewReader(conn)
+
+	bind := berEncode(classApplication, ldapBindRequest, true,
+		berInteger(tagInteger, 3),
+		berString(tagOctetString, ""),
+		berEncode(classContext, 0, false, nil),
+	)
+
+	result, err := ldapExchange(conn, reader, 1, bind, ldapBindResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Fields["ldap_bind_result"] = result
+	response.Fields["ldap_anonymous_bind"] = result == 0
+	response.addLine("bind result: %d", result)
+
+	attributes := make([][]byte, 0, len(rootDSEAttributes))
+	for _, attribute := range rootDSEAttributes {
+		attributes = append(attributes, berString(tagOctetString, attribute))
+	}
+
+	search := berEncode(classApplication, ldapSearchRequest, true,
+		berString(tagOctetString, ""),
+		berInteger(tagEnumerated, 0),
+		berInteger(tagEnumerated, 0),
+		berInteger(tagInteger, 0),
+		berInteger(tagInteger, 0),
+		berEncode(classUniversal, tagBoolean, false, []byte{0x00}),
+		berEncode(classContext, 7, false, []byte("objectClass")),
+		berSequence(attributes...),
+	)
+
+	if _, err := conn.Write(berSequence(berInteger(tagInteger, 2), search)); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxLDAPMessages; i++ {
+		op, err := ldapReadOperation(reader)
+		if err != nil {
+			// the bind result is still meaningful without the rootDSE
+			return response, nil
+		}
+
+		switch op.tag {
+		case ldapSearchResultEntry:
+			ldapAddAttributes(response, op)
+		case ldapSearchResultDone:
+			children, err := op.children()
+			if err == nil && len(children) > 0 {
+				response.Fields["ldap_search_result"] = children[0].integer()
+				response.addLine("search result: %d", children[0].integer())
+			}
+
+			return response, nil
+		}
+	}
+
+	return response, nil
+}
+
+// ldapExchange sends a request and returns the result code of its response
+func ldapExchange(conn net.Conn, reader *bufio.Reader, id int64, request []byte, responseOp int) (int64, error) {
+	if _, err := conn.Write(berSequence(berInteger(tagInteger, id), request)); err != nil {
+		return 0, err
+	}
+
+	op, err := ldapReadOperation(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	if op.tag != responseOp {
+		return 0, fmt.Errorf("unexpected ldap operation %d", op.tag)
+	}
+
+	children, err := op.children()
+	if err != nil || len(children) == 0 {
+		return 0, fmt.Errorf("invalid ldap response")
+	}
+
+	return children[0].integer(), nil
+}
+
+// ldapReadOperation reads a message and returns its protocol operation
+func ldapReadOperation(reader *bufio.Reader) (*berValue, error) {
+	message, err := berRead(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := message.children()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(children) < 2 || children[1].class != classApplication {
+		return nil, fmt.Errorf("invalid ldap message")
+	}
+
+	return children[1], nil
+}
+
+// ldapAddAttributes adds the attributes of a search result entry to a response
+func ldapAddAttributes(response *Response, entry *berValue) {
+	children, err := entry.children()
+	if err != nil || len(children) < 2 {
+		return
+	}
+
+	attributes, err := children[1].children()
+	if err != nil {
+		return
+	}
+
+	for _, attribute := range attributes {
+		parts, err := attribute.children()
+		if err != nil || len(parts) < 2 {
+			continue
+		}
+
+		values, err := parts[1].children()
+		if err != nil {
+			continue
+		}
+
+		var texts []string
+		for _, value := range values {
+			texts = append(texts, string(value.content))
+		}
+
+		name := string(parts[0].content)
+		response.Fields["ldap_"+strings.ToLower(name)] = strings.Join(texts, " ")
+
+		for _, text := range texts {
+			response.addLine("%s: %s", name, text)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strings"
+)
+
+// mongodbOpMsg is the opcode of the extensible messages
+const mongodbOpMsg = 2013
+
+// maxMongoDBMessage is the maximum size of the mongodb messages read
+const maxMongoDBMessage = 16 * 1024 * 1024
+
+// probeMongoDB reads the build information of a mongodb server and lists
+// its databases, which requires authentication unless disabled.
+//
+// The fields are mongodb_version, mongodb_unauthenticated, the databases
+// being listed without authentication, mongodb_databases, their names one
+// per line, and mongodb_error.
+func probeMongoDB(conn net.Conn, host string, options *Options) (*Response, error) {
+	response := newResponse()
+
+	buildInfo, err := mongodbCommand(conn, 1, "buildInfo")
+	if err != nil {
+		return nil, err
+	}
+
+	if version, ok := buildInfo["version"].(string); ok {
+		response.Fields["mongodb_version"] = version
+		response.addLine("version: %s", version)
+	}
+
+	databases, err := mongodbCommand(conn, 2, "listDatabases")
+	if err != nil {
+		return nil, err
+	}
+
+	unauthenticated := mongodbOK(databases)
+
+	response.Fields["mongodb_unauthenticated"] = unauthenticated
+	response.addLine("unauthenticated: %v", unauthenticated)
+
+	if !unauthenticated {
+		if message, ok := databases["errmsg"].(string); ok {
+			response.Fields["mongodb_error"] = message
+			response.addLine("error: %s", message)
+		}
+
+		return response, nil
+	}
+
+	var names []string
+
+	if list, ok := databases["databases"].(map[string]interface{}); ok {
+		for _, database := range list {
+			if document, ok := database.(map[string]interface{}); ok {
+				if name, ok := document["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	response.Fields["mongodb_databases"] = strings.Join(names, "\n")
+	for _, name := range names {
+		response.addLine("database: %s", name)
+	}
+
+	return response, nil
+}
+
+// mongodbOK returns true if a command succeeded
+func mongodbOK(document map[string]interface{}) bool {
+	switch ok := document["ok"].(type) {
+	case float64:
+		return ok == 1
+	case int32:
+		return ok == 1
+	case int64:
+		return ok == 1
+	}
+
+	return false
+}
+
+// mongodbCommand runs a command on the admin database
+func mongodbCommand(conn net.Conn, requestID int32, command string) (map[string]interface{}, error) {
+	var document bytes.Buffer
+
+	document.Write([]byte{0x10})
+	document.WriteString(command + "\x00")
+	_ = binary.Write(&document, binary.LittleEndian, int32(1))
+	document.Write([]byte{0x02})
+	document.WriteString("$db\x00")
+	_ = binary.Write(&document, binary.LittleEndian, int32(len("admin")+1))
+	document.WriteString("admin\x00")
+	document.WriteByte(0x00)
+
+	documentBytes := make([]byte, 4, 4+document.Len())
+	binary.LittleEndian.PutUint32(documentBytes, uint32(4+document.Len()))
+	documentBytes = append(documentBytes, document.Bytes()...)
+
+	// header, flags and a body section
+	message := make([]byte, 21, 21+len(documentBytes))
+	binary.LittleEndian.PutUint32(message[0:], uint32(21+len(documentBytes)))
+	binary.LittleEndian.PutUint32(message[4:], uint32(requestID))
+	binary.LittleEndian.PutUint32(message[12:], mongodbOpMsg)
+	message = append(message, documentBytes...)
+
+	if _, err := conn.Write(message); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+
+	length := binary.LittleEndian.Uint32(header)
+	if length < 21 || length > maxMongoDBMessage || binary.LittleEndian.Uint32(header[12:]) != mongodbOpMsg {
+		return nil, fmt.Errorf("not a mongodb server")
+	}
+
+	body := make([]byte, length-16)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+
+	if body[4] != 0x00 {
+		return nil, fmt.Errorf("unexpected mongodb section %d", body[4])
+	}
+
+	reply, _, err := bsonDecode(body[5:])
+
+	return reply, err
+}
+
+// bsonDecode decodes a bson document, arrays being decoded as documents
+// keyed by the indexes of their elements.
+func bsonDecode(data []byte) (map[string]interface{}, []byte, error) {
+	errInvalid := fmt.Errorf("invalid bson document")
+
+	if len(data) < 5 {
+		return nil, nil, errInvalid
+	}
+
+	length := int(binary.LittleEndian.Uint32(data))
+	if length < 5 || length > len(data) {
+		return nil, nil, errInvalid
+	}
+
+	document := make(map[string]interface{})
+	elements, rest := data[4:length-1], data[length:]
+
+	for len(elements) > 0 {
+		kind := elements[0]
+
+		end := bytes.IndexByte(elements[1:], 0x00)
+		if end < 0 {
+			return nil, nil, errInvalid
+		}
+
+		name := string(elements[1 : 1+end])
+		value := elements[2+end:]
+
+		var size int
+
+		switch kind {
+		case 0x01: // double
+			size = 8
+			if len(value) >= size {
+				document[name] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+			}
+		case 0x02: // string
+			if len(value) < 4 {
+				return nil, nil, errInvalid
+			}
+
+			size = 4 + int(binary.LittleEndian.Uint32(value))
+			if size > 4 && len(value) >= size {
+				document[name] = string(value[4 : size-1])
+			}
+		case 0x03, 0x04: // document and array
+			nested, remaining, err := bsonDecode(value)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			document[name] = nested
+			size = len(value) - len(remaining)
+		case 0x05: // binary
+			if len(value) < 4 {
+				return nil, nil, errInvalid
+			}
+
+			size = 5 + int(binary.LittleEndian.Uint32(value))
+		case 0x07: // object id
+			size = 12
+		case 0x08: // boolean
+			size = 1
+			if len(value) >= size {
+				document[name] = value[0] == 0x01
+			}
+		case 0x09, 0x11: // datetime and timestamp
+			size = 8
+		case 0x0a: // null
+			size = 0
+		case 0x10: // int32
+			size = 4
+			if len(value) >= size {
+				document[name] = int32(binary.LittleEndian.Uint32(value))
+			}
+		case 0x12: // int64
+			size = 8
+			if len(value) >= size {
+				document[name] = int64(binary.LittleEndian.Uint64(value))
+			}
+		case 0x13: // decimal128
+			size = 16
+		default:
+			return nil, nil, fmt.Errorf("unsupported bson type %d", kind)
+		}
+
+		if size < 0 || size > len(value) {
+			return nil, nil, errInvalid
+		}
+
+		elements = value[size:]
+	}
+
+	return document, rest, nil
+}
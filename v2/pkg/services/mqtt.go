@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// MQTT control packet types
+const (
+	mqttConnect   = 0x10
+	mqttConnAck   = 0x20
+	mqttPublish   = 0x30
+	mqttSubscribe = 0x82
+	mqttSubAck    = 0x90
+)
+
+// MQTT connect flags
+const (
+	mqttCleanSession = 0x02
+	mqttPassword     = 0x40
+	mqttUsername     = 0x80
+)
+
+// maxMQTTPacket is the maximum size of the mqtt packets read
+const maxMQTTPacket = 1024 * 1024
+
+// maxMQTTMessages is the maximum number of messages read after subscribing
+const maxMQTTMessages = 32
+
+// probeMQTT connects to an mqtt broker, with the username and password
+// options when set, and subscribes to the topic option, # by default,
+// collecting the topics of the messages received during the wait option.
+//
+// The fields are mqtt_return_code, mqtt_connected, mqtt_anonymous, the
+// broker accepting clients without credentials, mqtt_subscribed and
+// mqtt_topics, the topics of the received messages one per line.
+func probeMQTT(conn net.Conn, host string, options *Options) (*Response, error) {
+	username, password := options.Values["username"], options.Values["password"]
+
+	flags := byte(mqttCleanSession)
+	payload := mqttString("nuclei")
+
+	if username != "" {
+		flags |= mqttUsername | mqttPassword
+		payload = append(payload, mqttString(username)...)
+		payload = append(payload, mqttString(password)...)
+	}
+
+	// protocol name, level 4 (3.1.1), flags and a keep alive of 60 seconds
+	variable := append(mqttString("MQTT"), 0x04, flags, 0x00, 0x3c)
+
+	if err := mqttWrite(conn, mqttConnect, append(variable, payload...)); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	kind, data, err := mqttRead(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind&0xf0 != mqttConnAck || len(data) < 2 {
+		return nil, fmt.Errorf("not an mqtt broker")
+	}
+
+	code := int(data[1])
+
+	response := newResponse()
+	response.Fields["mqtt_return_code"] = code
+	response.Fields["mqtt_connected"] = code == 0
+	response.Fields["mqtt_anonymous"] = code == 0 && username == ""
+	response.addLine("return code: %d", code)
+
+	if code != 0 {
+		return response, nil
+	}
+
+	topic := options.Values["topic"]
+	if topic == "" {
+		topic = "#"
+	}
+
+	// packet identifier, topic filter and qos 0
+	subscribe := append([]byte{0x00, 0x01}, mqttString(topic)...)
+	if err := mqttWrite(conn, mqttSubscribe, append(subscribe, 0x00)); err != nil {
+		return nil, err
+	}
+
+	wait := defaultBannerWait
+	if value, err := time.ParseDuration(options.Values["wait"]); err == nil {
+		wait = value
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(wait)); err != nil {
+		return nil, err
+	}
+
+	topics := make(map[string]struct{})
+
+	var received []string
+
+	subscribed := false
+
+	for i := 0; i < maxMQTTMessages; i++ {
+		kind, data, err := mqttRead(reader)
+		if err != nil {
+			break
+		}
+
+		switch kind & 0xf0 {
+		case mqttSubAck:
+			subscribed = len(data) >= 3 && data[2] < 0x80
+			response.addLine("subscribed to %s: %v", topic, subscribed)
+		case mqttPublish:
+			if len(data) < 2 {
+				continue
+			}
+
+			length := int(binary.BigEndian.Uint16(data))
+			if len(data) < 2+length {
+				continue
+			}
+
+			name := string(data[2 : 2+length])
+			if _, ok := topics[name]; !ok {
+				topics[name] = struct{}{}
+				received = append(received, name)
+				response.addLine("message on %s", name)
+			}
+		}
+	}
+
+	response.Fields["mqtt_subscribed"] = subscribed
+	response.Fields["mqtt_topics"] = strings.Join(received, "\n")
+
+	return response, nil
+}
+
+// mqttString encodes a length prefixed string
+func mqttString(value string) []byte {
+	encoded := make([]byte, 2, 2+len(value))
+	binary.BigEndian.PutUint16(encoded, uint16(len(value)))
+
+	return append(encoded, value...)
+}
+
+// mqttWrite writes a control packet
+func mqttWrite(conn net.Conn, kind byte, data []byte) error {
+	packet := []byte{kind}
+
+	// the remaining length is a variable length integer
+	length := len(data)
+	for {
+		b := byte(length % 128)
+		length /= 128
+
+		if length > 0 {
+			b |= 0x80
+		}
+
+		packet = append(packet, b)
+
+		if length == 0 {
+			break
+		}
+	}
+
+	_, err := conn.Write(append(packet, data...))
+
+	return err
+}
+
+// mqttRead reads a control packet and returns its first byte and data
+func mqttRead(reader *bufio.Reader) (byte, []byte, error) {
+	kind, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, fmt.Errorf("invalid mqtt remaining length")
+		}
+
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		length += int(b&0x7f) * multiplier
+		multiplier *= 128
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	if length > maxMQTTPacket {
+		return 0, nil, fmt.Errorf("mqtt packet too large")
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return 0, nil, err
+	}
+
+	return kind, data, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// TDS packet types
+const (
+	tdsTabularResult = 0x04
+	tdsPrelogin      = 0x12
+)
+
+// Prelogin option tokens
+const (
+	preloginVersion    = 0x00
+	preloginEncryption = 0x01
+	preloginTerminator = 0xff
+)
+
+// mssqlEncryptions contains the names of the encryption modes
+var mssqlEncryptions = map[byte]string{
+	0x00: "off",
+	0x01: "on",
+	0x02: "not-supported",
+	0x03: "required",
+}
+
+// probeMSSQL sends a prelogin to a sql server. Logins aren't attempted as
+// they need the tls handshake to be tunneled in tds packets.
+//
+// The fields are mssql_version and mssql_encryption, the encryption mode of
+// the server (off, on, not-supported or required).
+func probeMSSQL(conn net.Conn, host string, options *Options) (*Response, error) {
+	// the version, encryption, instance, thread id and mars options
+	tokens := []struct {
+		token  byte
+		length int
+	}{{0x00, 6}, {0x01, 1}, {0x02, 1}, {0x03, 4}, {0x04, 1}}
+
+	offset := len(tokens)*5 + 1
+
+	var header, data []byte
+	for _, token := range tokens {
+		header = append(header, token.token, byte(offset>>8), byte(offset), byte(token.length>>8), byte(token.length))
+		data = append(data, make([]byte, token.length)...)
+		offset += token.length
+	}
+
+	payload := append(append(header, preloginTerminator), data...)
+
+	packet := []byte{tdsPrelogin, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	binary.BigEndian.PutUint16(packet[2:], uint16(len(packet)+len(payload)))
+
+	if _, err := conn.Write(append(packet, payload...)); err != nil {
+		return nil, err
+	}
+
+	responseHeader := make([]byte, 8)
+	if _, err := io.ReadFull(conn, responseHeader); err != nil {
+		return nil, err
+	}
+
+	length := int(binary.BigEndian.Uint16(responseHeader[2:]))
+	if responseHeader[0] != tdsTabularResult || length < 8 {
+		return nil, fmt.Errorf("not a sql server")
+	}
+
+	body := make([]byte, length-8)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+
+	for i := 0; i+5 <= len(body) && body[i] != preloginTerminator; i += 5 {
+		start := int(binary.BigEndian.Uint16(body[i+1:]))
+		size := int(binary.BigEndian.Uint16(body[i+3:]))
+
+		if start+size > len(body) {
+			return nil, fmt.Errorf("invalid prelogin response")
+		}
+
+		value := body[start : start+size]
+
+		switch body[i] {
+		case preloginVersion:
+			if len(value) >= 4 {
+				version := fmt.Sprintf("%d.%d.%d", value[0], value[1], binary.BigEndian.Uint16(value[2:]))
+
+				response.Fields["mssql_version"] = version
+				response.addLine("version: %s", version)
+			}
+		case preloginEncryption:
+			if len(value) >= 1 {
+				response.Fields["mssql_encryption"] = mssqlEncryptions[value[0]]
+				response.addLine("encryption: %s", mssqlEncryptions[value[0]])
+			}
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha1" // nolint:gosec // required by the mysql_native_password authentication
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// MySQL capabilities sent by the client
+const (
+	mysqlClientLongPassword   = 0x00000001
+	mysqlClientProtocol41     = 0x00000200
+	mysqlClientSecureConn     = 0x00008000
+	mysqlClientPluginAuth     = 0x00080000
+	mysqlClientMaxPacketSize  = 0x01000000
+	mysqlClientCharsetUTF8MB4 = 45
+)
+
+// MySQL packet markers
+const (
+	mysqlOK         = 0x00
+	mysqlAuthMore   = 0x01
+	mysqlAuthSwitch = 0xfe
+	mysqlError      = 0xff
+)
+
+// maxMySQLPacket is the maximum size of the mysql packets read
+const maxMySQLPacket = 1024 * 1024
+
+// probeMySQL reads the handshake of a mysql server and logs in when the
+// username option is set.
+//
+// The fields are mysql_version, mysql_auth_plugin, mysql_error, set when the
+// server refuses the client, and mysql_login, the login succeeding.
+func probeMySQL(conn net.Conn, host string, options *Options) (*Response, error) {
+	packet, _, err := mysqlRead(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+
+	if packet[0] == mysqlError {
+		message := mysqlErrorMessage(packet)
+
+		response.Fields["mysql_error"] = message
+		response.addLine("error: %s", message)
+
+		return response, nil
+	}
+
+	if packet[0] != 10 {
+		return nil, fmt.Errorf("unsupported mysql protocol version %d", packet[0])
+	}
+
+	version, rest := mysqlNullString(packet[1:])
+
+	// connection id, first part of the salt and filler
+	if len(rest) < 4+8+1+2+1+2+2+1+10 {
+		return nil, fmt.Errorf("invalid mysql handshake")
+	}
+
+	salt := append([]byte{}, rest[4:12]...)
+	rest = rest[13+2+1+2+2:]
+
+	saltLength := int(rest[0])
+	rest = rest[11:]
+
+	// the second part of the salt is at least 13 bytes, null terminated
+	secondLength := saltLength - 8
+	if secondLength < 13 {
+		secondLength = 13
+	}
+
+	if len(rest) < secondLength {
+		secondLength = len(rest)
+	}
+
+	salt = append(salt, bytes.TrimRight(rest[:secondLength], "\x00")...)
+	plugin, _ := mysqlNullString(rest[secondLength:])
+
+	response.Fields["mysql_version"] = version
+	response.Fields["mysql_auth_plugin"] = plugin
+	response.addLine("version: %s", version)
+	response.addLine("auth plugin: %s", plugin)
+
+	username := options.Values["username"]
+	if username == "" {
+		return response, nil
+	}
+
+	login, err := mysqlLogin(conn, username, options.Values["password"], plugin, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Fields["mysql_login"] = login
+	response.addLine("login %s: %v", username, login)
+
+	return response, nil
+}
+
+// mysqlLogin sends the handshake response and follows the authentication
+// switches up to the result of the login.
+func mysqlLogin(conn net.Conn, username, password, plugin string, salt []byte) (bool, error) {
+	if plugin != "caching_sha2_password" {
+		plugin = "mysql_native_password"
+	}
+
+	authData := mysqlScramble(plugin, password, salt)
+
+	payload := make([]byte, 32)
+	binary.LittleEndian.PutUint32(payload, mysqlClientLongPassword|mysqlClientProtocol41|mysqlClientSecureConn|mysqlClientPluginAuth)
+	binary.LittleEndian.PutUint32(payload[4:], mysqlClientMaxPacketSize)
+	payload[8] = mysqlClientCharsetUTF8MB4
+
+	payload = append(payload, username...)
+	payload = append(payload, 0x00, byte(len(authData)))
+	payload = append(payload, authData...)
+	payload = append(payload, plugin...)
+	payload = append(payload, 0x00)
+
+	sequence := byte(1)
+
+	for {
+		if err := mysqlWrite(conn, sequence, payload); err != nil {
+			return false, err
+		}
+
+		packet, received, err := mysqlRead(conn)
+		if err != nil {
+			return false, err
+		}
+
+		sequence = received + 1
+
+		switch packet[0] {
+		case mysqlOK:
+			return true, nil
+		case mysqlError:
+			return false, nil
+		case mysqlAuthSwitch:
+			var newSalt []byte
+
+			plugin, newSalt = mysqlNullString(packet[1:])
+			if plugin != "mysql_native_password" && plugin != "caching_sha2_password" {
+				return false, fmt.Errorf("unsupported mysql auth plugin %s", plugin)
+			}
+
+			payload = mysqlScramble(plugin, password, bytes.TrimRight(newSalt, "\x00"))
+		case mysqlAuthMore:
+			// caching_sha2_password fast authentication succeeded
+			if len(packet) > 1 && packet[1] == 0x03 {
+				packet, _, err = mysqlRead(conn)
+				if err != nil {
+					return false, err
+				}
+
+				return packet[0] == mysqlOK, nil
+			}
+
+			// the full authentication needs a secure connection
+			return false, fmt.Errorf("mysql full authentication is not supported")
+		default:
+			return false, fmt.Errorf("unexpected mysql packet %d", packet[0])
+		}
+	}
+}
+
+// mysqlScramble computes the authentication data of a password
+func mysqlScramble(plugin, password string, salt []byte) []byte {
+	if password == "" {
+		return nil
+	}
+
+	if plugin == "caching_sha2_password" {
+		// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + salt)
+		first := sha256.Sum256([]byte(password))
+		second := sha256.Sum256(first[:])
+		third := sha256.Sum256(append(second[:], salt...))
+
+		for i := range first {
+			first[i] ^= third[i]
+		}
+
+		return first[:]
+	}
+
+	// SHA1(password) XOR SHA1(salt + SHA1(SHA1(password)))
+	first := sha1.Sum([]byte(password))                                // nolint:gosec // required by the protocol
+	second := sha1.Sum(first[:])                                       // nolint:gosec // required by the protocol
+	third := sha1.Sum(append(append([]byte{}, salt...), second[:]...)) // nolint:gosec // required by the protocol
+
+	for i := range first {
+		first[i] ^= third[i]
+	}
+
+	return first[:]
+}
+
+// mysqlRead reads a packet and returns its payload and sequence number
+func mysqlRead(conn net.Conn) ([]byte, byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, 0, err
+	}
+
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if length == 0 || length > maxMySQLPacket {
+		return nil, 0, fmt.Errorf("invalid mysql packet length")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return nil, 0, err
+	}
+
+	return payload, header[3], nil
+}
+
+// mysqlWrite writes a packet
+func mysqlWrite(conn net.Conn, sequence byte, payload []byte) error {
+	length := len(payload)
+	header := []byte{byte(length), byte(length >> 8), byte(length >> 16), sequence}
+
+	_, err := conn.Write(append(header, payload...))
+
+	return err
+}
+
+// mysqlNullString returns a null terminated string and the data after it
+func mysqlNullString(data []byte) (string, []byte) {
+	end := bytes.IndexByte(data, 0x00)
+	if end < 0 {
+		return string(data), nil
+	}
+
+	return string(data[:end]), data[end+1:]
+}
+
+// mysqlErrorMessage returns the message of an error packet
+func mysqlErrorMessage(packet []byte) string {
+	if len(packet) < 3 {
+		return ""
+	}
+
+	code := binary.LittleEndian.Uint16(packet[1:3])
+	message := packet[3:]
+
+	// the sql state marker and state are only sent after the handshake
+	if len(message) > 6 && message[0] == '#' {
+		message = message[6:]
+	}
+
+	return fmt.Sprintf("%d %s", code, message)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/md5" // nolint:gosec // required by the md5 password authentication
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PostgreSQL authentication requests
+const (
+	postgresAuthOK           = 0
+	postgresAuthCleartext    = 3
+	postgresAuthMD5          = 5
+	postgresAuthSASL         = 10
+	postgresAuthSASLContinue = 11
+	postgresAuthSASLFinal    = 12
+)
+
+// postgresProtocolVersion is the version 3.0 of the protocol
+const postgresProtocolVersion = 196608
+
+// maxPostgresMessage is the maximum size of the postgres messages read
+const maxPostgresMessage = 1024 * 1024
+
+// postgresAuthMethods contains the names of the authentication requests
+var postgresAuthMethods = map[uint32]string{
+	postgresAuthOK:        "trust",
+	postgresAuthCleartext: "password",
+	postgresAuthMD5:       "md5",
+	postgresAuthSASL:      "scram-sha-256",
+}
+
+// probePostgres starts a session on a postgres server as the username
+// option, postgres by default, and authenticates with the password option.
+//
+// The fields are postgres_auth, the authentication requested by the server,
+// postgres_login, the session being started, postgres_version, set when
+// logged in, and postgres_error.
+func probePostgres(conn net.Conn, host string, options *Options) (*Response, error) {
+	username := options.Values["username"]
+	if username == "" {
+		username = "postgres"
+	}
+
+	database := options.Values["database"]
+	if database == "" {
+		database = username
+	}
+
+	startup := make([]byte, 8)
+	binary.BigEndian.PutUint32(startup[4:], postgresProtocolVersion)
+	startup = append(startup, "user\x00"+username+"\x00database\x00"+database+"\x00\x00"...)
+	binary.BigEndian.PutUint32(startup, uint32(len(startup)))
+
+	if _, err := conn.Write(startup); err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+	password := options.Values["password"]
+
+	var scram *postgresSCRAM
+
+	for {
+		kind, payload, err := postgresRead(conn)
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case 'E':
+			message := postgresErrorMessage(payload)
+
+			response.Fields["postgres_login"] = false
+			response.Fields["postgres_error"] = message
+			response.addLine("error: %s", message)
+
+			return response, nil
+		case 'S':
+			parts := strings.SplitN(string(payload), "\x00", 3)
+			if len(parts) >= 2 && parts[0] == "server_version" {
+				response.Fields["postgres_version"] = parts[1]
+				response.addLine("version: %s", parts[1])
+			}
+		case 'Z':
+			// the server is ready for queries
+			return response, nil
+		case 'R':
+			if len(payload) < 4 {
+				return nil, fmt.Errorf("invalid postgres authentication request")
+			}
+
+			method := binary.BigEndian.Uint32(payload)
+
+			if _, ok := response.Fields["postgres_auth"]; !ok {
+				name, ok := postgresAuthMethods[method]
+				if !ok {
+					name = strconv.Itoa(int(method))
+				}
+
+				response.Fields["postgres_auth"] = name
+				response.addLine("authentication: %s", name)
+			}
+
+			var reply []byte
+
+			switch method {
+			case postgresAuthOK:
+				response.Fields["postgres_login"] = true
+				response.addLine("login %s: true", username)
+
+				continue
+			case postgresAuthCleartext:
+				reply = postgresMessage('p', []byte(password+"\x00"))
+			case postgresAuthMD5:
+				if len(payload) < 8 {
+					return nil, fmt.Errorf("invalid postgres md5 salt")
+				}
+
+				inner := md5.Sum([]byte(password + username))                                   // nolint:gosec // required by the protocol
+				outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), payload[4:8]...)) // nolint:gosec // required by the protocol
+
+				reply = postgresMessage('p', []byte("md5"+hex.EncodeToString(outer[:])+"\x00"))
+			case postgresAuthSASL:
+				if !strings.Contains(string(payload[4:]), "SCRAM-SHA-256\x00") {
+					return nil, fmt.Errorf("unsupported postgres sasl mechanisms")
+				}
+
+				scram = newPostgresSCRAM(password)
+				first := scram.clientFirst()
+
+				message := append([]byte("SCRAM-SHA-256\x00"), make([]byte, 4)...)
+				binary.BigEndian.PutUint32(message[len(message)-4:], uint32(len(first)))
+
+				reply = postgresMessage('p', append(message, first...))
+			case postgresAuthSASLContinue:
+				if scram == nil {
+					return nil, fmt.Errorf("unexpected postgres sasl message")
+				}
+
+				final, err := scram.clientFinal(string(payload[4:]))
+				if err != nil {
+					return nil, err
+				}
+
+				reply = postgresMessage('p', []byte(final))
+			case postgresAuthSASLFinal:
+				continue
+			default:
+				return nil, fmt.Errorf("unsupported postgres authentication %d", method)
+			}
+
+			if _, err := conn.Write(reply); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// postgresMessage encodes a message of the given kind
+func postgresMessage(kind byte, payload []byte) []byte {
+	message := []byte{kind, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(message[1:], uint32(len(payload)+4))
+
+	return append(message, payload...)
+}
+
+// postgresRead reads a message and returns its kind and payload
+func postgresRead(conn net.Conn) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[1:])
+	if length < 4 || length > maxPostgresMessage {
+		return 0, nil, fmt.Errorf("invalid postgres message length")
+	}
+
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
+
+// postgresErrorMessage returns the message field of an error response
+func postgresErrorMessage(payload []byte) string {
+	for _, field := range strings.Split(string(payload), "\x00") {
+		if strings.HasPrefix(field, "M") {
+			return field[1:]
+		}
+	}
+
+	return ""
+}
+
+// postgresSCRAM is the client side of a SCRAM-SHA-256 exchange
+type postgresSCRAM struct {
+	password        string
+	nonce           string
+	clientFirstBare string
+}
+
+func newPostgresSCRAM(password string) *postgresSCRAM {
+	nonce := make([]byte, 18)
+	_, _ = rand.Read(nonce)
+
+	return &postgresSCRAM{password: password, nonce: base64.StdEncoding.EncodeToString(nonce)}
+}
+
+// clientFirst returns the client first message, the user being the one of the startup message
+func (s *postgresSCRAM) clientFirst() string {
+	s.clientFirstBare = "n=,r=" + s.nonce
+	return "n,," + s.clientFirstBare
+}
+
+// clientFinal returns the client final message answering a server first message
+func (s *postgresSCRAM) clientFinal(serverFirst string) (string, error) {
+	var nonce, salt string
+
+	iterations := 0
+
+	for _, attribute := range strings.Split(serverFirst, ",") {
+		if len(attribute) < 2 || attribute[1] != '=' {
+			continue
+		}
+
+		switch attribute[0] {
+		case 'r':
+			nonce = attribute[2:]
+		case 's':
+			salt = attribute[2:]
+		case 'i':
+			iterations, _ = strconv.Atoi(attribute[2:])
+		}
+	}
+
+	if !strings.HasPrefix(nonce, s.nonce) || iterations <= 0 || iterations > maxSCRAMIterations {
+		return "", fmt.Errorf("invalid postgres scram challenge")
+	}
+
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", err
+	}
+
+	saltedPassword := pbkdf2SHA256([]byte(s.password), saltBytes, iterations)
+	clientKey := hmacSHA256(saltedPassword, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := s.clientFirstBare + "," + serverFirst + "," + withoutProof
+
+	proof := hmacSHA256(storedKey[:], []byte(authMessage))
+	for i := range proof {
+		proof[i] ^= clientKey[i]
+	}
+
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// maxSCRAMIterations is the maximum number of iterations accepted from a server
+const maxSCRAMIterations = 1 << 20
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// pbkdf2SHA256 derives a key of the size of a sha256 digest
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	u := hmacSHA256(password, append(append([]byte{}, salt...), 0, 0, 0, 1))
+
+	key := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = hmacSHA256(password, u)
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+
+	return key
+}
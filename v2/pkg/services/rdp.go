@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// RDP security protocols
+const (
+	rdpProtocolRDP      = 0x00
+	rdpProtocolSSL      = 0x01
+	rdpProtocolHybrid   = 0x02
+	rdpProtocolRDSTLS   = 0x04
+	rdpProtocolHybridEx = 0x08
+)
+
+// RDP negotiation types
+const (
+	rdpNegotiationResponse = 0x02
+	rdpNegotiationFailure  = 0x03
+)
+
+// rdpHybridRequired is the failure code of servers requiring NLA
+const rdpHybridRequired = 0x05
+
+// rdpProtocols contains the names of the rdp security protocols
+var rdpProtocols = map[uint32]string{
+	rdpProtocolRDP:      "rdp",
+	rdpProtocolSSL:      "ssl",
+	rdpProtocolHybrid:   "hybrid",
+	rdpProtocolRDSTLS:   "rdstls",
+	rdpProtocolHybridEx: "hybrid_ex",
+}
+
+// probeRDP performs the initial negotiation of an rdp server offering every
+// security protocol, then offering standard rdp security and tls alone over
+// new connections to detect the protocols the server requires.
+//
+// The fields are rdp_selected_protocol, rdp_nla_supported, rdp_nla_required
+// and rdp_standard_security, the server accepting the legacy rdp security.
+// The selected protocol is rdp when the server refuses the offer.
+func probeRDP(conn net.Conn, host string, options *Options) (*Response, error) {
+	selected, failure, err := rdpNegotiate(conn, rdpProtocolSSL|rdpProtocolHybrid|rdpProtocolHybridEx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newResponse()
+
+	// servers only supporting standard rdp security refuse the offer
+	if failure != 0 {
+		selected = rdpProtocolRDP
+		response.addLine("negotiation failure: %d", failure)
+	}
+
+	response.Fields["rdp_selected_protocol"] = rdpProtocols[selected]
+	response.Fields["rdp_nla_supported"] = selected == rdpProtocolHybrid || selected == rdpProtocolHybridEx
+	response.addLine("selected protocol: %s", rdpProtocols[selected])
+
+	address := conn.RemoteAddr().String()
+
+	// the failures of the next negotiations only mean the server refused them
+	standard, failure, err := rdpNegotiateAddress(address, rdpProtocolRDP, options)
+	response.Fields["rdp_standard_security"] = err == nil && failure == 0 && standard == rdpProtocolRDP
+	response.addLine("standard security: %v (failure %d)", response.Fields["rdp_standard_security"], failure)
+
+	_, failure, err = rdpNegotiateAddress(address, rdpProtocolSSL, options)
+	response.Fields["rdp_nla_required"] = err == nil && failure == rdpHybridRequired
+	response.addLine("nla required: %v (failure %d)", response.Fields["rdp_nla_required"], failure)
+
+	return response, nil
+}
+
+// rdpNegotiateAddress negotiates the security protocols over a new connection
+func rdpNegotiateAddress(address string, protocols uint32, options *Options) (uint32, uint32, error) {
+	conn, err := net.DialTimeout("tcp", address, options.Timeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(options.Timeout)); err != nil {
+		return 0, 0, err
+	}
+
+	return rdpNegotiate(conn, protocols)
+}
+
+// rdpNegotiate sends an x224 connection request offering the given
+// protocols and returns the selected protocol or the failure code.
+func rdpNegotiate(conn net.Conn, protocols uint32) (selected, failure uint32, err error) {
+	request := []byte{
+		// tpkt header
+		0x03, 0x00, 0x00, 0x13,
+		// x224 connection request
+		0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// rdp negotiation request
+		0x01, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint32(request[15:], protocols)
+
+	if _, err := conn.Write(request); err != nil {
+		return 0, 0, err
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, 0, err
+	}
+
+	if header[0] != 0x03 {
+		return 0, 0, fmt.Errorf("not an rdp server")
+	}
+
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if length < 11 {
+		return 0, 0, fmt.Errorf("invalid rdp response length")
+	}
+
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return 0, 0, err
+	}
+
+	// x224 connection confirm
+	if data[1]&0xf0 != 0xd0 {
+		return 0, 0, fmt.Errorf("invalid x224 connection confirm")
+	}
+
+	// servers predating the negotiation only support standard rdp security
+	if len(data) < 15 {
+		return rdpProtocolRDP, 0, nil
+	}
+
+	negotiation := data[7:]
+	value := binary.LittleEndian.Uint32(negotiation[4:8])
+
+	switch negotiation[0] {
+	case rdpNegotiationResponse:
+		return value, 0, nil
+	case rdpNegotiationFailure:
+		return 0, value, nil
+	}
+
+	return 0, 0, fmt.Errorf("unknown rdp negotiation type %d", negotiation[0])
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxRedisReply is the maximum size of the redis replies read
+const maxRedisReply = 1024 * 1024
+
+// probeRedis reads the server information of a redis server, logging in
+// first when the password option is set, with the username option for acls.
+//
+// The fields are redis_version, redis_mode, redis_os, redis_auth_required,
+// redis_unauthenticated, the information being readable without logging in,
+// redis_login and redis_error.
+func probeRedis(conn net.Conn, host string, options *Options) (*Response, error) {
+	reader := bufio.NewReader(conn)
+	response := newResponse()
+
+	username, password := options.Values["username"], options.Values["password"]
+
+	if password != "" {
+		args := []string{"AUTH", password}
+		if username != "" {
+			args = []string{"AUTH", username, password}
+		}
+
+		reply, err := redisCommand(conn, reader, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Fields["redis_login"] = reply == "OK"
+		response.addLine("login: %s", reply)
+	}
+
+	reply, err := redisCommand(conn, reader, "INFO", "server")
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(reply, "-") {
+		response.Fields["redis_auth_required"] = strings.HasPrefix(reply, "-NOAUTH")
+		response.Fields["redis_unauthenticated"] = false
+		response.Fields["redis_error"] = strings.TrimPrefix(reply, "-")
+		response.addLine("error: %s", strings.TrimPrefix(reply, "-"))
+
+		return response, nil
+	}
+
+	response.Fields["redis_auth_required"] = false
+	response.Fields["redis_unauthenticated"] = password == ""
+
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "redis_version", "redis_mode", "os":
+			response.Fields["redis_"+strings.TrimPrefix(parts[0], "redis_")] = parts[1]
+			response.addLine("%s", line)
+		}
+	}
+
+	return response, nil
+}
+
+// redisCommand sends a command and returns its reply, errors being
+// returned prefixed with a dash.
+func redisCommand(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	command := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		command += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return line, nil
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length > maxRedisReply {
+			return "", fmt.Errorf("invalid redis bulk reply")
+		}
+
+		if length < 0 {
+			return "", nil
+		}
+
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return "", err
+		}
+
+		return string(data[:length]), nil
+	}
+
+	return "", fmt.Errorf("not a redis server")
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Options contains the configuration of a probe
+type Options struct {
+	// Timeout is the timeout of the whole probe
+	Timeout time.Duration
+	// TLS wraps the connection to the service in tls
+	TLS bool
+	// Values contains the service specific options of the probe
+	Values map[string]string
+}
+
+// Response contains the outcome of a probe
+type Response struct {
+	// Fields contains the values exposed to the dsl matchers
+	Fields map[string]interface{}
+	// Raw is a textual dump of the responses of the service
+	Raw string
+}
+
+// newResponse creates an empty response
+func newResponse() *Response {
+	return &Response{Fields: make(map[string]interface{})}
+}
+
+// addLine appends a line to the raw dump of the response
+func (r *Response) addLine(format string, args ...interface{}) {
+	r.Raw += fmt.Sprintf(format, args...) + "\n"
+}
+
+// probeFunc probes a service over an established connection
+type probeFunc func(conn net.Conn, host string, options *Options) (*Response, error)
+
+// service describes a supported service
+type service struct {
+	port  string
+	probe probeFunc
+	// rawTLS reports a service negotiating tls itself, ignoring the tls option
+	rawTLS bool
+}
+
+// services contains the supported services by name
+var services = map[string]*service{
+	"ldap":     {port: "389", probe: probeLDAP},
+	"kerberos": {port: "88", probe: probeKerberos},
+	"telnet":   {port: "23", probe: probeTelnet},
+	"ftp":      {port: "21", probe: probeFTP},
+	"rdp":      {port: "3389", probe: probeRDP},
+	"vnc":      {port: "5900", probe: probeVNC},
+	"mysql":    {port: "3306", probe: probeMySQL},
+	"postgres": {port: "5432", probe: probePostgres},
+	"mssql":    {port: "1433", probe: probeMSSQL},
+	"mongodb":  {port: "27017", probe: probeMongoDB},
+	"redis":    {port: "6379", probe: probeRedis},
+	"mqtt":     {port: "1883", probe: probeMQTT},
+	"amqp":     {port: "5672", probe: probeAMQP},
+	"jarm":     {port: "443", probe: probeJARM, rawTLS: true},
+}
+
+// IsSupported returns true if a service can be probed
+func IsSupported(name string) bool {
+	_, ok := services[name]
+	return ok
+}
+
+// Names returns the names of the supported services
+func Names() []string {
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DefaultPort returns the default port of a service
+func DefaultPort(name string) string {
+	if s, ok := services[name]; ok {
+		return s.port
+	}
+
+	return ""
+}
+
+// Probe connects to the service at address and probes it
+func Probe(name, address string, options *Options) (*Response, error) {
+	s, ok := services[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown service %s", name)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, options.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(options.Timeout)); err != nil {
+		return nil, err
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.TLS && !s.rawTLS {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true}) // nolint:gosec // services are probed regardless of their certificate
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+
+		conn = tlsConn
+	}
+
+	return s.probe(conn, strings.TrimSuffix(host, "."), options)
+}
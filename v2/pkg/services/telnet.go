@@ -0,0 +1,123 @@
+package services
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Telnet commands
+const (
+	telnetIAC  = 255
+	telnetDont = 254
+	telnetDo   = 253
+	telnetWont = 252
+	telnetWill = 251
+	telnetSB   = 250
+	telnetSE   = 240
+)
+
+// defaultBannerWait is the default time waited for the banner of a service
+const defaultBannerWait = 3 * time.Second
+
+// probeTelnet captures the banner of a telnet server, refusing all the
+// options it negotiates. The wait option is the time waited for the banner.
+//
+// The fields are telnet_banner and telnet_options, the options requested
+// by the server.
+func probeTelnet(conn net.Conn, host string, options *Options) (*Response, error) {
+	wait := defaultBannerWait
+	if value, err := time.ParseDuration(options.Values["wait"]); err == nil {
+		wait = value
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(wait)); err != nil {
+		return nil, err
+	}
+
+	var banner []byte
+	var negotiated []string
+
+	buffer := make([]byte, 4096)
+
+	for {
+		n, err := conn.Read(buffer)
+
+		data := buffer[:n]
+		for i := 0; i < len(data); i++ {
+			if data[i] != telnetIAC || i+1 >= len(data) {
+				banner = append(banner, data[i])
+				continue
+			}
+
+			command := data[i+1]
+
+			switch command {
+			case telnetDo, telnetWill, telnetDont, telnetWont:
+				if i+2 >= len(data) {
+					i = len(data)
+					continue
+				}
+
+				option := data[i+2]
+				negotiated = append(negotiated, telnetOptionName(command, option))
+
+				// refuse everything, the banner is all we want
+				reply := byte(telnetWont)
+				if command == telnetWill || command == telnetWont {
+					reply = telnetDont
+				}
+
+				if command == telnetDo || command == telnetWill {
+					if _, err := conn.Write([]byte{telnetIAC, reply, option}); err != nil {
+						return nil, err
+					}
+				}
+
+				i += 2
+			case telnetSB:
+				// skip the subnegotiation up to IAC SE
+				end := bytes.Index(data[i:], []byte{telnetIAC, telnetSE})
+				if end < 0 {
+					i = len(data)
+					continue
+				}
+
+				i += end + 1
+			case telnetIAC:
+				banner = append(banner, telnetIAC)
+				i++
+			default:
+				i++
+			}
+		}
+
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				break
+			}
+
+			if len(banner) == 0 && len(negotiated) == 0 {
+				return nil, err
+			}
+
+			break
+		}
+	}
+
+	response := newResponse()
+	response.Fields["telnet_banner"] = strings.TrimSpace(string(banner))
+	response.Fields["telnet_options"] = strings.Join(negotiated, " ")
+	response.Raw = string(banner)
+
+	return response, nil
+}
+
+// telnetOptionName returns a readable representation of a negotiation
+func telnetOptionName(command, option byte) string {
+	names := map[byte]string{telnetDo: "do", telnetDont: "dont", telnetWill: "will", telnetWont: "wont"}
+
+	return names[command] + "-" + strconv.Itoa(int(option))
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// vncNoAuthentication is the security type of servers without authentication
+const vncNoAuthentication = 1
+
+// maxVNCReason is the maximum length of the failure reasons read
+const maxVNCReason = 4096
+
+// vncProtocolVersion matches the protocol version sent by vnc servers
+var vncProtocolVersion = regexp.MustCompile(`^RFB (\d{3})\.(\d{3})\n$`)
+
+// vncSecurityTypes contains the names of the vnc security types
+var vncSecurityTypes = map[uint32]string{
+	0:  "invalid",
+	1:  "none",
+	2:  "vnc",
+	5:  "ra2",
+	6:  "ra2ne",
+	16: "tight",
+	17: "ultra",
+	18: "tls",
+	19: "vencrypt",
+	20: "gtk-vnc-sasl",
+	21: "md5",
+	22: "colin-dean-xvp",
+	30: "apple-remote-desktop",
+}
+
+// probeVNC performs the handshake of a vnc server up to the security types
+// it offers.
+//
+// The fields are vnc_version, vnc_security_types, the names of the offered
+// security types, and vnc_no_auth, the server not requiring authentication.
+func probeVNC(conn net.Conn, host string, options *Options) (*Response, error) {
+	banner := make([]byte, 12)
+	if _, err := io.ReadFull(conn, banner); err != nil {
+		return nil, err
+	}
+
+	matches := vncProtocolVersion.FindStringSubmatch(string(banner))
+	if matches == nil {
+		return nil, fmt.Errorf("not a vnc server")
+	}
+
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+
+	response := newResponse()
+	response.Fields["vnc_version"] = fmt.Sprintf("%d.%d", major, minor)
+	response.addLine("%s", strings.TrimSpace(string(banner)))
+
+	// the client answers with the highest version it supports
+	version := "RFB 003.008\n"
+	if major == 3 && minor < 7 {
+		version = "RFB 003.003\n"
+	} else if major == 3 && minor == 7 {
+		version = "RFB 003.007\n"
+	}
+
+	if _, err := conn.Write([]byte(version)); err != nil {
+		return nil, err
+	}
+
+	var types []uint32
+
+	if version == "RFB 003.003\n" {
+		// the server decides the security type
+		var securityType uint32
+		if err := binary.Read(conn, binary.BigEndian, &securityType); err != nil {
+			return nil, err
+		}
+
+		if securityType != 0 {
+			types = append(types, securityType)
+		}
+	} else {
+		count := make([]byte, 1)
+		if _, err := io.ReadFull(conn, count); err != nil {
+			return nil, err
+		}
+
+		offered := make([]byte, count[0])
+		if _, err := io.ReadFull(conn, offered); err != nil {
+			return nil, err
+		}
+
+		for _, securityType := range offered {
+			types = append(types, uint32(securityType))
+		}
+	}
+
+	if len(types) == 0 {
+		reason, err := vncReason(conn)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Fields["vnc_error"] = reason
+		response.addLine("error: %s", reason)
+	}
+
+	var names []string
+
+	noAuth := false
+
+	for _, securityType := range types {
+		name, ok := vncSecurityTypes[securityType]
+		if !ok {
+			name = strconv.Itoa(int(securityType))
+		}
+
+		names = append(names, name)
+
+		if securityType == vncNoAuthentication {
+			noAuth = true
+		}
+	}
+
+	response.Fields["vnc_security_types"] = strings.Join(names, " ")
+	response.Fields["vnc_no_auth"] = noAuth
+	response.addLine("security types: %s", strings.Join(names, " "))
+
+	return response, nil
+}
+
+// vncReason reads the reason of a failed handshake
+func vncReason(conn net.Conn) (string, error) {
+	var length uint32
+	if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+
+	if length > maxVNCReason {
+		return "", fmt.Errorf("vnc failure reason too long")
+	}
+
+	reason := make([]byte, length)
+	if _, err := io.ReadFull(conn, reason); err != nil {
+		return "", err
+	}
+
+	return string(reason), nil
+}
@@ -0,0 +1,161 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsAlgorithm  = "AWS4-HMAC-SHA256"
+	awsDateFormat = "20060102T150405Z"
+)
+
+// AWSOptions contains the credentials and scope of an AWS signature
+type AWSOptions struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+	Service      string
+}
+
+// SignAWS signs a request with AWS Signature Version 4, setting its
+// X-Amz-Date, X-Amz-Content-Sha256 and Authorization headers.
+func SignAWS(req *http.Request, body []byte, options *AWSOptions, now time.Time) error {
+	if options.AccessKey == "" || options.SecretKey == "" {
+		return errors.New("no aws credentials provided")
+	}
+
+	amzDate := now.UTC().Format(awsDateFormat)
+	date := amzDate[:8]
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if options.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", options.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	canonicalHeaders, signedHeaders := awsCanonicalHeaders(req.Header, host)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalURI(req.URL, options.Service),
+		awsCanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, options.Region, options.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{awsAlgorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+options.SecretKey), date)
+	key = hmacSHA256(key, options.Region)
+	key = hmacSHA256(key, options.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsAlgorithm, options.AccessKey, scope, signedHeaders, signature))
+
+	return nil
+}
+
+// awsCanonicalURI returns the encoded path of a request, encoded twice
+// for all the services but s3.
+func awsCanonicalURI(u *url.URL, service string) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+
+	if service == "s3" {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery returns the query parameters sorted by name and value
+func awsCanonicalQuery(query url.Values) string {
+	var parameters []string
+
+	for name, values := range query {
+		for _, value := range values {
+			parameters = append(parameters, awsEscape(name)+"="+awsEscape(value))
+		}
+	}
+
+	sort.Strings(parameters)
+
+	return strings.Join(parameters, "&")
+}
+
+// awsCanonicalHeaders returns the canonical headers and the list of signed headers,
+// the host, content type and amz headers being signed.
+func awsCanonicalHeaders(header http.Header, host string) (canonical, signed string) {
+	headers := map[string]string{"host": host}
+
+	for name, values := range header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			trimmed := make([]string, len(values))
+			for i, value := range values {
+				trimmed[i] = strings.Join(strings.Fields(value), " ")
+			}
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	builder := &strings.Builder{}
+	for _, name := range names {
+		builder.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return builder.String(), strings.Join(names, ";")
+}
+
+// awsEscape encodes a value as required by the signature, spaces as %20
+func awsEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,3 @@
+// Package signer signs http requests for the cloud provider
+// apis requiring authenticated requests.
+package signer
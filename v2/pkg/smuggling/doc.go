@@ -0,0 +1,3 @@
+// Package smuggling implements the detection of HTTP request smuggling
+// (CL.TE and TE.CL desync) using differential timing probes.
+package smuggling
@@ -0,0 +1,188 @@
+package smuggling
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/connector"
+)
+
+const (
+	// CLTE is a front-end using Content-Length and a back-end using Transfer-Encoding
+	CLTE = "cl.te"
+	// TECL is a front-end using Transfer-Encoding and a back-end using Content-Length
+	TECL = "te.cl"
+)
+
+// Techniques contains the supported techniques in the order they must be tested,
+// the TE.CL probe can poison the back-end connection of a CL.TE target.
+var Techniques = []string{CLTE, TECL}
+
+// probeBodies contains for each technique the content length and the body of the
+// probe making a vulnerable back-end wait for data which is never sent.
+var probeBodies = map[string]struct {
+	contentLength int
+	body          string
+}{
+	CLTE: {contentLength: 4, body: "1\r\nA\r\nX"},
+	TECL: {contentLength: 6, body: "0\r\n\r\nX"},
+}
+
+// controlBody is a chunked body with a matching content length, valid for any setup
+const controlBody = "0\r\n\r\n"
+
+// ErrStopped is returned when the probes are stopped before a verdict
+var ErrStopped = errors.New("the smuggling probes were stopped")
+
+// Options contains the configuration of the detection
+type Options struct {
+	// Method is the method of the probes
+	Method string
+	// Path is the path of the probes
+	Path string
+	// Timeout is the duration after which a probe is considered delayed
+	Timeout time.Duration
+	// Confirmations is the number of times a delay is confirmed with a new probe pair
+	Confirmations int
+	// Connector opens the connections of the probes, directly when nil
+	Connector *connector.Connector
+	// OnRequest is optionally called before every request, the detection
+	// being stopped with ErrStopped when it returns false
+	OnRequest func() bool
+}
+
+// Finding contains the evidences of a detected desync
+type Finding struct {
+	// Technique is the detected technique
+	Technique string
+	// Control is the duration of the last control request
+	Control time.Duration
+	// Delayed is the duration of the last delayed probe
+	Delayed time.Duration
+	// ConnectionClosed reports if the server closed the connection of a probe without response
+	ConnectionClosed bool
+	// Probe is the raw probe request
+	Probe string
+}
+
+// probeResult is the outcome of a single request
+type probeResult struct {
+	duration time.Duration
+	timedOut bool
+	closed   bool
+}
+
+// Detect tests a technique on a target and returns a finding if the
+// probe is delayed while the control request is not, on every attempt.
+func Detect(target, technique string, options *Options) (*Finding, error) {
+	probe, ok := probeBodies[technique]
+	if !ok {
+		return nil, fmt.Errorf("unknown smuggling technique %s", technique)
+	}
+
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	probeRequest := buildRequest(options, parsed, probe.contentLength, probe.body)
+	controlRequest := buildRequest(options, parsed, len(controlBody), controlBody)
+
+	finding := &Finding{Technique: technique, Probe: probeRequest}
+
+	for attempt := 0; attempt <= options.Confirmations; attempt++ {
+		control, err := send(parsed, controlRequest, options)
+		if err != nil {
+			return nil, err
+		}
+
+		// a slow target can't be told apart from a vulnerable one
+		if control.timedOut {
+			return nil, nil
+		}
+
+		delayed, err := send(parsed, probeRequest, options)
+		if err != nil {
+			return nil, err
+		}
+
+		if !delayed.timedOut {
+			return nil, nil
+		}
+
+		finding.Control = control.duration
+		finding.Delayed = delayed.duration
+		finding.ConnectionClosed = finding.ConnectionClosed || delayed.closed
+	}
+
+	return finding, nil
+}
+
+// buildRequest builds a raw request with conflicting length headers
+func buildRequest(options *Options, target *url.URL, contentLength int, body string) string {
+	method := options.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	path := options.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return fmt.Sprintf("%s %s HTTP/1.1\r\nHost: %s\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: %d\r\nTransfer-Encoding: chunked\r\nConnection: close\r\n\r\n%s",
+		method, path, target.Host, contentLength, body)
+}
+
+// send writes a raw request on a new connection and waits for the response
+func send(target *url.URL, raw string, options *Options) (*probeResult, error) {
+	if options.OnRequest != nil && !options.OnRequest() {
+		return nil, ErrStopped
+	}
+
+	timeout := options.Timeout
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := options.Connector.Dial(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	start := time.Now()
+
+	conn.SetDeadline(start.Add(timeout)) // nolint:errcheck // best effort
+
+	if _, err := conn.Write([]byte(raw)); err != nil {
+		return nil, err
+	}
+
+	result := &probeResult{}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	result.duration = time.Since(start)
+
+	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			result.timedOut = true
+			return result, nil
+		}
+
+		// the server dropped the connection without answering
+		result.closed = true
+
+		return result, nil
+	}
+
+	resp.Body.Close()
+
+	return result, nil
+}
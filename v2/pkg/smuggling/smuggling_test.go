@@ -0,0 +1,86 @@
+package smuggling
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		target   string
+		length   int
+		body     string
+		expected string
+	}{
+		{
+			name:     "defaults",
+			options:  &Options{},
+			target:   "https://example.com",
+			length:   4,
+			body:     "1\r\nA\r\nX",
+			expected: "POST / HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 4\r\nTransfer-Encoding: chunked\r\nConnection: close\r\n\r\n1\r\nA\r\nX",
+		},
+		{
+			name:     "method and path",
+			options:  &Options{Method: "GET", Path: "/login?next=/"},
+			target:   "http://example.com:8080/ignored",
+			length:   5,
+			body:     "0\r\n\r\n",
+			expected: "GET /login?next=/ HTTP/1.1\r\nHost: example.com:8080\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 5\r\nTransfer-Encoding: chunked\r\nConnection: close\r\n\r\n0\r\n\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		target, err := url.Parse(test.target)
+		require.Nil(t, err, "Could not parse target of %s request", test.name)
+		require.Equal(t, test.expected, buildRequest(test.options, target, test.length, test.body), "Could not build %s request", test.name)
+	}
+}
+
+func TestProbeBodies(t *testing.T) {
+	tests := []struct {
+		technique string
+		// chunked reports if the back-end reads the body as chunked
+		chunked bool
+	}{
+		{technique: CLTE, chunked: true},
+		{technique: TECL, chunked: false},
+	}
+
+	for _, test := range tests {
+		probe, ok := probeBodies[test.technique]
+		require.True(t, ok, "Could not get probe of %s", test.technique)
+
+		// the front-end forwards the body it reads, the back-end then
+		// waits for the rest of the body it expects
+		if test.chunked {
+			forwarded := probe.body[:probe.contentLength]
+			require.False(t, strings.HasSuffix(forwarded, "0\r\n\r\n"), "Could complete chunked body of %s probe", test.technique)
+		} else {
+			forwarded := probe.body[:strings.Index(probe.body, "0\r\n\r\n")+len("0\r\n\r\n")]
+			require.Less(t, len(forwarded), probe.contentLength, "Could complete body of %s probe", test.technique)
+		}
+	}
+
+	require.Len(t, controlBody, 5, "Could not build control body")
+}
+
+func TestDetectStopped(t *testing.T) {
+	requests := 0
+	options := &Options{OnRequest: func() bool {
+		requests++
+		return false
+	}}
+
+	_, err := Detect("http://127.0.0.1:1", CLTE, options)
+	require.Equal(t, ErrStopped, err, "Could not stop detection")
+	require.Equal(t, 1, requests, "Could not stop detection before the first request")
+
+	_, err = Detect("http://127.0.0.1:1", "cl.cl", options)
+	require.NotNil(t, err, "Could detect unknown technique")
+}
@@ -0,0 +1,4 @@
+// Package sso parses the metadata published by the single sign-on
+// providers and services, the saml entity descriptors and the openid
+// connect discovery documents, for the templates of sso misconfigurations.
+package sso
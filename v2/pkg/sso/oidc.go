@@ -0,0 +1,88 @@
+package sso
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// OIDCPaths are the paths of the openid connect discovery documents
+var OIDCPaths = []string{
+	"/.well-known/openid-configuration",
+	"/.well-known/oauth-authorization-server",
+}
+
+// OIDCMetadata is an openid connect discovery document
+type OIDCMetadata struct {
+	Issuer                   string   `json:"issuer"`
+	AuthorizationEndpoint    string   `json:"authorization_endpoint"`
+	TokenEndpoint            string   `json:"token_endpoint"`
+	UserinfoEndpoint         string   `json:"userinfo_endpoint"`
+	JWKSURI                  string   `json:"jwks_uri"`
+	RegistrationEndpoint     string   `json:"registration_endpoint"`
+	EndSessionEndpoint       string   `json:"end_session_endpoint"`
+	IntrospectionEndpoint    string   `json:"introspection_endpoint"`
+	RevocationEndpoint       string   `json:"revocation_endpoint"`
+	SigningAlgorithms        []string `json:"id_token_signing_alg_values_supported"`
+	ResponseTypes            []string `json:"response_types_supported"`
+	GrantTypes               []string `json:"grant_types_supported"`
+	TokenEndpointAuthMethods []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// IsOIDC returns true if a body looks like an openid connect discovery
+// document, before parsing it
+func IsOIDC(body string) bool {
+	trimmed := strings.TrimSpace(body)
+
+	return strings.HasPrefix(trimmed, "{") && strings.Contains(trimmed, `"issuer"`)
+}
+
+// ParseOIDC parses an openid connect discovery document
+func ParseOIDC(body string) (*OIDCMetadata, error) {
+	metadata := &OIDCMetadata{}
+	if err := json.Unmarshal([]byte(body), metadata); err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer == "" || (metadata.AuthorizationEndpoint == "" && metadata.TokenEndpoint == "") {
+		return nil, errors.New("the document has no issuer or endpoints")
+	}
+
+	return metadata, nil
+}
+
+// Endpoints returns the endpoints of the provider, in the order of the
+// document fields
+func (m *OIDCMetadata) Endpoints() []string {
+	var endpoints []string
+
+	for _, endpoint := range []string{
+		m.AuthorizationEndpoint,
+		m.TokenEndpoint,
+		m.UserinfoEndpoint,
+		m.JWKSURI,
+		m.RegistrationEndpoint,
+		m.EndSessionEndpoint,
+		m.IntrospectionEndpoint,
+		m.RevocationEndpoint,
+	} {
+		if endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	return endpoints
+}
+
+// InsecureEndpoints returns the endpoints of the provider served over http
+func (m *OIDCMetadata) InsecureEndpoints() []string {
+	var insecure []string
+
+	for _, endpoint := range m.Endpoints() {
+		if strings.HasPrefix(strings.ToLower(endpoint), "http://") {
+			insecure = append(insecure, endpoint)
+		}
+	}
+
+	return insecure
+}
@@ -0,0 +1,171 @@
+package sso
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+)
+
+// SAMLPaths are the common paths of the saml metadata of the providers
+// and the services
+var SAMLPaths = []string{
+	"/FederationMetadata/2007-06/FederationMetadata.xml",
+	"/saml/metadata",
+	"/saml2/metadata",
+	"/simplesaml/saml2/idp/metadata.php",
+	"/Shibboleth.sso/Metadata",
+	"/idp/shibboleth",
+}
+
+// samlEntity is a saml EntityDescriptor, or an EntitiesDescriptor
+// grouping them
+type samlEntity struct {
+	EntityID string        `xml:"entityID,attr"`
+	IDP      []samlRole    `xml:"IDPSSODescriptor"`
+	SP       []samlRole    `xml:"SPSSODescriptor"`
+	Entities []*samlEntity `xml:"EntityDescriptor"`
+}
+
+// samlRole is the descriptor of an identity provider or a service provider
+type samlRole struct {
+	WantAuthnRequestsSigned string         `xml:"WantAuthnRequestsSigned,attr"`
+	AuthnRequestsSigned     string         `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned    string         `xml:"WantAssertionsSigned,attr"`
+	Keys                    []samlKey      `xml:"KeyDescriptor"`
+	SingleSignOn            []samlEndpoint `xml:"SingleSignOnService"`
+	SingleLogout            []samlEndpoint `xml:"SingleLogoutService"`
+	AssertionConsumer       []samlEndpoint `xml:"AssertionConsumerService"`
+	ArtifactResolution      []samlEndpoint `xml:"ArtifactResolutionService"`
+}
+
+// samlKey is a key of a descriptor with its certificates
+type samlKey struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+// samlEndpoint is a service of a descriptor
+type samlEndpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// SAMLMetadata is the metadata of a saml identity provider or service
+// provider
+type SAMLMetadata struct {
+	// EntityID is the id of the entity
+	EntityID string
+	// IdentityProvider reports if the entity is an identity provider
+	IdentityProvider bool
+	// ServiceProvider reports if the entity is a service provider
+	ServiceProvider bool
+	// Endpoints contains the locations of the services of the entity
+	Endpoints []string
+	// Bindings contains the bindings of the services of the entity
+	Bindings []string
+	// WantAuthnRequestsSigned reports if the identity provider requires
+	// signed authentication requests
+	WantAuthnRequestsSigned bool
+	// AuthnRequestsSigned reports if the service provider signs its
+	// authentication requests
+	AuthnRequestsSigned bool
+	// WantAssertionsSigned reports if the service provider requires
+	// signed assertions
+	WantAssertionsSigned bool
+	// Certificates contains the signing and encryption certificates of the entity
+	Certificates []*x509.Certificate
+}
+
+// IsSAML returns true if a body looks like saml metadata, before parsing it
+func IsSAML(body string) bool {
+	return strings.Contains(body, "EntityDescriptor")
+}
+
+// ParseSAML parses the metadata of the first saml entity of a body
+func ParseSAML(body string) (*SAMLMetadata, error) {
+	entity := &samlEntity{}
+	if err := xml.Unmarshal([]byte(body), entity); err != nil {
+		return nil, err
+	}
+
+	// the federations group their entities
+	if len(entity.IDP)+len(entity.SP) == 0 && len(entity.Entities) > 0 {
+		entity = entity.Entities[0]
+	}
+
+	if len(entity.IDP)+len(entity.SP) == 0 {
+		return nil, errors.New("the metadata has no identity or service provider")
+	}
+
+	metadata := &SAMLMetadata{
+		EntityID:         entity.EntityID,
+		IdentityProvider: len(entity.IDP) > 0,
+		ServiceProvider:  len(entity.SP) > 0,
+	}
+
+	seen := make(map[string]bool)
+	for _, role := range append(append([]samlRole{}, entity.IDP...), entity.SP...) {
+		metadata.WantAuthnRequestsSigned = metadata.WantAuthnRequestsSigned || isTrue(role.WantAuthnRequestsSigned)
+		metadata.AuthnRequestsSigned = metadata.AuthnRequestsSigned || isTrue(role.AuthnRequestsSigned)
+		metadata.WantAssertionsSigned = metadata.WantAssertionsSigned || isTrue(role.WantAssertionsSigned)
+
+		for _, endpoints := range [][]samlEndpoint{role.SingleSignOn, role.SingleLogout, role.AssertionConsumer, role.ArtifactResolution} {
+			for _, endpoint := range endpoints {
+				if endpoint.Location != "" && !seen[endpoint.Location] {
+					seen[endpoint.Location] = true
+					metadata.Endpoints = append(metadata.Endpoints, endpoint.Location)
+				}
+
+				if endpoint.Binding != "" && !seen[endpoint.Binding] {
+					seen[endpoint.Binding] = true
+					metadata.Bindings = append(metadata.Bindings, endpoint.Binding)
+				}
+			}
+		}
+
+		for _, key := range role.Keys {
+			for _, encoded := range key.Certificates {
+				if certificate, err := parseCertificate(encoded); err == nil {
+					metadata.Certificates = append(metadata.Certificates, certificate)
+				}
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+// isTrue returns true for the xml boolean true values
+func isTrue(value string) bool {
+	value = strings.TrimSpace(value)
+
+	return value == "true" || value == "1"
+}
+
+// parseCertificate parses a base64 encoded certificate of the metadata,
+// wrapped over several lines
+func parseCertificate(encoded string) (*x509.Certificate, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(data)
+}
+
+// CertificateNotAfter returns the earliest expiration of the certificates
+// of the entity, zero without certificates
+func (m *SAMLMetadata) CertificateNotAfter() time.Time {
+	var notAfter time.Time
+
+	for _, certificate := range m.Certificates {
+		if notAfter.IsZero() || certificate.NotAfter.Before(notAfter) {
+			notAfter = certificate.NotAfter
+		}
+	}
+
+	return notAfter
+}
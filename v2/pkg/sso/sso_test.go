@@ -0,0 +1,164 @@
+package sso
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOIDC(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		endpoints []string
+		insecure  []string
+		err       bool
+	}{
+		{
+			name:      "secure",
+			body:      `{"issuer":"https://idp.example.com","authorization_endpoint":"https://idp.example.com/auth","token_endpoint":"https://idp.example.com/token"}`,
+			endpoints: []string{"https://idp.example.com/auth", "https://idp.example.com/token"},
+		},
+		{
+			name:      "insecure",
+			body:      `{"issuer":"http://idp.example.com","token_endpoint":"https://idp.example.com/token","jwks_uri":"HTTP://idp.example.com/jwks"}`,
+			endpoints: []string{"https://idp.example.com/token", "HTTP://idp.example.com/jwks"},
+			insecure:  []string{"HTTP://idp.example.com/jwks"},
+		},
+		{name: "without issuer", body: `{"authorization_endpoint":"https://idp.example.com/auth"}`, err: true},
+		{name: "without endpoints", body: `{"issuer":"https://idp.example.com","jwks_uri":"https://idp.example.com/jwks"}`, err: true},
+		{name: "invalid json", body: `{"issuer":`, err: true},
+	}
+
+	for _, test := range tests {
+		metadata, err := ParseOIDC(test.body)
+		if test.err {
+			require.NotNil(t, err, "Could parse invalid %s document", test.name)
+			continue
+		}
+
+		require.Nil(t, err, "Could not parse valid %s document", test.name)
+		require.Equal(t, test.endpoints, metadata.Endpoints(), "Could not get endpoints of %s document", test.name)
+		require.Equal(t, test.insecure, metadata.InsecureEndpoints(), "Could not get insecure endpoints of %s document", test.name)
+	}
+}
+
+func TestIsOIDC(t *testing.T) {
+	tests := []struct {
+		body string
+		oidc bool
+	}{
+		{body: ` {"issuer":"https://idp.example.com"}`, oidc: true},
+		{body: `{"name":"example"}`, oidc: false},
+		{body: `<html>"issuer"</html>`, oidc: false},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.oidc, IsOIDC(test.body), "Could not detect document %s", test.body)
+	}
+}
+
+// newCertificate returns a base64 encoded self signed certificate
+// expiring at a date
+func newCertificate(t *testing.T, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, "Could not generate key")
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+
+	data, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err, "Could not create certificate")
+
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestParseSAML(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := newCertificate(t, notAfter.AddDate(1, 0, 0))
+	earlier := newCertificate(t, notAfter)
+
+	// the certificates are wrapped over several lines like in the metadata
+	wrapped := earlier[:40] + "\n      " + earlier[40:]
+
+	idp := `<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" xmlns:ds="http://www.w3.org/2000/09/xmldsig#" entityID="https://idp.example.com">
+  <md:IDPSSODescriptor WantAuthnRequestsSigned="1">
+    <md:KeyDescriptor use="signing"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>` + later + `</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:KeyDescriptor use="encryption"><ds:KeyInfo><ds:X509Data><ds:X509Certificate>` + wrapped + `</ds:X509Certificate></ds:X509Data></ds:KeyInfo></md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://idp.example.com/sso"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="http://idp.example.com/slo"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+	sp := `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">
+  <EntityDescriptor entityID="https://sp.example.com">
+    <SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true">
+      <AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://sp.example.com/acs"/>
+    </SPSSODescriptor>
+  </EntityDescriptor>
+</EntitiesDescriptor>`
+
+	tests := []struct {
+		name     string
+		body     string
+		expected *SAMLMetadata
+		notAfter time.Time
+		err      bool
+	}{
+		{
+			name: "identity provider",
+			body: idp,
+			expected: &SAMLMetadata{
+				EntityID:                "https://idp.example.com",
+				IdentityProvider:        true,
+				Endpoints:               []string{"https://idp.example.com/sso", "http://idp.example.com/slo"},
+				Bindings:                []string{"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect", "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"},
+				WantAuthnRequestsSigned: true,
+			},
+			notAfter: notAfter,
+		},
+		{
+			name: "federation",
+			body: sp,
+			expected: &SAMLMetadata{
+				EntityID:             "https://sp.example.com",
+				ServiceProvider:      true,
+				Endpoints:            []string{"https://sp.example.com/acs"},
+				Bindings:             []string{"urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"},
+				WantAssertionsSigned: true,
+			},
+		},
+		{name: "without provider", body: `<EntityDescriptor entityID="https://example.com"/>`, err: true},
+		{name: "invalid xml", body: `<EntityDescriptor`, err: true},
+	}
+
+	for _, test := range tests {
+		metadata, err := ParseSAML(test.body)
+		if test.err {
+			require.NotNil(t, err, "Could parse invalid %s metadata", test.name)
+			continue
+		}
+
+		require.Nil(t, err, "Could not parse valid %s metadata", test.name)
+		require.True(t, test.notAfter.Equal(metadata.CertificateNotAfter()), "Could not get certificate expiration of %s metadata", test.name)
+
+		metadata.Certificates = nil
+		require.Equal(t, test.expected, metadata, "Could not parse %s metadata", test.name)
+	}
+
+	require.True(t, IsSAML(idp), "Could not detect saml metadata")
+	require.False(t, IsSAML(`{"issuer":"https://idp.example.com"}`), "Could detect invalid saml metadata")
+}
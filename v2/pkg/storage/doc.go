@@ -0,0 +1,3 @@
+// Package storage checks the existence and the permissions of the
+// buckets of the cloud storage providers (S3, GCS and Azure Blob).
+package storage
@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/signer"
+)
+
+// Cloud storage providers
+const (
+	ProviderS3    = "s3"
+	ProviderGCS   = "gcs"
+	ProviderAzure = "azure"
+)
+
+// Bucket checks
+const (
+	// CheckExists reports the buckets which exist
+	CheckExists = "exists"
+	// CheckUnclaimed reports the buckets which don't exist and could be claimed by anyone
+	CheckUnclaimed = "unclaimed"
+	// CheckList reports the buckets whose objects can be listed
+	CheckList = "list"
+	// CheckWrite reports the buckets where objects can be written, the written object being deleted
+	CheckWrite = "write"
+)
+
+// Providers contains the supported cloud storage providers
+var Providers = map[string]bool{ProviderS3: true, ProviderGCS: true, ProviderAzure: true}
+
+// Checks contains the supported bucket checks
+var Checks = map[string]bool{CheckExists: true, CheckUnclaimed: true, CheckList: true, CheckWrite: true}
+
+// writeCheckContent is the content of the objects written by the write check
+const writeCheckContent = "nuclei write permission check"
+
+// Options contains the configuration of the checks
+type Options struct {
+	// Timeout is the timeout of each request
+	Timeout time.Duration
+	// Transport is the transport of the requests, the default one when nil
+	Transport http.RoundTripper
+	// OnRequest is optionally called for every request sent
+	OnRequest func()
+}
+
+// State contains the state of a bucket observed by a listing request
+type State struct {
+	// URL is the listing url of the bucket
+	URL string
+	// Exists reports if the bucket exists
+	Exists bool
+	// Listable reports if the objects of the bucket can be listed
+	Listable bool
+	// Status is the status code of the listing request, 0 if the host didn't resolve
+	Status int
+}
+
+// Checker runs the bucket checks of a provider, signing the requests with the
+// credentials of the environment when provided (AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY, GOOGLE_OAUTH_ACCESS_TOKEN, AZURE_STORAGE_SAS_TOKEN)
+// so the buckets open to any authenticated user are detected too.
+type Checker struct {
+	provider   string
+	options    *Options
+	httpClient *http.Client
+}
+
+// New creates a checker for a provider
+func New(provider string, options *Options) (*Checker, error) {
+	if !Providers[provider] {
+		return nil, fmt.Errorf("unknown storage provider %s", provider)
+	}
+
+	return &Checker{
+		provider: provider,
+		options:  options,
+		httpClient: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: options.Transport,
+			// the region redirections of s3 are handled by the checker
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Probe lists a bucket to find out if it exists and can be listed. The
+// azure buckets are named account/container.
+func (c *Checker) Probe(bucket string) (*State, error) {
+	switch c.provider {
+	case ProviderS3:
+		return c.probeS3(bucket, "us-east-1")
+	case ProviderGCS:
+		listURL := "https://storage.googleapis.com/storage/v1/b/" + bucket + "/o?maxResults=1"
+
+		resp, err := c.do(http.MethodGet, listURL, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &State{URL: listURL, Exists: resp.status != http.StatusNotFound, Listable: resp.status == http.StatusOK, Status: resp.status}, nil
+	default:
+		account, container, err := splitAzureBucket(bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		listURL := fmt.Sprintf("https://%s.blob.core.windows.net/%s?restype=container&comp=list&maxresults=1", account, container)
+
+		// the storage accounts which don't resolve can be registered
+		if _, err := net.LookupHost(account + ".blob.core.windows.net"); err != nil {
+			return &State{URL: listURL}, nil
+		}
+
+		resp, err := c.do(http.MethodGet, listURL, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		exists := !strings.Contains(resp.body, "ContainerNotFound")
+
+		return &State{URL: listURL, Exists: exists, Listable: resp.status == http.StatusOK, Status: resp.status}, nil
+	}
+}
+
+// probeS3 lists a s3 bucket, following the redirection to its region
+func (c *Checker) probeS3(bucket, region string) (*State, error) {
+	listURL := s3URL(bucket, region) + "?list-type=2&max-keys=1"
+
+	resp, err := c.do(http.MethodGet, listURL, nil, func(req *http.Request, payload []byte) error {
+		return signS3(req, payload, region)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.status == http.StatusMovedPermanently {
+		if bucketRegion := resp.header.Get("X-Amz-Bucket-Region"); bucketRegion != "" && bucketRegion != region {
+			return c.probeS3(bucket, bucketRegion)
+		}
+	}
+
+	exists := !(resp.status == http.StatusNotFound && strings.Contains(resp.body, "NoSuchBucket"))
+
+	return &State{URL: listURL, Exists: exists, Listable: resp.status == http.StatusOK, Status: resp.status}, nil
+}
+
+// Write writes an object to a bucket and deletes it, reporting if the write succeeded
+func (c *Checker) Write(bucket string) (string, bool, error) {
+	name := "nuclei-write-check-" + randomHex() + ".txt"
+	content := []byte(writeCheckContent)
+
+	var objectURL, deleteURL string
+
+	var sign func(req *http.Request, payload []byte) error
+
+	method := http.MethodPut
+
+	switch c.provider {
+	case ProviderS3:
+		region := "us-east-1"
+		if state, err := c.probeS3(bucket, region); err == nil {
+			region = s3RegionFromURL(state.URL)
+		}
+
+		objectURL = s3URL(bucket, region) + "/" + name
+		deleteURL = objectURL
+		sign = func(req *http.Request, payload []byte) error {
+			return signS3(req, payload, region)
+		}
+	case ProviderGCS:
+		method = http.MethodPost
+		objectURL = "https://storage.googleapis.com/upload/storage/v1/b/" + bucket + "/o?uploadType=media&name=" + name
+		deleteURL = "https://storage.googleapis.com/storage/v1/b/" + bucket + "/o/" + name
+	default:
+		account, container, err := splitAzureBucket(bucket)
+		if err != nil {
+			return "", false, err
+		}
+
+		objectURL = fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", account, container, name)
+		deleteURL = objectURL
+		sign = func(req *http.Request, payload []byte) error {
+			req.Header.Set("x-ms-blob-type", "BlockBlob")
+			req.Header.Set("x-ms-version", "2019-12-12")
+			return nil
+		}
+	}
+
+	resp, err := c.do(method, objectURL, content, sign)
+	if err != nil {
+		return objectURL, false, err
+	}
+
+	if resp.status != http.StatusOK && resp.status != http.StatusCreated {
+		return objectURL, false, nil
+	}
+
+	// don't leave the check object behind
+	_, _ = c.do(http.MethodDelete, deleteURL, nil, sign)
+
+	return objectURL, true, nil
+}
+
+// response contains the parts of a provider response used by the checks
+type response struct {
+	status int
+	header http.Header
+	body   string
+}
+
+// do sends a request with the credentials of the provider
+func (c *Checker) do(method, URL string, payload []byte, sign func(req *http.Request, payload []byte) error) (*response, error) {
+	if c.provider == ProviderAzure {
+		if token := strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?"); token != "" {
+			separator := "?"
+			if strings.Contains(URL, "?") {
+				separator = "&"
+			}
+			URL += separator + token
+		}
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, URL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.provider == ProviderGCS {
+		if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	if sign != nil {
+		if err := sign(req, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.options.OnRequest != nil {
+		c.options.OnRequest()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{status: resp.StatusCode, header: resp.Header, body: string(data)}, nil
+}
+
+// signS3 signs a s3 request when aws credentials are available
+func signS3(req *http.Request, payload []byte, region string) error {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil
+	}
+
+	return signer.SignAWS(req, payload, &signer.AWSOptions{
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       region,
+		Service:      "s3",
+	}, time.Now())
+}
+
+// s3URL returns the path style url of a bucket, supporting the bucket names with dots
+func s3URL(bucket, region string) string {
+	if region == "us-east-1" {
+		return "https://s3.amazonaws.com/" + bucket
+	}
+
+	return "https://s3." + region + ".amazonaws.com/" + bucket
+}
+
+// s3RegionFromURL returns the region of a path style bucket url
+func s3RegionFromURL(URL string) string {
+	if strings.HasPrefix(URL, "https://s3.amazonaws.com/") {
+		return "us-east-1"
+	}
+
+	host := strings.TrimPrefix(URL, "https://s3.")
+
+	return host[:strings.Index(host, ".amazonaws.com")]
+}
+
+// splitAzureBucket splits an azure bucket in its storage account and container
+func splitAzureBucket(bucket string) (account, container string, err error) {
+	parts := strings.SplitN(bucket, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("azure bucket %s must be named account/container", bucket)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// randomHex returns a random hex string naming the check objects
+func randomHex() string {
+	data := make([]byte, 8)
+	_, _ = rand.Read(data)
+
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,6 @@
+// Package store persists the scan runs, their targets and findings in a
+// sql database, to query the findings across the historical runs.
+//
+// The sqlite and postgres drivers are linked with the sqlite and postgres
+// build tags, like go build -tags sqlite,postgres ./cmd/nuclei.
+package store
@@ -0,0 +1,9 @@
+//go:build postgres
+// +build postgres
+
+package store
+
+import (
+	// registers the postgres database/sql driver
+	_ "github.com/lib/pq"
+)
@@ -0,0 +1,9 @@
+//go:build sqlite
+// +build sqlite
+
+package store
+
+import (
+	// registers the sqlite3 database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -0,0 +1,315 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/summary"
+)
+
+// schema creates the tables, %s being the auto incremented id column type
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id %s,
+	started TIMESTAMP NOT NULL,
+	finished TIMESTAMP,
+	requests BIGINT NOT NULL DEFAULT 0,
+	errors BIGINT NOT NULL DEFAULT 0,
+	findings INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS targets (
+	run_id BIGINT NOT NULL REFERENCES runs(id),
+	target TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS labels (
+	run_id BIGINT NOT NULL REFERENCES runs(id),
+	name TEXT NOT NULL,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS findings (
+	run_id BIGINT NOT NULL REFERENCES runs(id),
+	hash TEXT NOT NULL,
+	template TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	host TEXT NOT NULL,
+	matched TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_hash ON findings(hash);
+CREATE INDEX IF NOT EXISTS findings_run ON findings(run_id);
+`
+
+// sqlStore is a result store on a sql database
+type sqlStore struct {
+	db       *sql.DB
+	postgres bool
+}
+
+func openSQL(driver, dsn string) (*sqlStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s store (is nuclei built with its driver?): %s", driver, err)
+	}
+
+	s := &sqlStore{db: db, postgres: driver == "postgres"}
+
+	idColumn := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	if s.postgres {
+		idColumn = "BIGSERIAL PRIMARY KEY"
+	}
+
+	for _, statement := range strings.Split(fmt.Sprintf(schema, idColumn), ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// query replaces the ? placeholders by the numbered postgres ones
+func (s *sqlStore) query(query string) string {
+	if !s.postgres {
+		return query
+	}
+
+	builder := &strings.Builder{}
+
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			builder.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// BeginRun records the start of a run with its labels and returns its id
+func (s *sqlStore) BeginRun(started time.Time, labels map[string]string) (int64, error) {
+	var (
+		id  int64
+		err error
+	)
+
+	if s.postgres {
+		err = s.db.QueryRow("INSERT INTO runs (started) VALUES ($1) RETURNING id", started.UTC()).Scan(&id)
+	} else {
+		var result sql.Result
+		if result, err = s.db.Exec("INSERT INTO runs (started) VALUES (?)", started.UTC()); err == nil {
+			id, err = result.LastInsertId()
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+
+	err = s.insert("INSERT INTO labels (run_id, name, value) VALUES (?, ?, ?)", len(names), func(i int) []interface{} {
+		return []interface{}{id, names[i], labels[names[i]]}
+	})
+
+	return id, err
+}
+
+// EndRun records the end of a run with its statistics
+func (s *sqlStore) EndRun(runID int64, stats *summary.Report) error {
+	_, err := s.db.Exec(s.query("UPDATE runs SET finished = ?, requests = ?, errors = ?, findings = ? WHERE id = ?"),
+		time.Now().UTC(), stats.Requests, stats.Errors, stats.Findings, runID)
+
+	return err
+}
+
+// AddTargets records the targets scanned by a run
+func (s *sqlStore) AddTargets(runID int64, targets []string) error {
+	return s.insert("INSERT INTO targets (run_id, target) VALUES (?, ?)", len(targets), func(i int) []interface{} {
+		return []interface{}{runID, targets[i]}
+	})
+}
+
+// AddFindings records the findings of a run
+func (s *sqlStore) AddFindings(runID int64, findings []*summary.Record) error {
+	return s.insert("INSERT INTO findings (run_id, hash, template, severity, host, matched) VALUES (?, ?, ?, ?, ?, ?)", len(findings), func(i int) []interface{} {
+		f := findings[i]
+		return []interface{}{runID, f.Hash, f.Template, f.Severity, f.Host, f.Matched}
+	})
+}
+
+// insert executes an insert statement for each row in a transaction
+func (s *sqlStore) insert(query string, rows int, values func(i int) []interface{}) error {
+	if rows == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	statement, err := tx.Prepare(s.query(query))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer statement.Close()
+
+	for i := 0; i < rows; i++ {
+		if _, err := statement.Exec(values(i)...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Runs returns the latest runs, most recent first
+func (s *sqlStore) Runs(limit int) ([]*Run, error) {
+	rows, err := s.db.Query(s.query("SELECT id, started, finished, requests, errors, findings FROM runs ORDER BY id DESC LIMIT ?"), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*Run
+
+	for rows.Next() {
+		run := &Run{}
+
+		var finished sql.NullTime
+		if err := rows.Scan(&run.ID, &run.Started, &finished, &run.Requests, &run.Errors, &run.Findings); err != nil {
+			return nil, err
+		}
+
+		run.Finished = finished.Time
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		if run.Labels, err = s.labels(run.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return runs, nil
+}
+
+// labels returns the labels of a run
+func (s *sqlStore) labels(runID int64) (map[string]string, error) {
+	rows, err := s.db.Query(s.query("SELECT name, value FROM labels WHERE run_id = ?"), runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels map[string]string
+
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[name] = value
+	}
+
+	return labels, rows.Err()
+}
+
+// Findings returns the findings matching a filter across the runs
+func (s *sqlStore) Findings(filter *Filter) ([]*Finding, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.RunID != 0 {
+		conditions = append(conditions, "run_id = ?")
+		args = append(args, filter.RunID)
+	}
+
+	for column, value := range map[string]string{
+		"template": filter.Template,
+		"severity": filter.Severity,
+		"host":     filter.Host,
+		"hash":     filter.Hash,
+	} {
+		if value != "" {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, value)
+		}
+	}
+
+	for name, value := range filter.Labels {
+		conditions = append(conditions, "run_id IN (SELECT run_id FROM labels WHERE name = ? AND value = ?)")
+		args = append(args, name, value)
+	}
+
+	query := "SELECT run_id, hash, template, severity, host, matched FROM findings"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY run_id DESC"
+
+	rows, err := s.db.Query(s.query(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var findings []*Finding
+
+	for rows.Next() {
+		f := &Finding{}
+		if err := rows.Scan(&f.RunID, &f.Hash, &f.Template, &f.Severity, &f.Host, &f.Matched); err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// the findings carry the labels of their run
+	labels := make(map[int64]map[string]string)
+
+	for _, f := range findings {
+		if _, ok := labels[f.RunID]; !ok {
+			if labels[f.RunID], err = s.labels(f.RunID); err != nil {
+				return nil, err
+			}
+		}
+
+		f.Labels = labels[f.RunID]
+	}
+
+	return findings, nil
+}
+
+// Close closes the connection to the store
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
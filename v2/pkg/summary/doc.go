@@ -0,0 +1,3 @@
+// Package summary collects the statistics of a scan reported
+// once all the templates have been executed.
+package summary
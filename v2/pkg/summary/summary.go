@@ -0,0 +1,222 @@
+package summary
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Summary collects the statistics of a scan
+type Summary struct {
+	requests uint64
+	errors   uint64
+	started  time.Time
+	// phases contains the total time spent in the phases of the requests
+	phases [len(Phases)]int64
+
+	mutex      sync.Mutex
+	severities map[string]int
+	hosts      map[string]int
+	findings   map[string]*Record
+	// aliases contains the first finding of each group of identical findings
+	aliases map[string]*Record
+}
+
+// Record describes a unique finding of a scan
+type Record struct {
+	Hash     string            `json:"hash"`
+	Template string            `json:"template"`
+	Severity string            `json:"severity"`
+	Host     string            `json:"host"`
+	Matched  string            `json:"matched"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	// Aliases contains the other hosts the finding was found identical on
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// FormatLabels returns the labels as sorted key=value pairs
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Phases contains the phases of the requests whose time is collected
+var Phases = [...]string{"dns", "connect", "tls", "ttfb"}
+
+// Report is a snapshot of the statistics of a scan
+type Report struct {
+	Duration   string            `json:"duration"`
+	Requests   uint64            `json:"requests"`
+	Errors     uint64            `json:"errors"`
+	ErrorKinds map[string]int    `json:"error_kinds,omitempty"`
+	Phases     map[string]string `json:"phases,omitempty"`
+	Findings   int               `json:"findings"`
+	Severities map[string]int    `json:"severities"`
+	Hosts      map[string]int    `json:"hosts"`
+	// Events contains the notable events of the scan, like the request
+	// budgets exceeded
+	Events []string `json:"events,omitempty"`
+}
+
+// New creates a new summary starting the scan duration
+func New() *Summary {
+	return &Summary{
+		started:    time.Now(),
+		severities: make(map[string]int),
+		hosts:      make(map[string]int),
+		findings:   make(map[string]*Record),
+		aliases:    make(map[string]*Record),
+	}
+}
+
+// Request records a request sent to a target
+func (s *Summary) Request() {
+	if s == nil {
+		return
+	}
+
+	atomic.AddUint64(&s.requests, 1)
+}
+
+// Timings records the milliseconds spent by a request in each of the phases
+func (s *Summary) Timings(milliseconds ...float64) {
+	if s == nil {
+		return
+	}
+
+	for i := 0; i < len(milliseconds) && i < len(s.phases); i++ {
+		atomic.AddInt64(&s.phases[i], int64(milliseconds[i]*float64(time.Millisecond)))
+	}
+}
+
+// Error records a failed execution of a template against a target
+func (s *Summary) Error() {
+	if s == nil {
+		return
+	}
+
+	atomic.AddUint64(&s.errors, 1)
+}
+
+// Finding records a result found on a host
+func (s *Summary) Finding(record *Record) {
+	if s == nil {
+		return
+	}
+
+	if record.Severity == "" {
+		record.Severity = "unknown"
+	}
+
+	s.mutex.Lock()
+	s.severities[record.Severity]++
+	s.hosts[record.Host]++
+	s.findings[record.Hash] = record
+	s.mutex.Unlock()
+}
+
+// Alias reports whether a finding is identical to one already found on
+// another alias of the same address, in which case its url is added to the
+// aliases of the first one instead of it being recorded. The key identifies
+// the finding, the address and the response.
+func (s *Summary) Alias(key string, record *Record) bool {
+	if s == nil || key == "" {
+		return false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	first, ok := s.aliases[key]
+	if !ok {
+		s.aliases[key] = record
+		return false
+	}
+
+	if first.Matched != record.Matched {
+		first.Aliases = append(first.Aliases, record.Matched)
+	}
+
+	return true
+}
+
+// Findings returns the severity of each unique finding by hash
+func (s *Summary) Findings() map[string]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	findings := make(map[string]string, len(s.findings))
+	for hash, record := range s.findings {
+		findings[hash] = record.Severity
+	}
+
+	return findings
+}
+
+// Records returns the unique findings of the scan
+func (s *Summary) Records() []*Record {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records := make([]*Record, 0, len(s.findings))
+	for _, record := range s.findings {
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// Report returns a snapshot of the collected statistics
+func (s *Summary) Report() *Report {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	report := &Report{
+		Duration:   time.Since(s.started).Round(time.Second).String(),
+		Requests:   atomic.LoadUint64(&s.requests),
+		Errors:     atomic.LoadUint64(&s.errors),
+		Severities: make(map[string]int, len(s.severities)),
+		Hosts:      make(map[string]int, len(s.hosts)),
+	}
+
+	for i, phase := range Phases {
+		if total := atomic.LoadInt64(&s.phases[i]); total > 0 {
+			if report.Phases == nil {
+				report.Phases = make(map[string]string, len(Phases))
+			}
+			report.Phases[phase] = time.Duration(total).Round(time.Millisecond).String()
+		}
+	}
+
+	for severity, count := range s.severities {
+		report.Severities[severity] = count
+		report.Findings += count
+	}
+
+	for host, count := range s.hosts {
+		report.Hosts[host] = count
+	}
+
+	return report
+}
+
+// WriteJSON writes the report as json to a file
+func (r *Report) WriteJSON(file string) error {
+	data, err := jsoniter.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,4 @@
+// Package takeover verifies subdomain takeovers by combining the CNAME
+// chain of a host, a database of service fingerprints and the response
+// served for the host.
+package takeover
@@ -0,0 +1,92 @@
+package takeover
+
+import (
+	"os"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Fingerprint describes how a dangling host of a service can be recognized
+type Fingerprint struct {
+	// Service is the name of the service
+	Service string `json:"service"`
+	// CNames contains the domains the hosts of the service are aliased to
+	CNames []string `json:"cname"`
+	// Fingerprints contains the strings served for unclaimed hosts
+	Fingerprints []string `json:"fingerprint,omitempty"`
+	// Status is the status code served for unclaimed hosts, 0 for any
+	Status int `json:"status,omitempty"`
+	// NXDomain reports that the host is dangling when the alias doesn't resolve
+	NXDomain bool `json:"nxdomain,omitempty"`
+	// Vulnerable reports if unclaimed hosts of the service can be claimed by anyone
+	Vulnerable bool `json:"vulnerable"`
+}
+
+// matchesCName returns true if a cname belongs to the service
+func (f *Fingerprint) matchesCName(cname string) bool {
+	cname = strings.TrimSuffix(strings.ToLower(cname), ".")
+
+	for _, domain := range f.CNames {
+		domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+		if cname == domain || strings.HasSuffix(cname, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesResponse returns the fingerprint found in a response, if any
+func (f *Fingerprint) matchesResponse(status int, body string) (string, bool) {
+	if f.Status != 0 && f.Status != status {
+		return "", false
+	}
+
+	for _, fingerprint := range f.Fingerprints {
+		if strings.Contains(body, fingerprint) {
+			return fingerprint, true
+		}
+	}
+
+	return "", false
+}
+
+// DefaultFingerprints contains the fingerprints of the common services
+var DefaultFingerprints = []*Fingerprint{
+	{Service: "AWS/S3", CNames: []string{"s3.amazonaws.com", "s3-website.amazonaws.com"}, Fingerprints: []string{"The specified bucket does not exist"}, Status: 404, Vulnerable: true},
+	{Service: "AWS/Elastic Beanstalk", CNames: []string{"elasticbeanstalk.com"}, NXDomain: true, Vulnerable: true},
+	{Service: "Microsoft Azure", CNames: []string{"azurewebsites.net", "cloudapp.net", "cloudapp.azure.com", "trafficmanager.net", "blob.core.windows.net", "azure-api.net", "azureedge.net"}, NXDomain: true, Vulnerable: true},
+	{Service: "Bitbucket", CNames: []string{"bitbucket.io"}, Fingerprints: []string{"Repository not found"}, Vulnerable: true},
+	{Service: "Ghost", CNames: []string{"ghost.io"}, Fingerprints: []string{"The thing you were looking for is no longer here, or never was"}, Vulnerable: true},
+	{Service: "GitHub", CNames: []string{"github.io"}, Fingerprints: []string{"There isn't a GitHub Pages site here."}, Status: 404, Vulnerable: true},
+	{Service: "Heroku", CNames: []string{"herokuapp.com", "herokudns.com", "herokussl.com"}, Fingerprints: []string{"No such app"}, Status: 404, Vulnerable: true},
+	{Service: "Help Scout", CNames: []string{"helpscoutdocs.com"}, Fingerprints: []string{"No settings were found for this company:"}, Vulnerable: true},
+	{Service: "Pantheon", CNames: []string{"pantheonsite.io"}, Fingerprints: []string{"The gods are wise, but do not know of the site which you seek."}, Status: 404, Vulnerable: true},
+	{Service: "Readme.io", CNames: []string{"readme.io"}, Fingerprints: []string{"Project doesnt exist... yet!"}, Vulnerable: true},
+	{Service: "Shopify", CNames: []string{"myshopify.com"}, Fingerprints: []string{"Sorry, this shop is currently unavailable."}, Vulnerable: true},
+	{Service: "Surge.sh", CNames: []string{"surge.sh"}, Fingerprints: []string{"project not found"}, Vulnerable: true},
+	{Service: "Tumblr", CNames: []string{"domains.tumblr.com"}, Fingerprints: []string{"Whatever you were looking for doesn't currently exist at this address"}, Vulnerable: true},
+	{Service: "Unbounce", CNames: []string{"unbouncepages.com"}, Fingerprints: []string{"The requested URL was not found on this server."}, Vulnerable: true},
+	{Service: "Wordpress", CNames: []string{"wordpress.com"}, Fingerprints: []string{"Do you want to register"}, Vulnerable: true},
+	{Service: "Zendesk", CNames: []string{"zendesk.com"}, Fingerprints: []string{"Help Center Closed"}, Vulnerable: true},
+	{Service: "Fastly", CNames: []string{"fastly.net"}, Fingerprints: []string{"Fastly error: unknown domain"}, Vulnerable: false},
+	{Service: "Netlify", CNames: []string{"netlify.app", "netlify.com"}, Fingerprints: []string{"Not Found - Request ID"}, Vulnerable: false},
+	{Service: "Cloudfront", CNames: []string{"cloudfront.net"}, Fingerprints: []string{"The request could not be satisfied"}, Vulnerable: false},
+}
+
+// LoadFingerprints loads a fingerprint database from a json file
+func LoadFingerprints(file string) ([]*Fingerprint, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var fingerprints []*Fingerprint
+	if err := jsoniter.NewDecoder(f).Decode(&fingerprints); err != nil {
+		return nil, err
+	}
+
+	return fingerprints, nil
+}
@@ -0,0 +1,226 @@
+package takeover
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	retryabledns "github.com/projectdiscovery/retryabledns"
+)
+
+// maxCNameChain is the maximum number of aliases followed for a host
+const maxCNameChain = 10
+
+// maxBodySize is the maximum size of the responses read for fingerprints
+const maxBodySize = 1024 * 1024
+
+// Options contains the configuration of the verifier
+type Options struct {
+	// Fingerprints contains the fingerprints of the services
+	Fingerprints []*Fingerprint
+	// Resolvers contains the resolvers used for the dns queries
+	Resolvers []string
+	// Timeout is the timeout of the http requests
+	Timeout time.Duration
+	// Transport is the transport of the http requests, the default one
+	// when nil
+	Transport http.RoundTripper
+	// OnRequest is optionally called for every request sent
+	OnRequest func()
+}
+
+// Result contains the verdict of the takeover verification of a host
+type Result struct {
+	// Host is the verified host
+	Host string
+	// CNames contains the cname chain of the host
+	CNames []string
+	// Service is the service the host is aliased to, empty if unknown
+	Service string
+	// Dangling reports if the last alias of the chain doesn't resolve
+	Dangling bool
+	// Fingerprint is the fingerprint found in the response of the host
+	Fingerprint string
+	// Vulnerable reports if the host can be claimed
+	Vulnerable bool
+}
+
+// Verifier verifies the subdomain takeovers of hosts
+type Verifier struct {
+	options    *Options
+	dnsClient  *retryabledns.Client
+	httpClient *http.Client
+}
+
+// New creates a takeover verifier
+func New(options *Options) *Verifier {
+	return &Verifier{
+		options:   options,
+		dnsClient: retryabledns.New(options.Resolvers, 1),
+		httpClient: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: options.Transport,
+			// the fingerprints are served by the host itself, not the page it redirects to
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Verify verifies if a host can be taken over. A host is reported as
+// vulnerable only if it is aliased to a service whose unclaimed hosts can
+// be claimed, and either its alias doesn't resolve for the services
+// detected by a dangling alias, or the response of the host contains the
+// fingerprint of the service.
+func (v *Verifier) Verify(host string) (*Result, error) {
+	result := &Result{Host: host}
+
+	cnames, err := v.resolveCNames(host)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CNames = cnames
+
+	var fingerprint *Fingerprint
+
+	for _, cname := range cnames {
+		for _, candidate := range v.options.Fingerprints {
+			if candidate.matchesCName(cname) {
+				fingerprint = candidate
+				break
+			}
+		}
+
+		if fingerprint != nil {
+			break
+		}
+	}
+
+	// hosts not aliased to a known service can't be verified
+	if fingerprint == nil {
+		return result, nil
+	}
+
+	result.Service = fingerprint.Service
+
+	result.Dangling, err = v.isDangling(cnames[len(cnames)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	if fingerprint.NXDomain {
+		result.Vulnerable = fingerprint.Vulnerable && result.Dangling
+		return result, nil
+	}
+
+	// a dangling alias can't serve the fingerprint of the service
+	if result.Dangling {
+		return result, nil
+	}
+
+	status, body, err := v.fetch(host)
+	if err != nil {
+		return result, nil
+	}
+
+	if matched, ok := fingerprint.matchesResponse(status, body); ok {
+		result.Fingerprint = matched
+		result.Vulnerable = fingerprint.Vulnerable
+	}
+
+	return result, nil
+}
+
+// resolveCNames returns the cname chain of a host
+func (v *Verifier) resolveCNames(host string) ([]string, error) {
+	var cnames []string
+
+	name := dns.Fqdn(host)
+
+	for i := 0; i < maxCNameChain; i++ {
+		resp, err := v.query(name, dns.TypeCNAME)
+		if err != nil {
+			return nil, err
+		}
+
+		var target string
+
+		for _, answer := range resp.Answer {
+			if cname, ok := answer.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				target = cname.Target
+				break
+			}
+		}
+
+		if target == "" {
+			break
+		}
+
+		cnames = append(cnames, strings.TrimSuffix(target, "."))
+		name = target
+	}
+
+	return cnames, nil
+}
+
+// isDangling returns true if a name doesn't exist
+func (v *Verifier) isDangling(name string) (bool, error) {
+	resp, err := v.query(dns.Fqdn(name), dns.TypeA)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Rcode == dns.RcodeNameError, nil
+}
+
+func (v *Verifier) query(name string, qtype uint16) (*dns.Msg, error) {
+	req := new(dns.Msg)
+	req.Id = dns.Id()
+	req.RecursionDesired = true
+	req.Question = []dns.Question{{Name: name, Qtype: qtype, Qclass: dns.ClassINET}}
+
+	if v.options.OnRequest != nil {
+		v.options.OnRequest()
+	}
+
+	resp, err := v.dnsClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve %s: %s", name, err)
+	}
+
+	return resp, nil
+}
+
+// fetch returns the response of a host, over https first
+func (v *Verifier) fetch(host string) (status int, body string, err error) {
+	for _, scheme := range []string{"https", "http"} {
+		if v.options.OnRequest != nil {
+			v.options.OnRequest()
+		}
+
+		var resp *http.Response
+
+		resp, err = v.httpClient.Get(scheme + "://" + host)
+		if err != nil {
+			continue
+		}
+
+		data, readErr := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		resp.Body.Close()
+
+		if readErr != nil {
+			err = readErr
+			continue
+		}
+
+		return resp.StatusCode, string(data), nil
+	}
+
+	return 0, "", err
+}
@@ -0,0 +1,220 @@
+package targets
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Credentials contains the client certificates and the headers used
+// to authenticate to the infrastructure apis.
+type Credentials struct {
+	certificates []tls.Certificate
+	headers      map[string]map[string]string
+}
+
+// NewCredentials creates an empty set of credentials
+func NewCredentials() *Credentials {
+	return &Credentials{headers: make(map[string]map[string]string)}
+}
+
+// ClientCertificates returns the client certificates to present to the servers requesting one
+func (c *Credentials) ClientCertificates() []tls.Certificate {
+	if c == nil {
+		return nil
+	}
+
+	return c.certificates
+}
+
+// Headers returns the authentication headers of a host
+func (c *Credentials) Headers(host string) map[string]string {
+	if c == nil {
+		return nil
+	}
+
+	return c.headers[host]
+}
+
+// addHeader adds an authentication header for a host
+func (c *Credentials) addHeader(host, name, value string) {
+	if c.headers[host] == nil {
+		c.headers[host] = make(map[string]string)
+	}
+
+	c.headers[host][name] = value
+}
+
+// kubeconfig contains the parts of a kubeconfig file used to reach a cluster
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server string `yaml:"server"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Username              string `yaml:"username"`
+			Password              string `yaml:"password"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// LoadKubeconfig loads the credentials of a kubeconfig context, the current one
+// if not provided, and returns the api server url of its cluster.
+func (c *Credentials) LoadKubeconfig(file, context string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	config := &kubeconfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return "", err
+	}
+
+	if context == "" {
+		context = config.CurrentContext
+	}
+
+	var clusterName, userName string
+
+	found := false
+
+	for _, ctx := range config.Contexts {
+		if ctx.Name == context {
+			clusterName, userName, found = ctx.Context.Cluster, ctx.Context.User, true
+			break
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("context %s not found", context)
+	}
+
+	var server string
+
+	for _, cluster := range config.Clusters {
+		if cluster.Name == clusterName {
+			server = cluster.Cluster.Server
+			break
+		}
+	}
+
+	if server == "" {
+		return "", fmt.Errorf("no server found for cluster %s", clusterName)
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return "", err
+	}
+
+	// relative paths are relative to the kubeconfig file
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+
+		return filepath.Join(filepath.Dir(file), path)
+	}
+
+	for _, user := range config.Users {
+		if user.Name != userName {
+			continue
+		}
+
+		credentials := user.User
+
+		switch {
+		case credentials.ClientCertificateData != "" || credentials.ClientCertificate != "":
+			certificate, err := loadKeyPair(credentials.ClientCertificateData, resolve(credentials.ClientCertificate), credentials.ClientKeyData, resolve(credentials.ClientKey))
+			if err != nil {
+				return "", err
+			}
+
+			c.certificates = append(c.certificates, certificate)
+		case credentials.Token != "" || credentials.TokenFile != "":
+			token := credentials.Token
+			if token == "" {
+				tokenData, err := ioutil.ReadFile(resolve(credentials.TokenFile))
+				if err != nil {
+					return "", err
+				}
+
+				token = strings.TrimSpace(string(tokenData))
+			}
+
+			c.addHeader(serverURL.Host, "Authorization", "Bearer "+token)
+		case credentials.Username != "":
+			auth := base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password))
+			c.addHeader(serverURL.Host, "Authorization", "Basic "+auth)
+		}
+	}
+
+	return server, nil
+}
+
+// LoadDockerCerts loads the client certificate of a docker certificates
+// directory, containing cert.pem and key.pem like DOCKER_CERT_PATH.
+func (c *Credentials) LoadDockerCerts(directory string) error {
+	certificate, err := tls.LoadX509KeyPair(filepath.Join(directory, "cert.pem"), filepath.Join(directory, "key.pem"))
+	if err != nil {
+		return err
+	}
+
+	c.certificates = append(c.certificates, certificate)
+
+	return nil
+}
+
+// loadKeyPair loads a certificate and its key, either inline as base64 or from files
+func loadKeyPair(certificateData, certificateFile, keyData, keyFile string) (tls.Certificate, error) {
+	certificate, err := readPEM(certificateData, certificateFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	key, err := readPEM(keyData, keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.X509KeyPair(certificate, key)
+}
+
+// readPEM returns pem data provided inline as base64 or in a file
+func readPEM(data, file string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	if file == "" {
+		return nil, errors.New("missing client certificate or key")
+	}
+
+	return ioutil.ReadFile(file)
+}
@@ -0,0 +1,3 @@
+// Package targets resolves the target schemes of infrastructure apis
+// like Kubernetes and Docker and the credentials used to reach them.
+package targets
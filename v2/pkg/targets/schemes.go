@@ -0,0 +1,47 @@
+package targets
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// scheme is the http scheme and default port a target scheme resolves to
+type scheme struct {
+	scheme string
+	port   string
+}
+
+// schemes contains the target schemes of the infrastructure apis
+var schemes = map[string]scheme{
+	"k8s":     {scheme: "https", port: "6443"},
+	"kubelet": {scheme: "https", port: "10250"},
+	"docker":  {scheme: "http", port: "2375"},
+	"dockers": {scheme: "https", port: "2376"},
+}
+
+// Normalize resolves the infrastructure api schemes of a target to an http
+// url (eg. k8s://host to https://host:6443), other targets being unchanged.
+func Normalize(target string) string {
+	index := strings.Index(target, "://")
+	if index < 0 {
+		return target
+	}
+
+	resolved, ok := schemes[strings.ToLower(target[:index])]
+	if !ok {
+		return target
+	}
+
+	parsed, err := url.Parse("http" + target[index:])
+	if err != nil {
+		return target
+	}
+
+	parsed.Scheme = resolved.scheme
+	if parsed.Port() == "" {
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), resolved.port)
+	}
+
+	return parsed.String()
+}
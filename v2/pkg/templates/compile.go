@@ -2,12 +2,22 @@ package templates
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/projectdiscovery/nuclei/v2/pkg/generators"
+	"github.com/projectdiscovery/nuclei/v2/pkg/headeraudit"
 	"github.com/projectdiscovery/nuclei/v2/pkg/matchers"
+	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/services"
+	"github.com/projectdiscovery/nuclei/v2/pkg/smuggling"
+	"github.com/projectdiscovery/nuclei/v2/pkg/storage"
+	"github.com/projectdiscovery/nuclei/v2/pkg/takeover"
+	"github.com/projectdiscovery/nuclei/v2/pkg/tlsprofile"
+	"github.com/projectdiscovery/nuclei/v2/pkg/useragent"
 	"gopkg.in/yaml.v2"
 )
 
@@ -29,10 +39,22 @@ func Parse(file string) (*Template, error) {
 	template.path = file
 
 	// If no requests, and it is also not a workflow, return error.
-	if len(template.BulkRequestsHTTP)+len(template.RequestsDNS) <= 0 {
+	if len(template.BulkRequestsHTTP)+len(template.RequestsDNS)+len(template.RequestsSmuggling)+len(template.RequestsStorage)+len(template.RequestsTakeover)+len(template.RequestsAudit)+len(template.RequestsService) <= 0 {
 		return nil, fmt.Errorf("no requests defined for %s", template.ID)
 	}
 
+	if template.Info.Intrusiveness != "" {
+		if _, ok := IntrusivenessLevel(template.Info.Intrusiveness); !ok {
+			return nil, fmt.Errorf("unknown intrusiveness %s in %s", template.Info.Intrusiveness, template.ID)
+		}
+	}
+
+	for _, test := range template.Tests {
+		if err := test.Compile(template.path); err != nil {
+			return nil, fmt.Errorf("%s in %s", err, template.ID)
+		}
+	}
+
 	// Compile the matchers and the extractors for http requests
 	for _, request := range template.BulkRequestsHTTP {
 		// Get the condition between the matchers
@@ -43,6 +65,90 @@ func Parse(file string) (*Template, error) {
 			request.SetMatchersCondition(condition)
 		}
 
+		if condition == matchers.WeightedCondition && (request.MatchersThreshold <= 0 || request.MatchersThreshold > 1) {
+			return nil, fmt.Errorf("matchers-threshold must be between 0 and 1 for weighted matchers in %s", template.ID)
+		}
+
+		switch request.Connection {
+		case "", requests.ConnectionClose, requests.ConnectionKeepAlive:
+		case requests.ConnectionReusePrevious:
+			if request.Pipeline || request.Threads > 0 {
+				return nil, fmt.Errorf("connection reuse-previous can't be used with pipeline or threads in %s", template.ID)
+			}
+		default:
+			return nil, fmt.Errorf("unknown connection %s in %s", request.Connection, template.ID)
+		}
+
+		if request.TLSCABundle != "" && !filepath.IsAbs(request.TLSCABundle) {
+			request.TLSCABundle = filepath.Join(filepath.Dir(template.path), request.TLSCABundle)
+		}
+
+		if request.TLSCABundle != "" && !generators.FileExists(request.TLSCABundle) {
+			return nil, fmt.Errorf("the ca bundle %s does not exist in %s", request.TLSCABundle, template.ID)
+		}
+
+		if _, err := tlsprofile.New(request.TLSMinVersion, request.TLSMaxVersion, request.TLSCipherSuites); err != nil {
+			return nil, fmt.Errorf("%s in %s", err, template.ID)
+		}
+
+		if err := request.Injection.Validate(); err != nil {
+			return nil, fmt.Errorf("%s in %s", err, template.ID)
+		}
+
+		if request.Injection != nil && (request.UnsafeExact || request.Signature != nil) {
+			return nil, fmt.Errorf("the exact and signed requests can't be injected in %s", template.ID)
+		}
+
+		if request.CacheBuster != "" && (request.UnsafeExact || request.Signature != nil) {
+			return nil, fmt.Errorf("the exact and signed requests can't have a cache buster in %s", template.ID)
+		}
+
+		if request.Rebind != "" && net.ParseIP(request.Rebind) == nil {
+			return nil, fmt.Errorf("invalid rebinding address %s in %s", request.Rebind, template.ID)
+		}
+
+		if request.BodyEncoding != "" && !requests.BodyEncodings[request.BodyEncoding] {
+			return nil, fmt.Errorf("unknown body encoding %s in %s", request.BodyEncoding, template.ID)
+		}
+
+		if err := request.Hooks.Compile(); err != nil {
+			return nil, fmt.Errorf("%s in %s", err, template.ID)
+		}
+
+		if request.Hooks != nil && request.UnsafeExact {
+			return nil, fmt.Errorf("the exact requests can't be changed by hooks in %s", template.ID)
+		}
+
+		if request.UserAgent != "" && !useragent.Strategies[request.UserAgent] {
+			return nil, fmt.Errorf("unknown user agent strategy %s in %s", request.UserAgent, template.ID)
+		}
+
+		if request.BodyEncoding != "" && request.UnsafeExact {
+			return nil, fmt.Errorf("the bodies of the exact requests can't be compressed in %s", template.ID)
+		}
+
+		if request.ChunkSize < 0 || (request.ChunkExtension != "" && request.ChunkSize == 0 && len(request.Trailers) == 0) {
+			return nil, fmt.Errorf("invalid chunk size in %s", template.ID)
+		}
+
+		if (request.ChunkSize > 0 || len(request.Trailers) > 0) && (!request.Unsafe || request.UnsafeExact || len(request.Raw) == 0) {
+			return nil, fmt.Errorf("only the unsafe raw requests can be chunked in %s", template.ID)
+		}
+
+		if request.HTTP3 && (request.Unsafe || request.UnsafeExact || request.Pipeline || request.Connection == requests.ConnectionReusePrevious) {
+			return nil, fmt.Errorf("http3 requests can't be unsafe, pipelined or reuse connections in %s", template.ID)
+		}
+
+		if request.Signature != nil {
+			if err := request.Signature.Validate(); err != nil {
+				return nil, fmt.Errorf("%s in %s", err, template.ID)
+			}
+
+			if request.Unsafe || request.UnsafeExact || request.Pipeline {
+				return nil, fmt.Errorf("signed requests can't be unsafe or pipelined in %s", template.ID)
+			}
+		}
+
 		// Set the attack type - used only in raw requests
 		attack, ok := generators.AttackTypes[request.AttackType]
 		if !ok {
@@ -55,6 +161,39 @@ func Parse(file string) (*Template, error) {
 		for name, payload := range request.Payloads {
 			switch pt := payload.(type) {
 			case string:
+				// built-in wordlists are replaced by their content
+				if strings.HasPrefix(pt, generators.BuiltinPrefix) {
+					wordlist, err := generators.BuiltinWordlist(pt)
+					if err != nil {
+						return nil, err
+					}
+
+					request.Payloads[name] = wordlist
+
+					continue
+				}
+
+				// host wordlists are resolved for each target while running
+				if generators.IsHostWordlist(pt) {
+					if err := generators.ValidateHostWordlist(pt); err != nil {
+						return nil, err
+					}
+
+					continue
+				}
+
+				// remote wordlists are replaced by their cached copy
+				if generators.IsRemoteWordlist(pt) {
+					wordlistPath, err := generators.DownloadWordlist(pt)
+					if err != nil {
+						return nil, fmt.Errorf("could not download the %s wordlist for payload %s: %s", pt, name, err)
+					}
+
+					request.Payloads[name] = wordlistPath
+
+					continue
+				}
+
 				// check if it's a multiline string list
 				if len(strings.Split(pt, "\n")) <= 1 {
 					// check if it's a worldlist file
@@ -87,11 +226,42 @@ func Parse(file string) (*Template, error) {
 			}
 		}
 
+		// Compile the payload encoders if any
+		encoders := make(map[string][]*generators.Encoder, len(request.PayloadEncoders))
+
+		for name, definitions := range request.PayloadEncoders {
+			if _, ok := request.Payloads[name]; !ok {
+				return nil, fmt.Errorf("encoders defined for unknown payload %s in %s", name, template.ID)
+			}
+
+			for _, definition := range definitions {
+				encoder, err := generators.CompileEncoder(definition)
+				if err != nil {
+					return nil, fmt.Errorf("could not compile encoder %s for payload %s: %s", definition, name, err)
+				}
+
+				encoders[name] = append(encoders[name], encoder)
+			}
+		}
+
+		request.SetPayloadEncoders(encoders)
+
 		for _, matcher := range request.Matchers {
 			matchErr := matcher.CompileMatchers()
 			if matchErr != nil {
 				return nil, matchErr
 			}
+
+			// the diff matchers compare the responses of the requests sent in order
+			if matcher.GetType() == matchers.DiffMatcher {
+				if request.Pipeline || request.Threads > 0 {
+					return nil, fmt.Errorf("diff matchers can't be used with pipeline or threads in %s", template.ID)
+				}
+
+				if matcher.Reference > request.Total() {
+					return nil, fmt.Errorf("diff matcher references unknown request %d in %s", matcher.Reference, template.ID)
+				}
+			}
 		}
 
 		for _, extractor := range request.Extractors {
@@ -114,6 +284,14 @@ func Parse(file string) (*Template, error) {
 			request.SetMatchersCondition(condition)
 		}
 
+		if condition == matchers.WeightedCondition && (request.MatchersThreshold <= 0 || request.MatchersThreshold > 1) {
+			return nil, fmt.Errorf("matchers-threshold must be between 0 and 1 for weighted matchers in %s", template.ID)
+		}
+
+		if request.Wildcard && request.IsZoneTransfer() {
+			return nil, fmt.Errorf("wildcard detection can't be used with zone transfers in %s", template.ID)
+		}
+
 		for _, matcher := range request.Matchers {
 			err = matcher.CompileMatchers()
 			if err != nil {
@@ -129,5 +307,90 @@ func Parse(file string) (*Template, error) {
 		}
 	}
 
+	// Validate the techniques of the smuggling requests
+	for _, request := range template.RequestsSmuggling {
+		for _, technique := range request.Techniques {
+			if !isSmugglingTechnique(technique) {
+				return nil, fmt.Errorf("unknown smuggling technique %s in %s", technique, template.ID)
+			}
+		}
+	}
+
+	// Validate the providers and checks of the storage requests
+	for _, request := range template.RequestsStorage {
+		if !storage.Providers[request.Provider] {
+			return nil, fmt.Errorf("unknown storage provider %s in %s", request.Provider, template.ID)
+		}
+
+		if len(request.Buckets) == 0 || len(request.Checks) == 0 {
+			return nil, fmt.Errorf("storage requests need buckets and checks in %s", template.ID)
+		}
+
+		for _, check := range request.Checks {
+			if !storage.Checks[check] {
+				return nil, fmt.Errorf("unknown storage check %s in %s", check, template.ID)
+			}
+		}
+	}
+
+	// Compile the matchers and the extractors for service requests
+	for _, request := range template.RequestsService {
+		if !services.IsSupported(request.Type) {
+			return nil, fmt.Errorf("unknown service %s in %s, supported services are %s", request.Type, template.ID, strings.Join(services.Names(), ", "))
+		}
+
+		condition, ok := matchers.ConditionTypes[request.MatchersCondition]
+		if !ok {
+			request.SetMatchersCondition(matchers.ORCondition)
+		} else {
+			request.SetMatchersCondition(condition)
+		}
+
+		if condition == matchers.WeightedCondition && (request.MatchersThreshold <= 0 || request.MatchersThreshold > 1) {
+			return nil, fmt.Errorf("matchers-threshold must be between 0 and 1 for weighted matchers in %s", template.ID)
+		}
+
+		for _, matcher := range request.Matchers {
+			if err := matcher.CompileMatchers(); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, extractor := range request.Extractors {
+			if err := extractor.CompileExtractors(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Validate the fingerprint databases of the takeover requests
+	for _, request := range template.RequestsTakeover {
+		if request.Fingerprints == "" {
+			continue
+		}
+
+		if _, err := takeover.LoadFingerprints(request.Fingerprints); err != nil {
+			return nil, fmt.Errorf("could not load takeover fingerprints in %s: %s", template.ID, err)
+		}
+	}
+
+	// Validate the policies of the audit requests
+	for _, request := range template.RequestsAudit {
+		if _, err := headeraudit.Policy(request.Profile, request.Checks, request.Exclude); err != nil {
+			return nil, fmt.Errorf("%s in %s", err, template.ID)
+		}
+	}
+
 	return template, nil
 }
+
+// isSmugglingTechnique checks if a smuggling technique is supported
+func isSmugglingTechnique(technique string) bool {
+	for _, known := range smuggling.Techniques {
+		if technique == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"regexp"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/extractors"
+)
+
+// placeholder matches the names of the placeholders of the requests
+var placeholder = regexp.MustCompile(`\{\{([A-Za-z0-9_]+)}}`)
+
+// PublishedValues returns the names of the values published to the scan
+// context by the extractors of the template.
+func (t *Template) PublishedValues() []string {
+	var all []*extractors.Extractor
+
+	for _, request := range t.BulkRequestsHTTP {
+		all = append(all, request.Extractors...)
+	}
+
+	for _, request := range t.RequestsService {
+		all = append(all, request.Extractors...)
+	}
+
+	var names []string
+
+	for _, extractor := range all {
+		if extractor.Publish && extractor.Name != "" {
+			names = append(names, extractor.Name)
+		}
+	}
+
+	return names
+}
+
+// Placeholders returns the names of the placeholders of the http requests
+func (t *Template) Placeholders() []string {
+	seen := make(map[string]struct{})
+
+	var names []string
+
+	add := func(text string) {
+		for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+			if _, ok := seen[match[1]]; !ok {
+				seen[match[1]] = struct{}{}
+				names = append(names, match[1])
+			}
+		}
+	}
+
+	for _, request := range t.BulkRequestsHTTP {
+		for _, path := range request.Path {
+			add(path)
+		}
+
+		for _, raw := range request.Raw {
+			add(raw)
+		}
+
+		for name, value := range request.Headers {
+			add(name)
+			add(value)
+		}
+
+		add(request.Body)
+	}
+
+	return names
+}
@@ -0,0 +1,95 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/storage"
+)
+
+// The intrusiveness classes of the templates, from the least to the most intrusive
+const (
+	// Passive templates only send requests any visitor would, or query third parties
+	Passive = "passive"
+	// SafeActive templates probe the targets without changing their state
+	SafeActive = "safe-active"
+	// Intrusive templates send payloads or requests that may change the state of the targets
+	Intrusive = "intrusive"
+	// Destructive templates may delete data or disrupt the targets
+	Destructive = "destructive"
+)
+
+// intrusivenessClasses contains the intrusiveness classes in increasing order
+var intrusivenessClasses = []string{Passive, SafeActive, Intrusive, Destructive}
+
+// IntrusivenessClasses returns the intrusiveness classes of the templates,
+// from the least to the most intrusive
+func IntrusivenessClasses() []string {
+	return append([]string(nil), intrusivenessClasses...)
+}
+
+// IntrusivenessLevel returns the rank of an intrusiveness class, safe being
+// accepted for safe-active, and false if the class is unknown
+func IntrusivenessLevel(class string) (int, bool) {
+	class = strings.ToLower(class)
+	if class == "safe" {
+		class = SafeActive
+	}
+
+	for level, known := range intrusivenessClasses {
+		if class == known {
+			return level, true
+		}
+	}
+
+	return 0, false
+}
+
+// safeMethods contains the http methods which don't change the state of the targets
+var safeMethods = map[string]bool{"": true, "GET": true, "HEAD": true, "OPTIONS": true}
+
+// Intrusiveness returns the intrusiveness class of the template. Unless it
+// is declared, it is inferred from the requests: the payloads, the unsafe
+// methods, the smuggling probes, the bucket writes and the credential checks
+// are intrusive, the dns queries passive and the other probes safe-active.
+func (t *Template) Intrusiveness() string {
+	if t.Info.Intrusiveness != "" {
+		return strings.ToLower(t.Info.Intrusiveness)
+	}
+
+	if len(t.RequestsSmuggling) > 0 {
+		return Intrusive
+	}
+
+	for _, request := range t.BulkRequestsHTTP {
+		if len(request.Payloads) > 0 || !safeMethods[strings.ToUpper(request.Method)] {
+			return Intrusive
+		}
+
+		for _, raw := range request.Raw {
+			fields := strings.Fields(raw)
+			if len(fields) > 0 && !safeMethods[strings.ToUpper(fields[0])] {
+				return Intrusive
+			}
+		}
+	}
+
+	for _, request := range t.RequestsStorage {
+		for _, check := range request.Checks {
+			if check == storage.CheckWrite {
+				return Intrusive
+			}
+		}
+	}
+
+	for _, request := range t.RequestsService {
+		if len(request.Credentials) > 0 {
+			return Intrusive
+		}
+	}
+
+	if len(t.BulkRequestsHTTP)+len(t.RequestsStorage)+len(t.RequestsTakeover)+len(t.RequestsAudit)+len(t.RequestsService) == 0 {
+		return Passive
+	}
+
+	return SafeActive
+}
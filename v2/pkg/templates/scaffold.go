@@ -0,0 +1,172 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templateIDPattern matches the valid template ids
+var templateIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// scaffolds contains the request skeletons of the protocols, the matchers
+// stubs to be completed by the author marked with TODO
+var scaffolds = map[string]string{
+	"http": `requests:
+  - method: GET
+    path:
+      - "{{BaseURL}}/"
+
+    matchers-condition: and
+    matchers:
+      - type: word
+        words:
+          - "TODO"
+        part: body
+
+      - type: status
+        status:
+          - 200
+
+    extractors:
+      - type: regex
+        part: body
+        regex:
+          - "TODO"
+`,
+	"dns": `dns:
+  - name: "{{FQDN}}"
+    type: A
+    class: inet
+    recursion: true
+    retries: 3
+
+    matchers:
+      - type: word
+        words:
+          - "TODO"
+`,
+	"smuggling": `smuggling:
+  - method: POST
+    path: /
+    techniques:
+      - cl.te
+      - te.cl
+    confirmations: 2
+`,
+	"storage": `storage:
+  - provider: s3
+    buckets:
+      - "{{Hostname}}"
+    checks:
+      - exists
+      - list
+`,
+	"takeover": `takeover:
+  - timeout: 10
+`,
+	"audit": `audit:
+  - path:
+      - /
+    profile: baseline
+`,
+	"service": `service:
+  - type: redis
+    timeout: 10
+
+    matchers:
+      - type: word
+        words:
+          - "TODO"
+`,
+}
+
+// ScaffoldProtocols returns the protocols the templates can be scaffolded for
+func ScaffoldProtocols() []string {
+	protocols := make([]string, 0, len(scaffolds)+1)
+	for protocol := range scaffolds {
+		protocols = append(protocols, protocol)
+	}
+	protocols = append(protocols, "workflow")
+	sort.Strings(protocols)
+
+	return protocols
+}
+
+// ScaffoldOptions contains the information of a scaffolded template
+type ScaffoldOptions struct {
+	// Protocol is the protocol of the requests of the template
+	Protocol string
+	// ID is the id of the template
+	ID string
+	// Name is the name of the template, the id by default
+	Name string
+	// Author is the author of the template
+	Author string
+	// Severity is the severity of the template, info by default
+	Severity string
+	// Tags contains the comma separated tags of the template
+	Tags string
+}
+
+// Scaffold returns the skeleton of a template for a protocol, with the
+// matchers stubs to be completed
+func Scaffold(options *ScaffoldOptions) ([]byte, error) {
+	skeleton, ok := scaffolds[options.Protocol]
+	if !ok && options.Protocol != "workflow" {
+		return nil, fmt.Errorf("unknown protocol %s, expected one of %s", options.Protocol, strings.Join(ScaffoldProtocols(), ", "))
+	}
+
+	if !templateIDPattern.MatchString(options.ID) {
+		return nil, fmt.Errorf("invalid template id '%s'", options.ID)
+	}
+
+	if options.Author == "" {
+		return nil, errors.New("no template author provided")
+	}
+
+	severity := strings.ToLower(options.Severity)
+	if severity == "" {
+		severity = "info"
+	}
+
+	if !knownSeverities[severity] {
+		return nil, fmt.Errorf("unknown severity %s", options.Severity)
+	}
+
+	name := options.Name
+	if name == "" {
+		name = options.ID
+	}
+
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "id: %s\n\ninfo:\n  name: %s\n  author: %s\n", options.ID, quote(name), quote(options.Author))
+
+	if options.Protocol == "workflow" {
+		builder.WriteString("  description: TODO\n\nvariables:\n  detect: TODO/detect.yaml\n  exploit: TODO/exploit.yaml\n\nlogic: |\n  if detect() {\n    exploit()\n  }\n")
+
+		return []byte(builder.String()), nil
+	}
+
+	fmt.Fprintf(&builder, "  severity: %s\n  description: TODO\n", severity)
+	if options.Tags != "" {
+		fmt.Fprintf(&builder, "  tags: %s\n", quote(options.Tags))
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(skeleton)
+
+	return []byte(builder.String()), nil
+}
+
+// quote quotes the yaml values which would not be read back as strings
+func quote(value string) string {
+	if strings.ContainsAny(value, ":#{}[]&*!|>'\"%@`") || strings.TrimSpace(value) != value {
+		return fmt.Sprintf("%q", value)
+	}
+
+	return value
+}
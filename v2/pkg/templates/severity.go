@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// knownSeverities contains the severities a template can be mapped to
+var knownSeverities = map[string]bool{
+	"info":     true,
+	"low":      true,
+	"medium":   true,
+	"high":     true,
+	"critical": true,
+}
+
+// Severities returns the known severities of the templates
+func Severities() []string {
+	severities := make([]string, 0, len(knownSeverities))
+	for severity := range knownSeverities {
+		severities = append(severities, severity)
+	}
+	sort.Strings(severities)
+
+	return severities
+}
+
+// SeverityMapping overrides the severity of the templates at load time
+type SeverityMapping struct {
+	// Templates maps a template id to the severity to use for it
+	Templates map[string]string `yaml:"templates,omitempty"`
+	// Severities maps a template severity to the one to use instead
+	Severities map[string]string `yaml:"severities,omitempty"`
+}
+
+// ParseSeverityMapping parses a yaml severity mapping file
+func ParseSeverityMapping(file string) (*SeverityMapping, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mapping := &SeverityMapping{}
+
+	err = yaml.NewDecoder(f).Decode(mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalize the mapping so lookups are case insensitive
+	templateSeverities := make(map[string]string, len(mapping.Templates))
+
+	for id, severity := range mapping.Templates {
+		severity = strings.ToLower(severity)
+		if !knownSeverities[severity] {
+			return nil, fmt.Errorf("unknown severity %s for template %s", severity, id)
+		}
+
+		templateSeverities[id] = severity
+	}
+
+	severities := make(map[string]string, len(mapping.Severities))
+
+	for from, to := range mapping.Severities {
+		to = strings.ToLower(to)
+		if !knownSeverities[to] {
+			return nil, fmt.Errorf("unknown severity %s for severity %s", to, from)
+		}
+
+		severities[strings.ToLower(from)] = to
+	}
+
+	mapping.Templates = templateSeverities
+	mapping.Severities = severities
+
+	return mapping, nil
+}
+
+// Severity returns the severity to use for a template, the template
+// id overrides taking precedence over the severity overrides.
+func (m *SeverityMapping) Severity(id, severity string) string {
+	if m == nil {
+		return severity
+	}
+
+	if override, ok := m.Templates[id]; ok {
+		return override
+	}
+
+	if override, ok := m.Severities[strings.ToLower(severity)]; ok {
+		return override
+	}
+
+	return severity
+}
+
+// Apply overrides the severity of a template
+func (m *SeverityMapping) Apply(template *Template) {
+	if m == nil || template == nil {
+		return
+	}
+
+	template.Info.Severity = m.Severity(template.ID, template.Info.Severity)
+}
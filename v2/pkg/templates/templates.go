@@ -2,6 +2,7 @@ package templates
 
 import (
 	"github.com/projectdiscovery/nuclei/v2/pkg/requests"
+	"github.com/projectdiscovery/nuclei/v2/pkg/templatetest"
 )
 
 // Template is a request template parsed from a yaml file
@@ -14,7 +15,27 @@ type Template struct {
 	BulkRequestsHTTP []*requests.BulkHTTPRequest `yaml:"requests,omitempty"`
 	// RequestsDNS contains the dns request to make in the template
 	RequestsDNS []*requests.DNSRequest `yaml:"dns,omitempty"`
-	path        string
+	// RequestsSmuggling contains the request smuggling probes to make in the template
+	RequestsSmuggling []*requests.SmugglingRequest `yaml:"smuggling,omitempty"`
+	// RequestsStorage contains the cloud storage bucket checks to make in the template
+	RequestsStorage []*requests.StorageRequest `yaml:"storage,omitempty"`
+	// RequestsTakeover contains the subdomain takeover verifications to make in the template
+	RequestsTakeover []*requests.TakeoverRequest `yaml:"takeover,omitempty"`
+	// RequestsAudit contains the security header audits to make in the template
+	RequestsAudit []*requests.AuditRequest `yaml:"audit,omitempty"`
+	// RequestsService contains the network service probes to make in the template
+	RequestsService []*requests.ServiceRequest `yaml:"service,omitempty"`
+	// RequiresURLs runs the template against the urls discovered for each target too
+	RequiresURLs bool `yaml:"requires-urls,omitempty"`
+	// Requires contains the template ids or the capabilities which must
+	// have matched on a target before the template is run on it
+	Requires []string `yaml:"requires,omitempty"`
+	// Provides contains the capabilities the template provides to the
+	// templates requiring them when it matches on a target
+	Provides []string `yaml:"provides,omitempty"`
+	// Tests contains the fixtures the template is validated against in test mode
+	Tests []*templatetest.Test `yaml:"tests,omitempty"`
+	path  string
 }
 
 // GetPath of the workflow
@@ -32,6 +53,29 @@ type Info struct {
 	Severity string `yaml:"severity,omitempty"`
 	// Description optionally describes the template.
 	Description string `yaml:"description,omitempty"`
+	// Tags optionally contains comma separated tags for the template
+	Tags string `yaml:"tags,omitempty"`
+	// Priority optionally orders the template execution, higher first
+	Priority int `yaml:"priority,omitempty"`
+	// Classification optionally classifies the vulnerability detected by the template
+	Classification *Classification `yaml:"classification,omitempty"`
+	// Intrusiveness optionally classifies the impact of the template on the
+	// targets (passive, safe-active, intrusive or destructive)
+	Intrusiveness string `yaml:"intrusiveness,omitempty"`
+}
+
+// Classification contains the vulnerability classification of a template
+type Classification struct {
+	// CVSSScore is the CVSS base score of the vulnerability
+	CVSSScore float64 `yaml:"cvss-score,omitempty" json:"cvss_score,omitempty"`
+	// CVSSMetrics is the CVSS vector string of the vulnerability
+	CVSSMetrics string `yaml:"cvss-metrics,omitempty" json:"cvss_metrics,omitempty"`
+	// CWEID contains the CWE ids of the vulnerability
+	CWEID []string `yaml:"cwe-id,omitempty" json:"cwe_id,omitempty"`
+	// CVEID contains the CVE ids of the vulnerability
+	CVEID []string `yaml:"cve-id,omitempty" json:"cve_id,omitempty"`
+	// EPSS is the exploit prediction scoring system score of the vulnerability
+	EPSS float64 `yaml:"epss,omitempty" json:"epss,omitempty"`
 }
 
 func (t *Template) GetHTTPRequestCount() int64 {
@@ -51,3 +95,53 @@ func (t *Template) GetDNSRequestCount() int64 {
 
 	return count
 }
+
+func (t *Template) GetSmugglingRequestCount() int64 {
+	var count int64 = 0
+	for _, request := range t.RequestsSmuggling {
+		count += request.GetRequestCount()
+	}
+
+	return count
+}
+
+func (t *Template) GetStorageRequestCount() int64 {
+	var count int64 = 0
+	for _, request := range t.RequestsStorage {
+		count += request.GetRequestCount()
+	}
+
+	return count
+}
+
+func (t *Template) GetTakeoverRequestCount() int64 {
+	var count int64 = 0
+	for _, request := range t.RequestsTakeover {
+		count += request.GetRequestCount()
+	}
+
+	return count
+}
+
+func (t *Template) GetAuditRequestCount() int64 {
+	var count int64 = 0
+	for _, request := range t.RequestsAudit {
+		count += request.GetRequestCount()
+	}
+
+	return count
+}
+
+func (t *Template) GetServiceRequestCount() int64 {
+	var count int64 = 0
+	for _, request := range t.RequestsService {
+		count += request.GetRequestCount()
+	}
+
+	return count
+}
+
+// GetRequestCount returns the number of requests of all the protocols of the template
+func (t *Template) GetRequestCount() int64 {
+	return t.GetHTTPRequestCount() + t.GetDNSRequestCount() + t.GetSmugglingRequestCount() + t.GetStorageRequestCount() + t.GetTakeoverRequestCount() + t.GetAuditRequestCount() + t.GetServiceRequestCount()
+}
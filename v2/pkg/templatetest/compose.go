@@ -0,0 +1,28 @@
+package templatetest
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ComposeUp starts the services of a docker compose file, waiting for them
+// to be running
+func ComposeUp(file string) error {
+	return compose(file, "up", "-d", "--wait")
+}
+
+// ComposeDown stops and removes the services of a docker compose file
+func ComposeDown(file string) error {
+	return compose(file, "down", "--volumes")
+}
+
+// compose runs a docker compose command on a file
+func compose(file string, args ...string) error {
+	output, err := exec.Command("docker", append([]string{"compose", "-f", file}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker compose %s failed: %s: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
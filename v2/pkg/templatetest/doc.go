@@ -0,0 +1,4 @@
+// Package templatetest contains the fixtures the templates ship to be
+// validated in test mode, the responses served to their requests or the
+// docker compose targets they run against, with the expected outcomes.
+package templatetest
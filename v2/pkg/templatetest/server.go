@@ -0,0 +1,92 @@
+package templatetest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Server serves the fixture responses of a test
+type Server struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	byPath   map[string]*Response
+	ordered  []*Response
+	position int
+}
+
+// NewServer starts a server of the responses of a test
+func NewServer(responses []*Response) *Server {
+	s := &Server{byPath: make(map[string]*Response)}
+
+	for _, response := range responses {
+		if response.Path != "" {
+			s.byPath[response.Path] = response
+		} else {
+			s.ordered = append(s.ordered, response)
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	return s
+}
+
+// next returns the response to a request, the responses without path being
+// served in order, the last one repeatedly
+func (s *Server) next(req *http.Request) *Response {
+	if response, ok := s.byPath[req.URL.RequestURI()]; ok {
+		return response
+	}
+	if response, ok := s.byPath[req.URL.Path]; ok {
+		return response
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.ordered) == 0 {
+		return nil
+	}
+
+	response := s.ordered[s.position]
+	if s.position < len(s.ordered)-1 {
+		s.position++
+	}
+
+	return response
+}
+
+// serve writes the fixture response of a request
+func (s *Server) serve(w http.ResponseWriter, req *http.Request) {
+	response := s.next(req)
+	if response == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	body := []byte(response.Body)
+	if response.BodyFile != "" {
+		data, err := ioutil.ReadFile(response.BodyFile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body = data
+	}
+
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+	w.Write(body) // nolint:errcheck // the client may have gone
+}
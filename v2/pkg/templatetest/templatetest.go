@@ -0,0 +1,106 @@
+package templatetest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Test is a fixture a template is validated against
+type Test struct {
+	// Name is the name of the test
+	Name string `yaml:"name"`
+	// Responses are the responses served to the http requests of the
+	// template, by path or in order for the ones without path
+	Responses []*Response `yaml:"responses,omitempty"`
+	// Target is the url of a live target the template runs against
+	Target string `yaml:"target,omitempty"`
+	// Compose is a docker compose file started before running the template
+	// against the target, relative to the template directory
+	Compose string `yaml:"compose,omitempty"`
+	// Matched is the expected outcome of the template
+	Matched bool `yaml:"matched"`
+	// Findings is the expected number of findings, not checked when zero
+	Findings int `yaml:"findings,omitempty"`
+}
+
+// Response is a fixture http response
+type Response struct {
+	// Path is the path of the requests the response is served to
+	Path string `yaml:"path,omitempty"`
+	// Status is the status code of the response, 200 by default
+	Status int `yaml:"status,omitempty"`
+	// Headers contains the headers of the response
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Body is the body of the response
+	Body string `yaml:"body,omitempty"`
+	// BodyFile is a file containing the body of the response, relative to
+	// the template directory
+	BodyFile string `yaml:"body-file,omitempty"`
+}
+
+// Compile validates the test of a template, resolving its files relative
+// to the template directory
+func (t *Test) Compile(templatePath string) error {
+	if (len(t.Responses) == 0) == (t.Target == "") {
+		return errors.New("a test needs either responses or a target")
+	}
+
+	if t.Compose != "" && t.Target == "" {
+		return errors.New("a test can't start a compose file without target")
+	}
+
+	if t.Findings < 0 || (t.Findings > 0 && !t.Matched) {
+		return errors.New("a test can't expect findings without match")
+	}
+
+	directory := filepath.Dir(templatePath)
+
+	if t.Compose != "" {
+		t.Compose = resolve(directory, t.Compose)
+		if _, err := os.Stat(t.Compose); err != nil {
+			return fmt.Errorf("could not find the compose file: %s", err)
+		}
+	}
+
+	for _, response := range t.Responses {
+		if response.BodyFile == "" {
+			continue
+		}
+
+		if response.Body != "" {
+			return errors.New("a response can't have both a body and a body file")
+		}
+
+		response.BodyFile = resolve(directory, response.BodyFile)
+		if _, err := os.Stat(response.BodyFile); err != nil {
+			return fmt.Errorf("could not find the body file: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// Check returns an error describing the difference between the outcome of
+// the template and the expected one
+func (t *Test) Check(matched bool, findings int) error {
+	if matched != t.Matched {
+		return fmt.Errorf("expected matched %v, got %v", t.Matched, matched)
+	}
+
+	if t.Findings > 0 && findings != t.Findings {
+		return fmt.Errorf("expected %d findings, got %d", t.Findings, findings)
+	}
+
+	return nil
+}
+
+// resolve returns the path of a file relative to a directory
+func resolve(directory, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+
+	return filepath.Join(directory, file)
+}
@@ -0,0 +1,4 @@
+// Package tlsprofile restricts the tls versions and the cipher suites
+// negotiated with the targets, either to reach the legacy hosts or to
+// check that the deprecated protocols are disabled.
+package tlsprofile
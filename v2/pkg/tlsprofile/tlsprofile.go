@@ -0,0 +1,136 @@
+package tlsprofile
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// Versions contains the tls versions by name
+var Versions = map[string]uint16{
+	"tls1.0": tls.VersionTLS10,
+	"tls1.1": tls.VersionTLS11,
+	"tls1.2": tls.VersionTLS12,
+	"tls1.3": tls.VersionTLS13,
+}
+
+// Profile contains the tls versions and the cipher suites allowed for the
+// connections to the targets
+type Profile struct {
+	minVersion   uint16
+	maxVersion   uint16
+	cipherSuites []uint16
+}
+
+// New creates a profile from the names of the versions and the cipher
+// suites, returning nil when nothing is restricted. The cipher suites
+// only apply up to tls1.2, the tls1.3 ones can't be configured.
+func New(minVersion, maxVersion string, cipherSuites []string) (*Profile, error) {
+	return (*Profile)(nil).With(minVersion, maxVersion, cipherSuites)
+}
+
+// With returns the profile of a template, its versions and cipher suites
+// overriding the ones of the scan when defined
+func (p *Profile) With(minVersion, maxVersion string, cipherSuites []string) (*Profile, error) {
+	profile := &Profile{}
+	if p != nil {
+		*profile = *p
+	}
+
+	var err error
+
+	if minVersion != "" {
+		if profile.minVersion, err = parseVersion(minVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if maxVersion != "" {
+		if profile.maxVersion, err = parseVersion(maxVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	if profile.minVersion != 0 && profile.maxVersion != 0 && profile.minVersion > profile.maxVersion {
+		return nil, fmt.Errorf("tls version %s is above %s", versionName(profile.minVersion), versionName(profile.maxVersion))
+	}
+
+	if len(cipherSuites) > 0 {
+		if profile.cipherSuites, err = parseCipherSuites(cipherSuites); err != nil {
+			return nil, err
+		}
+	}
+
+	if profile.minVersion == 0 && profile.maxVersion == 0 && len(profile.cipherSuites) == 0 {
+		return nil, nil
+	}
+
+	return profile, nil
+}
+
+// Apply restricts the versions and the cipher suites of a tls configuration
+func (p *Profile) Apply(config *tls.Config) *tls.Config {
+	if p == nil {
+		return config
+	}
+
+	if p.minVersion != 0 {
+		config.MinVersion = p.minVersion
+	}
+	if p.maxVersion != 0 {
+		config.MaxVersion = p.maxVersion
+	}
+	if len(p.cipherSuites) > 0 {
+		config.CipherSuites = p.cipherSuites
+	}
+
+	return config
+}
+
+// parseVersion returns the tls version of a name
+func parseVersion(name string) (uint16, error) {
+	version, ok := Versions[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("unknown tls version %s", name)
+	}
+
+	return version, nil
+}
+
+// versionName returns the name of a tls version
+func versionName(version uint16) string {
+	for name, value := range Versions {
+		if value == version {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// parseCipherSuites returns the ids of the cipher suites by their IANA
+// names, the insecure ones included
+func parseCipherSuites(names []string) ([]uint16, error) {
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %s", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,4 @@
+// Package trust contains the root certificates trusted for the tls
+// connections to the targets, verifying their certificate chains either
+// to enforce them or to match on their validity.
+package trust
@@ -0,0 +1,121 @@
+package trust
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// Store contains the roots trusted for the certificates of the targets and
+// whether the connections to the targets with invalid chains are refused
+type Store struct {
+	verify  bool
+	bundles []string
+	roots   *x509.CertPool
+}
+
+// New creates a store of the system roots and of the certificates of the
+// PEM bundles, returning nil when the chains are neither enforced nor
+// verified against custom roots.
+func New(verify bool, bundles ...string) (*Store, error) {
+	s := &Store{verify: verify}
+
+	for _, bundle := range bundles {
+		if bundle != "" {
+			s.bundles = append(s.bundles, bundle)
+		}
+	}
+
+	if !s.verify && len(s.bundles) == 0 {
+		return nil, nil
+	}
+
+	if len(s.bundles) == 0 {
+		return s, nil
+	}
+
+	// the bundles are trusted in addition to the system roots
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+
+	for _, bundle := range s.bundles {
+		data, err := ioutil.ReadFile(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca bundle %s: %s", bundle, err)
+		}
+
+		if !roots.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in ca bundle %s", bundle)
+		}
+	}
+
+	s.roots = roots
+
+	return s, nil
+}
+
+// With returns the store of a template, enforcing the chains when either
+// the scan or the template does and trusting the bundle of the template
+// in addition to the ones of the scan.
+func (s *Store) With(verify bool, bundle string) (*Store, error) {
+	if s == nil {
+		return New(verify, bundle)
+	}
+
+	if bundle == "" && (s.verify || !verify) {
+		return s, nil
+	}
+
+	bundles := append([]string{}, s.bundles...)
+
+	return New(s.verify || verify, append(bundles, bundle)...)
+}
+
+// Verify returns true if the connections to the targets with invalid
+// certificate chains are refused
+func (s *Store) Verify() bool {
+	return s != nil && s.verify
+}
+
+// Roots returns the trusted roots, nil for the system ones
+func (s *Store) Roots() *x509.CertPool {
+	if s == nil {
+		return nil
+	}
+
+	return s.roots
+}
+
+// Config returns the tls configuration of the connections to a host
+func (s *Store) Config(serverName string) *tls.Config {
+	return &tls.Config{
+		ServerName:         serverName,
+		RootCAs:            s.Roots(),
+		InsecureSkipVerify: !s.Verify(), // nolint:gosec // the chains are only verified when requested
+	}
+}
+
+// VerifyChain verifies the certificates presented by a host, the leaf
+// first, against the trusted roots
+func (s *Store) VerifyChain(certificates []*x509.Certificate, host string) error {
+	if len(certificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         s.Roots(),
+		Intermediates: intermediates,
+	})
+
+	return err
+}
@@ -0,0 +1,4 @@
+// Package tunnel opens the connections to the targets through the
+// proxies, either socks5 proxies or http proxies tunneling them with
+// CONNECT requests authenticated with the basic or ntlm schemes.
+package tunnel
@@ -0,0 +1,141 @@
+package tunnel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	ntlmNegotiateUnicode         = 0x00000001
+	ntlmRequestTarget            = 0x00000004
+	ntlmNegotiateNTLM            = 0x00000200
+	ntlmNegotiateAlwaysSign      = 0x00008000
+	ntlmExtendedSessionSecurity  = 0x00080000
+	ntlmNegotiateTargetInfo      = 0x00800000
+	ntlmNegotiate128             = 0x20000000
+	ntlmNegotiate56              = 0x80000000
+	ntlmNegotiateFlags           = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign | ntlmExtendedSessionSecurity | ntlmNegotiateTargetInfo | ntlmNegotiate128 | ntlmNegotiate56
+	ntlmChallengeMinLength       = 48
+	ntlmAuthenticateHeaderLength = 64
+	// windowsEpochOffset is the number of 100ns intervals between 1601
+	// and 1970, the epochs of the windows and unix timestamps
+	windowsEpochOffset = 116444736000000000
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// ntlmCredentials are the credentials of the ntlm authentication, the
+// user being given as DOMAIN\user
+type ntlmCredentials struct {
+	domain   string
+	user     string
+	password string
+}
+
+func newNTLMCredentials(user, password string) *ntlmCredentials {
+	credentials := &ntlmCredentials{user: user, password: password}
+
+	if i := strings.Index(user, `\`); i != -1 {
+		credentials.domain = user[:i]
+		credentials.user = user[i+1:]
+	}
+
+	return credentials
+}
+
+// negotiate returns the ntlm negotiate message starting the authentication
+func (c *ntlmCredentials) negotiate() []byte {
+	message := make([]byte, 32)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 1)
+	binary.LittleEndian.PutUint32(message[12:], ntlmNegotiateFlags)
+
+	return message
+}
+
+// authenticate returns the ntlmv2 authenticate message answering the
+// challenge message of the server
+func (c *ntlmCredentials) authenticate(challenge []byte) ([]byte, error) {
+	if len(challenge) < ntlmChallengeMinLength || !bytes.Equal(challenge[:8], ntlmSignature) || binary.LittleEndian.Uint32(challenge[8:]) != 2 {
+		return nil, errors.New("invalid ntlm challenge")
+	}
+
+	serverChallenge := challenge[24:32]
+
+	targetInfoLength := int(binary.LittleEndian.Uint16(challenge[40:]))
+	targetInfoOffset := int(binary.LittleEndian.Uint32(challenge[44:]))
+	if targetInfoOffset+targetInfoLength > len(challenge) {
+		return nil, errors.New("invalid ntlm challenge target info")
+	}
+	targetInfo := challenge[targetInfoOffset : targetInfoOffset+targetInfoLength]
+
+	clientChallenge := make([]byte, 8)
+	if _, err := rand.Read(clientChallenge); err != nil {
+		return nil, err
+	}
+
+	// NTOWFv2 keyed with the md4 of the password
+	key := hmacMD5(md4(utf16le(c.password)), utf16le(strings.ToUpper(c.user)+c.domain))
+
+	timestamp := make([]byte, 8)
+	binary.LittleEndian.PutUint64(timestamp, uint64(time.Now().UnixNano()/100+windowsEpochOffset))
+
+	blob := &bytes.Buffer{}
+	blob.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	blob.Write(timestamp)
+	blob.Write(clientChallenge)
+	blob.Write([]byte{0, 0, 0, 0})
+	blob.Write(targetInfo)
+	blob.Write([]byte{0, 0, 0, 0})
+
+	ntProof := hmacMD5(key, append(append([]byte{}, serverChallenge...), blob.Bytes()...))
+	ntResponse := append(ntProof, blob.Bytes()...)
+	lmResponse := append(hmacMD5(key, append(append([]byte{}, serverChallenge...), clientChallenge...)), clientChallenge...)
+
+	fields := [][]byte{lmResponse, ntResponse, utf16le(c.domain), utf16le(c.user), utf16le(""), nil}
+
+	message := make([]byte, ntlmAuthenticateHeaderLength)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 3)
+
+	offset := ntlmAuthenticateHeaderLength
+	for i, field := range fields {
+		binary.LittleEndian.PutUint16(message[12+i*8:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(message[14+i*8:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(message[16+i*8:], uint32(offset))
+		offset += len(field)
+	}
+	binary.LittleEndian.PutUint32(message[60:], ntlmNegotiateFlags)
+
+	for _, field := range fields {
+		message = append(message, field...)
+	}
+
+	return message, nil
+}
+
+func hmacMD5(key, data []byte) []byte {
+	mac := hmac.New(md5.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// utf16le encodes a string as little endian utf-16
+func utf16le(value string) []byte {
+	encoded := utf16.Encode([]rune(value))
+
+	data := make([]byte, len(encoded)*2)
+	for i, r := range encoded {
+		binary.LittleEndian.PutUint16(data[i*2:], r)
+	}
+
+	return data
+}
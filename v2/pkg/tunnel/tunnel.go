@@ -0,0 +1,278 @@
+package tunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Auths contains the supported proxy authentication schemes
+var Auths = map[string]bool{
+	"basic": true,
+	"ntlm":  true,
+}
+
+// Options contains the configuration of the proxy
+type Options struct {
+	// ProxyURL is the url of the http or https proxy, with its credentials
+	ProxyURL string
+	// Auth is the authentication scheme of the proxy (basic, ntlm)
+	Auth string
+	// SocksURL is the url of the socks5 proxy, with its credentials. The
+	// http proxy is reached through it when both are configured.
+	SocksURL string
+	// Timeout is the timeout of the connection to the proxy
+	Timeout time.Duration
+}
+
+// Dialer opens the connections to the targets, tunneled through the
+// proxies when configured
+type Dialer struct {
+	proxy   *url.URL
+	address string
+	auth    string
+	socks   proxy.ContextDialer
+	timeout time.Duration
+}
+
+// New creates a dialer from the options, returning nil without proxy
+func New(options *Options) (*Dialer, error) {
+	if options.ProxyURL == "" && options.SocksURL == "" {
+		return nil, nil
+	}
+
+	d := &Dialer{timeout: options.Timeout}
+
+	if options.SocksURL != "" {
+		socks, err := newSocksDialer(options.SocksURL, options.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		d.socks = socks
+	}
+
+	if options.ProxyURL == "" {
+		return d, nil
+	}
+
+	proxyURL, err := url.Parse(options.ProxyURL)
+	if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy url %s", options.ProxyURL)
+	}
+
+	auth := strings.ToLower(options.Auth)
+	if auth == "" {
+		auth = "basic"
+	}
+	if !Auths[auth] {
+		return nil, fmt.Errorf("unknown proxy authentication %s", options.Auth)
+	}
+
+	d.proxy, d.address, d.auth = proxyURL, proxyURL.Host, auth
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		d.address = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	return d, nil
+}
+
+// newSocksDialer creates a dialer connecting through a socks5 proxy
+func newSocksDialer(socksURL string, timeout time.Duration) (proxy.ContextDialer, error) {
+	parsed, err := url.Parse(socksURL)
+	if err != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid socks proxy url %s", socksURL)
+	}
+
+	var auth *proxy.Auth
+	if parsed.User != nil {
+		auth = &proxy.Auth{User: parsed.User.Username()}
+		auth.Password, _ = parsed.User.Password()
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", parsed.Host, auth, &net.Dialer{Timeout: timeout})
+	if err != nil {
+		return nil, err
+	}
+
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.New("the socks proxy dialer doesn't support contexts")
+	}
+
+	return contextDialer, nil
+}
+
+// Auth returns the authentication scheme of the http proxy if any
+func (d *Dialer) Auth() string {
+	if d == nil || d.proxy == nil {
+		return ""
+	}
+
+	return d.auth
+}
+
+// Dial opens a connection to an address through the proxy, or directly
+// without proxy
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext opens a connection to an address through the proxy, or
+// directly without proxy
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if d == nil {
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}
+
+	var dialer proxy.ContextDialer = &net.Dialer{Timeout: d.timeout}
+	if d.socks != nil {
+		dialer = d.socks
+	}
+
+	if d.proxy == nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", d.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.proxy.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: d.proxy.Hostname(), InsecureSkipVerify: true}) // nolint:gosec // intended for scanning
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline) // nolint:errcheck // best effort
+	} else if d.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(d.timeout)) // nolint:errcheck // best effort
+	}
+
+	if err := d.connect(conn, address); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{}) // nolint:errcheck // best effort
+
+	return conn, nil
+}
+
+// connect asks the proxy to tunnel the connection to the address,
+// authenticating on the same connection for ntlm
+func (d *Dialer) connect(conn net.Conn, address string) error {
+	reader := bufio.NewReader(conn)
+
+	var (
+		username string
+		password string
+	)
+
+	if d.proxy.User != nil {
+		username = d.proxy.User.Username()
+		password, _ = d.proxy.User.Password()
+	}
+
+	if d.proxy.User == nil || d.auth == "basic" {
+		var authorization string
+		if d.proxy.User != nil {
+			authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		}
+
+		resp, err := roundTrip(conn, reader, address, authorization)
+		if err != nil {
+			return err
+		}
+
+		return connectStatus(resp)
+	}
+
+	credentials := newNTLMCredentials(username, password)
+
+	resp, err := roundTrip(conn, reader, address, "NTLM "+base64.StdEncoding.EncodeToString(credentials.negotiate()))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusProxyAuthRequired {
+		return connectStatus(resp)
+	}
+
+	var challenge []byte
+
+	for _, header := range resp.Header.Values("Proxy-Authenticate") {
+		if strings.HasPrefix(header, "NTLM ") {
+			challenge, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "NTLM "))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if challenge == nil {
+		return errors.New("the proxy did not answer with a ntlm challenge")
+	}
+
+	authenticate, err := credentials.authenticate(challenge)
+	if err != nil {
+		return err
+	}
+
+	resp, err = roundTrip(conn, reader, address, "NTLM "+base64.StdEncoding.EncodeToString(authenticate))
+	if err != nil {
+		return err
+	}
+
+	return connectStatus(resp)
+}
+
+// roundTrip sends a CONNECT request and reads the response, consuming its
+// body as the connection is reused
+func roundTrip(conn net.Conn, reader *bufio.Reader, address, authorization string) (*http.Response, error) {
+	request := "CONNECT " + address + " HTTP/1.1\r\nHost: " + address + "\r\nProxy-Connection: Keep-Alive\r\n"
+	if authorization != "" {
+		request += "Proxy-Authorization: " + authorization + "\r\n"
+	}
+	request += "\r\n"
+
+	if _, err := io.WriteString(conn, request); err != nil {
+		return nil, err
+	}
+
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body) // nolint:errcheck // best effort
+		resp.Body.Close()
+	}
+
+	return resp, nil
+}
+
+func connectStatus(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy refused the connection: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package useragent picks the user agents of the http requests, fixed,
+// rotated from a pool or from a browser profile per host.
+package useragent
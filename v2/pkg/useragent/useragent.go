@@ -0,0 +1,182 @@
+package useragent
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default is the user agent of the requests unless configured otherwise
+const Default = "Nuclei - Open-source project (github.com/projectdiscovery/nuclei)"
+
+// The strategies picking the user agents
+const (
+	// Fixed sends the custom user agent, or the default one
+	Fixed = "fixed"
+	// Random sends a user agent of the pool chosen for every request
+	Random = "random"
+	// Browser sends the headers of a browser profile chosen for every host
+	Browser = "browser"
+	// None sends no user agent
+	None = "none"
+)
+
+// Strategies contains the supported strategies
+var Strategies = map[string]bool{Fixed: true, Random: true, Browser: true, None: true}
+
+// Profile contains the user agent and the headers sent with it
+type Profile struct {
+	UserAgent string
+	Headers   map[string]string
+}
+
+// browserAccept is the accept header of the html pages of the browsers
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+// profiles contains realistic browser profiles
+var profiles = []*Profile{
+	{
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.9", "Upgrade-Insecure-Requests": "1"},
+	},
+	{
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.9", "Upgrade-Insecure-Requests": "1"},
+	},
+	{
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.5", "Upgrade-Insecure-Requests": "1"},
+	},
+	{
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.5", "Upgrade-Insecure-Requests": "1"},
+	},
+	{
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.9"},
+	},
+	{
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+		Headers:   map[string]string{"Accept": browserAccept, "Accept-Language": "en-US,en;q=0.9", "Upgrade-Insecure-Requests": "1"},
+	},
+}
+
+// Options contains the configuration of the user agents
+type Options struct {
+	// Strategy is the strategy picking the user agents, fixed by default
+	Strategy string
+	// UserAgent is the custom user agent of the fixed strategy
+	UserAgent string
+	// PoolFile is a file of the user agents of the random strategy, one per
+	// line, the user agents of the browser profiles being used otherwise
+	PoolFile string
+}
+
+// Picker picks the user agents of the requests
+type Picker struct {
+	strategy string
+	fixed    string
+	pool     []string
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+// New returns a picker of user agents
+func New(options *Options) (*Picker, error) {
+	p := &Picker{
+		strategy: strings.ToLower(options.Strategy),
+		fixed:    options.UserAgent,
+		random:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	if p.strategy == "" {
+		p.strategy = Fixed
+	}
+
+	if !Strategies[p.strategy] {
+		return nil, fmt.Errorf("unknown user agent strategy %s", options.Strategy)
+	}
+
+	if options.PoolFile != "" {
+		pool, err := readPool(options.PoolFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pool) == 0 {
+			return nil, fmt.Errorf("no user agents in %s", options.PoolFile)
+		}
+		p.pool = pool
+	}
+
+	return p, nil
+}
+
+// readPool reads a file of user agents, skipping the empty lines and comments
+func readPool(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var pool []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			pool = append(pool, line)
+		}
+	}
+
+	return pool, scanner.Err()
+}
+
+// Pick returns the profile of a request to a host with a strategy, the
+// strategy of the picker being used when empty. It returns nil when no
+// user agent is sent.
+func (p *Picker) Pick(strategy, host string) *Profile {
+	if strategy == "" && p != nil {
+		strategy = p.strategy
+	}
+
+	switch strategy {
+	case None:
+		return nil
+	case Random:
+		return &Profile{UserAgent: p.randomUserAgent()}
+	case Browser:
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(strings.ToLower(host)))
+
+		return profiles[hash.Sum32()%uint32(len(profiles))]
+	}
+
+	if p != nil && p.fixed != "" {
+		return &Profile{UserAgent: p.fixed}
+	}
+
+	return &Profile{UserAgent: Default}
+}
+
+// randomUserAgent returns a user agent of the pool
+func (p *Picker) randomUserAgent() string {
+	if p == nil {
+		return profiles[rand.Intn(len(profiles))].UserAgent
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.pool) > 0 {
+		return p.pool[p.random.Intn(len(p.pool))]
+	}
+
+	return profiles[p.random.Intn(len(profiles))].UserAgent
+}
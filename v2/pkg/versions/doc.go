@@ -0,0 +1,3 @@
+// Package versions compares the versions of the detected software and
+// evaluates version constraints like ">=1.2, <1.4.7".
+package versions
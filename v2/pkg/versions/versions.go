@@ -0,0 +1,251 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Compare compares two versions, returning -1, 0 or 1 if the first version
+// is lower, equal or greater than the second. Versions are split in numeric
+// and alphabetic parts, the missing parts being zero, and pre-releases like
+// 1.2.0-rc1 are lower than their release.
+func Compare(a, b string) int {
+	mainA, preA := split(a)
+	mainB, preB := split(b)
+
+	if result := compareParts(parts(mainA), parts(mainB)); result != 0 {
+		return result
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+
+	return compareParts(parts(preA), parts(preB))
+}
+
+// split separates the release and the pre-release of a version
+func split(version string) (release, prerelease string) {
+	version = strings.TrimSpace(strings.ToLower(version))
+	version = strings.TrimPrefix(version, "v")
+
+	// the build metadata isn't part of the precedence
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+
+	if i := strings.Index(version, "-"); i > 0 {
+		return version[:i], version[i+1:]
+	}
+
+	return version, ""
+}
+
+// parts splits a version in its numeric and alphabetic parts
+func parts(version string) []string {
+	var result []string
+
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			result = append(result, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range version {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case current.Len() > 0 && unicode.IsDigit(r) != isNumeric(current.String()):
+			flush()
+			current.WriteRune(r)
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return result
+}
+
+// compareParts compares the parts of two versions in order. The missing
+// numeric parts are zero and the extra alphabetic parts are patch levels
+// like in 1.0.2a.
+func compareParts(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a) && !isNumeric(b[i]):
+			return -1
+		case i >= len(b) && !isNumeric(a[i]):
+			return 1
+		}
+
+		partA, partB := "0", "0"
+		if i < len(a) {
+			partA = a[i]
+		}
+
+		if i < len(b) {
+			partB = b[i]
+		}
+
+		if result := comparePart(partA, partB); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// comparePart compares numerically the numeric parts, alphabetically the
+// others, the numeric parts being greater.
+func comparePart(a, b string) int {
+	numericA, numericB := isNumeric(a), isNumeric(b)
+
+	switch {
+	case numericA && numericB:
+		// numbers of any length are compared by their digits
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return compareInts(len(a), len(b))
+		}
+	case numericA:
+		return 1
+	case numericB:
+		return -1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func isNumeric(value string) bool {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+// operators contains the comparison operators of the constraints, the
+// longest first since they are matched as prefixes.
+var operators = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// condition is a single comparison of a constraint like <2.4.50
+type condition struct {
+	operator string
+	version  string
+}
+
+// Satisfies returns true if a version satisfies a constraint. The
+// conditions separated by commas must all be satisfied, and at least one
+// of the groups separated by || must be.
+func Satisfies(version, constraint string) (bool, error) {
+	groups, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+
+	if strings.TrimSpace(version) == "" {
+		return false, nil
+	}
+
+	for _, group := range groups {
+		satisfied := true
+
+		for _, c := range group {
+			if !c.satisfied(version) {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Validate checks the syntax of a constraint
+func Validate(constraint string) error {
+	_, err := parseConstraint(constraint)
+
+	return err
+}
+
+// parseConstraint parses the groups of conditions of a constraint
+func parseConstraint(constraint string) ([][]condition, error) {
+	var groups [][]condition
+
+	for _, group := range strings.Split(constraint, "||") {
+		var conditions []condition
+
+		for _, value := range strings.Split(group, ",") {
+			value = strings.TrimSpace(value)
+			c := condition{operator: "="}
+
+			for _, operator := range operators {
+				if strings.HasPrefix(value, operator) {
+					c.operator = operator
+					value = strings.TrimSpace(value[len(operator):])
+
+					break
+				}
+			}
+
+			if value == "" {
+				return nil, fmt.Errorf("invalid version constraint %s", constraint)
+			}
+
+			c.version = value
+			conditions = append(conditions, c)
+		}
+
+		groups = append(groups, conditions)
+	}
+
+	return groups, nil
+}
+
+// satisfied returns true if a version satisfies the condition
+func (c condition) satisfied(version string) bool {
+	result := Compare(version, c.version)
+
+	switch c.operator {
+	case ">=":
+		return result >= 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case "<":
+		return result < 0
+	case "!=":
+		return result != 0
+	}
+
+	return result == 0
+}
@@ -0,0 +1,39 @@
+package vulndb
+
+// DefaultProducts contains the products detected by the templates
+var DefaultProducts = []*Product{
+	{Name: "apache http server", Aliases: []string{"apache", "httpd", "apache httpd"}, CPEs: []string{"cpe:2.3:a:apache:http_server"}},
+	{Name: "nginx", CPEs: []string{"cpe:2.3:a:f5:nginx", "cpe:2.3:a:igor_sysoev:nginx"}},
+	{Name: "openssh", Aliases: []string{"ssh"}, CPEs: []string{"cpe:2.3:a:openbsd:openssh"}},
+	{Name: "openssl", CPEs: []string{"cpe:2.3:a:openssl:openssl"}},
+	{Name: "apache tomcat", Aliases: []string{"tomcat"}, CPEs: []string{"cpe:2.3:a:apache:tomcat"}},
+	{Name: "php", CPEs: []string{"cpe:2.3:a:php:php"}},
+	{Name: "exim", CPEs: []string{"cpe:2.3:a:exim:exim"}},
+	{Name: "vsftpd", CPEs: []string{"cpe:2.3:a:vsftpd_project:vsftpd"}},
+	{Name: "proftpd", CPEs: []string{"cpe:2.3:a:proftpd:proftpd"}},
+	{Name: "grafana", CPEs: []string{"cpe:2.3:a:grafana:grafana"}},
+	{Name: "elasticsearch", CPEs: []string{"cpe:2.3:a:elastic:elasticsearch", "cpe:2.3:a:elasticsearch:elasticsearch"}},
+}
+
+// DefaultVulnerabilities contains well known vulnerabilities of the
+// default products, the update command replacing them with the NVD data.
+var DefaultVulnerabilities = []*Vulnerability{
+	{ID: "CVE-2021-41773", Product: "apache http server", Versions: "=2.4.49", Severity: "high"},
+	{ID: "CVE-2021-42013", Product: "apache http server", Versions: ">=2.4.49, <=2.4.50", Severity: "critical"},
+	{ID: "CVE-2021-40438", Product: "apache http server", Versions: "<=2.4.48", Severity: "critical"},
+	{ID: "CVE-2021-44790", Product: "apache http server", Versions: "<=2.4.51", Severity: "critical"},
+	{ID: "CVE-2013-2028", Product: "nginx", Versions: ">=1.3.9, <=1.4.0", Severity: "high"},
+	{ID: "CVE-2017-7529", Product: "nginx", Versions: ">=0.5.6, <1.13.3", Severity: "high"},
+	{ID: "CVE-2021-23017", Product: "nginx", Versions: ">=0.6.18, <1.20.1", Severity: "high"},
+	{ID: "CVE-2018-15473", Product: "openssh", Versions: "<=7.7", Severity: "medium"},
+	{ID: "CVE-2023-38408", Product: "openssh", Versions: "<9.3p2", Severity: "critical"},
+	{ID: "CVE-2024-6387", Product: "openssh", Versions: "<4.4p1 || >=8.5p1, <9.8p1", Severity: "high"},
+	{ID: "CVE-2014-0160", Product: "openssl", Versions: ">=1.0.1, <1.0.1g", Severity: "high"},
+	{ID: "CVE-2020-1938", Product: "apache tomcat", Versions: ">=7.0.0, <7.0.100 || >=8.5.0, <8.5.51 || >=9.0.0, <9.0.31", Severity: "critical"},
+	{ID: "CVE-2019-11043", Product: "php", Versions: ">=7.1.0, <7.1.33 || >=7.2.0, <7.2.24 || >=7.3.0, <7.3.11", Severity: "critical"},
+	{ID: "CVE-2019-10149", Product: "exim", Versions: ">=4.87, <=4.91", Severity: "critical"},
+	{ID: "CVE-2011-2523", Product: "vsftpd", Versions: "=2.3.4", Severity: "critical"},
+	{ID: "CVE-2015-3306", Product: "proftpd", Versions: "=1.3.5", Severity: "critical"},
+	{ID: "CVE-2021-43798", Product: "grafana", Versions: ">=8.0.0-beta1, <8.0.7 || >=8.1.0, <8.1.8 || >=8.2.0, <8.2.7 || >=8.3.0, <8.3.1", Severity: "high"},
+	{ID: "CVE-2015-1427", Product: "elasticsearch", Versions: "<1.3.8 || >=1.4.0, <1.4.3", Severity: "high"},
+}
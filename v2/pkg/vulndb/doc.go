@@ -0,0 +1,4 @@
+// Package vulndb correlates the product and version fingerprints of the
+// findings with an offline database of known vulnerabilities, bundled and
+// updatable from the NVD.
+package vulndb
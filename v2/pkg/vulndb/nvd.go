@@ -0,0 +1,232 @@
+package vulndb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	// nvdURL is the endpoint of the NVD CVE API
+	nvdURL = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	// nvdPageSize is the maximum number of vulnerabilities per page
+	nvdPageSize = 2000
+	// nvdDelay and nvdKeyDelay respect the NVD public rate limits
+	// without and with an api key.
+	nvdDelay    = 6 * time.Second
+	nvdKeyDelay = 600 * time.Millisecond
+)
+
+// nvdResponse is a page of the NVD CVE API
+type nvdResponse struct {
+	TotalResults    int `json:"totalResults"`
+	Vulnerabilities []struct {
+		CVE struct {
+			ID      string `json:"id"`
+			Metrics struct {
+				V31 []nvdMetric `json:"cvssMetricV31"`
+				V30 []nvdMetric `json:"cvssMetricV30"`
+				V2  []nvdMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					CPEMatch []nvdMatch `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// nvdMetric is a cvss metric, whose severity is in the cvss data since v3
+type nvdMetric struct {
+	BaseSeverity string `json:"baseSeverity"`
+	CVSSData     struct {
+		BaseSeverity string `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+// nvdMatch is a vulnerable cpe with its version range
+type nvdMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// UpdateOptions contains the configuration of the NVD update
+type UpdateOptions struct {
+	// APIKey is an optional NVD api key raising the rate limits
+	APIKey string
+	// Timeout is the timeout of each request
+	Timeout time.Duration
+	// OnProduct is called before the vulnerabilities of a product are fetched
+	OnProduct func(product *Product)
+}
+
+// Update fetches from the NVD the vulnerabilities of the cpes of the
+// products, returning the updated database.
+func Update(products []*Product, options *UpdateOptions) (*Database, error) {
+	client := &http.Client{Timeout: options.Timeout}
+
+	delay := nvdDelay
+	if options.APIKey != "" {
+		delay = nvdKeyDelay
+	}
+
+	var vulnerabilities []*Vulnerability
+
+	first := true
+
+	for _, product := range products {
+		if options.OnProduct != nil {
+			options.OnProduct(product)
+		}
+
+		for _, cpe := range product.CPEs {
+			for start, total := 0, 1; start < total; start += nvdPageSize {
+				if !first {
+					time.Sleep(delay)
+				}
+				first = false
+
+				page, err := fetchPage(client, options.APIKey, cpe, start)
+				if err != nil {
+					return nil, fmt.Errorf("could not fetch the vulnerabilities of %s: %s", cpe, err)
+				}
+
+				total = page.TotalResults
+				vulnerabilities = append(vulnerabilities, pageVulnerabilities(product, cpe, page)...)
+			}
+		}
+	}
+
+	return New(products, vulnerabilities), nil
+}
+
+// fetchPage fetches a page of the vulnerabilities of a cpe
+func fetchPage(client *http.Client, apiKey, cpe string, start int) (*nvdResponse, error) {
+	query := url.Values{}
+	query.Set("virtualMatchString", cpe)
+	query.Set("startIndex", strconv.Itoa(start))
+	query.Set("resultsPerPage", strconv.Itoa(nvdPageSize))
+
+	req, err := http.NewRequest(http.MethodGet, nvdURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey != "" {
+		req.Header.Set("apiKey", apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	page := &nvdResponse{}
+	if err := jsoniter.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// pageVulnerabilities converts the vulnerable cpe matches of a cpe to
+// vulnerabilities of the product.
+func pageVulnerabilities(product *Product, cpe string, page *nvdResponse) []*Vulnerability {
+	var vulnerabilities []*Vulnerability
+
+	for _, item := range page.Vulnerabilities {
+		severity := nvdSeverity(item.CVE.Metrics.V31, item.CVE.Metrics.V30, item.CVE.Metrics.V2)
+
+		for _, configuration := range item.CVE.Configurations {
+			for _, node := range configuration.Nodes {
+				for _, match := range node.CPEMatch {
+					if !match.Vulnerable || !strings.HasPrefix(match.Criteria, cpe+":") {
+						continue
+					}
+
+					if constraint := match.constraint(); constraint != "" {
+						vulnerabilities = append(vulnerabilities, &Vulnerability{
+							ID:       item.CVE.ID,
+							Product:  product.Name,
+							Versions: constraint,
+							Severity: severity,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return vulnerabilities
+}
+
+// constraint returns the version constraint of a cpe match, the exact
+// version of its cpe without a range.
+func (m *nvdMatch) constraint() string {
+	var conditions []string
+
+	if m.VersionStartIncluding != "" {
+		conditions = append(conditions, ">="+m.VersionStartIncluding)
+	}
+
+	if m.VersionStartExcluding != "" {
+		conditions = append(conditions, ">"+m.VersionStartExcluding)
+	}
+
+	if m.VersionEndIncluding != "" {
+		conditions = append(conditions, "<="+m.VersionEndIncluding)
+	}
+
+	if m.VersionEndExcluding != "" {
+		conditions = append(conditions, "<"+m.VersionEndExcluding)
+	}
+
+	if len(conditions) > 0 {
+		return strings.Join(conditions, ", ")
+	}
+
+	// cpe:2.3:part:vendor:product:version:update:...
+	parts := strings.Split(m.Criteria, ":")
+	if len(parts) < 7 || parts[5] == "*" || parts[5] == "-" {
+		return ""
+	}
+
+	version := parts[5]
+	if parts[6] != "*" && parts[6] != "-" {
+		version += parts[6]
+	}
+
+	return "=" + version
+}
+
+// nvdSeverity returns the lowercased severity of the most recent cvss version
+func nvdSeverity(metrics ...[]nvdMetric) string {
+	for _, list := range metrics {
+		for _, metric := range list {
+			if severity := metric.CVSSData.BaseSeverity; severity != "" {
+				return strings.ToLower(severity)
+			}
+
+			if severity := metric.BaseSeverity; severity != "" {
+				return strings.ToLower(severity)
+			}
+		}
+	}
+
+	return ""
+}
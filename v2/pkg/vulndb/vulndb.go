@@ -0,0 +1,157 @@
+package vulndb
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/nuclei/v2/pkg/versions"
+)
+
+// Product is a product whose vulnerabilities are known
+type Product struct {
+	// Name is the name of the product the vulnerabilities refer to
+	Name string `json:"name"`
+	// Aliases are the names the product is detected as
+	Aliases []string `json:"aliases,omitempty"`
+	// CPEs are the cpe prefixes of the product like cpe:2.3:a:f5:nginx
+	CPEs []string `json:"cpes"`
+}
+
+// Vulnerability is a vulnerability of the versions of a product
+type Vulnerability struct {
+	// ID is the identifier of the vulnerability like CVE-2021-41773
+	ID string `json:"id"`
+	// Product is the name of the vulnerable product
+	Product string `json:"product"`
+	// Versions is the constraint on the vulnerable versions
+	Versions string `json:"versions"`
+	// Severity is the severity of the vulnerability, if known
+	Severity string `json:"severity,omitempty"`
+}
+
+// Database contains the known vulnerabilities of the products
+type Database struct {
+	Products        []*Product       `json:"products"`
+	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+
+	// names maps the lowercased aliases and cpes to the product names
+	names map[string]string
+	// byProduct contains the vulnerabilities of each product
+	byProduct map[string][]*Vulnerability
+}
+
+// New creates a database of vulnerabilities
+func New(products []*Product, vulnerabilities []*Vulnerability) *Database {
+	db := &Database{Products: products, Vulnerabilities: vulnerabilities}
+	db.index()
+
+	return db
+}
+
+// Default returns the bundled database
+func Default() *Database {
+	return New(DefaultProducts, DefaultVulnerabilities)
+}
+
+// Load reads a database from a json file
+func Load(file string) (*Database, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	db := &Database{}
+	if err := jsoniter.NewDecoder(f).Decode(db); err != nil {
+		return nil, err
+	}
+
+	db.index()
+
+	return db, nil
+}
+
+// Save writes the database to a json file
+func (db *Database) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return jsoniter.NewEncoder(f).Encode(db)
+}
+
+// index builds the lookup tables of the database
+func (db *Database) index() {
+	db.names = make(map[string]string)
+	db.byProduct = make(map[string][]*Vulnerability)
+
+	for _, product := range db.Products {
+		db.names[strings.ToLower(product.Name)] = product.Name
+
+		for _, alias := range product.Aliases {
+			db.names[strings.ToLower(alias)] = product.Name
+		}
+
+		for _, cpe := range product.CPEs {
+			db.names[strings.ToLower(cpe)] = product.Name
+		}
+	}
+
+	for _, vulnerability := range db.Vulnerabilities {
+		db.byProduct[vulnerability.Product] = append(db.byProduct[vulnerability.Product], vulnerability)
+	}
+}
+
+// product returns the name of a detected product, which can be an alias
+// or a cpe with or without its version.
+func (db *Database) product(detected string) (string, bool) {
+	detected = strings.ToLower(strings.TrimSpace(detected))
+
+	if name, ok := db.names[detected]; ok {
+		return name, true
+	}
+
+	// cpe:2.3:a:vendor:product:version:...
+	if parts := strings.Split(detected, ":"); len(parts) > 5 && parts[0] == "cpe" {
+		name, ok := db.names[strings.Join(parts[:5], ":")]
+		return name, ok
+	}
+
+	return "", false
+}
+
+// Correlate returns the sorted identifiers of the vulnerabilities of a
+// detected product version.
+func (db *Database) Correlate(product, version string) []string {
+	if db == nil || product == "" || version == "" {
+		return nil
+	}
+
+	name, ok := db.product(product)
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+
+	var ids []string
+
+	for _, vulnerability := range db.byProduct[name] {
+		if _, ok := seen[vulnerability.ID]; ok {
+			continue
+		}
+
+		if satisfied, err := versions.Satisfies(version, vulnerability.Versions); err == nil && satisfied {
+			seen[vulnerability.ID] = struct{}{}
+			ids = append(ids, vulnerability.ID)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
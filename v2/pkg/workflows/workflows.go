@@ -30,4 +30,8 @@ type Info struct {
 	Severity string `yaml:"severity,omitempty"`
 	// Description optionally describes the template.
 	Description string `yaml:"description,omitempty"`
+	// Tags optionally contains comma separated tags for the workflow
+	Tags string `yaml:"tags,omitempty"`
+	// Priority optionally orders the workflow execution, higher first
+	Priority int `yaml:"priority,omitempty"`
 }